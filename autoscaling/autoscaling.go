@@ -7,6 +7,7 @@
 package autoscaling
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
@@ -30,11 +31,15 @@ var timeNow = time.Now
 type AutoScaling struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 // New creates a new AutoScaling Client.
 func New(auth aws.Auth, region aws.Region) *AutoScaling {
-	return &AutoScaling{auth, region}
+	return &AutoScaling{auth, region.WithEndpoints(), nil}
 }
 
 // ----------------------------------------------------------------------------
@@ -63,51 +68,60 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	RequestId string  `xml:"RequestId"`
 	Errors    []Error `xml:"Error"`
 }
 
-func (as *AutoScaling) query(params map[string]string, resp interface{}) error {
+func (as *AutoScaling) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	params["Version"] = "2011-01-01"
-	data := strings.NewReader(multimap(params).Encode())
-
-	hreq, err := http.NewRequest("POST", as.Region.AutoScalingEndpoint+"/", data)
-	if err != nil {
-		return err
-	}
-
-	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
-
-	token := as.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
-	}
-
-	signer := aws.NewV4Signer(as.Auth, "autoscaling", as.Region)
-	signer.Sign(hreq)
-
-	if debug {
-		log.Printf("%v -> {\n", hreq)
-	}
-	r, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		log.Printf("Error calling Amazon %v", err)
-		return err
-	}
-
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
-	}
-	if r.StatusCode != 200 {
-		return buildError(r)
+	data := multimap(params).Encode()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "autoscaling",
+		Region:      as.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: as.RateLimiter,
+	}
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("POST", as.Region.AutoScalingEndpoint+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+			return nil
+		},
+		Sign: aws.NewV4Signer(as.Auth, "autoscaling", as.Region).SignHandler(),
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				log.Printf("%v -> {\n", r.HTTPRequest)
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -293,6 +307,11 @@ type CreateAutoScalingGroupParams struct {
 //
 // See http://goo.gl/zDZbuQ for more details.
 func (as *AutoScaling) AttachInstances(name string, instanceIds []string) (resp *SimpleResp, err error) {
+	return as.AttachInstancesWithContext(context.Background(), name, instanceIds)
+}
+
+// AttachInstancesWithContext is like AttachInstances but makes its requests with ctx.
+func (as *AutoScaling) AttachInstancesWithContext(ctx context.Context, name string, instanceIds []string) (resp *SimpleResp, err error) {
 	params := makeParams("AttachInstances")
 	params["AutoScalingGroupName"] = name
 
@@ -302,7 +321,7 @@ func (as *AutoScaling) AttachInstances(name string, instanceIds []string) (resp
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -314,6 +333,12 @@ func (as *AutoScaling) AttachInstances(name string, instanceIds []string) (resp
 //
 // See http://goo.gl/3S13Bv for more details.
 func (as *AutoScaling) CreateAutoScalingGroup(options *CreateAutoScalingGroupParams) (
+	resp *SimpleResp, err error) {
+	return as.CreateAutoScalingGroupWithContext(context.Background(), options)
+}
+
+// CreateAutoScalingGroupWithContext is like CreateAutoScalingGroup but makes its requests with ctx.
+func (as *AutoScaling) CreateAutoScalingGroupWithContext(ctx context.Context, options *CreateAutoScalingGroupParams) (
 	resp *SimpleResp, err error) {
 	params := makeParams("CreateAutoScalingGroup")
 
@@ -361,7 +386,7 @@ func (as *AutoScaling) CreateAutoScalingGroup(options *CreateAutoScalingGroupPar
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -425,6 +450,12 @@ type LaunchConfiguration struct {
 // See http://goo.gl/8e0BSF for more details.
 func (as *AutoScaling) CreateLaunchConfiguration(lc *LaunchConfiguration) (
 	resp *SimpleResp, err error) {
+	return as.CreateLaunchConfigurationWithContext(context.Background(), lc)
+}
+
+// CreateLaunchConfigurationWithContext is like CreateLaunchConfiguration but makes its requests with ctx.
+func (as *AutoScaling) CreateLaunchConfigurationWithContext(ctx context.Context, lc *LaunchConfiguration) (
+	resp *SimpleResp, err error) {
 
 	var b64 = base64.StdEncoding
 
@@ -505,7 +536,7 @@ func (as *AutoScaling) CreateLaunchConfiguration(lc *LaunchConfiguration) (
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -515,6 +546,11 @@ func (as *AutoScaling) CreateLaunchConfiguration(lc *LaunchConfiguration) (
 //
 // See http://goo.gl/e1UIXb for more details.
 func (as *AutoScaling) CreateOrUpdateTags(tags []Tag) (resp *SimpleResp, err error) {
+	return as.CreateOrUpdateTagsWithContext(context.Background(), tags)
+}
+
+// CreateOrUpdateTagsWithContext is like CreateOrUpdateTags but makes its requests with ctx.
+func (as *AutoScaling) CreateOrUpdateTagsWithContext(ctx context.Context, tags []Tag) (resp *SimpleResp, err error) {
 	params := makeParams("CreateOrUpdateTags")
 
 	for i, t := range tags {
@@ -532,7 +568,7 @@ func (as *AutoScaling) CreateOrUpdateTags(tags []Tag) (resp *SimpleResp, err err
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -556,6 +592,12 @@ type CompleteLifecycleActionParams struct {
 //
 // See http://goo.gl/k4fl0p for more details
 func (as *AutoScaling) CompleteLifecycleAction(options *CompleteLifecycleActionParams) (
+	resp *SimpleResp, err error) {
+	return as.CompleteLifecycleActionWithContext(context.Background(), options)
+}
+
+// CompleteLifecycleActionWithContext is like CompleteLifecycleAction but makes its requests with ctx.
+func (as *AutoScaling) CompleteLifecycleActionWithContext(ctx context.Context, options *CompleteLifecycleActionParams) (
 	resp *SimpleResp, err error) {
 	params := makeParams("CompleteLifecycleAction")
 
@@ -565,7 +607,7 @@ func (as *AutoScaling) CompleteLifecycleAction(options *CompleteLifecycleActionP
 	params["LifecycleHookName"] = options.LifecycleHookName
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -575,6 +617,12 @@ func (as *AutoScaling) CompleteLifecycleAction(options *CompleteLifecycleActionP
 //
 // See http://goo.gl/us7VSffor for more details.
 func (as *AutoScaling) DeleteAutoScalingGroup(asgName string, forceDelete bool) (
+	resp *SimpleResp, err error) {
+	return as.DeleteAutoScalingGroupWithContext(context.Background(), asgName, forceDelete)
+}
+
+// DeleteAutoScalingGroupWithContext is like DeleteAutoScalingGroup but makes its requests with ctx.
+func (as *AutoScaling) DeleteAutoScalingGroupWithContext(ctx context.Context, asgName string, forceDelete bool) (
 	resp *SimpleResp, err error) {
 	params := makeParams("DeleteAutoScalingGroup")
 	params["AutoScalingGroupName"] = asgName
@@ -584,7 +632,7 @@ func (as *AutoScaling) DeleteAutoScalingGroup(asgName string, forceDelete bool)
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -594,11 +642,16 @@ func (as *AutoScaling) DeleteAutoScalingGroup(asgName string, forceDelete bool)
 //
 // See http://goo.gl/xksfyR for more details.
 func (as *AutoScaling) DeleteLaunchConfiguration(name string) (resp *SimpleResp, err error) {
+	return as.DeleteLaunchConfigurationWithContext(context.Background(), name)
+}
+
+// DeleteLaunchConfigurationWithContext is like DeleteLaunchConfiguration but makes its requests with ctx.
+func (as *AutoScaling) DeleteLaunchConfigurationWithContext(ctx context.Context, name string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteLaunchConfiguration")
 	params["LaunchConfigurationName"] = name
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -609,12 +662,17 @@ func (as *AutoScaling) DeleteLaunchConfiguration(name string) (resp *SimpleResp,
 //
 // See http://goo.gl/MwX1vG for more details.
 func (as *AutoScaling) DeleteLifecycleHook(asgName, lifecycleHookName string) (resp *SimpleResp, err error) {
+	return as.DeleteLifecycleHookWithContext(context.Background(), asgName, lifecycleHookName)
+}
+
+// DeleteLifecycleHookWithContext is like DeleteLifecycleHook but makes its requests with ctx.
+func (as *AutoScaling) DeleteLifecycleHookWithContext(ctx context.Context, asgName, lifecycleHookName string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteLifecycleHook")
 	params["AutoScalingGroupName"] = asgName
 	params["LifecycleHookName"] = lifecycleHookName
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -624,13 +682,19 @@ func (as *AutoScaling) DeleteLifecycleHook(asgName, lifecycleHookName string) (r
 //
 // See http://goo.gl/jTqoYz for more details
 func (as *AutoScaling) DeleteNotificationConfiguration(asgName string, topicARN string) (
+	resp *SimpleResp, err error) {
+	return as.DeleteNotificationConfigurationWithContext(context.Background(), asgName, topicARN)
+}
+
+// DeleteNotificationConfigurationWithContext is like DeleteNotificationConfiguration but makes its requests with ctx.
+func (as *AutoScaling) DeleteNotificationConfigurationWithContext(ctx context.Context, asgName string, topicARN string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("DeleteNotificationConfiguration")
 	params["AutoScalingGroupName"] = asgName
 	params["TopicARN"] = topicARN
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -642,12 +706,17 @@ func (as *AutoScaling) DeleteNotificationConfiguration(asgName string, topicARN
 //
 // See http://goo.gl/aOQPH2 for more details
 func (as *AutoScaling) DeletePolicy(asgName string, policyName string) (resp *SimpleResp, err error) {
+	return as.DeletePolicyWithContext(context.Background(), asgName, policyName)
+}
+
+// DeletePolicyWithContext is like DeletePolicy but makes its requests with ctx.
+func (as *AutoScaling) DeletePolicyWithContext(ctx context.Context, asgName string, policyName string) (resp *SimpleResp, err error) {
 	params := makeParams("DeletePolicy")
 	params["AutoScalingGroupName"] = asgName
 	params["PolicyName"] = policyName
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -657,12 +726,17 @@ func (as *AutoScaling) DeletePolicy(asgName string, policyName string) (resp *Si
 //
 // See http://goo.gl/Zss9CH for more details
 func (as *AutoScaling) DeleteScheduledAction(asgName string, scheduledActionName string) (resp *SimpleResp, err error) {
+	return as.DeleteScheduledActionWithContext(context.Background(), asgName, scheduledActionName)
+}
+
+// DeleteScheduledActionWithContext is like DeleteScheduledAction but makes its requests with ctx.
+func (as *AutoScaling) DeleteScheduledActionWithContext(ctx context.Context, asgName string, scheduledActionName string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteScheduledAction")
 	params["AutoScalingGroupName"] = asgName
 	params["ScheduledActionName"] = scheduledActionName
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -672,6 +746,11 @@ func (as *AutoScaling) DeleteScheduledAction(asgName string, scheduledActionName
 //
 // See http://goo.gl/o8HzAk for more details.
 func (as *AutoScaling) DeleteTags(tags []Tag) (resp *SimpleResp, err error) {
+	return as.DeleteTagsWithContext(context.Background(), tags)
+}
+
+// DeleteTagsWithContext is like DeleteTags but makes its requests with ctx.
+func (as *AutoScaling) DeleteTagsWithContext(ctx context.Context, tags []Tag) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteTags")
 
 	for i, t := range tags {
@@ -685,7 +764,7 @@ func (as *AutoScaling) DeleteTags(tags []Tag) (resp *SimpleResp, err error) {
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -704,10 +783,15 @@ type DescribeAccountLimitsResp struct {
 //
 // See http://goo.gl/tKsMN0 for more details.
 func (as *AutoScaling) DescribeAccountLimits() (resp *DescribeAccountLimitsResp, err error) {
+	return as.DescribeAccountLimitsWithContext(context.Background())
+}
+
+// DescribeAccountLimitsWithContext is like DescribeAccountLimits but makes its requests with ctx.
+func (as *AutoScaling) DescribeAccountLimitsWithContext(ctx context.Context) (resp *DescribeAccountLimitsResp, err error) {
 	params := makeParams("DescribeAccountLimits")
 
 	resp = new(DescribeAccountLimitsResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -732,10 +816,15 @@ type DescribeAdjustmentTypesResp struct {
 //
 // See http://goo.gl/hGx3Pc for more details.
 func (as *AutoScaling) DescribeAdjustmentTypes() (resp *DescribeAdjustmentTypesResp, err error) {
+	return as.DescribeAdjustmentTypesWithContext(context.Background())
+}
+
+// DescribeAdjustmentTypesWithContext is like DescribeAdjustmentTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeAdjustmentTypesWithContext(ctx context.Context) (resp *DescribeAdjustmentTypesResp, err error) {
 	params := makeParams("DescribeAdjustmentTypes")
 
 	resp = new(DescribeAdjustmentTypesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -756,6 +845,12 @@ type DescribeAutoScalingGroupsResp struct {
 //
 // See http://goo.gl/nW74Ut for more details.
 func (as *AutoScaling) DescribeAutoScalingGroups(names []string, maxRecords int, nextToken string) (
+	resp *DescribeAutoScalingGroupsResp, err error) {
+	return as.DescribeAutoScalingGroupsWithContext(context.Background(), names, maxRecords, nextToken)
+}
+
+// DescribeAutoScalingGroupsWithContext is like DescribeAutoScalingGroups but makes its requests with ctx.
+func (as *AutoScaling) DescribeAutoScalingGroupsWithContext(ctx context.Context, names []string, maxRecords int, nextToken string) (
 	resp *DescribeAutoScalingGroupsResp, err error) {
 	params := makeParams("DescribeAutoScalingGroups")
 
@@ -770,12 +865,34 @@ func (as *AutoScaling) DescribeAutoScalingGroups(names []string, maxRecords int,
 	}
 
 	resp = new(DescribeAutoScalingGroupsResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeAutoScalingGroupsPages returns a Pager over the pages of DescribeAutoScalingGroups results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeAutoScalingGroupsPages(names []string, maxRecords int, nextToken string) *aws.Pager[*DescribeAutoScalingGroupsResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeAutoScalingGroupsResp, bool, error) {
+		resp, err := as.DescribeAutoScalingGroupsWithContext(ctx, names, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeAutoScalingGroupsAll returns the description of every Auto Scaling group in names,
+// or of every group if names is empty, walking every page of DescribeAutoScalingGroups
+// results.
+func (as *AutoScaling) DescribeAutoScalingGroupsAll(ctx context.Context, names []string) ([]AutoScalingGroup, error) {
+	return aws.All(ctx, as.DescribeAutoScalingGroupsPages(names, 0, ""), func(resp *DescribeAutoScalingGroupsResp) []AutoScalingGroup {
+		return resp.AutoScalingGroups
+	})
+}
+
 // DescribeAutoScalingInstances response wrapper
 //
 // See http://goo.gl/ckzORt for more details.
@@ -792,6 +909,12 @@ type DescribeAutoScalingInstancesResp struct {
 //
 // See http://goo.gl/ckzORt for more details.
 func (as *AutoScaling) DescribeAutoScalingInstances(ids []string, maxRecords int, nextToken string) (
+	resp *DescribeAutoScalingInstancesResp, err error) {
+	return as.DescribeAutoScalingInstancesWithContext(context.Background(), ids, maxRecords, nextToken)
+}
+
+// DescribeAutoScalingInstancesWithContext is like DescribeAutoScalingInstances but makes its requests with ctx.
+func (as *AutoScaling) DescribeAutoScalingInstancesWithContext(ctx context.Context, ids []string, maxRecords int, nextToken string) (
 	resp *DescribeAutoScalingInstancesResp, err error) {
 	params := makeParams("DescribeAutoScalingInstances")
 
@@ -806,12 +929,34 @@ func (as *AutoScaling) DescribeAutoScalingInstances(ids []string, maxRecords int
 	}
 
 	resp = new(DescribeAutoScalingInstancesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeAutoScalingInstancesPages returns a Pager over the pages of DescribeAutoScalingInstances results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeAutoScalingInstancesPages(ids []string, maxRecords int, nextToken string) *aws.Pager[*DescribeAutoScalingInstancesResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeAutoScalingInstancesResp, bool, error) {
+		resp, err := as.DescribeAutoScalingInstancesWithContext(ctx, ids, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeAutoScalingInstancesAll returns the description of every instance in ids, or of
+// every instance if ids is empty, walking every page of DescribeAutoScalingInstances
+// results.
+func (as *AutoScaling) DescribeAutoScalingInstancesAll(ctx context.Context, ids []string) ([]Instance, error) {
+	return aws.All(ctx, as.DescribeAutoScalingInstancesPages(ids, 0, ""), func(resp *DescribeAutoScalingInstancesResp) []Instance {
+		return resp.AutoScalingInstances
+	})
+}
+
 // DescribeAutoScalingNotificationTypes response wrapper
 //
 // See http://goo.gl/pmLIoE for more details.
@@ -824,10 +969,15 @@ type DescribeAutoScalingNotificationTypesResp struct {
 //
 // See http://goo.gl/pmLIoE for more details.
 func (as *AutoScaling) DescribeAutoScalingNotificationTypes() (resp *DescribeAutoScalingNotificationTypesResp, err error) {
+	return as.DescribeAutoScalingNotificationTypesWithContext(context.Background())
+}
+
+// DescribeAutoScalingNotificationTypesWithContext is like DescribeAutoScalingNotificationTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeAutoScalingNotificationTypesWithContext(ctx context.Context) (resp *DescribeAutoScalingNotificationTypesResp, err error) {
 	params := makeParams("DescribeAutoScalingNotificationTypes")
 
 	resp = new(DescribeAutoScalingNotificationTypesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -849,6 +999,12 @@ type DescribeLaunchConfigurationsResp struct {
 //
 // See http://goo.gl/y31YYE for more details.
 func (as *AutoScaling) DescribeLaunchConfigurations(names []string, maxRecords int, nextToken string) (
+	resp *DescribeLaunchConfigurationsResp, err error) {
+	return as.DescribeLaunchConfigurationsWithContext(context.Background(), names, maxRecords, nextToken)
+}
+
+// DescribeLaunchConfigurationsWithContext is like DescribeLaunchConfigurations but makes its requests with ctx.
+func (as *AutoScaling) DescribeLaunchConfigurationsWithContext(ctx context.Context, names []string, maxRecords int, nextToken string) (
 	resp *DescribeLaunchConfigurationsResp, err error) {
 	params := makeParams("DescribeLaunchConfigurations")
 
@@ -863,13 +1019,35 @@ func (as *AutoScaling) DescribeLaunchConfigurations(names []string, maxRecords i
 	}
 
 	resp = new(DescribeLaunchConfigurationsResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 
 	return resp, nil
 }
 
+// DescribeLaunchConfigurationsPages returns a Pager over the pages of DescribeLaunchConfigurations results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeLaunchConfigurationsPages(names []string, maxRecords int, nextToken string) *aws.Pager[*DescribeLaunchConfigurationsResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeLaunchConfigurationsResp, bool, error) {
+		resp, err := as.DescribeLaunchConfigurationsWithContext(ctx, names, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeLaunchConfigurationsAll returns every launch configuration in names, or every one if
+// names is empty, walking every page of DescribeLaunchConfigurations
+// results.
+func (as *AutoScaling) DescribeLaunchConfigurationsAll(ctx context.Context, names []string) ([]LaunchConfiguration, error) {
+	return aws.All(ctx, as.DescribeLaunchConfigurationsPages(names, 0, ""), func(resp *DescribeLaunchConfigurationsResp) []LaunchConfiguration {
+		return resp.LaunchConfigurations
+	})
+}
+
 // DescribeLifecycleHookTypesResult wraps a DescribeLifecycleHookTypes response
 //
 // See http://goo.gl/qiAH31 for more details.
@@ -882,11 +1060,17 @@ type DescribeLifecycleHookTypesResult struct {
 //
 // See http://goo.gl/E9IBtY for more information
 func (as *AutoScaling) DescribeLifecycleHookTypes() (
+	resp *DescribeLifecycleHookTypesResult, err error) {
+	return as.DescribeLifecycleHookTypesWithContext(context.Background())
+}
+
+// DescribeLifecycleHookTypesWithContext is like DescribeLifecycleHookTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeLifecycleHookTypesWithContext(ctx context.Context) (
 	resp *DescribeLifecycleHookTypesResult, err error) {
 	params := makeParams("DescribeLifecycleHookTypes")
 
 	resp = new(DescribeLifecycleHookTypesResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -919,6 +1103,12 @@ type DescribeLifecycleHooksResult struct {
 //
 // See http://goo.gl/wQkWiz for more information
 func (as *AutoScaling) DescribeLifecycleHooks(asgName string, hookNames []string) (
+	resp *DescribeLifecycleHooksResult, err error) {
+	return as.DescribeLifecycleHooksWithContext(context.Background(), asgName, hookNames)
+}
+
+// DescribeLifecycleHooksWithContext is like DescribeLifecycleHooks but makes its requests with ctx.
+func (as *AutoScaling) DescribeLifecycleHooksWithContext(ctx context.Context, asgName string, hookNames []string) (
 	resp *DescribeLifecycleHooksResult, err error) {
 	params := makeParams("DescribeLifecycleHooks")
 	params["AutoScalingGroupName"] = asgName
@@ -928,7 +1118,7 @@ func (as *AutoScaling) DescribeLifecycleHooks(asgName string, hookNames []string
 	}
 
 	resp = new(DescribeLifecycleHooksResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -961,10 +1151,15 @@ type DescribeMetricCollectionTypesResp struct {
 //
 // See http://goo.gl/UyYc3i for more details.
 func (as *AutoScaling) DescribeMetricCollectionTypes() (resp *DescribeMetricCollectionTypesResp, err error) {
+	return as.DescribeMetricCollectionTypesWithContext(context.Background())
+}
+
+// DescribeMetricCollectionTypesWithContext is like DescribeMetricCollectionTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeMetricCollectionTypesWithContext(ctx context.Context) (resp *DescribeMetricCollectionTypesResp, err error) {
 	params := makeParams("DescribeMetricCollectionTypes")
 
 	resp = new(DescribeMetricCollectionTypesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -993,6 +1188,12 @@ type DescribeNotificationConfigurationsResp struct {
 //
 // http://goo.gl/qiAH31 for more details.
 func (as *AutoScaling) DescribeNotificationConfigurations(asgNames []string, maxRecords int, nextToken string) (
+	resp *DescribeNotificationConfigurationsResp, err error) {
+	return as.DescribeNotificationConfigurationsWithContext(context.Background(), asgNames, maxRecords, nextToken)
+}
+
+// DescribeNotificationConfigurationsWithContext is like DescribeNotificationConfigurations but makes its requests with ctx.
+func (as *AutoScaling) DescribeNotificationConfigurationsWithContext(ctx context.Context, asgNames []string, maxRecords int, nextToken string) (
 	resp *DescribeNotificationConfigurationsResp, err error) {
 	params := makeParams("DescribeNotificationConfigurations")
 
@@ -1007,12 +1208,34 @@ func (as *AutoScaling) DescribeNotificationConfigurations(asgNames []string, max
 	}
 
 	resp = new(DescribeNotificationConfigurationsResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeNotificationConfigurationsPages returns a Pager over the pages of DescribeNotificationConfigurations results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeNotificationConfigurationsPages(asgNames []string, maxRecords int, nextToken string) *aws.Pager[*DescribeNotificationConfigurationsResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeNotificationConfigurationsResp, bool, error) {
+		resp, err := as.DescribeNotificationConfigurationsWithContext(ctx, asgNames, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeNotificationConfigurationsAll returns every notification configuration of the Auto Scaling
+// groups in asgNames, walking every page of DescribeNotificationConfigurations
+// results.
+func (as *AutoScaling) DescribeNotificationConfigurationsAll(ctx context.Context, asgNames []string) ([]NotificationConfiguration, error) {
+	return aws.All(ctx, as.DescribeNotificationConfigurationsPages(asgNames, 0, ""), func(resp *DescribeNotificationConfigurationsResp) []NotificationConfiguration {
+		return resp.NotificationConfigurations
+	})
+}
+
 // Alarm encapsulates the Alarm data type.
 //
 // See http://goo.gl/Q0uPAB for more details
@@ -1049,6 +1272,12 @@ type DescribePoliciesResp struct {
 //
 // http://goo.gl/bN7A9Tfor more details.
 func (as *AutoScaling) DescribePolicies(asgName string, policyNames []string, maxRecords int, nextToken string) (
+	resp *DescribePoliciesResp, err error) {
+	return as.DescribePoliciesWithContext(context.Background(), asgName, policyNames, maxRecords, nextToken)
+}
+
+// DescribePoliciesWithContext is like DescribePolicies but makes its requests with ctx.
+func (as *AutoScaling) DescribePoliciesWithContext(ctx context.Context, asgName string, policyNames []string, maxRecords int, nextToken string) (
 	resp *DescribePoliciesResp, err error) {
 	params := makeParams("DescribePolicies")
 
@@ -1066,12 +1295,33 @@ func (as *AutoScaling) DescribePolicies(asgName string, policyNames []string, ma
 	}
 
 	resp = new(DescribePoliciesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribePoliciesPages returns a Pager over the pages of DescribePolicies results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribePoliciesPages(asgName string, policyNames []string, maxRecords int, nextToken string) *aws.Pager[*DescribePoliciesResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribePoliciesResp, bool, error) {
+		resp, err := as.DescribePoliciesWithContext(ctx, asgName, policyNames, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribePoliciesAll returns every scaling policy matching asgName and policyNames, walking every page of DescribePolicies
+// results.
+func (as *AutoScaling) DescribePoliciesAll(ctx context.Context, asgName string, policyNames []string) ([]ScalingPolicy, error) {
+	return aws.All(ctx, as.DescribePoliciesPages(asgName, policyNames, 0, ""), func(resp *DescribePoliciesResp) []ScalingPolicy {
+		return resp.ScalingPolicies
+	})
+}
+
 // Activity encapsulates the Activity data type
 //
 // See http://goo.gl/fRaVi1 for more details
@@ -1102,6 +1352,12 @@ type DescribeScalingActivitiesResp struct {
 //
 // http://goo.gl/noOXIC more details.
 func (as *AutoScaling) DescribeScalingActivities(asgName string, activityIds []string, maxRecords int, nextToken string) (
+	resp *DescribeScalingActivitiesResp, err error) {
+	return as.DescribeScalingActivitiesWithContext(context.Background(), asgName, activityIds, maxRecords, nextToken)
+}
+
+// DescribeScalingActivitiesWithContext is like DescribeScalingActivities but makes its requests with ctx.
+func (as *AutoScaling) DescribeScalingActivitiesWithContext(ctx context.Context, asgName string, activityIds []string, maxRecords int, nextToken string) (
 	resp *DescribeScalingActivitiesResp, err error) {
 	params := makeParams("DescribeScalingActivities")
 
@@ -1119,12 +1375,34 @@ func (as *AutoScaling) DescribeScalingActivities(asgName string, activityIds []s
 	}
 
 	resp = new(DescribeScalingActivitiesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeScalingActivitiesPages returns a Pager over the pages of DescribeScalingActivities results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeScalingActivitiesPages(asgName string, activityIds []string, maxRecords int, nextToken string) *aws.Pager[*DescribeScalingActivitiesResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeScalingActivitiesResp, bool, error) {
+		resp, err := as.DescribeScalingActivitiesWithContext(ctx, asgName, activityIds, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeScalingActivitiesAll returns every scaling activity matching asgName and
+// activityIds, walking every page of DescribeScalingActivities
+// results.
+func (as *AutoScaling) DescribeScalingActivitiesAll(ctx context.Context, asgName string, activityIds []string) ([]Activity, error) {
+	return aws.All(ctx, as.DescribeScalingActivitiesPages(asgName, activityIds, 0, ""), func(resp *DescribeScalingActivitiesResp) []Activity {
+		return resp.Activities
+	})
+}
+
 // ProcessType encapsulates the Auto Scaling process data type
 //
 // See http://goo.gl/9BvNik for more details.
@@ -1144,10 +1422,15 @@ type DescribeScalingProcessTypesResp struct {
 //
 // See http://goo.gl/rkp2tw for more details.
 func (as *AutoScaling) DescribeScalingProcessTypes() (resp *DescribeScalingProcessTypesResp, err error) {
+	return as.DescribeScalingProcessTypesWithContext(context.Background())
+}
+
+// DescribeScalingProcessTypesWithContext is like DescribeScalingProcessTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeScalingProcessTypesWithContext(ctx context.Context) (resp *DescribeScalingProcessTypesResp, err error) {
 	params := makeParams("DescribeScalingProcessTypes")
 
 	resp = new(DescribeScalingProcessTypesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1195,6 +1478,12 @@ type DescribeScheduledActionsParams struct {
 //
 // See http://goo.gl/zqrJLx for more details.
 func (as *AutoScaling) DescribeScheduledActions(options *DescribeScheduledActionsParams) (
+	resp *DescribeScheduledActionsResult, err error) {
+	return as.DescribeScheduledActionsWithContext(context.Background(), options)
+}
+
+// DescribeScheduledActionsWithContext is like DescribeScheduledActions but makes its requests with ctx.
+func (as *AutoScaling) DescribeScheduledActionsWithContext(ctx context.Context, options *DescribeScheduledActionsParams) (
 	resp *DescribeScheduledActionsResult, err error) {
 	params := makeParams("DescribeScheduledActions")
 
@@ -1218,7 +1507,7 @@ func (as *AutoScaling) DescribeScheduledActions(options *DescribeScheduledAction
 	}
 
 	resp = new(DescribeScheduledActionsResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1238,6 +1527,12 @@ type DescribeTagsResp struct {
 //
 // See http://goo.gl/ZTEU3G for more details.
 func (as *AutoScaling) DescribeTags(filter *Filter, maxRecords int, nextToken string) (
+	resp *DescribeTagsResp, err error) {
+	return as.DescribeTagsWithContext(context.Background(), filter, maxRecords, nextToken)
+}
+
+// DescribeTagsWithContext is like DescribeTags but makes its requests with ctx.
+func (as *AutoScaling) DescribeTagsWithContext(ctx context.Context, filter *Filter, maxRecords int, nextToken string) (
 	resp *DescribeTagsResp, err error) {
 	params := makeParams("DescribeTags")
 
@@ -1251,12 +1546,33 @@ func (as *AutoScaling) DescribeTags(filter *Filter, maxRecords int, nextToken st
 	filter.addParams(params)
 
 	resp = new(DescribeTagsResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeTagsPages returns a Pager over the pages of DescribeTags results,
+// starting with the page of nextToken.
+func (as *AutoScaling) DescribeTagsPages(filter *Filter, maxRecords int, nextToken string) *aws.Pager[*DescribeTagsResp] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeTagsResp, bool, error) {
+		resp, err := as.DescribeTagsWithContext(ctx, filter, maxRecords, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeTagsAll returns every tag matching filter, walking every page of DescribeTags
+// results.
+func (as *AutoScaling) DescribeTagsAll(ctx context.Context, filter *Filter) ([]Tag, error) {
+	return aws.All(ctx, as.DescribeTagsPages(filter, 0, ""), func(resp *DescribeTagsResp) []Tag {
+		return resp.Tags
+	})
+}
+
 // DescribeTerminationPolicyTypes response wrapper
 //
 // See http://goo.gl/ZTEU3G for more details.
@@ -1269,10 +1585,15 @@ type DescribeTerminationPolicyTypesResp struct {
 //
 // See http://goo.gl/ZTEU3G for more details.
 func (as *AutoScaling) DescribeTerminationPolicyTypes() (resp *DescribeTerminationPolicyTypesResp, err error) {
+	return as.DescribeTerminationPolicyTypesWithContext(context.Background())
+}
+
+// DescribeTerminationPolicyTypesWithContext is like DescribeTerminationPolicyTypes but makes its requests with ctx.
+func (as *AutoScaling) DescribeTerminationPolicyTypesWithContext(ctx context.Context) (resp *DescribeTerminationPolicyTypesResp, err error) {
 	params := makeParams("DescribeTerminationPolicyTypes")
 
 	resp = new(DescribeTerminationPolicyTypesResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1288,6 +1609,12 @@ type DetachInstancesResult struct {
 //
 // See http://goo.gl/cNwrqF for more details
 func (as *AutoScaling) DetachInstances(asgName string, instanceIds []string, decrementCapacity bool) (
+	resp *DetachInstancesResult, err error) {
+	return as.DetachInstancesWithContext(context.Background(), asgName, instanceIds, decrementCapacity)
+}
+
+// DetachInstancesWithContext is like DetachInstances but makes its requests with ctx.
+func (as *AutoScaling) DetachInstancesWithContext(ctx context.Context, asgName string, instanceIds []string, decrementCapacity bool) (
 	resp *DetachInstancesResult, err error) {
 	params := makeParams("DetachInstances")
 	params["AutoScalingGroupName"] = asgName
@@ -1298,7 +1625,7 @@ func (as *AutoScaling) DetachInstances(asgName string, instanceIds []string, dec
 	}
 
 	resp = new(DetachInstancesResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1309,6 +1636,12 @@ func (as *AutoScaling) DetachInstances(asgName string, instanceIds []string, dec
 //
 // See http://goo.gl/kAvzQw for more details.
 func (as *AutoScaling) DisableMetricsCollection(asgName string, metrics []string) (
+	resp *SimpleResp, err error) {
+	return as.DisableMetricsCollectionWithContext(context.Background(), asgName, metrics)
+}
+
+// DisableMetricsCollectionWithContext is like DisableMetricsCollection but makes its requests with ctx.
+func (as *AutoScaling) DisableMetricsCollectionWithContext(ctx context.Context, asgName string, metrics []string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("DisableMetricsCollection")
 	params["AutoScalingGroupName"] = asgName
@@ -1318,7 +1651,7 @@ func (as *AutoScaling) DisableMetricsCollection(asgName string, metrics []string
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1331,6 +1664,12 @@ func (as *AutoScaling) DisableMetricsCollection(asgName string, metrics []string
 //
 // See http://goo.gl/UcVDWn for more details.
 func (as *AutoScaling) EnableMetricsCollection(asgName string, metrics []string, granularity string) (
+	resp *SimpleResp, err error) {
+	return as.EnableMetricsCollectionWithContext(context.Background(), asgName, metrics, granularity)
+}
+
+// EnableMetricsCollectionWithContext is like EnableMetricsCollection but makes its requests with ctx.
+func (as *AutoScaling) EnableMetricsCollectionWithContext(ctx context.Context, asgName string, metrics []string, granularity string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("EnableMetricsCollection")
 	params["AutoScalingGroupName"] = asgName
@@ -1341,7 +1680,7 @@ func (as *AutoScaling) EnableMetricsCollection(asgName string, metrics []string,
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1357,6 +1696,12 @@ type EnterStandbyResult struct {
 //
 // See http://goo.gl/BJ3lXs for more information
 func (as *AutoScaling) EnterStandby(asgName string, instanceIds []string, decrementCapacity bool) (
+	resp *EnterStandbyResult, err error) {
+	return as.EnterStandbyWithContext(context.Background(), asgName, instanceIds, decrementCapacity)
+}
+
+// EnterStandbyWithContext is like EnterStandby but makes its requests with ctx.
+func (as *AutoScaling) EnterStandbyWithContext(ctx context.Context, asgName string, instanceIds []string, decrementCapacity bool) (
 	resp *EnterStandbyResult, err error) {
 	params := makeParams("EnterStandby")
 	params["AutoScalingGroupName"] = asgName
@@ -1367,7 +1712,7 @@ func (as *AutoScaling) EnterStandby(asgName string, instanceIds []string, decrem
 	}
 
 	resp = new(EnterStandbyResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1377,6 +1722,12 @@ func (as *AutoScaling) EnterStandby(asgName string, instanceIds []string, decrem
 //
 // See http://goo.gl/BxHpFc for more details.
 func (as *AutoScaling) ExecutePolicy(policyName string, asgName string, honorCooldown bool) (
+	resp *SimpleResp, err error) {
+	return as.ExecutePolicyWithContext(context.Background(), policyName, asgName, honorCooldown)
+}
+
+// ExecutePolicyWithContext is like ExecutePolicy but makes its requests with ctx.
+func (as *AutoScaling) ExecutePolicyWithContext(ctx context.Context, policyName string, asgName string, honorCooldown bool) (
 	resp *SimpleResp, err error) {
 	params := makeParams("ExecutePolicy")
 	params["PolicyName"] = policyName
@@ -1389,7 +1740,7 @@ func (as *AutoScaling) ExecutePolicy(policyName string, asgName string, honorCoo
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1405,6 +1756,12 @@ type ExitStandbyResult struct {
 //
 // See http://goo.gl/9zQV4G for more information
 func (as *AutoScaling) ExitStandby(asgName string, instanceIds []string) (
+	resp *ExitStandbyResult, err error) {
+	return as.ExitStandbyWithContext(context.Background(), asgName, instanceIds)
+}
+
+// ExitStandbyWithContext is like ExitStandby but makes its requests with ctx.
+func (as *AutoScaling) ExitStandbyWithContext(ctx context.Context, asgName string, instanceIds []string) (
 	resp *ExitStandbyResult, err error) {
 	params := makeParams("ExitStandby")
 	params["AutoScalingGroupName"] = asgName
@@ -1414,7 +1771,7 @@ func (as *AutoScaling) ExitStandby(asgName string, instanceIds []string) (
 	}
 
 	resp = new(ExitStandbyResult)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1445,6 +1802,12 @@ type PutLifecycleHookParams struct {
 //
 // See http://goo.gl/9XrROq for more details.
 func (as *AutoScaling) PutLifecycleHook(options *PutLifecycleHookParams) (
+	resp *SimpleResp, err error) {
+	return as.PutLifecycleHookWithContext(context.Background(), options)
+}
+
+// PutLifecycleHookWithContext is like PutLifecycleHook but makes its requests with ctx.
+func (as *AutoScaling) PutLifecycleHookWithContext(ctx context.Context, options *PutLifecycleHookParams) (
 	resp *SimpleResp, err error) {
 	params := makeParams("PutLifecycleHook")
 	params["AutoScalingGroupName"] = options.AutoScalingGroupName
@@ -1470,7 +1833,7 @@ func (as *AutoScaling) PutLifecycleHook(options *PutLifecycleHookParams) (
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1480,6 +1843,12 @@ func (as *AutoScaling) PutLifecycleHook(options *PutLifecycleHookParams) (
 //
 // See http://goo.gl/9XrROq for more details.
 func (as *AutoScaling) PutNotificationConfiguration(asgName string, notificationTypes []string, topicARN string) (
+	resp *SimpleResp, err error) {
+	return as.PutNotificationConfigurationWithContext(context.Background(), asgName, notificationTypes, topicARN)
+}
+
+// PutNotificationConfigurationWithContext is like PutNotificationConfiguration but makes its requests with ctx.
+func (as *AutoScaling) PutNotificationConfigurationWithContext(ctx context.Context, asgName string, notificationTypes []string, topicARN string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("PutNotificationConfiguration")
 	params["AutoScalingGroupName"] = asgName
@@ -1490,7 +1859,7 @@ func (as *AutoScaling) PutNotificationConfiguration(asgName string, notification
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1520,6 +1889,12 @@ type PutScalingPolicyResp struct {
 //
 // See http://goo.gl/o0E8hl for more details.
 func (as *AutoScaling) PutScalingPolicy(options *PutScalingPolicyParams) (
+	resp *PutScalingPolicyResp, err error) {
+	return as.PutScalingPolicyWithContext(context.Background(), options)
+}
+
+// PutScalingPolicyWithContext is like PutScalingPolicy but makes its requests with ctx.
+func (as *AutoScaling) PutScalingPolicyWithContext(ctx context.Context, options *PutScalingPolicyParams) (
 	resp *PutScalingPolicyResp, err error) {
 	params := makeParams("PutScalingPolicy")
 	params["AutoScalingGroupName"] = options.AutoScalingGroupName
@@ -1535,7 +1910,7 @@ func (as *AutoScaling) PutScalingPolicy(options *PutScalingPolicyParams) (
 	}
 
 	resp = new(PutScalingPolicyResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1565,6 +1940,12 @@ type PutScheduledUpdateGroupActionParams struct {
 //
 // See http://goo.gl/sLPi0d for more details.
 func (as *AutoScaling) PutScheduledUpdateGroupAction(options *PutScheduledUpdateGroupActionParams) (
+	resp *SimpleResp, err error) {
+	return as.PutScheduledUpdateGroupActionWithContext(context.Background(), options)
+}
+
+// PutScheduledUpdateGroupActionWithContext is like PutScheduledUpdateGroupAction but makes its requests with ctx.
+func (as *AutoScaling) PutScheduledUpdateGroupActionWithContext(ctx context.Context, options *PutScheduledUpdateGroupActionParams) (
 	resp *SimpleResp, err error) {
 	params := makeParams("PutScheduledUpdateGroupAction")
 	params["AutoScalingGroupName"] = options.AutoScalingGroupName
@@ -1584,7 +1965,7 @@ func (as *AutoScaling) PutScheduledUpdateGroupAction(options *PutScheduledUpdate
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1603,6 +1984,12 @@ func (as *AutoScaling) PutScheduledUpdateGroupAction(options *PutScheduledUpdate
 //
 // See http://goo.gl/jc70xp for more details.
 func (as *AutoScaling) RecordLifecycleActionHeartbeat(asgName, lifecycleActionToken, hookName string) (
+	resp *SimpleResp, err error) {
+	return as.RecordLifecycleActionHeartbeatWithContext(context.Background(), asgName, lifecycleActionToken, hookName)
+}
+
+// RecordLifecycleActionHeartbeatWithContext is like RecordLifecycleActionHeartbeat but makes its requests with ctx.
+func (as *AutoScaling) RecordLifecycleActionHeartbeatWithContext(ctx context.Context, asgName, lifecycleActionToken, hookName string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("RecordLifecycleActionHeartbeat")
 	params["AutoScalingGroupName"] = asgName
@@ -1610,7 +1997,7 @@ func (as *AutoScaling) RecordLifecycleActionHeartbeat(asgName, lifecycleActionTo
 	params["LifecycleHookName"] = hookName
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1621,6 +2008,12 @@ func (as *AutoScaling) RecordLifecycleActionHeartbeat(asgName, lifecycleActionTo
 //
 // See http://goo.gl/XWIIg1 for more details.
 func (as *AutoScaling) ResumeProcesses(asgName string, processes []string) (
+	resp *SimpleResp, err error) {
+	return as.ResumeProcessesWithContext(context.Background(), asgName, processes)
+}
+
+// ResumeProcessesWithContext is like ResumeProcesses but makes its requests with ctx.
+func (as *AutoScaling) ResumeProcessesWithContext(ctx context.Context, asgName string, processes []string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("ResumeProcesses")
 	params["AutoScalingGroupName"] = asgName
@@ -1630,7 +2023,7 @@ func (as *AutoScaling) ResumeProcesses(asgName string, processes []string) (
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1640,6 +2033,12 @@ func (as *AutoScaling) ResumeProcesses(asgName string, processes []string) (
 //
 // See http://goo.gl/3WGZbI for more details.
 func (as *AutoScaling) SetDesiredCapacity(asgName string, desiredCapacity int, honorCooldown bool) (
+	resp *SimpleResp, err error) {
+	return as.SetDesiredCapacityWithContext(context.Background(), asgName, desiredCapacity, honorCooldown)
+}
+
+// SetDesiredCapacityWithContext is like SetDesiredCapacity but makes its requests with ctx.
+func (as *AutoScaling) SetDesiredCapacityWithContext(ctx context.Context, asgName string, desiredCapacity int, honorCooldown bool) (
 	resp *SimpleResp, err error) {
 	params := makeParams("SetDesiredCapacity")
 	params["AutoScalingGroupName"] = asgName
@@ -1647,7 +2046,7 @@ func (as *AutoScaling) SetDesiredCapacity(asgName string, desiredCapacity int, h
 	params["HonorCooldown"] = strconv.FormatBool(honorCooldown)
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1657,6 +2056,12 @@ func (as *AutoScaling) SetDesiredCapacity(asgName string, desiredCapacity int, h
 //
 // See http://goo.gl/j4ZRxh for more details.
 func (as *AutoScaling) SetInstanceHealth(id string, healthStatus string, respectGracePeriod bool) (
+	resp *SimpleResp, err error) {
+	return as.SetInstanceHealthWithContext(context.Background(), id, healthStatus, respectGracePeriod)
+}
+
+// SetInstanceHealthWithContext is like SetInstanceHealth but makes its requests with ctx.
+func (as *AutoScaling) SetInstanceHealthWithContext(ctx context.Context, id string, healthStatus string, respectGracePeriod bool) (
 	resp *SimpleResp, err error) {
 	params := makeParams("SetInstanceHealth")
 	params["HealthStatus"] = healthStatus
@@ -1665,7 +2070,7 @@ func (as *AutoScaling) SetInstanceHealth(id string, healthStatus string, respect
 	params["ShouldRespectGracePeriod"] = strconv.FormatBool(respectGracePeriod)
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1679,6 +2084,12 @@ func (as *AutoScaling) SetInstanceHealth(id string, healthStatus string, respect
 //
 // See http://goo.gl/DUJpQy for more details.
 func (as *AutoScaling) SuspendProcesses(asgName string, processes []string) (
+	resp *SimpleResp, err error) {
+	return as.SuspendProcessesWithContext(context.Background(), asgName, processes)
+}
+
+// SuspendProcessesWithContext is like SuspendProcesses but makes its requests with ctx.
+func (as *AutoScaling) SuspendProcessesWithContext(ctx context.Context, asgName string, processes []string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("SuspendProcesses")
 	params["AutoScalingGroupName"] = asgName
@@ -1688,7 +2099,7 @@ func (as *AutoScaling) SuspendProcesses(asgName string, processes []string) (
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1707,13 +2118,19 @@ type TerminateInstanceInAutoScalingGroupResp struct {
 //
 // See http://goo.gl/ki5hMh for more details.
 func (as *AutoScaling) TerminateInstanceInAutoScalingGroup(id string, decrCap bool) (
+	resp *TerminateInstanceInAutoScalingGroupResp, err error) {
+	return as.TerminateInstanceInAutoScalingGroupWithContext(context.Background(), id, decrCap)
+}
+
+// TerminateInstanceInAutoScalingGroupWithContext is like TerminateInstanceInAutoScalingGroup but makes its requests with ctx.
+func (as *AutoScaling) TerminateInstanceInAutoScalingGroupWithContext(ctx context.Context, id string, decrCap bool) (
 	resp *TerminateInstanceInAutoScalingGroupResp, err error) {
 	params := makeParams("TerminateInstanceInAutoScalingGroup")
 	params["InstanceId"] = id
 	params["ShouldDecrementDesiredCapacity"] = strconv.FormatBool(decrCap)
 
 	resp = new(TerminateInstanceInAutoScalingGroupResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1727,6 +2144,11 @@ func (as *AutoScaling) TerminateInstanceInAutoScalingGroup(id string, decrCap bo
 //
 // See http://goo.gl/rqrmxy for more details.
 func (as *AutoScaling) UpdateAutoScalingGroup(asg *AutoScalingGroup) (resp *SimpleResp, err error) {
+	return as.UpdateAutoScalingGroupWithContext(context.Background(), asg)
+}
+
+// UpdateAutoScalingGroupWithContext is like UpdateAutoScalingGroup but makes its requests with ctx.
+func (as *AutoScaling) UpdateAutoScalingGroupWithContext(ctx context.Context, asg *AutoScalingGroup) (resp *SimpleResp, err error) {
 	params := makeParams("UpdateAutoScalingGroup")
 
 	params["AutoScalingGroupName"] = asg.AutoScalingGroupName
@@ -1761,7 +2183,7 @@ func (as *AutoScaling) UpdateAutoScalingGroup(asg *AutoScalingGroup) (resp *Simp
 	}
 
 	resp = new(SimpleResp)
-	if err := as.query(params, resp); err != nil {
+	if err := as.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
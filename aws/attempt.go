@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"time"
 )
 
@@ -11,6 +12,48 @@ type AttemptStrategy interface {
 	Start() Attempt
 }
 
+// ContextAttemptStrategy is implemented by attempt strategies whose
+// sequences of attempts can be stopped by a context.
+type ContextAttemptStrategy interface {
+	AttemptStrategy
+	// StartContext begins a new sequence of attempts that ends when ctx
+	// is done, interrupting any wait between attempts.
+	StartContext(ctx context.Context) Attempt
+}
+
+// StartContext begins a new sequence of attempts for s that ends when ctx
+// is done. The first attempt is always made, as is one promised by
+// HasNext, so that the operation fails with ctx's error; waits before them
+// are cut short if s implements ContextAttemptStrategy, as
+// FixedAttemptStrategy and OneAttemptStrategy do.
+func StartContext(ctx context.Context, s AttemptStrategy) Attempt {
+	if cs, ok := s.(ContextAttemptStrategy); ok {
+		return cs.StartContext(ctx)
+	}
+	return &contextAttempt{ctx: ctx, attempt: s.Start(), force: true}
+}
+
+// contextAttempt stops a sequence of attempts of a strategy that does not
+// implement ContextAttemptStrategy when its context is done.
+type contextAttempt struct {
+	ctx     context.Context
+	attempt Attempt
+	force   bool
+}
+
+func (a *contextAttempt) Next(err error) bool {
+	if !a.force && a.ctx.Err() != nil {
+		return false
+	}
+	a.force = false
+	return a.attempt.Next(err)
+}
+
+func (a *contextAttempt) HasNext() bool {
+	a.force = a.ctx.Err() == nil && a.attempt.HasNext()
+	return a.force
+}
+
 // Attempt represents a sequence of attempts to perform an action successfully.
 type Attempt interface {
 	// Next waits until it is time to perform the next attempt or returns
@@ -36,6 +79,7 @@ type FixedAttemptStrategy struct {
 // FixedAttempt implements the Attempt interface for the FixedAttemptStrategy.
 type FixedAttempt struct {
 	strategy FixedAttemptStrategy
+	ctx      context.Context
 	last     time.Time
 	end      time.Time
 	force    bool
@@ -44,9 +88,16 @@ type FixedAttempt struct {
 
 // Start begins a new sequence of attempts for the given strategy.
 func (s FixedAttemptStrategy) Start() Attempt {
+	return s.StartContext(context.Background())
+}
+
+// StartContext begins a new sequence of attempts for the given strategy
+// that ends when ctx is done.
+func (s FixedAttemptStrategy) StartContext(ctx context.Context) Attempt {
 	now := time.Now()
 	return &FixedAttempt{
 		strategy: s,
+		ctx:      ctx,
 		last:     now,
 		end:      now.Add(s.Total),
 		force:    true,
@@ -56,6 +107,9 @@ func (s FixedAttemptStrategy) Start() Attempt {
 // Next waits until it is time to perform the next attempt or returns
 // false if it is time to stop trying.
 func (a *FixedAttempt) Next(err error) bool {
+	if !a.force && a.ctx.Err() != nil {
+		return false
+	}
 	now := time.Now()
 	sleep := a.nextSleep(now)
 	if !a.force && !now.Add(sleep).Before(a.end) && a.strategy.Min <= a.count {
@@ -63,7 +117,12 @@ func (a *FixedAttempt) Next(err error) bool {
 	}
 	a.force = false
 	if sleep > 0 && a.count > 0 {
-		time.Sleep(sleep)
+		timer := time.NewTimer(sleep)
+		select {
+		case <-timer.C:
+		case <-a.ctx.Done():
+			timer.Stop()
+		}
 		now = time.Now()
 	}
 	a.count++
@@ -83,6 +142,9 @@ func (a *FixedAttempt) nextSleep(now time.Time) time.Duration {
 // one fails. If it returns true, the following call to Next is
 // guaranteed to return true.
 func (a *FixedAttempt) HasNext() bool {
+	if a.ctx.Err() != nil {
+		return a.force
+	}
 	if a.force || a.strategy.Min > a.count {
 		return true
 	}
@@ -107,6 +169,11 @@ func (s OneAttemptStrategy) Start() Attempt {
 	return &OneAttempt{count: 0}
 }
 
+// StartContext is the same as Start: the single attempt is always made.
+func (s OneAttemptStrategy) StartContext(ctx context.Context) Attempt {
+	return s.Start()
+}
+
 func (a *OneAttempt) Next(err error) bool {
 	if a.count == 0 {
 		a.count++
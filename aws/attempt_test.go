@@ -1,6 +1,7 @@
 package aws_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/hughe/goamz/aws"
@@ -56,3 +57,40 @@ func (S) TestAttemptNextHasNext(c *C) {
 	c.Assert(a.HasNext(), Equals, false)
 	c.Assert(a.Next(nil), Equals, false)
 }
+
+func (S) TestAttemptContext(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := aws.StartContext(ctx, aws.FixedAttemptStrategy{Total: 5e9, Delay: 1e9})
+	c.Assert(a.Next(nil), Equals, true)
+	c.Assert(a.HasNext(), Equals, true)
+	go func() {
+		time.Sleep(5e7)
+		cancel()
+	}()
+	// The attempt promised by HasNext is made, without the full wait.
+	t0 := time.Now()
+	c.Assert(a.Next(nil), Equals, true)
+	c.Assert(time.Since(t0) < 5e8, Equals, true)
+	c.Assert(a.HasNext(), Equals, false)
+	c.Assert(a.Next(nil), Equals, false)
+
+	// The first attempt is made even if ctx is already done.
+	a = aws.StartContext(ctx, aws.FixedAttemptStrategy{Total: 5e9, Delay: 1e9})
+	c.Assert(a.Next(nil), Equals, true)
+	c.Assert(a.Next(nil), Equals, false)
+}
+
+type plainStrategy struct{ aws.FixedAttemptStrategy }
+
+func (s plainStrategy) Start() aws.Attempt { return s.FixedAttemptStrategy.Start() }
+
+func (S) TestAttemptContextOtherStrategy(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := aws.StartContext(ctx, plainStrategy{aws.FixedAttemptStrategy{Total: 5e9}})
+	c.Assert(a.Next(nil), Equals, true)
+	c.Assert(a.HasNext(), Equals, true)
+	cancel()
+	c.Assert(a.Next(nil), Equals, true)
+	c.Assert(a.HasNext(), Equals, false)
+	c.Assert(a.Next(nil), Equals, false)
+}
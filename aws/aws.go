@@ -10,17 +10,15 @@
 package aws
 
 import (
-	"encoding/json"
+	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
+	"strings"
 	"time"
-
-	"github.com/vaughan0/go-ini"
 )
 
 // Defines the valid signers
@@ -101,11 +99,32 @@ type AWSService interface {
 	BuildError(r *http.Response) error
 }
 
+// ContextAWSService is an AWSService whose queries can be made with a
+// context.
+type ContextAWSService interface {
+	AWSService
+	// QueryContext is like Query but makes the request with ctx.
+	QueryContext(ctx context.Context, method, path string, params map[string]string) (*http.Response, error)
+}
+
+// QueryContext queries s with ctx if s is a ContextAWSService, and without
+// it otherwise.
+func QueryContext(ctx context.Context, s AWSService, method, path string, params map[string]string) (*http.Response, error) {
+	if cs, ok := s.(ContextAWSService); ok {
+		return cs.QueryContext(ctx, method, path, params)
+	}
+	return s.Query(method, path, params)
+}
+
 // Implements a Server Query/Post API to easily query AWS services and build
 // errors when desired
 type Service struct {
 	service ServiceInfo
 	signer  Signer
+
+	// RateLimiter, if not nil, limits the requests of the service (see
+	// RateLimiter).
+	RateLimiter *RateLimiter
 }
 
 // Create a base set of params for an action
@@ -134,22 +153,54 @@ func NewService(auth Auth, service ServiceInfo) (s *Service, err error) {
 }
 
 func (s *Service) Query(method, path string, params map[string]string) (resp *http.Response, err error) {
-	params["Timestamp"] = time.Now().UTC().Format(time.RFC3339)
+	return s.QueryContext(context.Background(), method, path, params)
+}
+
+// QueryContext is like Query but makes the request with ctx.
+func (s *Service) QueryContext(ctx context.Context, method, path string, params map[string]string) (resp *http.Response, err error) {
+	if method != "GET" && method != "POST" {
+		return nil, fmt.Errorf("Unsupported method %q", method)
+	}
 	u, err := url.Parse(s.service.Endpoint)
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path
 
-	s.signer.Sign(method, path, params)
-	if method == "GET" {
-		u.RawQuery = multimap(params).Encode()
-		resp, err = http.Get(u.String())
-	} else if method == "POST" {
-		resp, err = http.PostForm(u.String(), multimap(params))
-	}
-
-	return
+	// Service has never limited how long its requests take.
+	r := &Request{Context: ctx, Operation: params["Action"], Client: UntimedRetryingClient, RateLimiter: s.RateLimiter}
+	if host := strings.SplitN(u.Host, ".", 2); len(host) == 2 {
+		r.Service = host[0]
+	}
+	err = DefaultPipeline.Run(r, Handlers{
+		Build: func(r *Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest(method, u.String(), nil)
+			return err
+		},
+		Sign: func(r *Request) error {
+			signed := make(map[string]string, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = Now().UTC().Format(time.RFC3339)
+			s.signer.Sign(method, path, signed)
+			// The signature is part of the query, so a POST body can
+			// only be set once the request is signed.
+			query := multimap(signed).Encode()
+			if method == "GET" {
+				r.HTTPRequest.URL.RawQuery = query
+				return nil
+			}
+			r.HTTPRequest.Body = ioutil.NopCloser(strings.NewReader(query))
+			r.HTTPRequest.ContentLength = int64(len(query))
+			r.HTTPRequest.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(query)), nil
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return nil
+		},
+	})
+	return r.HTTPResponse, err
 }
 
 func (s *Service) BuildError(r *http.Response) error {
@@ -184,26 +235,60 @@ func (err *Error) Error() string {
 	)
 }
 
+// Auth holds the credentials used to sign requests.
+//
+// An Auth created by AuthFromProvider (or returned by GetAuth for expiring
+// credentials) is bound to a CredentialsProvider, and copies of it share
+// that provider. Requests are signed with the snapshot returned by Current
+// so that credentials are renewed ahead of their expiration.
 type Auth struct {
 	AccessKey, SecretKey string
 	token                string
 	expiration           time.Time
+	provider             *CachedProvider
 }
 
 func (a *Auth) Token() string {
-	if a.token == "" {
-		return ""
-	}
-	if time.Since(a.expiration) >= -30*time.Second { //in an ideal world this should be zero assuming the instance is synching it's clock
-		*a, _ = GetAuth("", "", "", time.Time{})
+	if a.provider != nil {
+		return a.Current().token
 	}
 	return a.token
 }
 
 func (a *Auth) Expiration() time.Time {
+	if a.provider != nil {
+		return a.Current().expiration
+	}
 	return a.expiration
 }
 
+// Credentials returns the Auth's current credentials, retrieving them from
+// its provider if it has one.
+func (a *Auth) Credentials() (Credentials, error) {
+	if a.provider == nil {
+		return Credentials{a.AccessKey, a.SecretKey, a.token, a.expiration}, nil
+	}
+	return a.provider.Retrieve()
+}
+
+// Current returns a snapshot of the Auth holding its current credentials,
+// renewing them through its provider if they are about to expire. The
+// snapshot is not bound to the provider, so signing a request with it
+// always uses one consistent set of keys and token. If the provider fails,
+// the snapshot holds the credentials it last retrieved, even though they
+// have expired. Current does not modify a, so it is safe to call on an
+// Auth shared between goroutines.
+func (a *Auth) Current() Auth {
+	if a.provider == nil {
+		return *a
+	}
+	creds, err := a.provider.Retrieve()
+	if err != nil {
+		creds = a.provider.last()
+	}
+	return authFromCredentials(creds)
+}
+
 // To be used with other APIs that return auth credentials such as STS
 func NewAuth(accessKey, secretKey, token string, expiration time.Time) *Auth {
 	return &Auth{
@@ -214,6 +299,24 @@ func NewAuth(accessKey, secretKey, token string, expiration time.Time) *Auth {
 	}
 }
 
+// AuthFromProvider creates an Auth that retrieves its credentials from p.
+// The provider is wrapped in a CachedProvider (unless it already is one),
+// and the initial credentials are retrieved immediately so that a
+// misconfigured provider is reported here rather than on first use.
+func AuthFromProvider(p CredentialsProvider) (auth Auth, err error) {
+	cached, ok := p.(*CachedProvider)
+	if !ok {
+		cached = NewCachedProvider(p)
+	}
+	creds, err := cached.Retrieve()
+	if err != nil {
+		return
+	}
+	auth = authFromCredentials(creds)
+	auth.provider = cached
+	return
+}
+
 // ResponseMetadata
 type ResponseMetadata struct {
 	RequestId string // A unique ID for tracking the request
@@ -242,97 +345,44 @@ func multimap(p map[string]string) url.Values {
 	return q
 }
 
-type credentials struct {
-	Code            string
-	LastUpdated     string
-	Type            string
-	AccessKeyId     string
-	SecretAccessKey string
-	Token           string
-	Expiration      string
-}
-
-// GetMetaData retrieves instance metadata about the current machine.
-//
-// See http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/AESDG-chapter-instancedata.html for more details.
-func GetMetaData(path string) (contents []byte, err error) {
-	url := "http://169.254.169.254/latest/meta-data/" + path
-
-	resp, err := RetryingClient.Get(url)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		err = fmt.Errorf("Code %d returned for url %s", resp.StatusCode, url)
-		return
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-	return []byte(body), err
-}
-
-func getInstanceCredentials() (cred credentials, err error) {
-	credentialPath := "iam/security-credentials/"
-
-	// Get the instance role
-	role, err := GetMetaData(credentialPath)
-	if err != nil {
-		return
-	}
-
-	// Get the instance role credentials
-	credentialJSON, err := GetMetaData(credentialPath + string(role))
-	if err != nil {
-		return
-	}
-
-	err = json.Unmarshal([]byte(credentialJSON), &cred)
-	return
-}
-
 // GetAuth creates an Auth based on either passed in credentials,
 // environment information or instance based role credentials.
+//
+// Sources that hand out expiring credentials (such as the instance role)
+// produce an Auth bound to that source, which renews the credentials as
+// they approach expiration.
 func GetAuth(accessKey string, secretKey, token string, expiration time.Time) (auth Auth, err error) {
 	// First try passed in credentials
 	if accessKey != "" && secretKey != "" {
-		return Auth{accessKey, secretKey, token, expiration}, nil
+		return Auth{AccessKey: accessKey, SecretKey: secretKey, token: token, expiration: expiration}, nil
 	}
 
-	// Next try to get auth from the shared credentials file
-	auth, err = SharedAuth()
-	if err == nil {
-		// Found auth, return
+	// Next try the shared credentials file, the environment, a web
+	// identity token, the ECS task role and the instance role, in that
+	// order.
+	chain := ChainProvider{defaultProviders()}
+	provider, creds, err := chain.retrieve()
+	if err != nil {
 		return
 	}
-
-	// Next try to get auth from the environment
-	auth, err = EnvAuth()
-	if err == nil {
-		// Found auth, return
-		return
+	auth = authFromCredentials(creds)
+	if !creds.Expiration.IsZero() {
+		// Bind the Auth to the source, starting from the credentials
+		// just retrieved.
+		cached := NewCachedProvider(provider)
+		cached.creds, cached.cached = creds, true
+		auth.provider = cached
 	}
+	return
+}
 
-	// Next try getting auth from the instance role
-	cred, err := getInstanceCredentials()
-	if err == nil {
-		// Found auth, return
-		auth.AccessKey = cred.AccessKeyId
-		auth.SecretKey = cred.SecretAccessKey
-		auth.token = cred.Token
-		exptdate, err := time.Parse("2006-01-02T15:04:05Z", cred.Expiration)
-		if err != nil {
-			err = fmt.Errorf("Error Parseing expiration date: cred.Expiration :%s , error: %s \n", cred.Expiration, err)
-		}
-		auth.expiration = exptdate
-		return auth, err
+func authFromCredentials(creds Credentials) Auth {
+	return Auth{
+		AccessKey:  creds.AccessKey,
+		SecretKey:  creds.SecretKey,
+		token:      creds.Token,
+		expiration: creds.Expiration,
 	}
-	err = errors.New("No valid AWS authentication found")
-	return auth, err
 }
 
 // EnvAuth creates an Auth based on environment information.
@@ -340,68 +390,16 @@ func GetAuth(accessKey string, secretKey, token string, expiration time.Time) (a
 // variables are used.
 // AWS_SESSION_TOKEN is used if present.
 func EnvAuth() (auth Auth, err error) {
-	auth.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
-	if auth.AccessKey == "" {
-		auth.AccessKey = os.Getenv("AWS_ACCESS_KEY")
-	}
-
-	auth.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
-	if auth.SecretKey == "" {
-		auth.SecretKey = os.Getenv("AWS_SECRET_KEY")
-	}
-	if auth.AccessKey == "" {
-		err = errors.New("AWS_ACCESS_KEY_ID or AWS_ACCESS_KEY not found in environment")
-	}
-	if auth.SecretKey == "" {
-		err = errors.New("AWS_SECRET_ACCESS_KEY or AWS_SECRET_KEY not found in environment")
-	}
-
-	auth.token = os.Getenv("AWS_SESSION_TOKEN")
-	return
+	creds, err := EnvProvider{}.Retrieve()
+	return authFromCredentials(creds), err
 }
 
 // SharedAuth creates an Auth based on shared credentials stored in
 // $HOME/.aws/credentials. The AWS_PROFILE environment variables is used to
 // select the profile.
 func SharedAuth() (auth Auth, err error) {
-	var profileName = os.Getenv("AWS_PROFILE")
-
-	if profileName == "" {
-		profileName = "default"
-	}
-
-	var credentialsFile = os.Getenv("AWS_CREDENTIAL_FILE")
-	if credentialsFile == "" {
-		var homeDir = os.Getenv("HOME")
-		if homeDir == "" {
-			err = errors.New("Could not get HOME")
-			return
-		}
-		credentialsFile = homeDir + "/.aws/credentials"
-	}
-
-	file, err := ini.LoadFile(credentialsFile)
-	if err != nil {
-		err = errors.New("Couldn't parse AWS credentials file")
-		return
-	}
-
-	var profile = file[profileName]
-	if profile == nil {
-		err = errors.New("Couldn't find profile in AWS credentials file")
-		return
-	}
-
-	auth.AccessKey = profile["aws_access_key_id"]
-	auth.SecretKey = profile["aws_secret_access_key"]
-
-	if auth.AccessKey == "" {
-		err = errors.New("AWS_ACCESS_KEY_ID not found in environment in credentials file")
-	}
-	if auth.SecretKey == "" {
-		err = errors.New("AWS_SECRET_ACCESS_KEY not found in credentials file")
-	}
-	return
+	creds, err := SharedCredentialsProvider{}.Retrieve()
+	return authFromCredentials(creds), err
 }
 
 // Encode takes a string and URI-encodes it in a way suitable
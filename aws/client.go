@@ -1,6 +1,10 @@
 package aws
 
 import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
 	"math"
 	"net"
 	"net/http"
@@ -11,7 +15,20 @@ type RetryableFunc func(*http.Request, *http.Response, error) bool
 type WaitFunc func(try int)
 type DeadlineFunc func() time.Time
 
+// ResilientTransport is an http.RoundTripper that retries failed requests.
+//
+// Each attempt is made with Transport. Retries stop as soon as the
+// request's context is done, including while waiting between attempts.
+// Request bodies are rewound between attempts with the request's GetBody
+// function (which http.NewRequest sets for the common body types); a
+// request with a body but no GetBody is never retried.
 type ResilientTransport struct {
+	// Transport makes the individual attempts. If nil, NewClient sets it
+	// to an *http.Transport that dials with DialTimeout and uses the
+	// proxy configured in the environment; RoundTrip uses
+	// http.DefaultTransport if it is still nil.
+	Transport http.RoundTripper
+
 	// Timeout is the maximum amount of time a dial will wait for
 	// a connect to complete.
 	//
@@ -20,51 +37,66 @@ type ResilientTransport struct {
 	// With or without a timeout, the operating system may impose
 	// its own earlier timeout. For instance, TCP timeouts are
 	// often around 3 minutes.
+	//
+	// DialTimeout is only used by the Transport created by NewClient.
 	DialTimeout time.Duration
 
+	// AttemptTimeout, if non-zero, limits each attempt, from sending the
+	// request until its response body is closed. An attempt that times
+	// out before the response headers arrive is retried.
+	AttemptTimeout time.Duration
+
 	// MaxTries, if non-zero, specifies the number of times we will retry on
 	// failure. Retries are only attempted for temporary network errors or known
 	// safe failures.
-	MaxTries    int
-	Deadline    DeadlineFunc
+	MaxTries int
+
+	// Deadline, if set and AttemptTimeout is zero, is called at the start
+	// of each attempt and gives the time at which it times out.
+	Deadline DeadlineFunc
+
 	ShouldRetry RetryableFunc
 	Wait        WaitFunc
-	transport   *http.Transport
+
+	// Policy, if not nil, decides which requests are retried and how
+	// long to wait between attempts, in place of MaxTries, ShouldRetry
+	// and Wait.
+	Policy *RetryPolicy
 }
 
 // Convenience method for creating an http client
 func NewClient(rt *ResilientTransport) *http.Client {
-	rt.transport = &http.Transport{
-		Dial: func(netw, addr string) (net.Conn, error) {
-			c, err := net.DialTimeout(netw, addr, rt.DialTimeout)
-			if err != nil {
-				return nil, err
-			}
-			c.SetDeadline(rt.Deadline())
-			return c, nil
-		},
-		Proxy: http.ProxyFromEnvironment,
+	if rt.Transport == nil {
+		rt.Transport = &http.Transport{
+			DialContext:         (&net.Dialer{Timeout: rt.DialTimeout}).DialContext,
+			Proxy:               http.ProxyFromEnvironment,
+			TLSHandshakeTimeout: 10 * time.Second,
+		}
 	}
-	// TODO: Would be nice is ResilientTransport allowed clients to initialize
-	// with http.Transport attributes.
 	return &http.Client{
 		Transport: rt,
 	}
 }
 
 var retryingTransport = &ResilientTransport{
-	Deadline: func() time.Time {
-		return time.Now().Add(5 * time.Second)
-	},
-	DialTimeout: 10 * time.Second,
-	MaxTries:    3,
-	ShouldRetry: awsRetry,
-	Wait:        ExpBackoff,
+	AttemptTimeout: 5 * time.Second,
+	DialTimeout:    10 * time.Second,
+	Policy:         DefaultRetryPolicy,
 }
 
 // Exported default client
 var RetryingClient = NewClient(retryingTransport)
 
+// UntimedRetryingClient retries failed requests like RetryingClient, but
+// does not limit how long each attempt may take. It is used by the service
+// clients whose requests may be held by the service for a while, such as
+// SQS ReceiveMessage long polls, and which were not timed before they
+// retried.
+var UntimedRetryingClient = NewClient(&ResilientTransport{
+	DialTimeout: 10 * time.Second,
+	Policy:      DefaultRetryPolicy,
+})
+
 func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return t.tries(req)
 }
@@ -74,23 +106,218 @@ func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error
 // If a wait function is specified, wait that amount of time
 // In between requests.
 func (t *ResilientTransport) tries(req *http.Request) (res *http.Response, err error) {
-	for try := 0; try < t.MaxTries; try += 1 {
-		res, err = t.transport.RoundTrip(req)
+	ctx := req.Context()
+	policy := t.Policy
+	maxTries := t.MaxTries
+	if policy != nil {
+		maxTries = policy.MaxAttempts
+	}
+	retryTokens := 0
+	attemptReq := req
+	observer, _ := ctx.Value(attemptObserverKey{}).(attemptObserver)
+	for try := 0; ; try++ {
+		res, err = t.attempt(attemptReq)
+		if observer != nil {
+			observer.attempted(res, err)
+		}
 
-		if !t.ShouldRetry(req, res, err) {
+		if try+1 >= maxTries || ctx.Err() != nil || !t.shouldRetry(req, res, err) {
 			break
 		}
+		next, rewindErr := rewindBody(req)
+		if rewindErr != nil {
+			break
+		}
+		if policy != nil {
+			cost, ok := policy.acquire(err)
+			if !ok {
+				break
+			}
+			retryTokens += cost
+		}
 		if res != nil {
-			res.Body.Close()
+			discardBody(res)
+		}
+		var waitErr error
+		if policy != nil {
+			waitErr = sleepContext(ctx, policy.Delay(try, res))
+		} else if t.Wait != nil {
+			waitErr = waitContext(ctx, t.Wait, try)
 		}
-		if t.Wait != nil {
-			t.Wait(try)
+		if waitErr == nil && observer != nil {
+			waitErr = observer.retrying(ctx)
 		}
+		if waitErr != nil {
+			return nil, waitErr
+		}
+		attemptReq = next
+	}
+	if policy != nil && err == nil && res.StatusCode < 300 {
+		policy.succeeded(retryTokens)
 	}
-
 	return
 }
 
+func (t *ResilientTransport) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if t.Policy != nil {
+		return t.Policy.Retryable(res, err)
+	}
+	if t.ShouldRetry != nil {
+		return t.ShouldRetry(req, res, err)
+	}
+	return awsRetry(req, res, err)
+}
+
+// An attemptObserver, carried by the context of a request, is told by
+// ResilientTransport of the outcome of each attempt at the request, and
+// may hold back its retries.
+type attemptObserver interface {
+	attempted(res *http.Response, err error)
+	retrying(ctx context.Context) error
+}
+
+type attemptObserverKey struct{}
+
+// withAttemptObserver returns a copy of ctx carrying o along with any
+// attemptObserver ctx already carries.
+func withAttemptObserver(ctx context.Context, o attemptObserver) context.Context {
+	if prev, ok := ctx.Value(attemptObserverKey{}).(attemptObserver); ok {
+		o = attemptObservers{prev, o}
+	}
+	return context.WithValue(ctx, attemptObserverKey{}, o)
+}
+
+type attemptObservers []attemptObserver
+
+func (os attemptObservers) attempted(res *http.Response, err error) {
+	for _, o := range os {
+		o.attempted(res, err)
+	}
+}
+
+func (os attemptObservers) retrying(ctx context.Context) error {
+	for _, o := range os {
+		if err := o.retrying(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt makes a single attempt at req, limited by AttemptTimeout or
+// Deadline.
+func (t *ResilientTransport) attempt(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	parent := req.Context()
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if t.AttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, t.AttemptTimeout)
+	} else if t.Deadline != nil {
+		ctx, cancel = context.WithDeadline(parent, t.Deadline())
+	}
+	res, err := transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		if ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+			err = &attemptTimeoutError{err}
+		}
+		return nil, err
+	}
+	res.Body = &cancelOnClose{res.Body, cancel}
+	return res, nil
+}
+
+// rewindBody returns a copy of req, with a fresh body, for the next
+// attempt.
+func rewindBody(req *http.Request) (*http.Request, error) {
+	next := req.WithContext(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errNotRewindable
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+var errNotRewindable = errors.New("request body cannot be rewound")
+
+// rewindable reports whether rewindBody can rewind the body of req.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// discardBody reads a little of the body of a response that will not be
+// used, so that its connection may be reused, and closes it.
+func discardBody(res *http.Response) {
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 4096))
+	res.Body.Close()
+}
+
+// waitContext calls wait, returning early with the context's error if ctx
+// is done first.
+func waitContext(ctx context.Context, wait WaitFunc, try int) error {
+	if ctx.Done() == nil {
+		wait(try)
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		wait(try)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sleepContext waits for d, returning early with the context's error if
+// ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelOnClose releases the context of an attempt when the response body
+// is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// attemptTimeoutError is returned for an attempt that exceeded its
+// timeout. It is a temporary net.Error, so the attempt is retried.
+type attemptTimeoutError struct {
+	err error
+}
+
+func (e *attemptTimeoutError) Error() string   { return "attempt timed out: " + e.err.Error() }
+func (e *attemptTimeoutError) Timeout() bool   { return true }
+func (e *attemptTimeoutError) Temporary() bool { return true }
+
 func ExpBackoff(try int) {
 	time.Sleep(100 * time.Millisecond *
 		time.Duration(math.Exp2(float64(try))))
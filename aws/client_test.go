@@ -1,12 +1,14 @@
 package aws_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/hughe/goamz/aws"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -119,3 +121,121 @@ func TestClient_fails(t *testing.T) {
 		t.Fatal("Didn't retry enough")
 	}
 }
+
+func TestClient_rewindsBody(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			http.Error(w, "error", 500)
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := aws.RetryingClient.Post(ts.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Fatalf("Body not resent: %q", bodies)
+	}
+}
+
+func TestClient_noRetryWithoutGetBody(t *testing.T) {
+	tries := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tries += 1
+		http.Error(w, "error", 500)
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL, ioutil.NopCloser(strings.NewReader("payload")))
+	resp, err := aws.RetryingClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 500 || tries != 1 {
+		t.Fatalf("Unrewindable body retried: status %d, %d tries", resp.StatusCode, tries)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClient_customTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("X-Custom"))
+	}))
+	defer ts.Close()
+
+	client := aws.NewClient(&aws.ResilientTransport{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Custom", "yes")
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+		MaxTries: 3,
+	})
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "yes" {
+		t.Fatal("Custom transport not used.")
+	}
+}
+
+func TestClient_attemptTimeout(t *testing.T) {
+	var tries int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&tries, 1) == 1 {
+			time.Sleep(500 * time.Millisecond)
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := aws.NewClient(&aws.ResilientTransport{
+		AttemptTimeout: 100 * time.Millisecond,
+		MaxTries:       3,
+	})
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if n := atomic.LoadInt32(&tries); string(b) != "ok" || n != 2 {
+		t.Fatalf("Timed out attempt not retried: %q after %d tries", b, n)
+	}
+}
+
+func TestClient_contextCancelsWait(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error", 500)
+	}))
+	defer ts.Close()
+
+	client := aws.NewClient(&aws.ResilientTransport{
+		MaxTries: 3,
+		Wait:     func(try int) { time.Sleep(time.Minute) },
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	start := time.Now()
+	_, err := client.Do(req.WithContext(ctx))
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("Expected context error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("Wait not interrupted by context.")
+	}
+}
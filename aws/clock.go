@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"bytes"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// A Clock gives the time with which requests are signed: the local time
+// corrected by an offset learned from AWS when it rejects a request as
+// signed with a skewed clock.
+type Clock struct {
+	offset int64 // nanoseconds; accessed atomically
+}
+
+// DefaultClock is the Clock of all the signers in this project. Its
+// offset is learned by DefaultPipeline, which signs a request again with
+// the corrected time when it is rejected for clock skew.
+var DefaultClock = &Clock{}
+
+// Now returns the current time according to DefaultClock.
+func Now() time.Time {
+	return DefaultClock.Now()
+}
+
+// Now returns the current time, corrected by the offset of c.
+func (c *Clock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns how far ahead of the local clock the clock of AWS was
+// found to be.
+func (c *Clock) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.offset))
+}
+
+// SetOffset sets the offset of c.
+func (c *Clock) SetOffset(d time.Duration) {
+	atomic.StoreInt64(&c.offset, int64(d))
+}
+
+// skewCodes are the error codes with which AWS services reject requests
+// signed too long before or after their own time.
+var skewCodes = map[string]bool{
+	"RequestTimeTooSkewed": true,
+	"RequestExpired":       true,
+	"RequestInTheFuture":   true,
+}
+
+// IsSkewError reports whether an error response with the given body means
+// the request was signed with a skewed clock. Besides the dedicated error
+// codes, some services report signature errors whose messages tell of
+// expired or not yet current signatures.
+func IsSkewError(body []byte) bool {
+	code := ErrorCode(body)
+	if skewCodes[code] {
+		return true
+	}
+	if code == "InvalidSignatureException" || code == "SignatureDoesNotMatch" {
+		return bytes.Contains(body, []byte("Signature expired")) || bytes.Contains(body, []byte("Signature not yet current"))
+	}
+	return false
+}
+
+// Correct sets the offset of c from the Date header of res, if res
+// rejects a request as signed with a skewed clock, and reports whether it
+// did. The body of res is left to be read again.
+func (c *Clock) Correct(res *http.Response) bool {
+	if res == nil || res.StatusCode < 400 {
+		return false
+	}
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil || !IsSkewError(peekErrorBody(res)) {
+		return false
+	}
+	c.SetOffset(date.Sub(time.Now()))
+	return true
+}
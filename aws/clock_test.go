@@ -0,0 +1,97 @@
+package aws_test
+
+import (
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockSkewCorrection(t *testing.T) {
+	defer aws.DefaultClock.SetOffset(0)
+
+	// The server's clock is an hour ahead.
+	v := &aws.Verifier{
+		Credentials: aws.NewStaticCredentials(aws.Credentials{AccessKey: "access", SecretKey: "secret"}),
+		Now:         func() time.Time { return time.Now().Add(time.Hour) },
+	}
+	var dates []string
+	var reject bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dates = append(dates, r.Header.Get("X-Amz-Date"))
+		err := v.Verify(r)
+		if reject {
+			err = &aws.SignatureError{Code: "RequestTimeTooSkewed", ServerTime: time.Now()}
+		}
+		if err != nil {
+			e := err.(*aws.SignatureError)
+			w.Header().Set("Date", e.ServerTime.UTC().Format(http.TimeFormat))
+			w.WriteHeader(e.HTTPStatusCode())
+			fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", e.Code, e.Message)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	run := func() (*aws.Request, string) {
+		var body string
+		r := &aws.Request{Client: http.DefaultClient, Data: &body}
+		err := (&aws.Pipeline{}).Run(r, aws.Handlers{
+			Build: func(r *aws.Request) (err error) {
+				r.HTTPRequest, err = http.NewRequest("GET", ts.URL, nil)
+				return err
+			},
+			Sign: aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "iam", aws.USEast).SignHandler(),
+			Unmarshal: func(r *aws.Request) error {
+				b, err := ioutil.ReadAll(r.HTTPResponse.Body)
+				*r.Data.(*string) = string(b)
+				return err
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.HTTPResponse.Body.Close()
+		return r, body
+	}
+
+	r, body := run()
+	if body != "ok" || r.Attempts != 2 || len(dates) != 2 || dates[0] == dates[1] {
+		t.Fatalf("Got %q after %d attempts dated %v", body, r.Attempts, dates)
+	}
+	if offset := aws.DefaultClock.Offset(); offset < 59*time.Minute || offset > 61*time.Minute {
+		t.Fatalf("Clock offset is %v", offset)
+	}
+
+	// Later requests are signed with the corrected time.
+	r, body = run()
+	if body != "ok" || r.Attempts != 1 {
+		t.Fatalf("Got %q after %d attempts", body, r.Attempts)
+	}
+
+	// A request rejected again is not retried any further.
+	reject = true
+	r, body = run()
+	if r.Attempts != 2 || r.HTTPResponse.StatusCode != 403 {
+		t.Fatalf("Got %q after %d attempts", body, r.Attempts)
+	}
+}
+
+func TestIsSkewError(t *testing.T) {
+	for body, want := range map[string]bool{
+		`<Error><Code>RequestTimeTooSkewed</Code></Error>`:                                                                                                     true,
+		`<Response><Errors><Error><Code>RequestExpired</Code></Error></Errors></Response>`:                                                                     true,
+		`{"__type":"com.amazon.coral.service#InvalidSignatureException","message":"Signature expired: 20150830T123600Z is now earlier than 20150830T124100Z"}`: true,
+		`<ErrorResponse><Error><Code>SignatureDoesNotMatch</Code><Message>Signature not yet current</Message></Error></ErrorResponse>`:                         true,
+		`<Error><Code>SignatureDoesNotMatch</Code></Error>`:                                                                                                    false,
+		`<Error><Code>Throttling</Code></Error>`:                                                                                                               false,
+	} {
+		if got := aws.IsSkewError([]byte(body)); got != want {
+			t.Errorf("IsSkewError(%s) = %v, want %v", body, got, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ContainerCredentialsEndpoint is the address of the ECS task credentials
+// endpoint, to which AWS_CONTAINER_CREDENTIALS_RELATIVE_URI is relative.
+const ContainerCredentialsEndpoint = "http://169.254.170.2"
+
+// ContainerProvider retrieves the credentials of the task role of an ECS
+// task (or of an EKS pod identity) from the container credentials
+// endpoint.
+//
+// If URI is empty, it is taken from AWS_CONTAINER_CREDENTIALS_RELATIVE_URI,
+// which is resolved against ContainerCredentialsEndpoint, or else from
+// AWS_CONTAINER_CREDENTIALS_FULL_URI. A full URI must use https or refer to
+// a loopback or link-local address. If AuthorizationToken is empty, it is
+// taken from AWS_CONTAINER_AUTHORIZATION_TOKEN or read from the file named
+// by AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE; if set, it is sent in the
+// Authorization header.
+type ContainerProvider struct {
+	URI                string
+	AuthorizationToken string
+
+	// Client is used to make requests; RetryingClient is used if nil.
+	Client *http.Client
+}
+
+type containerCredentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	Token           string
+	Expiration      string
+	RoleArn         string
+
+	// Set on failure.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (p ContainerProvider) uri() (string, error) {
+	if p.URI != "" {
+		return p.URI, nil
+	}
+	if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+		return ContainerCredentialsEndpoint + relative, nil
+	}
+	full := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if full == "" {
+		return "", errors.New("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or AWS_CONTAINER_CREDENTIALS_FULL_URI not found in environment")
+	}
+	u, err := url.Parse(full)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "https" {
+		ip := net.ParseIP(u.Hostname())
+		if u.Hostname() != "localhost" && (ip == nil || !(ip.IsLoopback() || ip.IsLinkLocalUnicast())) {
+			return "", fmt.Errorf("AWS_CONTAINER_CREDENTIALS_FULL_URI %q must use https or a loopback host", full)
+		}
+	}
+	return full, nil
+}
+
+func (p ContainerProvider) authorizationToken() (string, error) {
+	if p.AuthorizationToken != "" {
+		return p.AuthorizationToken, nil
+	}
+	if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+		return token, nil
+	}
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		token, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+	return "", nil
+}
+
+func (p ContainerProvider) Retrieve() (creds Credentials, err error) {
+	uri, err := p.uri()
+	if err != nil {
+		return
+	}
+	token, err := p.authorizationToken()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	client := p.Client
+	if client == nil {
+		client = RetryingClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	var cred containerCredentials
+	jsonErr := json.Unmarshal(body, &cred)
+	if resp.StatusCode != 200 {
+		if jsonErr == nil && cred.Message != "" {
+			err = fmt.Errorf("Code %d returned for url %s: %s: %s", resp.StatusCode, uri, cred.Code, cred.Message)
+		} else {
+			err = fmt.Errorf("Code %d returned for url %s", resp.StatusCode, uri)
+		}
+		return
+	}
+	if jsonErr != nil {
+		err = fmt.Errorf("invalid container credentials from %s: %v", uri, jsonErr)
+		return
+	}
+	creds.AccessKey = cred.AccessKeyId
+	creds.SecretKey = cred.SecretAccessKey
+	creds.Token = cred.Token
+	if cred.Expiration != "" {
+		creds.Expiration, err = time.Parse(time.RFC3339, cred.Expiration)
+	}
+	return
+}
@@ -0,0 +1,93 @@
+package aws_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+// newContainerEndpoint returns a fake container credentials endpoint that
+// requires the authorization token "Bearer secret" and hands out
+// credentials with the access keys "access1", "access2", ...
+func newContainerEndpoint(expiration time.Time) (*httptest.Server, *[]*http.Request) {
+	var reqs []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reqs = append(reqs, req)
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"code": "AccessDenied", "message": "invalid authorization token"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"AccessKeyId": "access%d", "SecretAccessKey": "secret", "Token": "token", "Expiration": %q, "RoleArn": "arn:aws:iam::123456789012:role/task"}`,
+			len(reqs), expiration.UTC().Format(time.RFC3339))
+	}))
+	return server, &reqs
+}
+
+func (s *S) TestContainerProvider(c *C) {
+	server, reqs := newContainerEndpoint(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer server.Close()
+
+	p := aws.ContainerProvider{URI: server.URL + "/v2/credentials/task", AuthorizationToken: "Bearer secret"}
+	creds, err := p.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{
+		AccessKey:  "access1",
+		SecretKey:  "secret",
+		Token:      "token",
+		Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	c.Assert((*reqs)[0].URL.Path, Equals, "/v2/credentials/task")
+
+	p.AuthorizationToken = "Bearer wrong"
+	_, err = p.Retrieve()
+	c.Assert(err, ErrorMatches, "Code 403 returned for url .*: AccessDenied: invalid authorization token")
+}
+
+func (s *S) TestContainerProviderEnv(c *C) {
+	server, reqs := newContainerEndpoint(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer server.Close()
+
+	os.Clearenv()
+	_, err := aws.ContainerProvider{}.Retrieve()
+	c.Assert(err, ErrorMatches, "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or AWS_CONTAINER_CREDENTIALS_FULL_URI not found in environment")
+
+	tokenFile := filepath.Join(c.MkDir(), "token")
+	c.Assert(ioutil.WriteFile(tokenFile, []byte("Bearer secret\n"), 0600), IsNil)
+	os.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/creds")
+	os.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", tokenFile)
+	creds, err := aws.ContainerProvider{}.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "access1")
+	c.Assert((*reqs)[0].URL.Path, Equals, "/creds")
+
+	os.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "http://example.com/creds")
+	_, err = aws.ContainerProvider{}.Retrieve()
+	c.Assert(err, ErrorMatches, `AWS_CONTAINER_CREDENTIALS_FULL_URI "http://example.com/creds" must use https or a loopback host`)
+}
+
+func (s *S) TestGetAuthContainer(c *C) {
+	server, reqs := newContainerEndpoint(time.Now().Add(30 * time.Second))
+	defer server.Close()
+
+	os.Clearenv()
+	os.Setenv("HOME", c.MkDir())
+	os.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/creds")
+	os.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "Bearer secret")
+	auth, err := aws.GetAuth("", "", "", time.Time{})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "access1")
+	c.Assert(len(*reqs), Equals, 1)
+
+	// The credentials are within the expiry window, so they are renewed.
+	current := auth.Current()
+	c.Assert(current.AccessKey, Equals, "access2")
+	c.Assert(current.Token(), Equals, "token")
+}
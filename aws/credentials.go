@@ -0,0 +1,239 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials holds a set of AWS keys, an optional session token and the
+// time at which they expire. A zero Expiration means the credentials never
+// expire.
+type Credentials struct {
+	AccessKey  string
+	SecretKey  string
+	Token      string
+	Expiration time.Time
+}
+
+// expiresWithin reports whether the credentials expire within d of now.
+func (c Credentials) expiresWithin(d time.Duration) bool {
+	if c.Expiration.IsZero() {
+		return false
+	}
+	return !time.Now().Add(d).Before(c.Expiration)
+}
+
+// A CredentialsProvider is a source of AWS credentials.
+//
+// Retrieve is called whenever fresh credentials are needed. Implementations
+// need not cache; wrap a provider with NewCachedProvider to share one set of
+// credentials between requests and renew them ahead of expiration.
+type CredentialsProvider interface {
+	Retrieve() (Credentials, error)
+}
+
+// StaticProvider returns a fixed set of credentials.
+type StaticProvider struct {
+	Credentials
+}
+
+func (p StaticProvider) Retrieve() (Credentials, error) {
+	if p.AccessKey == "" || p.SecretKey == "" {
+		return Credentials{}, errors.New("static credentials are empty")
+	}
+	return p.Credentials, nil
+}
+
+// EnvProvider retrieves credentials from the environment.
+// The AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment
+// variables are used (or AWS_ACCESS_KEY and AWS_SECRET_KEY).
+// AWS_SESSION_TOKEN is used if present.
+type EnvProvider struct{}
+
+func (p EnvProvider) Retrieve() (creds Credentials, err error) {
+	creds.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	if creds.AccessKey == "" {
+		creds.AccessKey = os.Getenv("AWS_ACCESS_KEY")
+	}
+
+	creds.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if creds.SecretKey == "" {
+		creds.SecretKey = os.Getenv("AWS_SECRET_KEY")
+	}
+	if creds.AccessKey == "" {
+		err = errors.New("AWS_ACCESS_KEY_ID or AWS_ACCESS_KEY not found in environment")
+	}
+	if creds.SecretKey == "" {
+		err = errors.New("AWS_SECRET_ACCESS_KEY or AWS_SECRET_KEY not found in environment")
+	}
+
+	creds.Token = os.Getenv("AWS_SESSION_TOKEN")
+	return
+}
+
+// SharedCredentialsProvider retrieves the credentials of a profile from
+// the shared credentials and config files (see LoadSharedConfigFiles).
+// If Filename is empty, AWS_SHARED_CREDENTIALS_FILE, AWS_CREDENTIAL_FILE or
+// else $HOME/.aws/credentials is used; if ConfigFilename is empty,
+// AWS_CONFIG_FILE or else $HOME/.aws/config is used. If Profile is empty,
+// AWS_PROFILE or else "default" is used.
+//
+// Profiles that assume a role with the credentials of another source are
+// not supported here; use sts.ProfileAuth for those.
+type SharedCredentialsProvider struct {
+	Filename       string
+	ConfigFilename string
+	Profile        string
+}
+
+func (p SharedCredentialsProvider) Retrieve() (creds Credentials, err error) {
+	config, err := LoadSharedConfigFiles(p.Filename, p.ConfigFilename)
+	if err != nil {
+		return
+	}
+	chain, err := config.ProfileChain(p.Profile)
+	if err != nil {
+		return
+	}
+	if len(chain) > 1 {
+		profile := chain[len(chain)-1]
+		err = fmt.Errorf("profile %q assumes role %s; use sts.ProfileAuth", profile.Name, profile.RoleArn)
+		return
+	}
+	source, err := config.SourceProvider(chain[0])
+	if err != nil {
+		return
+	}
+	return source.Retrieve()
+}
+
+// InstanceMetadataProvider retrieves the credentials of the IAM role
+// attached to the current EC2 instance. Client is used to query the
+// instance metadata service; DefaultMetadataClient is used if it is nil.
+type InstanceMetadataProvider struct {
+	Client *MetadataClient
+}
+
+func (p InstanceMetadataProvider) Retrieve() (creds Credentials, err error) {
+	client := p.Client
+	if client == nil {
+		client = DefaultMetadataClient
+	}
+	cred, err := client.getInstanceCredentials()
+	if err != nil {
+		return
+	}
+	creds.AccessKey = cred.AccessKeyId
+	creds.SecretKey = cred.SecretAccessKey
+	creds.Token = cred.Token
+	creds.Expiration, err = time.Parse("2006-01-02T15:04:05Z", cred.Expiration)
+	if err != nil {
+		err = fmt.Errorf("Error Parseing expiration date: cred.Expiration :%s , error: %s \n", cred.Expiration, err)
+	}
+	return
+}
+
+// ChainProvider tries each of Providers in turn and returns the
+// credentials of the first one that succeeds. It stops at a provider that
+// is configured but cannot work, such as web identity credentials in a
+// program without the sts package, and returns its error.
+type ChainProvider struct {
+	Providers []CredentialsProvider
+}
+
+func (p ChainProvider) Retrieve() (Credentials, error) {
+	_, creds, err := p.retrieve()
+	return creds, err
+}
+
+// retrieve is like Retrieve but also returns the provider that succeeded.
+func (p ChainProvider) retrieve() (CredentialsProvider, Credentials, error) {
+	var errs []string
+	for _, provider := range p.Providers {
+		creds, err := provider.Retrieve()
+		if err == nil {
+			return provider, creds, nil
+		}
+		var stop chainStopError
+		if errors.As(err, &stop) {
+			return nil, Credentials{}, err
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return nil, Credentials{}, errors.New("No valid AWS authentication found")
+	}
+	return nil, Credentials{}, fmt.Errorf("No valid AWS authentication found: %s", strings.Join(errs, "; "))
+}
+
+// defaultProviders returns the providers consulted by GetAuth, in order.
+func defaultProviders() []CredentialsProvider {
+	return []CredentialsProvider{
+		SharedCredentialsProvider{},
+		EnvProvider{},
+		WebIdentityEnvProvider{},
+		ContainerProvider{},
+		InstanceMetadataProvider{},
+	}
+}
+
+// DefaultExpiryWindow is how long before their expiration a CachedProvider
+// renews credentials.
+const DefaultExpiryWindow = time.Minute
+
+// CachedProvider wraps a CredentialsProvider and caches the credentials it
+// returns until they are within ExpiryWindow of expiring. It is safe for
+// concurrent use; at most one goroutine renews the credentials at a time
+// and the others wait for its result.
+type CachedProvider struct {
+	Provider     CredentialsProvider
+	ExpiryWindow time.Duration
+
+	mu     sync.Mutex
+	creds  Credentials
+	cached bool
+}
+
+// NewCachedProvider returns a CachedProvider for p that renews credentials
+// DefaultExpiryWindow before they expire.
+func NewCachedProvider(p CredentialsProvider) *CachedProvider {
+	return &CachedProvider{Provider: p, ExpiryWindow: DefaultExpiryWindow}
+}
+
+// Retrieve returns the cached credentials, renewing them first if they are
+// missing or about to expire. If renewal fails but the cached credentials
+// have not yet actually expired, they are returned instead of the error.
+func (p *CachedProvider) Retrieve() (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached && !p.creds.expiresWithin(p.ExpiryWindow) {
+		return p.creds, nil
+	}
+	creds, err := p.Provider.Retrieve()
+	if err != nil {
+		if p.cached && !p.creds.expiresWithin(0) {
+			return p.creds, nil
+		}
+		return Credentials{}, err
+	}
+	p.creds, p.cached = creds, true
+	return creds, nil
+}
+
+// last returns the credentials last retrieved, even if they have expired.
+func (p *CachedProvider) last() Credentials {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.creds
+}
+
+// Expire forces the next call to Retrieve to renew the credentials.
+func (p *CachedProvider) Expire() {
+	p.mu.Lock()
+	p.cached = false
+	p.mu.Unlock()
+}
@@ -0,0 +1,177 @@
+package aws_test
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+// countingProvider hands out a new set of credentials, valid for ttl, on
+// every call to Retrieve.
+type countingProvider struct {
+	mu    sync.Mutex
+	calls int
+	ttl   time.Duration
+	err   error
+}
+
+func (p *countingProvider) Retrieve() (aws.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return aws.Credentials{}, p.err
+	}
+	creds := aws.Credentials{
+		AccessKey: "access",
+		SecretKey: "secret",
+		Token:     "token" + strconv.Itoa(p.calls),
+	}
+	if p.ttl != 0 {
+		creds.Expiration = time.Now().Add(p.ttl)
+	}
+	return creds, nil
+}
+
+func (s *S) TestCachedProviderCaches(c *C) {
+	p := &countingProvider{ttl: time.Hour}
+	cp := aws.NewCachedProvider(p)
+	for i := 0; i < 3; i++ {
+		creds, err := cp.Retrieve()
+		c.Assert(err, IsNil)
+		c.Assert(creds.Token, Equals, "token1")
+	}
+	c.Assert(p.calls, Equals, 1)
+}
+
+func (s *S) TestCachedProviderRenewsAheadOfExpiry(c *C) {
+	p := &countingProvider{ttl: 30 * time.Second}
+	cp := aws.NewCachedProvider(p)
+	creds, err := cp.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.Token, Equals, "token1")
+
+	// The credentials are within the default one minute window.
+	creds, err = cp.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.Token, Equals, "token2")
+	c.Assert(p.calls, Equals, 2)
+}
+
+func (s *S) TestCachedProviderNeverExpires(c *C) {
+	p := &countingProvider{}
+	cp := aws.NewCachedProvider(p)
+	cp.Retrieve()
+	cp.Retrieve()
+	c.Assert(p.calls, Equals, 1)
+
+	cp.Expire()
+	creds, err := cp.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.Token, Equals, "token2")
+}
+
+func (s *S) TestCachedProviderKeepsValidCredentialsOnError(c *C) {
+	p := &countingProvider{ttl: 30 * time.Second}
+	cp := aws.NewCachedProvider(p)
+	_, err := cp.Retrieve()
+	c.Assert(err, IsNil)
+
+	p.err = errors.New("unavailable")
+	creds, err := cp.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.Token, Equals, "token1")
+
+	cp.ExpiryWindow = time.Hour
+	p.ttl = -time.Second
+	cp.Expire()
+	_, err = cp.Retrieve()
+	c.Assert(err, ErrorMatches, "unavailable")
+}
+
+func (s *S) TestCachedProviderConcurrent(c *C) {
+	p := &countingProvider{ttl: time.Hour}
+	cp := aws.NewCachedProvider(p)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cp.Retrieve()
+		}()
+	}
+	wg.Wait()
+	c.Assert(p.calls, Equals, 1)
+}
+
+func (s *S) TestChainProvider(c *C) {
+	os.Clearenv()
+	os.Setenv("AWS_ACCESS_KEY_ID", "access")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	chain := aws.ChainProvider{[]aws.CredentialsProvider{
+		&countingProvider{err: errors.New("first failed")},
+		aws.EnvProvider{},
+		&countingProvider{},
+	}}
+	creds, err := chain.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{AccessKey: "access", SecretKey: "secret"})
+}
+
+func (s *S) TestChainProviderNoneFound(c *C) {
+	chain := aws.ChainProvider{[]aws.CredentialsProvider{
+		&countingProvider{err: errors.New("first failed")},
+		&countingProvider{err: errors.New("second failed")},
+	}}
+	_, err := chain.Retrieve()
+	c.Assert(err, ErrorMatches, "No valid AWS authentication found: first failed; second failed")
+}
+
+func (s *S) TestAuthFromProvider(c *C) {
+	p := &countingProvider{ttl: 30 * time.Second}
+	auth, err := aws.AuthFromProvider(p)
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "access")
+	c.Assert(auth.SecretKey, Equals, "secret")
+
+	// Every snapshot renews the soon to expire credentials, and copies
+	// of the Auth share the provider.
+	copied := auth
+	current := copied.Current()
+	c.Assert(current.Token(), Equals, "token2")
+	c.Assert(auth.Token(), Equals, "token3")
+	c.Assert(auth.Expiration().After(time.Now()), Equals, true)
+}
+
+func (s *S) TestAuthCurrentProviderFails(c *C) {
+	p := &countingProvider{ttl: -time.Second}
+	auth, err := aws.AuthFromProvider(p)
+	c.Assert(err, IsNil)
+	c.Assert(auth.Token(), Equals, "token2")
+
+	// The expired credentials cannot be renewed: the snapshot holds
+	// those last retrieved, not those the Auth was created with.
+	p.err = errors.New("renewal failed")
+	current := auth.Current()
+	c.Assert(current.Token(), Equals, "token2")
+	c.Assert(current.AccessKey, Equals, "access")
+	c.Assert(p.calls, Equals, 3)
+}
+
+func (s *S) TestAuthFromProviderError(c *C) {
+	_, err := aws.AuthFromProvider(&countingProvider{err: errors.New("no credentials")})
+	c.Assert(err, ErrorMatches, "no credentials")
+}
+
+func (s *S) TestAuthCurrentStatic(c *C) {
+	auth := aws.Auth{AccessKey: "access", SecretKey: "secret"}
+	c.Assert(auth.Current(), Equals, auth)
+	creds, err := auth.Credentials()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{AccessKey: "access", SecretKey: "secret"})
+}
@@ -0,0 +1,345 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Endpoint describes where and how to send requests for a service in a
+// region.
+type Endpoint struct {
+	URL              string
+	SigningName      string // the service name used in V4 signatures
+	SigningRegion    string // the region name used in V4 signatures
+	SignatureVersion uint   // V2Signature or V4Signature
+}
+
+// An EndpointResolver maps a service name and a region name to the
+// endpoint of that service.
+//
+// Service names are the endpoint prefixes used by AWS, e.g. "ec2", "s3",
+// "email" (SES), "elasticloadbalancing" (ELB) or "monitoring"
+// (CloudWatch).
+type EndpointResolver interface {
+	ResolveEndpoint(service, region string) (Endpoint, error)
+}
+
+// EndpointResolverFunc is an adapter to allow the use of ordinary
+// functions as endpoint resolvers.
+type EndpointResolverFunc func(service, region string) (Endpoint, error)
+
+func (f EndpointResolverFunc) ResolveEndpoint(service, region string) (Endpoint, error) {
+	return f(service, region)
+}
+
+// StaticEndpoint returns an EndpointResolver that resolves every service
+// and region to url, with the standard signing settings of the service.
+// It is intended for Resolver.Overrides, e.g. to point s3 at a MinIO or
+// LocalStack server.
+func StaticEndpoint(url string) EndpointResolver {
+	return EndpointResolverFunc(func(service, region string) (Endpoint, error) {
+		return Endpoint{
+			URL:              url,
+			SigningName:      signingName(service),
+			SigningRegion:    region,
+			SignatureVersion: signatureVersion(service),
+		}, nil
+	})
+}
+
+// A Partition is a group of regions that share a DNS suffix.
+type Partition struct {
+	ID                 string
+	RegionRegex        *regexp.Regexp // matches the names of the partition's regions
+	DNSSuffix          string
+	DualStackDNSSuffix string
+
+	// Endpoints lists the endpoints that do not follow the standard
+	// https://{service}.{region}.{DNSSuffix} pattern, by service name and
+	// then by region name. The empty region name matches every region of
+	// the partition, for global services such as IAM.
+	Endpoints map[string]map[string]Endpoint
+}
+
+// DefaultPartitions are the partitions known to Resolver when its
+// Partitions field is nil.
+var DefaultPartitions = []Partition{
+	{
+		ID:                 "aws",
+		RegionRegex:        regexp.MustCompile(`^(us|eu|ap|sa|ca|me|af|il|mx)\-\w+\-\d+$`),
+		DNSSuffix:          "amazonaws.com",
+		DualStackDNSSuffix: "api.aws",
+		Endpoints: map[string]map[string]Endpoint{
+			"iam": {"": {URL: "https://iam.amazonaws.com", SigningRegion: "us-east-1"}},
+			"s3":  {"us-east-1": {URL: "https://s3.amazonaws.com"}},
+			"sdb": {"us-east-1": {URL: "https://sdb.amazonaws.com"}},
+			"sts": {"us-east-1": {URL: "https://sts.amazonaws.com"}},
+		},
+	},
+	{
+		ID:                 "aws-cn",
+		RegionRegex:        regexp.MustCompile(`^cn\-\w+\-\d+$`),
+		DNSSuffix:          "amazonaws.com.cn",
+		DualStackDNSSuffix: "api.amazonwebservices.com.cn",
+		Endpoints: map[string]map[string]Endpoint{
+			"iam": {"": {URL: "https://iam.cn-north-1.amazonaws.com.cn", SigningRegion: "cn-north-1"}},
+		},
+	},
+	{
+		ID:                 "aws-us-gov",
+		RegionRegex:        regexp.MustCompile(`^us\-gov\-\w+\-\d+$`),
+		DNSSuffix:          "amazonaws.com",
+		DualStackDNSSuffix: "api.aws",
+		Endpoints: map[string]map[string]Endpoint{
+			"iam": {"": {URL: "https://iam.us-gov.amazonaws.com", SigningRegion: "us-gov-west-1"}},
+			"sts": {"": {URL: "https://sts.us-gov-west-1.amazonaws.com", SigningRegion: "us-gov-west-1"}},
+		},
+	},
+}
+
+// Resolver is an EndpointResolver that derives endpoints from a table of
+// partitions.
+type Resolver struct {
+	// Partitions are searched in order for one whose RegionRegex
+	// matches the region; DefaultPartitions is used if nil.
+	Partitions []Partition
+
+	// UseFIPS and UseDualStack select the FIPS 140-2 validated and the
+	// IPv4/IPv6 dual-stack variants of endpoints. The endpoints listed
+	// in Partition.Endpoints only apply to the standard variant.
+	UseFIPS      bool
+	UseDualStack bool
+
+	// Overrides replaces the resolution of individual services.
+	Overrides map[string]EndpointResolver
+}
+
+// DefaultEndpointResolver resolves the endpoints of a Region that are
+// missing or left at their defaults (see Region.ResolveEndpoint and
+// Region.WithEndpoints), and so those the service clients use.
+var DefaultEndpointResolver EndpointResolver = &Resolver{}
+
+// Partition returns the partition region belongs to.
+func (r *Resolver) Partition(region string) (*Partition, error) {
+	partitions := r.Partitions
+	if partitions == nil {
+		partitions = DefaultPartitions
+	}
+	for i := range partitions {
+		if partitions[i].RegionRegex.MatchString(region) {
+			return &partitions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown region %q", region)
+}
+
+func (r *Resolver) ResolveEndpoint(service, region string) (endpoint Endpoint, err error) {
+	if override, ok := r.Overrides[service]; ok {
+		return override.ResolveEndpoint(service, region)
+	}
+	p, err := r.Partition(region)
+	if err != nil {
+		return
+	}
+	endpoint = Endpoint{
+		SigningName:      signingName(service),
+		SigningRegion:    region,
+		SignatureVersion: signatureVersion(service),
+	}
+	hostRegion := region
+	if regions, ok := p.Endpoints[service]; ok {
+		exception, ok := regions[region]
+		if !ok {
+			exception, ok = regions[""]
+		}
+		if ok {
+			if exception.SigningRegion != "" {
+				endpoint.SigningRegion = exception.SigningRegion
+				hostRegion = exception.SigningRegion
+			}
+			if !r.UseFIPS && !r.UseDualStack {
+				endpoint.URL = exception.URL
+				return
+			}
+		}
+	}
+
+	suffix := p.DNSSuffix
+	if r.UseDualStack {
+		suffix = p.DualStackDNSSuffix
+	}
+	prefix := service
+	if r.UseFIPS {
+		prefix += "-fips"
+	}
+	if service == "s3" && r.UseDualStack {
+		// S3 predates the api.aws dual-stack domain.
+		prefix += ".dualstack"
+		suffix = p.DNSSuffix
+	}
+	endpoint.URL = "https://" + prefix + "." + hostRegion + "." + suffix
+	return
+}
+
+func signingName(service string) string {
+	if service == "email" {
+		return "ses"
+	}
+	return service
+}
+
+func signatureVersion(service string) uint {
+	switch service {
+	case "monitoring", "rds":
+		return V2Signature
+	}
+	return V4Signature
+}
+
+// endpointFields maps service names to the Region field holding their
+// endpoint.
+var endpointFields = map[string]func(r *Region) *string{
+	"ec2":                  func(r *Region) *string { return &r.EC2Endpoint },
+	"s3":                   func(r *Region) *string { return &r.S3Endpoint },
+	"sdb":                  func(r *Region) *string { return &r.SDBEndpoint },
+	"email":                func(r *Region) *string { return &r.SESEndpoint },
+	"sns":                  func(r *Region) *string { return &r.SNSEndpoint },
+	"sqs":                  func(r *Region) *string { return &r.SQSEndpoint },
+	"iam":                  func(r *Region) *string { return &r.IAMEndpoint },
+	"elasticloadbalancing": func(r *Region) *string { return &r.ELBEndpoint },
+	"dynamodb":             func(r *Region) *string { return &r.DynamoDBEndpoint },
+	"monitoring":           func(r *Region) *string { return &r.CloudWatchServicepoint.Endpoint },
+	"autoscaling":          func(r *Region) *string { return &r.AutoScalingEndpoint },
+	"rds":                  func(r *Region) *string { return &r.RDSEndpoint.Endpoint },
+	"sts":                  func(r *Region) *string { return &r.STSEndpoint },
+	"cloudformation":       func(r *Region) *string { return &r.CloudFormationEndpoint },
+	"ecs":                  func(r *Region) *string { return &r.ECSEndpoint },
+}
+
+// ResolveEndpoint returns the endpoint of service in r.
+//
+// Endpoints missing from r, and the default endpoints of r's region
+// (those listed in Regions, or following the standard pattern of its
+// partition), are resolved with DefaultEndpointResolver, so that its
+// overrides and its FIPS and dual-stack variants apply to every known
+// region. Only while DefaultEndpointResolver is a Resolver that changes
+// nothing about service do the endpoints listed in Regions stay as they
+// are. Any other endpoint set in r is used as it is.
+//
+// The signing name and region of the endpoint are those the resolver
+// gives for its URL, if any, and otherwise the standard ones of service
+// in r.
+func (r Region) ResolveEndpoint(service string) (Endpoint, error) {
+	var url string
+	if field, ok := endpointFields[service]; ok {
+		url = *field(&r)
+	}
+	if url == "" {
+		return DefaultEndpointResolver.ResolveEndpoint(service, r.Name)
+	}
+	configured := Endpoint{
+		URL:              url,
+		SigningName:      signingName(service),
+		SigningRegion:    r.Name,
+		SignatureVersion: signatureVersion(service),
+	}
+	switch service {
+	case "monitoring":
+		configured.SignatureVersion = r.CloudWatchServicepoint.Signer
+	case "rds":
+		configured.SignatureVersion = r.RDSEndpoint.Signer
+	}
+	if r.Name == "" {
+		return configured, nil
+	}
+	resolved, err := DefaultEndpointResolver.ResolveEndpoint(service, r.Name)
+	switch {
+	case err != nil:
+		return configured, nil
+	case resolved.URL == url:
+		return resolved, nil
+	case !isDefaultEndpoint(service, r.Name, url) || keepsDefaultEndpoints(service):
+		return configured, nil
+	}
+	return resolved, nil
+}
+
+// isDefaultEndpoint reports whether url is a default endpoint of service
+// in region: the one listed in Regions, or the standard one of the
+// region's partition.
+func isDefaultEndpoint(service, region, url string) bool {
+	if known, ok := Regions[region]; ok && *endpointFields[service](&known) == url {
+		return true
+	}
+	standard, err := (&Resolver{}).ResolveEndpoint(service, region)
+	return err == nil && standard.URL == url
+}
+
+// keepsDefaultEndpoints reports whether DefaultEndpointResolver leaves the
+// default endpoints of service as they are.
+func keepsDefaultEndpoints(service string) bool {
+	r, ok := DefaultEndpointResolver.(*Resolver)
+	if !ok {
+		return false
+	}
+	_, overridden := r.Overrides[service]
+	return !r.UseFIPS && !r.UseDualStack && !overridden
+}
+
+// WithEndpoints returns a copy of r whose endpoint fields are resolved as
+// by ResolveEndpoint: the empty and default ones with
+// DefaultEndpointResolver. Fields the resolver cannot resolve, and all
+// fields of a Region without a Name, are left as they are.
+//
+// The service constructors call WithEndpoints, so a Region holding no
+// more than a Name may be passed to any of them, and the endpoints of the
+// Regions passed to them follow DefaultEndpointResolver.
+func (r Region) WithEndpoints() Region {
+	if r.Name == "" {
+		return r
+	}
+	for service := range endpointFields {
+		endpoint, err := r.ResolveEndpoint(service)
+		if err != nil {
+			continue
+		}
+		r.setEndpoint(service, endpoint)
+	}
+	return r
+}
+
+// ResolveRegion returns a Region named name whose endpoints are all
+// resolved with resolver, or with DefaultEndpointResolver if resolver is
+// nil. This is how to obtain, for example, the FIPS endpoints of a region:
+//
+//	region, err := aws.ResolveRegion(&aws.Resolver{UseFIPS: true}, "us-east-1")
+func ResolveRegion(resolver EndpointResolver, name string) (region Region, err error) {
+	if resolver == nil {
+		resolver = DefaultEndpointResolver
+	}
+	region.Name = name
+	for service := range endpointFields {
+		var endpoint Endpoint
+		endpoint, err = resolver.ResolveEndpoint(service, name)
+		if err != nil {
+			return Region{}, err
+		}
+		region.setEndpoint(service, endpoint)
+	}
+	// Only us-east-1 accepts buckets without a LocationConstraint, and
+	// upper case bucket names.
+	region.S3LocationConstraint = name != USEast.Name
+	region.S3LowercaseBucket = name != USEast.Name
+	return
+}
+
+// setEndpoint stores endpoint in the field of r for service.
+func (r *Region) setEndpoint(service string, endpoint Endpoint) {
+	*endpointFields[service](r) = endpoint.URL
+	switch service {
+	case "monitoring":
+		r.CloudWatchServicepoint.Signer = endpoint.SignatureVersion
+	case "rds":
+		r.RDSEndpoint.Signer = endpoint.SignatureVersion
+	}
+}
@@ -0,0 +1,164 @@
+package aws_test
+
+import (
+	"net/http"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+func (s *S) TestResolverPartitions(c *C) {
+	r := &aws.Resolver{}
+	tests := []struct {
+		service, region string
+		endpoint        aws.Endpoint
+	}{
+		{"ec2", "eu-west-1", aws.Endpoint{"https://ec2.eu-west-1.amazonaws.com", "ec2", "eu-west-1", aws.V4Signature}},
+		{"s3", "us-east-1", aws.Endpoint{"https://s3.amazonaws.com", "s3", "us-east-1", aws.V4Signature}},
+		{"email", "us-west-2", aws.Endpoint{"https://email.us-west-2.amazonaws.com", "ses", "us-west-2", aws.V4Signature}},
+		{"monitoring", "ap-south-1", aws.Endpoint{"https://monitoring.ap-south-1.amazonaws.com", "monitoring", "ap-south-1", aws.V2Signature}},
+		{"iam", "eu-central-1", aws.Endpoint{"https://iam.amazonaws.com", "iam", "us-east-1", aws.V4Signature}},
+		{"ec2", "cn-northwest-1", aws.Endpoint{"https://ec2.cn-northwest-1.amazonaws.com.cn", "ec2", "cn-northwest-1", aws.V4Signature}},
+		{"iam", "cn-north-1", aws.Endpoint{"https://iam.cn-north-1.amazonaws.com.cn", "iam", "cn-north-1", aws.V4Signature}},
+		{"ec2", "us-gov-east-1", aws.Endpoint{"https://ec2.us-gov-east-1.amazonaws.com", "ec2", "us-gov-east-1", aws.V4Signature}},
+		{"sts", "us-gov-east-1", aws.Endpoint{"https://sts.us-gov-west-1.amazonaws.com", "sts", "us-gov-west-1", aws.V4Signature}},
+	}
+	for _, t := range tests {
+		endpoint, err := r.ResolveEndpoint(t.service, t.region)
+		c.Assert(err, IsNil)
+		c.Check(endpoint, Equals, t.endpoint, Commentf("%s in %s", t.service, t.region))
+	}
+
+	p, err := r.Partition("us-gov-west-1")
+	c.Assert(err, IsNil)
+	c.Assert(p.ID, Equals, "aws-us-gov")
+
+	_, err = r.ResolveEndpoint("ec2", "mars-north-1")
+	c.Assert(err, ErrorMatches, `unknown region "mars-north-1"`)
+}
+
+func (s *S) TestResolverVariants(c *C) {
+	tests := []struct {
+		resolver        *aws.Resolver
+		service, region string
+		url             string
+	}{
+		{&aws.Resolver{UseFIPS: true}, "ec2", "us-west-2", "https://ec2-fips.us-west-2.amazonaws.com"},
+		{&aws.Resolver{UseFIPS: true}, "s3", "us-east-1", "https://s3-fips.us-east-1.amazonaws.com"},
+		{&aws.Resolver{UseDualStack: true}, "ec2", "eu-west-1", "https://ec2.eu-west-1.api.aws"},
+		{&aws.Resolver{UseDualStack: true}, "s3", "eu-west-1", "https://s3.dualstack.eu-west-1.amazonaws.com"},
+		{&aws.Resolver{UseDualStack: true}, "ec2", "cn-north-1", "https://ec2.cn-north-1.api.amazonwebservices.com.cn"},
+		{&aws.Resolver{UseFIPS: true, UseDualStack: true}, "sqs", "us-gov-west-1", "https://sqs-fips.us-gov-west-1.api.aws"},
+	}
+	for _, t := range tests {
+		endpoint, err := t.resolver.ResolveEndpoint(t.service, t.region)
+		c.Assert(err, IsNil)
+		c.Check(endpoint.URL, Equals, t.url)
+	}
+}
+
+func (s *S) TestResolverOverrides(c *C) {
+	r := &aws.Resolver{Overrides: map[string]aws.EndpointResolver{
+		"s3": aws.StaticEndpoint("http://localhost:9000"),
+	}}
+	endpoint, err := r.ResolveEndpoint("s3", "eu-west-1")
+	c.Assert(err, IsNil)
+	c.Assert(endpoint, Equals, aws.Endpoint{"http://localhost:9000", "s3", "eu-west-1", aws.V4Signature})
+
+	endpoint, err = r.ResolveEndpoint("ec2", "eu-west-1")
+	c.Assert(err, IsNil)
+	c.Assert(endpoint.URL, Equals, "https://ec2.eu-west-1.amazonaws.com")
+
+	region, err := aws.ResolveRegion(r, "eu-west-1")
+	c.Assert(err, IsNil)
+	c.Assert(region.S3Endpoint, Equals, "http://localhost:9000")
+	c.Assert(region.EC2Endpoint, Equals, "https://ec2.eu-west-1.amazonaws.com")
+	c.Assert(region.S3LocationConstraint, Equals, true)
+}
+
+func (s *S) TestResolverKnownRegions(c *C) {
+	// Every region in the table belongs to a partition.
+	r := &aws.Resolver{}
+	for name := range aws.Regions {
+		_, err := r.Partition(name)
+		c.Check(err, IsNil)
+	}
+}
+
+func (s *S) TestRegionResolveEndpoint(c *C) {
+	endpoint, err := aws.EUWest.ResolveEndpoint("elasticloadbalancing")
+	c.Assert(err, IsNil)
+	c.Assert(endpoint, Equals, aws.Endpoint{aws.EUWest.ELBEndpoint, "elasticloadbalancing", "eu-west-1", aws.V4Signature})
+
+	endpoint, err = aws.EUWest.ResolveEndpoint("monitoring")
+	c.Assert(err, IsNil)
+	c.Assert(endpoint.SignatureVersion, Equals, uint(aws.V2Signature))
+
+	// Fields missing from the Region are resolved.
+	endpoint, err = aws.Region{Name: "eu-south-1"}.ResolveEndpoint("dynamodb")
+	c.Assert(err, IsNil)
+	c.Assert(endpoint.URL, Equals, "https://dynamodb.eu-south-1.amazonaws.com")
+}
+
+func (s *S) TestRegionWithEndpoints(c *C) {
+	region := aws.Region{Name: "eu-south-1", S3Endpoint: "http://localhost:9000"}.WithEndpoints()
+	c.Assert(region.S3Endpoint, Equals, "http://localhost:9000")
+	c.Assert(region.EC2Endpoint, Equals, "https://ec2.eu-south-1.amazonaws.com")
+	c.Assert(region.SESEndpoint, Equals, "https://email.eu-south-1.amazonaws.com")
+	c.Assert(region.RDSEndpoint, Equals, aws.ServiceInfo{"https://rds.eu-south-1.amazonaws.com", aws.V2Signature})
+
+	// A Region without a name is left alone.
+	c.Assert(aws.Region{EC2Endpoint: "http://localhost"}.WithEndpoints(), Equals, aws.Region{EC2Endpoint: "http://localhost"})
+
+	// Known regions keep their endpoints.
+	c.Assert(aws.USEast.WithEndpoints(), Equals, aws.USEast)
+}
+
+func (s *S) TestRegionWithEndpointsFollowsResolver(c *C) {
+	defer func(r aws.EndpointResolver) { aws.DefaultEndpointResolver = r }(aws.DefaultEndpointResolver)
+
+	aws.DefaultEndpointResolver = &aws.Resolver{UseFIPS: true}
+	region := aws.USWest2.WithEndpoints()
+	c.Assert(region.EC2Endpoint, Equals, "https://ec2-fips.us-west-2.amazonaws.com")
+	c.Assert(region.SQSEndpoint, Equals, "https://sqs-fips.us-west-2.amazonaws.com")
+
+	aws.DefaultEndpointResolver = &aws.Resolver{Overrides: map[string]aws.EndpointResolver{
+		"s3": aws.StaticEndpoint("http://localhost:9000"),
+	}}
+	region = aws.EUWest.WithEndpoints()
+	c.Assert(region.S3Endpoint, Equals, "http://localhost:9000")
+	c.Assert(region.EC2Endpoint, Equals, aws.EUWest.EC2Endpoint)
+
+	// Endpoints other than the defaults of the region are kept.
+	region = aws.Region{Name: "eu-west-1", S3Endpoint: "http://localhost:8000"}.WithEndpoints()
+	c.Assert(region.S3Endpoint, Equals, "http://localhost:8000")
+}
+
+func (s *S) TestV4SignerSigningScope(c *C) {
+	defer func(r aws.EndpointResolver) { aws.DefaultEndpointResolver = r }(aws.DefaultEndpointResolver)
+	aws.DefaultEndpointResolver = &aws.Resolver{Overrides: map[string]aws.EndpointResolver{
+		"ec2": aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: "https://ec2.example.com", SigningName: "compute", SigningRegion: "eu-central-1"}, nil
+		}),
+	}}
+
+	name, region := aws.SigningScope("ec2", aws.EUWest.WithEndpoints())
+	c.Assert(name, Equals, "compute")
+	c.Assert(region, Equals, "eu-central-1")
+
+	req, err := http.NewRequest("GET", "https://ec2.example.com/", nil)
+	c.Assert(err, IsNil)
+	req.Header.Set("X-Amz-Date", "20150830T123600Z")
+	aws.NewV4Signer(aws.Auth{AccessKey: "AKID", SecretKey: "secret"}, "ec2", aws.EUWest).Sign(req)
+	c.Assert(req.Header.Get("Authorization"), Matches, ".*Credential=AKID/20150830/eu-central-1/compute/aws4_request,.*")
+
+	// The global IAM endpoint is signed for us-east-1 from every region.
+	name, region = aws.SigningScope("iam", aws.EUWest)
+	c.Assert(name, Equals, "iam")
+	c.Assert(region, Equals, "us-east-1")
+
+	// Endpoints of test servers are signed for the region they are in.
+	name, region = aws.SigningScope("ec2", aws.Region{Name: "eu-west-1", EC2Endpoint: "http://localhost:8000"})
+	c.Assert(name, Equals, "ec2")
+	c.Assert(region, Equals, "eu-west-1")
+}
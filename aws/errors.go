@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// An APIError is an error response from an AWS service. The errors with
+// which the clients of every service report error responses implement
+// it, so that they can be inspected alike with errors.As:
+//
+//	var apiErr aws.APIError
+//	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
+//		...
+//	}
+type APIError interface {
+	error
+	ErrorCode() string    // the AWS error code, e.g. "NoSuchKey"
+	ErrorMessage() string // the human-oriented error message
+	HTTPStatusCode() int  // the HTTP status of the response, or 0 if unknown
+	RequestID() string    // the ID of the request, if AWS sent one
+}
+
+type errorClass string
+
+func (c errorClass) Error() string { return string(c) }
+
+// Classes of errors, which errors from service clients match with
+// errors.Is. For example, errors.Is(err, aws.ErrNotFound) is the same as
+// IsNotFound(err). Errors of a client that are not APIErrors, such as
+// dynamodb.ErrNotFound, match them with Is methods of their own.
+var (
+	ErrNotFound     error = errorClass("aws: resource not found")
+	ErrThrottled    error = errorClass("aws: request throttled")
+	ErrAccessDenied error = errorClass("aws: access denied")
+	ErrRetryable    error = errorClass("aws: request may be retried")
+)
+
+// ErrorIs reports whether err is of the class target, one of ErrNotFound,
+// ErrThrottled, ErrAccessDenied and ErrRetryable. The error types of the
+// service clients implement their Is methods with it.
+func ErrorIs(err APIError, target error) bool {
+	switch target {
+	case ErrNotFound:
+		return isNotFound(err)
+	case ErrThrottled:
+		return isThrottle(err)
+	case ErrAccessDenied:
+		return isAccessDenied(err)
+	case ErrRetryable:
+		return isRetryable(err)
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an AWS error saying that the resource
+// acted on does not exist.
+func IsNotFound(err error) bool {
+	var apiErr APIError
+	return errors.Is(err, ErrNotFound) || errors.As(err, &apiErr) && isNotFound(apiErr)
+}
+
+// IsThrottle reports whether err is an AWS error saying that the request
+// was throttled.
+func IsThrottle(err error) bool {
+	var apiErr APIError
+	return errors.Is(err, ErrThrottled) || errors.As(err, &apiErr) && isThrottle(apiErr)
+}
+
+// IsAccessDenied reports whether err is an AWS error saying that the
+// caller is not allowed to make the request.
+func IsAccessDenied(err error) bool {
+	var apiErr APIError
+	return errors.Is(err, ErrAccessDenied) || errors.As(err, &apiErr) && isAccessDenied(apiErr)
+}
+
+// IsRetryable reports whether the request that failed with err may
+// succeed if made again: err is a throttling or transient AWS error, or a
+// temporary network error or timeout.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrRetryable) {
+		return true
+	}
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return isRetryable(apiErr)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && (netErr.Temporary() || netErr.Timeout())
+}
+
+func isNotFound(err APIError) bool {
+	code := err.ErrorCode()
+	if code == "" {
+		return err.HTTPStatusCode() == 404
+	}
+	return strings.HasPrefix(code, "NoSuch") ||
+		strings.Contains(code, "NotFound") ||
+		strings.HasSuffix(code, "NonExistentQueue")
+}
+
+func isThrottle(err APIError) bool {
+	return IsThrottleCode(err.ErrorCode()) || err.HTTPStatusCode() == 429
+}
+
+// accessDeniedCodes are the error codes with which AWS services refuse
+// requests the caller is not allowed to make.
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"AuthorizationError":    true,
+	"UnauthorizedOperation": true,
+	"UnauthorizedAccess":    true,
+}
+
+func isAccessDenied(err APIError) bool {
+	code := err.ErrorCode()
+	if code == "" {
+		return err.HTTPStatusCode() == 403
+	}
+	return accessDeniedCodes[code]
+}
+
+func isRetryable(err APIError) bool {
+	return retryableStatus(err.HTTPStatusCode()) || IsRetryableCode(err.ErrorCode())
+}
+
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as ErrNotFound.
+func (err *Error) Is(target error) bool { return ErrorIs(err, target) }
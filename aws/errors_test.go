@@ -0,0 +1,87 @@
+package aws_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"net"
+	"testing"
+)
+
+func TestErrorClasses(t *testing.T) {
+	for _, test := range []struct {
+		err                                   error
+		notFound, throttle, denied, retryable bool
+	}{
+		{err: &aws.Error{StatusCode: 400, Code: "InvalidInstanceID.NotFound"}, notFound: true},
+		{err: &aws.Error{StatusCode: 404, Code: "NoSuchKey"}, notFound: true},
+		{err: &aws.Error{StatusCode: 400, Code: "AWS.SimpleQueueService.NonExistentQueue"}, notFound: true},
+		{err: &aws.Error{StatusCode: 404}, notFound: true},
+		{err: &aws.Error{StatusCode: 400, Code: "Throttling"}, throttle: true, retryable: true},
+		{err: &aws.Error{StatusCode: 429}, throttle: true, retryable: true},
+		{err: &aws.Error{StatusCode: 403, Code: "AccessDenied"}, denied: true},
+		{err: &aws.Error{StatusCode: 403, Code: "UnauthorizedOperation"}, denied: true},
+		{err: &aws.SignatureError{Code: "AccessDenied"}, denied: true},
+		{err: &aws.SignatureError{Code: "SignatureDoesNotMatch"}},
+		{err: &aws.Error{StatusCode: 503, Code: "ServiceUnavailable"}, retryable: true},
+		{err: &aws.Error{StatusCode: 500}, retryable: true},
+		{err: &aws.Error{StatusCode: 400, Code: "ValidationError"}},
+		{err: &net.OpError{Op: "dial", Err: timeoutError{}}, retryable: true},
+		{err: errors.New("other")},
+	} {
+		for _, err := range []error{test.err, fmt.Errorf("wrapped: %w", test.err)} {
+			// Only AWS errors are of the classes matched by errors.Is.
+			api := isAPIError(err)
+			if aws.IsNotFound(err) != test.notFound || errors.Is(err, aws.ErrNotFound) != (api && test.notFound) {
+				t.Errorf("%v: IsNotFound is %v", err, aws.IsNotFound(err))
+			}
+			if aws.IsThrottle(err) != test.throttle || errors.Is(err, aws.ErrThrottled) != (api && test.throttle) {
+				t.Errorf("%v: IsThrottle is %v", err, aws.IsThrottle(err))
+			}
+			if aws.IsAccessDenied(err) != test.denied || errors.Is(err, aws.ErrAccessDenied) != (api && test.denied) {
+				t.Errorf("%v: IsAccessDenied is %v", err, aws.IsAccessDenied(err))
+			}
+			if aws.IsRetryable(err) != test.retryable || errors.Is(err, aws.ErrRetryable) != (api && test.retryable) {
+				t.Errorf("%v: IsRetryable is %v", err, aws.IsRetryable(err))
+			}
+		}
+	}
+}
+
+func TestErrorClassesOfOtherErrors(t *testing.T) {
+	err := fmt.Errorf("getting: %w", notFoundError{})
+	if !aws.IsNotFound(err) || !errors.Is(err, aws.ErrNotFound) {
+		t.Fatalf("%v is not found", err)
+	}
+	if aws.IsThrottle(err) || aws.IsAccessDenied(err) || aws.IsRetryable(err) {
+		t.Fatalf("%v is of another class", err)
+	}
+}
+
+// notFoundError is not an APIError, but is of the class ErrNotFound.
+type notFoundError struct{}
+
+func (notFoundError) Error() string        { return "not found" }
+func (notFoundError) Is(target error) bool { return target == aws.ErrNotFound }
+
+func isAPIError(err error) bool {
+	var apiErr aws.APIError
+	return errors.As(err, &apiErr)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timed out" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestAPIError(t *testing.T) {
+	err := fmt.Errorf("describing: %w", &aws.Error{StatusCode: 400, Code: "Bad", Message: "bad request", RequestId: "req-1"})
+	var apiErr aws.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("%v is not an APIError", err)
+	}
+	if apiErr.ErrorCode() != "Bad" || apiErr.ErrorMessage() != "bad request" || apiErr.HTTPStatusCode() != 400 || apiErr.RequestID() != "req-1" {
+		t.Fatalf("Got APIError %#v", apiErr)
+	}
+}
@@ -0,0 +1,284 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMetadataEndpoint is the address of the EC2 instance metadata
+// service.
+const DefaultMetadataEndpoint = "http://169.254.169.254"
+
+// DefaultMetadataTokenTTL is the lifetime requested for IMDSv2 session
+// tokens when MetadataClient.TokenTTL is zero.
+const DefaultMetadataTokenTTL = 6 * time.Hour
+
+const (
+	metadataTokenHeader    = "X-aws-ec2-metadata-token"
+	metadataTokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+)
+
+// MetadataClient retrieves data from the EC2 instance metadata service.
+//
+// Requests are authenticated with an IMDSv2 session token, obtained with
+// a PUT to /latest/api/token and reused until shortly before it expires.
+// If AllowV1Fallback is set and no token can be obtained, plain IMDSv1
+// requests are made instead.
+//
+// See http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-service.html
+// for more details.
+type MetadataClient struct {
+	// Endpoint is the base URL of the metadata service. If empty,
+	// AWS_EC2_METADATA_SERVICE_ENDPOINT or else DefaultMetadataEndpoint
+	// is used.
+	Endpoint string
+
+	// Client is used to make requests; RetryingClient is used if nil.
+	Client *http.Client
+
+	// TokenTTL is the lifetime requested for session tokens.
+	TokenTTL time.Duration
+
+	// AllowV1Fallback permits unauthenticated IMDSv1 requests when a
+	// session token cannot be obtained. The failure is remembered for
+	// TokenTTL so that the token request is not repeated on every call.
+	AllowV1Fallback bool
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// DefaultMetadataClient is used by GetMetaData and the other package level
+// metadata functions. It falls back to IMDSv1 unless the
+// AWS_EC2_METADATA_V1_DISABLED environment variable is set to "true".
+var DefaultMetadataClient = &MetadataClient{
+	AllowV1Fallback: !strings.EqualFold(os.Getenv("AWS_EC2_METADATA_V1_DISABLED"), "true"),
+}
+
+func (m *MetadataClient) endpoint() string {
+	endpoint := m.Endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	}
+	if endpoint == "" {
+		endpoint = DefaultMetadataEndpoint
+	}
+	return strings.TrimRight(endpoint, "/")
+}
+
+func (m *MetadataClient) client() *http.Client {
+	if m.Client != nil {
+		return m.Client
+	}
+	return RetryingClient
+}
+
+func (m *MetadataClient) tokenTTL() time.Duration {
+	if m.TokenTTL > 0 {
+		return m.TokenTTL
+	}
+	return DefaultMetadataTokenTTL
+}
+
+// sessionToken returns the cached session token, requesting a new one if
+// it is missing or about to expire. An empty token with a nil error means
+// IMDSv1 should be used.
+func (m *MetadataClient) sessionToken() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Now().Add(time.Minute).Before(m.tokenExpiry) {
+		return m.token, nil
+	}
+	ttl := m.tokenTTL()
+	token, err := m.requestToken(ttl)
+	if err != nil {
+		if !m.AllowV1Fallback {
+			return "", err
+		}
+		token = ""
+	}
+	m.token, m.tokenExpiry = token, time.Now().Add(ttl)
+	return token, nil
+}
+
+func (m *MetadataClient) requestToken(ttl time.Duration) (string, error) {
+	url := m.endpoint() + "/latest/api/token"
+	req, err := http.NewRequest("PUT", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(metadataTokenTTLHeader, strconv.Itoa(int(ttl/time.Second)))
+	resp, err := m.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Code %d returned for url %s", resp.StatusCode, url)
+	}
+	token, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// expireToken discards token, if it is still the cached one.
+func (m *MetadataClient) expireToken(token string) {
+	m.mu.Lock()
+	if m.token == token {
+		m.token, m.tokenExpiry = "", time.Time{}
+	}
+	m.mu.Unlock()
+}
+
+// get retrieves path relative to /latest/. A request rejected because the
+// session token is no longer valid is retried once with a new token.
+func (m *MetadataClient) get(path string) (contents []byte, err error) {
+	for attempt := 0; ; attempt++ {
+		var token string
+		token, err = m.sessionToken()
+		if err != nil {
+			return
+		}
+		url := m.endpoint() + "/latest/" + path
+		var req *http.Request
+		req, err = http.NewRequest("GET", url, nil)
+		if err != nil {
+			return
+		}
+		if token != "" {
+			req.Header.Set(metadataTokenHeader, token)
+		}
+		var resp *http.Response
+		resp, err = m.client().Do(req)
+		if err != nil {
+			return
+		}
+		contents, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return
+		}
+		if resp.StatusCode == 401 && attempt == 0 {
+			m.expireToken(token)
+			continue
+		}
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("Code %d returned for url %s", resp.StatusCode, url)
+		}
+		return
+	}
+}
+
+// GetMetaData retrieves instance metadata about the current machine.
+//
+// See http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/AESDG-chapter-instancedata.html for more details.
+func (m *MetadataClient) GetMetaData(path string) ([]byte, error) {
+	return m.get("meta-data/" + path)
+}
+
+// InstanceIdentityDocument describes the current instance.
+type InstanceIdentityDocument struct {
+	AccountId        string    `json:"accountId"`
+	Architecture     string    `json:"architecture"`
+	AvailabilityZone string    `json:"availabilityZone"`
+	ImageId          string    `json:"imageId"`
+	InstanceId       string    `json:"instanceId"`
+	InstanceType     string    `json:"instanceType"`
+	KernelId         string    `json:"kernelId"`
+	PendingTime      time.Time `json:"pendingTime"`
+	PrivateIP        string    `json:"privateIp"`
+	RamdiskId        string    `json:"ramdiskId"`
+	Region           string    `json:"region"`
+	Version          string    `json:"version"`
+}
+
+// GetInstanceIdentityDocument retrieves the identity document of the
+// current instance.
+func (m *MetadataClient) GetInstanceIdentityDocument() (doc InstanceIdentityDocument, err error) {
+	data, err := m.get("dynamic/instance-identity/document")
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &doc)
+	return
+}
+
+// GetInstanceRegion returns the region the current instance is running in.
+func (m *MetadataClient) GetInstanceRegion() (region Region, err error) {
+	doc, err := m.GetInstanceIdentityDocument()
+	if err != nil {
+		return
+	}
+	return RegionByName(doc.Region)
+}
+
+// GetInstanceId returns the ID of the current instance.
+func (m *MetadataClient) GetInstanceId() (string, error) {
+	id, err := m.GetMetaData("instance-id")
+	return string(id), err
+}
+
+type instanceCredentials struct {
+	Code            string
+	LastUpdated     string
+	Type            string
+	AccessKeyId     string
+	SecretAccessKey string
+	Token           string
+	Expiration      string
+}
+
+func (m *MetadataClient) getInstanceCredentials() (cred instanceCredentials, err error) {
+	credentialPath := "iam/security-credentials/"
+
+	// Get the instance role
+	role, err := m.GetMetaData(credentialPath)
+	if err != nil {
+		return
+	}
+
+	// Get the instance role credentials
+	credentialJSON, err := m.GetMetaData(credentialPath + strings.TrimSpace(string(role)))
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(credentialJSON, &cred)
+	return
+}
+
+// GetMetaData retrieves instance metadata about the current machine using
+// DefaultMetadataClient.
+//
+// See http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/AESDG-chapter-instancedata.html for more details.
+func GetMetaData(path string) (contents []byte, err error) {
+	return DefaultMetadataClient.GetMetaData(path)
+}
+
+// GetInstanceIdentityDocument retrieves the identity document of the
+// current instance using DefaultMetadataClient.
+func GetInstanceIdentityDocument() (InstanceIdentityDocument, error) {
+	return DefaultMetadataClient.GetInstanceIdentityDocument()
+}
+
+// GetInstanceRegion returns the region the current instance is running in
+// using DefaultMetadataClient.
+func GetInstanceRegion() (Region, error) {
+	return DefaultMetadataClient.GetInstanceRegion()
+}
+
+// GetInstanceId returns the ID of the current instance using
+// DefaultMetadataClient.
+func GetInstanceId() (string, error) {
+	return DefaultMetadataClient.GetInstanceId()
+}
@@ -0,0 +1,171 @@
+package aws_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+// fakeIMDS is a minimal instance metadata service. If v1 is false,
+// requests must carry the current session token.
+type fakeIMDS struct {
+	mu          sync.Mutex
+	v1          bool
+	noTokens    bool
+	tokens      int
+	ttls        []string
+	unauthCount int
+	paths       map[string]string
+}
+
+func (f *fakeIMDS) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if req.URL.Path == "/latest/api/token" {
+		if req.Method != "PUT" || f.noTokens {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		f.tokens++
+		f.ttls = append(f.ttls, req.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+		fmt.Fprintf(w, "token%d", f.tokens)
+		return
+	}
+	if !f.v1 && req.Header.Get("X-aws-ec2-metadata-token") != fmt.Sprintf("token%d", f.tokens) {
+		f.unauthCount++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, ok := f.paths[req.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fmt.Fprint(w, body)
+}
+
+var identityDocument = `{
+  "accountId" : "123456789012",
+  "architecture" : "x86_64",
+  "availabilityZone" : "eu-west-1b",
+  "imageId" : "ami-5fb8c835",
+  "instanceId" : "i-1234567890abcdef0",
+  "instanceType" : "t2.micro",
+  "kernelId" : null,
+  "pendingTime" : "2016-11-19T16:32:11Z",
+  "privateIp" : "172.31.21.181",
+  "ramdiskId" : null,
+  "region" : "eu-west-1",
+  "version" : "2010-08-31"
+}`
+
+func newFakeIMDS() (*fakeIMDS, *httptest.Server, *aws.MetadataClient) {
+	f := &fakeIMDS{paths: map[string]string{
+		"/latest/meta-data/instance-id":                   "i-1234567890abcdef0",
+		"/latest/dynamic/instance-identity/document":      identityDocument,
+		"/latest/meta-data/iam/security-credentials/":     "role\n",
+		"/latest/meta-data/iam/security-credentials/role": `{"Code": "Success", "AccessKeyId": "imdsaccess", "SecretAccessKey": "imdssecret", "Token": "imdstoken", "Expiration": "2030-01-01T00:00:00Z"}`,
+		"/latest/meta-data/placement/availability-zone":   "eu-west-1b",
+	}}
+	server := httptest.NewServer(f)
+	return f, server, &aws.MetadataClient{Endpoint: server.URL}
+}
+
+func (s *S) TestMetadataClientSessionToken(c *C) {
+	f, server, client := newFakeIMDS()
+	defer server.Close()
+	client.TokenTTL = 10 * time.Minute
+
+	data, err := client.GetMetaData("instance-id")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "i-1234567890abcdef0")
+	data, err = client.GetMetaData("placement/availability-zone")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "eu-west-1b")
+
+	// The token is requested once and reused.
+	c.Assert(f.tokens, Equals, 1)
+	c.Assert(f.ttls, DeepEquals, []string{"600"})
+
+	// A token the service no longer accepts is replaced.
+	f.tokens++
+	_, err = client.GetMetaData("instance-id")
+	c.Assert(err, IsNil)
+	c.Assert(f.tokens, Equals, 3)
+	c.Assert(f.unauthCount, Equals, 1)
+
+	_, err = client.GetMetaData("nonexistent")
+	c.Assert(err, ErrorMatches, "Code 404 returned for url .*/latest/meta-data/nonexistent")
+}
+
+func (s *S) TestMetadataClientV1Fallback(c *C) {
+	f, server, client := newFakeIMDS()
+	defer server.Close()
+	f.v1, f.noTokens = true, true
+
+	_, err := client.GetMetaData("instance-id")
+	c.Assert(err, ErrorMatches, "Code 403 returned for url .*/latest/api/token")
+
+	client.AllowV1Fallback = true
+	data, err := client.GetMetaData("instance-id")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "i-1234567890abcdef0")
+}
+
+func (s *S) TestMetadataClientEndpointFromEnv(c *C) {
+	_, server, _ := newFakeIMDS()
+	defer server.Close()
+	os.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL+"/")
+
+	id, err := (&aws.MetadataClient{}).GetInstanceId()
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, "i-1234567890abcdef0")
+}
+
+func (s *S) TestInstanceIdentityDocument(c *C) {
+	_, server, client := newFakeIMDS()
+	defer server.Close()
+
+	doc, err := client.GetInstanceIdentityDocument()
+	c.Assert(err, IsNil)
+	c.Assert(doc, Equals, aws.InstanceIdentityDocument{
+		AccountId:        "123456789012",
+		Architecture:     "x86_64",
+		AvailabilityZone: "eu-west-1b",
+		ImageId:          "ami-5fb8c835",
+		InstanceId:       "i-1234567890abcdef0",
+		InstanceType:     "t2.micro",
+		PendingTime:      time.Date(2016, 11, 19, 16, 32, 11, 0, time.UTC),
+		PrivateIP:        "172.31.21.181",
+		Region:           "eu-west-1",
+		Version:          "2010-08-31",
+	})
+
+	region, err := client.GetInstanceRegion()
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, aws.EUWest.Name)
+
+	id, err := client.GetInstanceId()
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, "i-1234567890abcdef0")
+}
+
+func (s *S) TestInstanceMetadataProvider(c *C) {
+	_, server, client := newFakeIMDS()
+	defer server.Close()
+
+	creds, err := aws.InstanceMetadataProvider{Client: client}.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{
+		AccessKey:  "imdsaccess",
+		SecretKey:  "imdssecret",
+		Token:      "imdstoken",
+		Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
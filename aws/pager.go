@@ -0,0 +1,75 @@
+package aws
+
+import "context"
+
+// A Pager walks the pages of results of a list or describe operation,
+// fetching each page only when it is asked for:
+//
+//	p := bucket.ListV2Pages("photos/", "", "", 0, false)
+//	for p.Next(ctx) {
+//		for _, key := range p.Page().Contents {
+//			...
+//		}
+//	}
+//	if err := p.Err(); err != nil {
+//		...
+//	}
+//
+// The service clients have a Pages method returning a Pager for each of
+// their paginated operations, and an All method collecting the items of
+// every page.
+type Pager[P any] struct {
+	fetch func(ctx context.Context) (page P, more bool, err error)
+	page  P
+	err   error
+	done  bool
+}
+
+// NewPager returns a Pager whose pages are fetched by fetch, which
+// returns the next page and whether there are more pages after it. fetch
+// keeps track of the marker or token of the next page itself.
+func NewPager[P any](fetch func(ctx context.Context) (page P, more bool, err error)) *Pager[P] {
+	return &Pager[P]{fetch: fetch}
+}
+
+// Next fetches the next page with ctx, and reports whether there was one.
+// It returns false after the last page, or once fetching a page fails or
+// ctx is done, in which case Err returns the error.
+func (p *Pager[P]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+	var zero P
+	if p.err = ctx.Err(); p.err != nil {
+		p.page, p.done = zero, true
+		return false
+	}
+	page, more, err := p.fetch(ctx)
+	if err != nil {
+		p.page, p.err, p.done = zero, err, true
+		return false
+	}
+	p.page, p.done = page, !more
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *Pager[P]) Page() P {
+	return p.page
+}
+
+// Err returns the error that stopped p, if any.
+func (p *Pager[P]) Err() error {
+	return p.err
+}
+
+// All walks the remaining pages of p and returns the items picked out of
+// each page by items. If a page cannot be fetched, All returns the items
+// of the pages before it along with the error.
+func All[P, T any](ctx context.Context, p *Pager[P], items func(page P) []T) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, items(p.Page())...)
+	}
+	return all, p.Err()
+}
@@ -0,0 +1,52 @@
+package aws_test
+
+import (
+	"context"
+	"errors"
+	"github.com/hughe/goamz/aws"
+	"reflect"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	pages := [][]int{{1, 2}, {3}, {4, 5}}
+	var fetched int
+	newPager := func(fail error) *aws.Pager[[]int] {
+		fetched = 0
+		return aws.NewPager(func(ctx context.Context) ([]int, bool, error) {
+			if fail != nil && fetched == 1 {
+				return nil, false, fail
+			}
+			fetched++
+			return pages[fetched-1], fetched < len(pages), nil
+		})
+	}
+	items := func(page []int) []int { return page }
+
+	// Pages are fetched lazily.
+	p := newPager(nil)
+	if fetched != 0 || !p.Next(context.Background()) || fetched != 1 || !reflect.DeepEqual(p.Page(), pages[0]) {
+		t.Fatalf("Got page %v after %d fetches", p.Page(), fetched)
+	}
+
+	all, err := aws.All(context.Background(), p, items)
+	if err != nil || !reflect.DeepEqual(all, []int{3, 4, 5}) || fetched != 3 {
+		t.Fatalf("Got %v, %v after %d fetches", all, err, fetched)
+	}
+	if p.Next(context.Background()) || fetched != 3 {
+		t.Fatalf("Next fetched past the last page")
+	}
+
+	fail := errors.New("fail")
+	all, err = aws.All(context.Background(), newPager(fail), items)
+	if err != fail || !reflect.DeepEqual(all, []int{1, 2}) {
+		t.Fatalf("Got %v, %v", all, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p = newPager(nil)
+	if p.Next(ctx) || p.Err() != context.Canceled || fetched != 0 {
+		t.Fatalf("Got %v after %d fetches", p.Err(), fetched)
+	}
+}
@@ -0,0 +1,254 @@
+package aws
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// A Phase is a step of the request pipeline.
+type Phase int
+
+// The phases of the request pipeline. Every request is built, then signed
+// and sent (which together make up the Retry phase, run again by
+// middleware that retries requests), then unmarshalled. The Call phase
+// wraps all the others, so that its middleware sees each operation whole.
+const (
+	BuildPhase Phase = iota
+	SignPhase
+	SendPhase
+	RetryPhase
+	UnmarshalPhase
+	CallPhase
+	numPhases
+)
+
+var phaseNames = [numPhases]string{"Build", "Sign", "Send", "Retry", "Unmarshal", "Call"}
+
+func (p Phase) String() string {
+	if p < 0 || p >= numPhases {
+		return "Phase(?)"
+	}
+	return phaseNames[p]
+}
+
+// A Request is a service operation on its way through a Pipeline.
+type Request struct {
+	Context context.Context
+
+	// Service is the endpoint prefix of the service, e.g. "ec2" or
+	// "elasticloadbalancing" (see EndpointResolver), and Operation the
+	// name of the operation, e.g. "DescribeInstances".
+	Service   string
+	Operation string
+
+	// Region is the name of the region the request is sent to, e.g.
+	// "us-east-1", or "" for global services.
+	Region string
+
+	// Client sends the request; RetryingClient is used if nil.
+	Client *http.Client
+
+	// RateLimiter, if not nil, limits the request on top of any
+	// RateLimiter installed in the Pipeline. Service clients set it
+	// from their own RateLimiter field.
+	RateLimiter *RateLimiter
+
+	HTTPRequest  *http.Request  // set by the Build phase
+	HTTPResponse *http.Response // set by the Send phase
+
+	// Data, if not nil, is what the Unmarshal phase decodes the response
+	// into.
+	Data interface{}
+
+	// Attempts is the number of times the request has been signed and
+	// sent.
+	Attempts int
+}
+
+// A Handler carries out a phase of a request.
+type Handler func(r *Request) error
+
+// Middleware wraps the Handler of a phase. It may act before or after
+// calling next, or not call it at all.
+type Middleware func(next Handler) Handler
+
+// Handlers are the handlers of the phases of an operation, supplied by
+// the service client.
+type Handlers struct {
+	// Build sets r.HTTPRequest.
+	Build Handler
+
+	// Sign signs r.HTTPRequest. It may be nil if the request is not
+	// signed.
+	Sign Handler
+
+	// Send sets r.HTTPResponse; if nil, the request is sent with
+	// r.Client.
+	Send Handler
+
+	// Unmarshal checks r.HTTPResponse and decodes it into r.Data. It may
+	// be nil if the caller reads the response itself.
+	Unmarshal Handler
+}
+
+// A Pipeline runs requests through the phases of the Handlers of their
+// operations, wrapping each phase in the middleware added to it.
+type Pipeline struct {
+	mu         sync.RWMutex
+	middleware [numPhases][]namedMiddleware
+}
+
+type namedMiddleware struct {
+	name string
+	m    Middleware
+}
+
+// DefaultPipeline is the Pipeline every service client runs its requests
+// through. Middleware added to it applies to all services.
+var DefaultPipeline = &Pipeline{}
+
+// Use adds m to phase under name. Middleware added first is outermost:
+// it is called first, and sees the results of the phase last. Middleware
+// replaces any middleware of the same name in the phase, keeping its
+// place.
+func (p *Pipeline) Use(phase Phase, name string, m Middleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list := p.middleware[phase]
+	for i := range list {
+		if list[i].name == name {
+			list[i].m = m
+			return
+		}
+	}
+	p.middleware[phase] = append(list, namedMiddleware{name, m})
+}
+
+// Remove removes the middleware added to phase under name.
+func (p *Pipeline) Remove(phase Phase, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	list := p.middleware[phase]
+	for i := range list {
+		if list[i].name == name {
+			p.middleware[phase] = append(list[:i:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+// chain wraps h in the middleware of phase.
+func (p *Pipeline) chain(phase Phase, h Handler) Handler {
+	p.mu.RLock()
+	list := p.middleware[phase]
+	p.mu.RUnlock()
+	for i := len(list) - 1; i >= 0; i-- {
+		h = list[i].m(h)
+	}
+	return h
+}
+
+// Run runs r through the phases of h. If the response is an error saying
+// the request was signed with a skewed clock, DefaultClock learns the
+// time of AWS from it and the Retry phase is run once more. The caller is
+// responsible for closing the body of r.HTTPResponse, which is set even if
+// Run returns an error from the Unmarshal phase.
+func (p *Pipeline) Run(r *Request, h Handlers) error {
+	if r.Context == nil {
+		r.Context = context.Background()
+	}
+	return p.chain(CallPhase, func(r *Request) error {
+		return p.run(r, h)
+	})(r)
+}
+
+func (p *Pipeline) run(r *Request, h Handlers) error {
+	sign, send := h.Sign, h.Send
+	if sign == nil {
+		sign = func(*Request) error { return nil }
+	}
+	if send == nil {
+		send = sendRequest
+	}
+	if l := r.RateLimiter; l != nil {
+		// The middleware of the client's limiter is innermost.
+		sign, send = l.waitMiddleware(sign), l.watchMiddleware(send)
+	}
+	sign, send = p.chain(SignPhase, sign), p.chain(SendPhase, send)
+	attempt := func(r *Request) error {
+		if r.Attempts > 0 {
+			if r.HTTPResponse != nil {
+				discardBody(r.HTTPResponse)
+				r.HTTPResponse = nil
+			}
+			next, err := rewindBody(r.HTTPRequest)
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest = next
+		}
+		if err := sign(r); err != nil {
+			return err
+		}
+		r.Attempts++
+		return send(r)
+	}
+
+	if err := p.chain(BuildPhase, h.Build)(r); err != nil {
+		return err
+	}
+	retry := p.chain(RetryPhase, attempt)
+	err := retry(r)
+	if err == nil && DefaultClock.Correct(r.HTTPResponse) && rewindable(r.HTTPRequest) {
+		// The request was rejected as signed with a skewed clock: sign
+		// and send it again, once, with the corrected time.
+		err = retry(r)
+	}
+	if err != nil {
+		return err
+	}
+	if h.Unmarshal == nil {
+		h.Unmarshal = func(*Request) error { return nil }
+	}
+	return p.chain(UnmarshalPhase, h.Unmarshal)(r)
+}
+
+// sendRequest is the default Send handler.
+func sendRequest(r *Request) (err error) {
+	client := r.Client
+	if client == nil {
+		client = RetryingClient
+	}
+	r.HTTPResponse, err = client.Do(r.HTTPRequest.WithContext(r.Context))
+	return err
+}
+
+// RetryMiddleware returns Middleware for the Retry phase that signs and
+// sends requests again as long as policy deems them retryable. The HTTP
+// clients of the services already retry failed requests; RetryMiddleware
+// is for clients that do not, and for retrying on the outcome of the
+// whole operation rather than of a single HTTP request.
+func RetryMiddleware(policy *RetryPolicy) Middleware {
+	return func(next Handler) Handler {
+		return func(r *Request) error {
+			err := next(r)
+			retries := 0
+			for try := 0; try+1 < policy.MaxAttempts && policy.Retryable(r.HTTPResponse, err); try++ {
+				cost, ok := policy.acquire(err)
+				if !ok {
+					break
+				}
+				retries += cost
+				if waitErr := sleepContext(r.Context, policy.Delay(try, r.HTTPResponse)); waitErr != nil {
+					return waitErr
+				}
+				err = next(r)
+			}
+			if err == nil && r.HTTPResponse.StatusCode < 300 {
+				policy.succeeded(retries)
+			}
+			return err
+		}
+	}
+}
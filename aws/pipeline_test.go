@@ -0,0 +1,198 @@
+package aws_test
+
+import (
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tracer returns Middleware that appends name to calls before and after
+// calling the next handler.
+func tracer(calls *[]string, name string) aws.Middleware {
+	return func(next aws.Handler) aws.Handler {
+		return func(r *aws.Request) error {
+			*calls = append(*calls, name)
+			err := next(r)
+			*calls = append(*calls, "/"+name)
+			return err
+		}
+	}
+}
+
+func getHandlers(url string, calls *[]string) aws.Handlers {
+	return aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			*calls = append(*calls, "build")
+			r.HTTPRequest, err = http.NewRequest("GET", url, nil)
+			return err
+		},
+		Sign: func(r *aws.Request) error {
+			*calls = append(*calls, "sign")
+			r.HTTPRequest.Header.Set("Authorization", fmt.Sprintf("attempt %d", r.Attempts+1))
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			*calls = append(*calls, "unmarshal")
+			b, err := ioutil.ReadAll(r.HTTPResponse.Body)
+			*r.Data.(*string) = string(b)
+			return err
+		},
+	}
+}
+
+func TestPipelineOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	var calls []string
+	p := &aws.Pipeline{}
+	p.Use(aws.UnmarshalPhase, "u", tracer(&calls, "u"))
+	p.Use(aws.SendPhase, "send", tracer(&calls, "send"))
+	p.Use(aws.SignPhase, "s1", tracer(&calls, "s1"))
+	p.Use(aws.SignPhase, "s2", tracer(&calls, "s2"))
+	p.Use(aws.RetryPhase, "retry", tracer(&calls, "retry"))
+	p.Use(aws.BuildPhase, "b", tracer(&calls, "b"))
+	p.Use(aws.BuildPhase, "gone", tracer(&calls, "gone"))
+	p.Remove(aws.BuildPhase, "gone")
+
+	var body string
+	r := &aws.Request{Data: &body}
+	if err := p.Run(r, getHandlers(ts.URL, &calls)); err != nil {
+		t.Fatal(err)
+	}
+	r.HTTPResponse.Body.Close()
+	want := []string{
+		"b", "build", "/b",
+		"retry", "s1", "s2", "sign", "/s2", "/s1", "send", "/send", "/retry",
+		"u", "unmarshal", "/u",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("Calls were %v, want %v", calls, want)
+	}
+	if body != "ok" || r.Attempts != 1 {
+		t.Fatalf("Got %q after %d attempts", body, r.Attempts)
+	}
+
+	// Using a name again replaces the middleware in its place.
+	calls = nil
+	p.Use(aws.SignPhase, "s1", tracer(&calls, "s1b"))
+	if err := p.Run(&aws.Request{Data: &body}, getHandlers(ts.URL, &calls)); err != nil {
+		t.Fatal(err)
+	}
+	if calls[4] != "s1b" || calls[5] != "s2" {
+		t.Fatalf("Replaced middleware out of place: %v", calls)
+	}
+}
+
+func TestPipelineHeaderInjection(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer ts.Close()
+
+	p := &aws.Pipeline{}
+	p.Use(aws.BuildPhase, "trace-id", func(next aws.Handler) aws.Handler {
+		return func(r *aws.Request) error {
+			if err := next(r); err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("X-Trace-Id", r.Service+"."+r.Operation)
+			return nil
+		}
+	})
+	var calls []string
+	var body string
+	r := &aws.Request{Service: "ec2", Operation: "DescribeInstances", Data: &body}
+	if err := p.Run(r, getHandlers(ts.URL, &calls)); err != nil {
+		t.Fatal(err)
+	}
+	r.HTTPResponse.Body.Close()
+	if got.Get("X-Trace-Id") != "ec2.DescribeInstances" || got.Get("Authorization") != "attempt 1" {
+		t.Fatalf("Server got headers %v", got)
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var bodies, auths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		auths = append(auths, r.Header.Get("Authorization"))
+		if len(bodies) < 3 {
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>Throttling</Code></Error></Errors></Response>`)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	p := &aws.Pipeline{}
+	p.Use(aws.RetryPhase, "retry", aws.RetryMiddleware(&aws.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	var calls []string
+	h := getHandlers(ts.URL, &calls)
+	h.Build = func(r *aws.Request) (err error) {
+		r.HTTPRequest, err = http.NewRequest("POST", ts.URL, strings.NewReader("Action=Go"))
+		return err
+	}
+	var body string
+	r := &aws.Request{Client: http.DefaultClient, Data: &body}
+	if err := p.Run(r, h); err != nil {
+		t.Fatal(err)
+	}
+	r.HTTPResponse.Body.Close()
+	if body != "ok" || r.Attempts != 3 {
+		t.Fatalf("Got %q after %d attempts", body, r.Attempts)
+	}
+	// Each attempt is signed again and sends the whole body.
+	if !reflect.DeepEqual(bodies, []string{"Action=Go", "Action=Go", "Action=Go"}) {
+		t.Fatalf("Server got bodies %q", bodies)
+	}
+	if !reflect.DeepEqual(auths, []string{"attempt 1", "attempt 2", "attempt 3"}) {
+		t.Fatalf("Server got signatures %q", auths)
+	}
+}
+
+func TestServiceQueryRunsThroughDefaultPipeline(t *testing.T) {
+	var got *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		r.ParseForm()
+	}))
+	defer ts.Close()
+
+	var seen []string
+	aws.DefaultPipeline.Use(aws.SendPhase, "test", func(next aws.Handler) aws.Handler {
+		return func(r *aws.Request) error {
+			seen = append(seen, r.Operation)
+			r.HTTPRequest.Header.Set("X-Injected", "yes")
+			return next(r)
+		}
+	})
+	defer aws.DefaultPipeline.Remove(aws.SendPhase, "test")
+
+	s, err := aws.NewService(aws.Auth{AccessKey: "access", SecretKey: "secret"}, aws.ServiceInfo{Endpoint: ts.URL, Signer: aws.V2Signature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Query("POST", "/", map[string]string{"Action": "DescribeThings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if !reflect.DeepEqual(seen, []string{"DescribeThings"}) {
+		t.Fatalf("Middleware saw %v", seen)
+	}
+	if got.Header.Get("X-Injected") != "yes" || got.Form.Get("Action") != "DescribeThings" || got.Form.Get("Signature") == "" {
+		t.Fatalf("Server got headers %v, form %v", got.Header, got.Form)
+	}
+}
@@ -0,0 +1,148 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultProcessTimeout is how long a ProcessProvider waits for its
+// command when Timeout is zero.
+const DefaultProcessTimeout = time.Minute
+
+// ProcessProvider retrieves credentials by running an external command,
+// as configured by the credential_process setting of a shared config
+// profile. The command is run by the shell and must print a JSON document
+// of the form
+//
+//	{
+//	  "Version": 1,
+//	  "AccessKeyId": "...",
+//	  "SecretAccessKey": "...",
+//	  "SessionToken": "...",
+//	  "Expiration": "2006-01-02T15:04:05Z"
+//	}
+//
+// on its standard output. SessionToken and Expiration are optional.
+type ProcessProvider struct {
+	Command string
+	Timeout time.Duration
+}
+
+type processCredentials struct {
+	Version         int
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      string
+}
+
+func (p ProcessProvider) Retrieve() (creds Credentials, err error) {
+	if p.Command == "" {
+		err = errors.New("credential_process command is empty")
+		return
+	}
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultProcessTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", p.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", p.Command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if err = cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		err = fmt.Errorf("credential_process %q failed: %v", p.Command, err)
+		return
+	}
+
+	var out processCredentials
+	if err = json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		err = fmt.Errorf("credential_process %q returned invalid JSON: %v", p.Command, err)
+		return
+	}
+	if out.Version != 1 {
+		err = fmt.Errorf("credential_process %q returned unsupported version %d", p.Command, out.Version)
+		return
+	}
+	if out.AccessKeyId == "" || out.SecretAccessKey == "" {
+		err = fmt.Errorf("credential_process %q returned no AccessKeyId or SecretAccessKey", p.Command)
+		return
+	}
+	creds.AccessKey = out.AccessKeyId
+	creds.SecretKey = out.SecretAccessKey
+	creds.Token = out.SessionToken
+	if out.Expiration != "" {
+		creds.Expiration, err = time.Parse(time.RFC3339, strings.TrimSpace(out.Expiration))
+		if err != nil {
+			err = fmt.Errorf("credential_process %q returned invalid Expiration: %v", p.Command, err)
+		}
+	}
+	return
+}
+
+// WebIdentityRoleProvider, if set, returns a provider for the credentials
+// of roleArn assumed with the OIDC token read from tokenFile on each
+// renewal. It is set by the sts package, which performs the
+// AssumeRoleWithWebIdentity call, so web identity credentials are only
+// available to programs that import it. In others, web identity
+// credentials fail with an error saying so, which GetAuth returns rather
+// than falling back on the sources that follow.
+var WebIdentityRoleProvider func(roleArn, sessionName, tokenFile string) CredentialsProvider
+
+// WebIdentityEnvProvider retrieves credentials by assuming the role named by
+// AWS_ROLE_ARN with the web identity token in AWS_WEB_IDENTITY_TOKEN_FILE.
+// AWS_ROLE_SESSION_NAME is used as the session name if present.
+type WebIdentityEnvProvider struct{}
+
+func (p WebIdentityEnvProvider) Retrieve() (Credentials, error) {
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	roleArn := os.Getenv("AWS_ROLE_ARN")
+	if tokenFile == "" || roleArn == "" {
+		return Credentials{}, errors.New("AWS_WEB_IDENTITY_TOKEN_FILE or AWS_ROLE_ARN not found in environment")
+	}
+	return webIdentityProvider(roleArn, os.Getenv("AWS_ROLE_SESSION_NAME"), tokenFile).Retrieve()
+}
+
+func webIdentityProvider(roleArn, sessionName, tokenFile string) CredentialsProvider {
+	if WebIdentityRoleProvider == nil {
+		return errorProvider{chainStopError{errors.New("web identity credentials require importing the github.com/hughe/goamz/sts package")}}
+	}
+	return WebIdentityRoleProvider(roleArn, sessionName, tokenFile)
+}
+
+// errorProvider always fails with err.
+type errorProvider struct {
+	err error
+}
+
+func (p errorProvider) Retrieve() (Credentials, error) {
+	return Credentials{}, p.err
+}
+
+// chainStopError is the error of a provider that is configured but cannot
+// work. A ChainProvider returns it instead of trying the providers that
+// follow, whose credentials are not those intended.
+type chainStopError struct {
+	error
+}
+
+func (e chainStopError) Unwrap() error { return e.error }
@@ -0,0 +1,115 @@
+package aws_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+// writeCredentialProcess writes a shell script that prints output and
+// returns the command to run it.
+func writeCredentialProcess(c *C, output string) string {
+	path := filepath.Join(c.MkDir(), "creds.sh")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + output + "\nEOF\n"
+	c.Assert(ioutil.WriteFile(path, []byte(script), 0755), IsNil)
+	return path
+}
+
+func (s *S) TestProcessProvider(c *C) {
+	cmd := writeCredentialProcess(c, `{
+  "Version": 1,
+  "AccessKeyId": "processaccess",
+  "SecretAccessKey": "processsecret",
+  "SessionToken": "processtoken",
+  "Expiration": "2030-01-01T00:00:00Z"
+}`)
+	creds, err := aws.ProcessProvider{Command: cmd}.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{
+		AccessKey:  "processaccess",
+		SecretKey:  "processsecret",
+		Token:      "processtoken",
+		Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+}
+
+func (s *S) TestProcessProviderErrors(c *C) {
+	_, err := aws.ProcessProvider{Command: writeCredentialProcess(c, `{"Version": 2}`)}.Retrieve()
+	c.Assert(err, ErrorMatches, `credential_process ".*" returned unsupported version 2`)
+
+	_, err = aws.ProcessProvider{Command: writeCredentialProcess(c, `{"Version": 1}`)}.Retrieve()
+	c.Assert(err, ErrorMatches, `credential_process ".*" returned no AccessKeyId or SecretAccessKey`)
+
+	_, err = aws.ProcessProvider{Command: writeCredentialProcess(c, `not json`)}.Retrieve()
+	c.Assert(err, ErrorMatches, `credential_process ".*" returned invalid JSON: .*`)
+
+	_, err = aws.ProcessProvider{Command: "exit 3"}.Retrieve()
+	c.Assert(err, ErrorMatches, `credential_process "exit 3" failed: exit status 3`)
+
+	_, err = aws.ProcessProvider{Command: "sleep 5", Timeout: 10 * time.Millisecond}.Retrieve()
+	c.Assert(err, ErrorMatches, `credential_process "sleep 5" failed: context deadline exceeded`)
+}
+
+func (s *S) TestSharedConfigCredentialProcess(c *C) {
+	d := c.MkDir()
+	cmd := writeCredentialProcess(c, `{"Version": 1, "AccessKeyId": "processaccess", "SecretAccessKey": "processsecret"}`)
+	config := filepath.Join(d, "config")
+	c.Assert(ioutil.WriteFile(config, []byte("[profile process]\ncredential_process = "+cmd+"\n"), 0644), IsNil)
+
+	p := aws.SharedCredentialsProvider{Filename: filepath.Join(d, "credentials"), ConfigFilename: config, Profile: "process"}
+	creds, err := p.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "processaccess")
+	c.Assert(creds.SecretKey, Equals, "processsecret")
+}
+
+type fakeWebIdentity struct {
+	roleArn, sessionName, tokenFile string
+}
+
+func (s *S) TestWebIdentityEnvProvider(c *C) {
+	defer func(f func(string, string, string) aws.CredentialsProvider) { aws.WebIdentityRoleProvider = f }(aws.WebIdentityRoleProvider)
+
+	os.Clearenv()
+	_, err := aws.WebIdentityEnvProvider{}.Retrieve()
+	c.Assert(err, ErrorMatches, "AWS_WEB_IDENTITY_TOKEN_FILE or AWS_ROLE_ARN not found in environment")
+
+	os.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "/var/run/token")
+	os.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/web")
+	os.Setenv("AWS_ROLE_SESSION_NAME", "app")
+
+	aws.WebIdentityRoleProvider = nil
+	_, err = aws.WebIdentityEnvProvider{}.Retrieve()
+	c.Assert(err, ErrorMatches, "web identity credentials require importing the .*/sts package")
+
+	// GetAuth does not fall back on other sources, such as the instance
+	// role.
+	_, server, _ := newFakeIMDS()
+	defer server.Close()
+	os.Setenv("HOME", c.MkDir())
+	os.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	_, err = aws.GetAuth("", "", "", time.Time{})
+	c.Assert(err, ErrorMatches, "web identity credentials require importing the .*/sts package")
+	os.Unsetenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+
+	var got fakeWebIdentity
+	aws.WebIdentityRoleProvider = func(roleArn, sessionName, tokenFile string) aws.CredentialsProvider {
+		got = fakeWebIdentity{roleArn, sessionName, tokenFile}
+		return aws.StaticProvider{aws.Credentials{AccessKey: "webaccess", SecretKey: "websecret", Token: "webtoken"}}
+	}
+	creds, err := aws.WebIdentityEnvProvider{}.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds.AccessKey, Equals, "webaccess")
+	c.Assert(got, Equals, fakeWebIdentity{"arn:aws:iam::123456789012:role/web", "app", "/var/run/token"})
+
+	// GetAuth picks up web identity credentials from the environment.
+	os.Setenv("HOME", c.MkDir())
+	auth, err := aws.GetAuth("", "", "", time.Time{})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "webaccess")
+	c.Assert(auth.Token(), Equals, "webtoken")
+}
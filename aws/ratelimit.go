@@ -0,0 +1,313 @@
+package aws
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A RateLimit is the rate of a token bucket: Rate requests per second on
+// average, in bursts of up to Burst requests. A Burst of zero allows
+// bursts of Rate requests, or of one request if Rate is less than one.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// A RateLimiter holds requests back, before they are signed, so that
+// they are sent to each service, and to each operation of a service, no
+// faster than the limits configured for them. The limits are applied
+// separately to each RateScope, an endpoint in a region: clients of a
+// service in different regions, or with different endpoints, each have
+// token buckets of their own, while clients sending requests to the
+// same endpoint in the same region share them.
+//
+// Each time a request is throttled, the rates of the buckets it is
+// limited by are cut by Backoff, down to a hundredth of their limits;
+// they then grow back at a steady pace, reaching their limits again over
+// Recovery. Retries made by a ResilientTransport, such as those of
+// RetryingClient, are limited and watched for throttling errors too.
+//
+// A RateLimiter acts on the requests of every client once installed in a
+// Pipeline:
+//
+//	limiter := &aws.RateLimiter{
+//		Services:   map[string]aws.RateLimit{"ec2": {Rate: 20}},
+//		Operations: map[string]aws.RateLimit{"ec2:DescribeInstances": {Rate: 5, Burst: 10}},
+//	}
+//	limiter.Install(aws.DefaultPipeline)
+//
+// or on those of a single client when set as its RateLimiter, which is
+// copied to each of its Requests (see Request.RateLimiter):
+//
+//	client := ec2.New(auth, aws.USEast)
+//	client.RateLimiter = limiter
+type RateLimiter struct {
+	// Services maps the endpoint prefixes of services, e.g. "ec2" or
+	// "autoscaling" (see Request.Service), to the limits of all the
+	// requests to them.
+	Services map[string]RateLimit
+
+	// Operations maps operations, named by the endpoint prefix of their
+	// service and their action name, e.g. "ec2:DescribeInstances", to
+	// the limits of the requests for them. Requests are held to the
+	// limits of both their service and their operation.
+	Operations map[string]RateLimit
+
+	// Backoff is the factor by which a rate is cut when a request is
+	// throttled; 0.5 if zero.
+	Backoff float64
+
+	// Recovery is how long a rate that was cut takes to grow back to its
+	// limit; one minute if zero.
+	Recovery time.Duration
+
+	mu      sync.Mutex
+	buckets map[rateKey]*rateBucket
+}
+
+// A RateScope identifies the requests a RateLimiter limits together:
+// those sent to the same endpoint in the same region.
+type RateScope struct {
+	Region string // as Request.Region
+	Host   string // the host of the endpoint, e.g. "ec2.us-east-1.amazonaws.com"
+}
+
+// requestRateScope returns the RateScope of r.
+func requestRateScope(r *Request) RateScope {
+	scope := RateScope{Region: r.Region}
+	if r.HTTPRequest != nil {
+		scope.Host = r.HTTPRequest.URL.Host
+	}
+	return scope
+}
+
+// rateKey names a bucket: that of a service, or of an operation of a
+// service ("service:operation"), in a scope.
+type rateKey struct {
+	scope RateScope
+	name  string
+}
+
+// rateBucket is a token bucket whose rate may have been cut below its
+// limit.
+type rateBucket struct {
+	limit  RateLimit
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// minRateFactor is how far below its limit the rate of a bucket may be
+// cut.
+const minRateFactor = 0.01
+
+func (b *rateBucket) burst() float64 {
+	if b.limit.Burst > 0 {
+		return float64(b.limit.Burst)
+	}
+	return math.Max(1, math.Floor(b.limit.Rate))
+}
+
+// advance fills b with the tokens added since it was last filled, and
+// lets its rate recover towards its limit.
+func (b *rateBucket) advance(now time.Time, recovery time.Duration) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	b.tokens = math.Min(b.burst(), b.tokens+elapsed*b.rate)
+	if b.rate < b.limit.Rate {
+		b.rate = math.Min(b.limit.Rate, b.rate+b.limit.Rate*elapsed/recovery.Seconds())
+	}
+}
+
+// reserve takes a token from b, and returns how long to wait until it is
+// due.
+func (b *rateBucket) reserve(now time.Time, recovery time.Duration) time.Duration {
+	b.advance(now, recovery)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// bucketsFor returns the buckets limiting requests for operation of
+// service in scope, creating them as needed. l.mu must be held.
+func (l *RateLimiter) bucketsFor(scope RateScope, service, operation string, now time.Time) []*rateBucket {
+	var buckets []*rateBucket
+	add := func(name string, limit RateLimit, ok bool) {
+		if !ok || limit.Rate <= 0 {
+			return
+		}
+		key := rateKey{scope, name}
+		b := l.buckets[key]
+		if b == nil || b.limit != limit {
+			b = &rateBucket{limit: limit, rate: limit.Rate, last: now}
+			b.tokens = b.burst()
+			if l.buckets == nil {
+				l.buckets = make(map[rateKey]*rateBucket)
+			}
+			l.buckets[key] = b
+		}
+		buckets = append(buckets, b)
+	}
+	limit, ok := l.Services[service]
+	add(service, limit, ok)
+	name := service + ":" + operation
+	limit, ok = l.Operations[name]
+	add(name, limit, ok)
+	return buckets
+}
+
+func (l *RateLimiter) recovery() time.Duration {
+	if l.Recovery > 0 {
+		return l.Recovery
+	}
+	return time.Minute
+}
+
+// Wait blocks until a request for operation of service may be sent in
+// scope, or until ctx is done, in which case it returns ctx's error.
+func (l *RateLimiter) Wait(ctx context.Context, scope RateScope, service, operation string) error {
+	now := time.Now()
+	l.mu.Lock()
+	buckets := l.bucketsFor(scope, service, operation, now)
+	var wait time.Duration
+	for _, b := range buckets {
+		if d := b.reserve(now, l.recovery()); d > wait {
+			wait = d
+		}
+	}
+	l.mu.Unlock()
+	if wait == 0 {
+		return nil
+	}
+	err := sleepContext(ctx, wait)
+	if err != nil {
+		// Give back the tokens that were not used.
+		l.mu.Lock()
+		for _, b := range buckets {
+			b.tokens = math.Min(b.burst(), b.tokens+1)
+		}
+		l.mu.Unlock()
+	}
+	return err
+}
+
+// Rate returns the current rate, in requests per second, of the bucket
+// limiting requests for operation of service in scope, or of all the
+// requests to service in scope if operation is empty. It is 0 if there
+// is no such limit.
+func (l *RateLimiter) Rate(scope RateScope, service, operation string) float64 {
+	name := service
+	if operation != "" {
+		name += ":" + operation
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.buckets[rateKey{scope, name}]
+	if b == nil {
+		// No request has been limited by it yet.
+		limit := l.Services[name]
+		if operation != "" {
+			limit = l.Operations[name]
+		}
+		return math.Max(0, limit.Rate)
+	}
+	b.advance(time.Now(), l.recovery())
+	return b.rate
+}
+
+// throttled cuts the rates of the buckets limiting requests for
+// operation of service in scope.
+func (l *RateLimiter) throttled(scope RateScope, service, operation string) {
+	backoff := l.Backoff
+	if backoff <= 0 || backoff >= 1 {
+		backoff = 0.5
+	}
+	now := time.Now()
+	l.mu.Lock()
+	for _, b := range l.bucketsFor(scope, service, operation, now) {
+		b.advance(now, l.recovery())
+		b.rate = math.Max(b.limit.Rate*minRateFactor, b.rate*backoff)
+	}
+	l.mu.Unlock()
+}
+
+// Install adds the middleware of l to p under the name "RateLimit": in
+// the Sign phase, where it waits for each attempt of a request to be
+// allowed, and in the Send phase, where it watches for throttling
+// errors.
+func (l *RateLimiter) Install(p *Pipeline) {
+	p.Use(SignPhase, "RateLimit", l.waitMiddleware)
+	p.Use(SendPhase, "RateLimit", l.watchMiddleware)
+}
+
+// waitMiddleware is the Sign phase middleware of l, which waits for each
+// attempt of a request to be allowed.
+func (l *RateLimiter) waitMiddleware(next Handler) Handler {
+	return func(r *Request) error {
+		if err := l.Wait(r.Context, requestRateScope(r), r.Service, r.Operation); err != nil {
+			return err
+		}
+		return next(r)
+	}
+}
+
+// watchMiddleware is the Send phase middleware of l, which watches for
+// throttling errors.
+func (l *RateLimiter) watchMiddleware(next Handler) Handler {
+	return func(r *Request) error {
+		ctx := r.Context
+		scope := requestRateScope(r)
+		o := &rateObserver{l: l, scope: scope, service: r.Service, operation: r.Operation}
+		r.Context = withAttemptObserver(ctx, o)
+		err := next(r)
+		r.Context = ctx
+		if err == nil && o.attempts == 0 && isThrottleResponse(r.HTTPResponse) {
+			// The client did not report its attempts.
+			l.throttled(scope, r.Service, r.Operation)
+		}
+		return err
+	}
+}
+
+// rateObserver applies a RateLimiter to the attempts of a
+// ResilientTransport at a request.
+type rateObserver struct {
+	l                  *RateLimiter
+	scope              RateScope
+	service, operation string
+	attempts           int
+}
+
+func (o *rateObserver) attempted(res *http.Response, err error) {
+	o.attempts++
+	if isThrottleResponse(res) {
+		o.l.throttled(o.scope, o.service, o.operation)
+	}
+}
+
+func (o *rateObserver) retrying(ctx context.Context) error {
+	return o.l.Wait(ctx, o.scope, o.service, o.operation)
+}
+
+// Uninstall removes the middleware of l from p.
+func (l *RateLimiter) Uninstall(p *Pipeline) {
+	p.Remove(SignPhase, "RateLimit")
+	p.Remove(SendPhase, "RateLimit")
+}
+
+// isThrottleResponse reports whether res says its request was throttled,
+// leaving its body to be read again.
+func isThrottleResponse(res *http.Response) bool {
+	if res == nil || res.StatusCode < 400 {
+		return false
+	}
+	return res.StatusCode == http.StatusTooManyRequests || IsThrottleCode(peekErrorCode(res))
+}
@@ -0,0 +1,210 @@
+package aws_test
+
+import (
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func newRateLimitServer(throttle *bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if *throttle {
+			w.WriteHeader(400)
+			fmt.Fprint(w, "<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>")
+		}
+	}))
+}
+
+// scopeOf returns the RateScope of requests to url in region.
+func scopeOf(t *testing.T, region, rawurl string) aws.RateScope {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aws.RateScope{Region: region, Host: u.Host}
+}
+
+func runLimited(t *testing.T, p *aws.Pipeline, client *http.Client, url, region, operation string) {
+	r := &aws.Request{Service: "svc", Operation: operation, Region: region, Client: client}
+	err := p.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", url, nil)
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.HTTPResponse.Body.Close()
+}
+
+func TestRateLimiter(t *testing.T) {
+	var throttle bool
+	ts := newRateLimitServer(&throttle)
+	defer ts.Close()
+
+	l := &aws.RateLimiter{
+		Services:   map[string]aws.RateLimit{"svc": {Rate: 100, Burst: 1}},
+		Operations: map[string]aws.RateLimit{"svc:Slow": {Rate: 20, Burst: 1}},
+		Recovery:   time.Hour,
+	}
+	p := &aws.Pipeline{}
+	l.Install(p)
+	scope := scopeOf(t, "us-east-1", ts.URL)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Fast")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("6 requests at 100/s took %v", elapsed)
+	}
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Slow")
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("3 requests at 20/s took %v", elapsed)
+	}
+
+	// Each throttled attempt made by the client cuts the rates.
+	throttle = true
+	client := aws.NewClient(&aws.ResilientTransport{Policy: &aws.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+	runLimited(t, p, client, ts.URL, "us-east-1", "Slow")
+	if rate := l.Rate(scope, "svc", ""); math.Abs(rate-12.5) > 0.1 {
+		t.Fatalf("Service rate is %v", rate)
+	}
+	if rate := l.Rate(scope, "svc", "Slow"); math.Abs(rate-2.5) > 0.1 {
+		t.Fatalf("Operation rate is %v", rate)
+	}
+	if rate := l.Rate(scope, "svc", "Fast"); rate != 0 {
+		t.Fatalf("Unlimited operation has rate %v", rate)
+	}
+
+	l.Uninstall(p)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Slow")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("3 requests took %v without a limiter", elapsed)
+	}
+}
+
+func TestRateLimiterRecovery(t *testing.T) {
+	throttle := true
+	ts := newRateLimitServer(&throttle)
+	defer ts.Close()
+
+	l := &aws.RateLimiter{
+		Services: map[string]aws.RateLimit{"svc": {Rate: 1000}},
+		Backoff:  0.1,
+		Recovery: 100 * time.Millisecond,
+	}
+	scope := scopeOf(t, "us-east-1", ts.URL)
+	if rate := l.Rate(scope, "svc", ""); rate != 1000 {
+		t.Fatalf("Rate is %v before any request", rate)
+	}
+	p := &aws.Pipeline{}
+	l.Install(p)
+	runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Op")
+	if rate := l.Rate(scope, "svc", ""); rate > 200 {
+		t.Fatalf("Rate is %v after throttling", rate)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if rate := l.Rate(scope, "svc", ""); rate != 1000 {
+		t.Fatalf("Rate is %v after recovery", rate)
+	}
+}
+
+func TestRateLimiterScopes(t *testing.T) {
+	var throttle bool
+	ts := newRateLimitServer(&throttle)
+	defer ts.Close()
+
+	l := &aws.RateLimiter{
+		Services: map[string]aws.RateLimit{"svc": {Rate: 10, Burst: 1}},
+		Recovery: time.Hour,
+	}
+	p := &aws.Pipeline{}
+	l.Install(p)
+	east := scopeOf(t, "us-east-1", ts.URL)
+	west := scopeOf(t, "us-west-2", ts.URL)
+
+	// Each region has a bucket of its own.
+	start := time.Now()
+	runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Op")
+	runLimited(t, p, http.DefaultClient, ts.URL, "us-west-2", "Op")
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("Requests to two regions at 10/s took %v", elapsed)
+	}
+
+	// Throttling in one region leaves the other alone.
+	throttle = true
+	runLimited(t, p, http.DefaultClient, ts.URL, "us-east-1", "Op")
+	if rate := l.Rate(east, "svc", ""); math.Abs(rate-5) > 0.1 {
+		t.Fatalf("Throttled rate is %v", rate)
+	}
+	if rate := l.Rate(west, "svc", ""); rate != 10 {
+		t.Fatalf("Rate of another region is %v", rate)
+	}
+	other := aws.RateScope{Region: "us-east-1", Host: "svc.example.com"}
+	if rate := l.Rate(other, "svc", ""); rate != 10 {
+		t.Fatalf("Rate of another endpoint is %v", rate)
+	}
+}
+
+func TestRequestRateLimiter(t *testing.T) {
+	var throttle bool
+	ts := newRateLimitServer(&throttle)
+	defer ts.Close()
+
+	// A limiter set on a request applies without being installed.
+	l := &aws.RateLimiter{
+		Services: map[string]aws.RateLimit{"svc": {Rate: 20, Burst: 1}},
+		Recovery: time.Hour,
+	}
+	run := func(l *aws.RateLimiter) {
+		r := &aws.Request{Service: "svc", Operation: "Op", Region: "us-east-1", Client: http.DefaultClient, RateLimiter: l}
+		err := aws.DefaultPipeline.Run(r, aws.Handlers{
+			Build: func(r *aws.Request) (err error) {
+				r.HTTPRequest, err = http.NewRequest("GET", ts.URL, nil)
+				return err
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.HTTPResponse.Body.Close()
+	}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		run(l)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("3 requests at 20/s took %v", elapsed)
+	}
+
+	// Requests without it are not limited, nor do they cut its rate.
+	throttle = true
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		run(nil)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("3 unlimited requests took %v", elapsed)
+	}
+	scope := scopeOf(t, "us-east-1", ts.URL)
+	if rate := l.Rate(scope, "svc", ""); rate != 20 {
+		t.Fatalf("Rate is %v after throttling of other requests", rate)
+	}
+	run(l)
+	if rate := l.Rate(scope, "svc", ""); math.Abs(rate-10) > 0.1 {
+		t.Fatalf("Rate is %v after throttling", rate)
+	}
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RegionByName returns the Region named name. Regions missing from
+// Regions are synthesized with ResolveRegion from the standard endpoint
+// patterns of their partition.
+func RegionByName(name string) (Region, error) {
+	if region, ok := Regions[name]; ok {
+		return region, nil
+	}
+	if name == "" {
+		return Region{}, errors.New("empty region name")
+	}
+	region, err := ResolveRegion(nil, name)
+	if err != nil {
+		return Region{}, fmt.Errorf("unknown region %q", name)
+	}
+	return region, nil
+}
+
+// GetRegion determines the Region to use. The first of the following that
+// names a region is used:
+//
+//   - name, if it is not empty;
+//   - the AWS_REGION or AWS_DEFAULT_REGION environment variable;
+//   - the region of the AWS_PROFILE (or default) profile in the shared
+//     config files;
+//   - the region of the current EC2 instance, from instance metadata,
+//     unless AWS_EC2_METADATA_DISABLED is "true".
+//
+// The region is then looked up with RegionByName.
+func GetRegion(name string) (Region, error) {
+	if name != "" {
+		return RegionByName(name)
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if name := os.Getenv(env); name != "" {
+			region, err := RegionByName(name)
+			if err != nil {
+				return Region{}, fmt.Errorf("%v in %s", err, env)
+			}
+			return region, nil
+		}
+	}
+
+	var errs []string
+	if config, err := LoadSharedConfig(); err != nil {
+		errs = append(errs, err.Error())
+	} else if _, err := config.Profile(""); err != nil {
+		// A profile missing from the shared config names no region,
+		// like a profile without one.
+		errs = append(errs, err.Error())
+	} else {
+		region, err := config.Region("")
+		if err != nil {
+			return Region{}, err
+		}
+		if region.Name != "" {
+			return region, nil
+		}
+	}
+
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		errs = append(errs, "instance metadata disabled")
+	} else {
+		region, err := GetInstanceRegion()
+		if err == nil {
+			return region, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return Region{}, fmt.Errorf("No AWS region found: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,107 @@
+package aws_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+func (s *S) TestRegionByName(c *C) {
+	region, err := aws.RegionByName("eu-west-1")
+	c.Assert(err, IsNil)
+	c.Assert(region, Equals, aws.EUWest)
+
+	// Regions missing from the table are synthesized.
+	region, err = aws.RegionByName("ap-southeast-3")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "ap-southeast-3")
+	c.Assert(region.EC2Endpoint, Equals, "https://ec2.ap-southeast-3.amazonaws.com")
+	c.Assert(region.S3Endpoint, Equals, "https://s3.ap-southeast-3.amazonaws.com")
+	c.Assert(region.IAMEndpoint, Equals, "https://iam.amazonaws.com")
+	c.Assert(region.S3LocationConstraint, Equals, true)
+
+	region, err = aws.RegionByName("cn-northwest-1")
+	c.Assert(err, IsNil)
+	c.Assert(region.EC2Endpoint, Equals, "https://ec2.cn-northwest-1.amazonaws.com.cn")
+
+	_, err = aws.RegionByName("eu-wst")
+	c.Assert(err, ErrorMatches, `unknown region "eu-wst"`)
+	_, err = aws.RegionByName("")
+	c.Assert(err, ErrorMatches, "empty region name")
+}
+
+func (s *S) TestGetRegion(c *C) {
+	os.Clearenv()
+	os.Setenv("HOME", writeSharedConfig(c))
+	os.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	// The explicit name comes first...
+	os.Setenv("AWS_REGION", "us-west-2")
+	region, err := aws.GetRegion("ap-south-1")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "ap-south-1")
+
+	// ... then the environment ...
+	region, err = aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "us-west-2")
+	os.Unsetenv("AWS_REGION")
+	os.Setenv("AWS_DEFAULT_REGION", "sa-east-1")
+	region, err = aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "sa-east-1")
+	os.Setenv("AWS_DEFAULT_REGION", "moon-1")
+	_, err = aws.GetRegion("")
+	c.Assert(err, ErrorMatches, `unknown region "moon-1" in AWS_DEFAULT_REGION`)
+	os.Unsetenv("AWS_DEFAULT_REGION")
+
+	// ... then the shared config.
+	region, err = aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "eu-west-1")
+	os.Setenv("AWS_PROFILE", "temp")
+	region, err = aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "us-west-2")
+
+	os.Setenv("AWS_PROFILE", "configonly")
+	_, err = aws.GetRegion("")
+	c.Assert(err, ErrorMatches, "No AWS region found: instance metadata disabled")
+}
+
+func (s *S) TestGetRegionWithoutProfile(c *C) {
+	_, server, _ := newFakeIMDS()
+	defer server.Close()
+
+	// A shared config without the default profile is skipped.
+	os.Clearenv()
+	home := c.MkDir()
+	err := os.Mkdir(filepath.Join(home, ".aws"), 0755)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(filepath.Join(home, ".aws", "config"), []byte("[profile other]\nregion = us-west-2\n"), 0644)
+	c.Assert(err, IsNil)
+	os.Setenv("HOME", home)
+	os.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	region, err := aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region, Equals, aws.EUWest)
+
+	os.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	_, err = aws.GetRegion("")
+	c.Assert(err, ErrorMatches, "No AWS region found: Couldn't find profile in AWS credentials file; instance metadata disabled")
+}
+
+func (s *S) TestGetRegionInstanceMetadata(c *C) {
+	_, server, _ := newFakeIMDS()
+	defer server.Close()
+
+	os.Clearenv()
+	os.Setenv("HOME", c.MkDir())
+	os.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	region, err := aws.GetRegion("")
+	c.Assert(err, IsNil)
+	c.Assert(region, Equals, aws.EUWest)
+}
@@ -0,0 +1,307 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throttleCodes are the error codes with which AWS services reject
+// requests made too quickly.
+var throttleCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// transientCodes are the error codes of failures that may succeed when
+// retried.
+var transientCodes = map[string]bool{
+	"RequestTimeout":          true,
+	"RequestTimeoutException": true,
+	"InternalError":           true,
+	"InternalFailure":         true,
+	"InternalServerError":     true,
+	"ServiceUnavailable":      true,
+	"IDPCommunicationError":   true,
+}
+
+// IsThrottleCode reports whether code is an AWS error code that means the
+// request was throttled.
+func IsThrottleCode(code string) bool {
+	return throttleCodes[code]
+}
+
+// IsRetryableCode reports whether code is an AWS error code of a
+// throttling or transient failure, after which the request may be
+// retried.
+func IsRetryableCode(code string) bool {
+	return throttleCodes[code] || transientCodes[code]
+}
+
+// ErrorCode returns the AWS error code in an error response body, which
+// may be in any of the XML formats (<Error><Code>, <ErrorResponse>,
+// <Response><Errors>) or the JSON format ("__type", "code" or "Code",
+// possibly prefixed with a namespace and '#') used by AWS services. It
+// returns "" if no code is found.
+func ErrorCode(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return ""
+	}
+	if body[0] == '{' {
+		var e struct {
+			Type  string `json:"__type"`
+			Code  string `json:"code"`
+			Code2 string `json:"Code"`
+		}
+		if json.Unmarshal(body, &e) != nil {
+			return ""
+		}
+		code := e.Type
+		if code == "" {
+			code = e.Code
+		}
+		if code == "" {
+			code = e.Code2
+		}
+		if i := strings.LastIndex(code, "#"); i >= 0 {
+			code = code[i+1:]
+		}
+		return code
+	}
+	// Find the first <Code> element, wherever it is.
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := t.(xml.StartElement); ok && start.Name.Local == "Code" {
+			var code string
+			if d.DecodeElement(&code, &start) != nil {
+				return ""
+			}
+			return strings.TrimSpace(code)
+		}
+	}
+}
+
+// A RetryTokenBucket bounds the number of retries made by the clients that
+// share it. Each retry takes tokens from the bucket, and each request that
+// succeeds returns some; when the bucket is empty requests are not
+// retried, so that an outage or a throttling service does not cause a
+// retry storm.
+type RetryTokenBucket struct {
+	mu       sync.Mutex
+	capacity int
+	tokens   int
+}
+
+// Token costs of the retry token bucket.
+const (
+	RetryCost        = 5  // taken for a retry
+	TimeoutRetryCost = 10 // taken for a retry after a timeout
+	NoRetryIncrement = 1  // returned by a request that succeeded first time
+)
+
+// NewRetryTokenBucket returns a full RetryTokenBucket holding capacity
+// tokens.
+func NewRetryTokenBucket(capacity int) *RetryTokenBucket {
+	return &RetryTokenBucket{capacity: capacity, tokens: capacity}
+}
+
+func (b *RetryTokenBucket) take(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+func (b *RetryTokenBucket) give(n int) {
+	b.mu.Lock()
+	b.tokens += n
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.mu.Unlock()
+}
+
+// Tokens returns the number of tokens in the bucket.
+func (b *RetryTokenBucket) Tokens() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// RetryPolicy decides which failed requests a ResilientTransport retries,
+// and how long it waits before each retry.
+//
+// Requests are retried after temporary network errors and timeouts, 5xx
+// responses other than 501, 429 responses and responses whose AWS error
+// code is retryable (see IsRetryableCode). The wait before each retry is
+// chosen at random between zero and BaseDelay*2^n, up to MaxDelay ("full
+// jitter"), unless the response has a Retry-After header.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the
+	// first.
+	MaxAttempts int
+
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Tokens, if not nil, limits the retries made under the policy.
+	Tokens *RetryTokenBucket
+}
+
+// DefaultRetryPolicy is used by RetryingClient, and so by the service
+// clients.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    20 * time.Second,
+	Tokens:      NewRetryTokenBucket(500),
+}
+
+// maxErrorBody is how much of an error response is read to find its
+// error code.
+const maxErrorBody = 64 * 1024
+
+// Retryable reports whether the request that produced res and err should
+// be retried. If res is an error response, its body is read to classify
+// it and replaced by an equivalent reader.
+func (p *RetryPolicy) Retryable(res *http.Response, err error) bool {
+	if err != nil {
+		if neterr, ok := err.(net.Error); ok {
+			return neterr.Temporary() || neterr.Timeout()
+		}
+		return false
+	}
+	if res.StatusCode < 400 {
+		return false
+	}
+	if retryableStatus(res.StatusCode) {
+		return true
+	}
+	return IsRetryableCode(peekErrorCode(res))
+}
+
+// retryableStatus reports whether an error response with the HTTP status
+// code status may be retried whatever its error code.
+func retryableStatus(status int) bool {
+	return status == 429 || (status >= 500 && status != 501)
+}
+
+// peekErrorCode returns the error code in the body of res, leaving the
+// body unchanged for the caller.
+func peekErrorCode(res *http.Response) string {
+	return ErrorCode(peekErrorBody(res))
+}
+
+// peekErrorBody returns the start of the body of res, leaving the body
+// unchanged for the caller.
+func peekErrorBody(res *http.Response) []byte {
+	if res.Body == nil {
+		return nil
+	}
+	head, err := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+	res.Body = &multiReadCloser{io.MultiReader(bytes.NewReader(head), res.Body), res.Body}
+	if err != nil {
+		return nil
+	}
+	return head
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Delay returns how long to wait before retry number try (starting at 0)
+// of a request that failed with res.
+func (p *RetryPolicy) Delay(try int, res *http.Response) time.Duration {
+	if res != nil {
+		if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				d = p.MaxDelay
+			}
+			return d
+		}
+	}
+	ceiling := p.MaxDelay
+	if try < 62 {
+		if d := p.BaseDelay << uint(try); d > 0 && (ceiling <= 0 || d < ceiling) {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// acquire takes the tokens for a retry after err, reporting whether the
+// retry may be made.
+func (p *RetryPolicy) acquire(err error) (int, bool) {
+	if p.Tokens == nil {
+		return 0, true
+	}
+	cost := RetryCost
+	if neterr, ok := err.(net.Error); ok && neterr.Timeout() {
+		cost = TimeoutRetryCost
+	}
+	return cost, p.Tokens.take(cost)
+}
+
+// succeeded returns tokens to the bucket after a request succeeded,
+// having taken retried tokens for its retries.
+func (p *RetryPolicy) succeeded(retried int) {
+	if p.Tokens == nil {
+		return
+	}
+	if retried == 0 {
+		p.Tokens.give(NoRetryIncrement)
+	} else {
+		p.Tokens.give(retried)
+	}
+}
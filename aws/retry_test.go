@@ -0,0 +1,201 @@
+package aws_test
+
+import (
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errorCodeTests = []struct {
+	body, code string
+}{
+	{`<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>x</Message></Error></Errors><RequestID>r</RequestID></Response>`, "RequestLimitExceeded"},
+	{`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`, "SlowDown"},
+	{`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code></Error></ErrorResponse>`, "Throttling"},
+	{`{"__type":"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException","message":"x"}`, "ProvisionedThroughputExceededException"},
+	{`{"code":"ThrottlingException"}`, "ThrottlingException"},
+	{`{"Code":"InternalError"}`, "InternalError"},
+	{`not an error document`, ""},
+	{``, ""},
+}
+
+func TestErrorCode(t *testing.T) {
+	for _, test := range errorCodeTests {
+		if code := aws.ErrorCode([]byte(test.body)); code != test.code {
+			t.Errorf("ErrorCode(%q) = %q, want %q", test.body, code, test.code)
+		}
+	}
+}
+
+func errorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestRetryPolicy_retryable(t *testing.T) {
+	p := &aws.RetryPolicy{}
+	tests := []struct {
+		status int
+		body   string
+		retry  bool
+	}{
+		{400, `<Response><Errors><Error><Code>Throttling</Code></Error></Errors></Response>`, true},
+		{400, `{"__type":"ProvisionedThroughputExceededException"}`, true},
+		{503, `<Error><Code>SlowDown</Code></Error>`, true},
+		{429, ``, true},
+		{500, ``, true},
+		{501, ``, false},
+		{400, `<ErrorResponse><Error><Code>ValidationError</Code></Error></ErrorResponse>`, false},
+		{404, `<Error><Code>NoSuchKey</Code></Error>`, false},
+		// Quotas and conflicts, which retrying does not help.
+		{400, `<ErrorResponse><Error><Code>LimitExceededException</Code></Error></ErrorResponse>`, false},
+		{400, `{"__type":"TransactionInProgressException"}`, false},
+		{200, ``, false},
+	}
+	for _, test := range tests {
+		res := errorResponse(test.status, test.body)
+		if retry := p.Retryable(res, nil); retry != test.retry {
+			t.Errorf("Retryable(%d, %q) = %v, want %v", test.status, test.body, retry, test.retry)
+		}
+		// The body must still be readable by the caller.
+		b, _ := ioutil.ReadAll(res.Body)
+		if string(b) != test.body {
+			t.Errorf("Body changed to %q", b)
+		}
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	p := &aws.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for try := 0; try < 10; try++ {
+		ceiling := 100 * time.Millisecond << uint(try)
+		if ceiling > time.Second {
+			ceiling = time.Second
+		}
+		for i := 0; i < 20; i++ {
+			if d := p.Delay(try, nil); d < 0 || d > ceiling {
+				t.Fatalf("Delay(%d) = %v, outside [0, %v]", try, d, ceiling)
+			}
+		}
+	}
+
+	res := errorResponse(503, "")
+	res.Header.Set("Retry-After", "0")
+	if d := p.Delay(3, res); d != 0 {
+		t.Errorf("Retry-After 0 gave delay %v", d)
+	}
+	res.Header.Set("Retry-After", "120")
+	if d := p.Delay(0, res); d != time.Second {
+		t.Errorf("Retry-After not capped at MaxDelay: %v", d)
+	}
+	res.Header.Set("Retry-After", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	if d := p.Delay(0, res); d != 0 {
+		t.Errorf("Past Retry-After date gave delay %v", d)
+	}
+}
+
+func TestRetryPolicy_throttledRequestRetried(t *testing.T) {
+	tries := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tries += 1
+		if tries == 1 {
+			w.WriteHeader(400)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>RequestLimitExceeded</Code></Error></Errors></Response>`)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := aws.NewClient(&aws.ResilientTransport{
+		Policy: &aws.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	})
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(b) != "ok" || tries != 2 {
+		t.Fatalf("Throttled request not retried: %q after %d tries", b, tries)
+	}
+}
+
+func TestRetryPolicy_tokenBucket(t *testing.T) {
+	tries := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tries += 1
+		http.Error(w, "error", 503)
+	}))
+	defer ts.Close()
+
+	bucket := aws.NewRetryTokenBucket(2 * aws.RetryCost)
+	client := aws.NewClient(&aws.ResilientTransport{
+		Policy: &aws.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, Tokens: bucket},
+	})
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	// Two retries drain the bucket; the third is not made.
+	if resp.StatusCode != 503 || tries != 3 || bucket.Tokens() != 0 {
+		t.Fatalf("Got status %d after %d tries with %d tokens left", resp.StatusCode, tries, bucket.Tokens())
+	}
+
+	// A request that fails at once costs nothing, and successes refill
+	// the bucket.
+	tries = 0
+	resp, err = client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if tries != 1 {
+		t.Fatalf("Retried with an empty bucket: %d tries", tries)
+	}
+}
+
+func TestRetryPolicy_succeededRefillsBucket(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	bucket := aws.NewRetryTokenBucket(10)
+	client := aws.NewClient(&aws.ResilientTransport{
+		Policy: &aws.RetryPolicy{MaxAttempts: 3, Tokens: bucket},
+	})
+	// Drain the bucket with a failing server first.
+	fail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error", 500)
+	}))
+	defer fail.Close()
+	resp, err := client.Get(fail.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if bucket.Tokens() != 0 {
+		t.Fatalf("Expected empty bucket, have %d tokens", bucket.Tokens())
+	}
+	for i := 0; i < 3; i++ {
+		resp, err = client.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if bucket.Tokens() != 3*aws.NoRetryIncrement {
+		t.Fatalf("Expected %d tokens, have %d", 3*aws.NoRetryIncrement, bucket.Tokens())
+	}
+}
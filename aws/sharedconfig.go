@@ -0,0 +1,299 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vaughan0/go-ini"
+)
+
+// Profile holds the settings of a named profile from the shared
+// credentials and config files. Settings from the credentials file take
+// precedence over those from the config file.
+type Profile struct {
+	Name string
+
+	AccessKey    string // aws_access_key_id
+	SecretKey    string // aws_secret_access_key
+	SessionToken string // aws_session_token
+	Region       string // region
+
+	// CredentialProcess is a command that prints the profile's
+	// credentials (see ProcessProvider).
+	CredentialProcess string // credential_process
+
+	// Role assumption settings. A profile with a RoleArn obtains its
+	// credentials by assuming that role with the credentials of
+	// SourceProfile, or of CredentialSource ("Environment",
+	// "Ec2InstanceMetadata" or "EcsContainer"), or with the web identity
+	// token in WebIdentityTokenFile.
+	RoleArn          string // role_arn
+	SourceProfile    string // source_profile
+	CredentialSource string // credential_source
+	ExternalId       string // external_id
+	MFASerial        string // mfa_serial
+	RoleSessionName  string // role_session_name
+	DurationSeconds  int    // duration_seconds
+
+	WebIdentityTokenFile string // web_identity_token_file
+}
+
+// hasKeys reports whether the profile holds static credentials.
+func (p *Profile) hasKeys() bool {
+	return p.AccessKey != "" || p.SecretKey != ""
+}
+
+// SharedConfig holds the profiles read from the shared credentials file
+// (~/.aws/credentials) and config file (~/.aws/config).
+type SharedConfig struct {
+	profiles map[string]*Profile
+}
+
+// LoadSharedConfig loads the shared credentials and config files. The
+// AWS_SHARED_CREDENTIALS_FILE (or AWS_CREDENTIAL_FILE) and AWS_CONFIG_FILE
+// environment variables override their default locations under
+// $HOME/.aws.
+func LoadSharedConfig() (*SharedConfig, error) {
+	return LoadSharedConfigFiles("", "")
+}
+
+// LoadSharedConfigFiles loads the given shared credentials and config
+// files. Empty names select the default locations, as for
+// LoadSharedConfig. It is an error only if neither file can be read.
+func LoadSharedConfigFiles(credentialsFile, configFile string) (*SharedConfig, error) {
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	}
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv("AWS_CREDENTIAL_FILE")
+	}
+	if configFile == "" {
+		configFile = os.Getenv("AWS_CONFIG_FILE")
+	}
+	if credentialsFile == "" || configFile == "" {
+		var homeDir = os.Getenv("HOME")
+		if homeDir == "" {
+			return nil, errors.New("Could not get HOME")
+		}
+		if credentialsFile == "" {
+			credentialsFile = homeDir + "/.aws/credentials"
+		}
+		if configFile == "" {
+			configFile = homeDir + "/.aws/config"
+		}
+	}
+
+	config := &SharedConfig{profiles: make(map[string]*Profile)}
+	configIni, configErr := ini.LoadFile(configFile)
+	if configErr == nil {
+		for section, values := range configIni {
+			// Profiles other than the default one are named
+			// "profile <name>" in the config file.
+			name := section
+			if fields := strings.Fields(section); len(fields) == 2 && fields[0] == "profile" {
+				name = fields[1]
+			} else if section != "default" {
+				continue
+			}
+			if err := config.merge(name, values); err != nil {
+				return nil, err
+			}
+		}
+	}
+	credentialsIni, credentialsErr := ini.LoadFile(credentialsFile)
+	if credentialsErr == nil {
+		for name, values := range credentialsIni {
+			if name == "" {
+				continue
+			}
+			if err := config.merge(name, values); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if configErr != nil && credentialsErr != nil {
+		return nil, errors.New("Couldn't parse AWS credentials file")
+	}
+	return config, nil
+}
+
+func (c *SharedConfig) merge(name string, values ini.Section) error {
+	p := c.profiles[name]
+	if p == nil {
+		p = &Profile{Name: name}
+		c.profiles[name] = p
+	}
+	for key, value := range values {
+		switch key {
+		case "aws_access_key_id":
+			p.AccessKey = value
+		case "aws_secret_access_key":
+			p.SecretKey = value
+		case "aws_session_token":
+			p.SessionToken = value
+		case "region":
+			p.Region = value
+		case "role_arn":
+			p.RoleArn = value
+		case "source_profile":
+			p.SourceProfile = value
+		case "credential_source":
+			p.CredentialSource = value
+		case "external_id":
+			p.ExternalId = value
+		case "mfa_serial":
+			p.MFASerial = value
+		case "role_session_name":
+			p.RoleSessionName = value
+		case "credential_process":
+			p.CredentialProcess = value
+		case "web_identity_token_file":
+			p.WebIdentityTokenFile = value
+		case "duration_seconds":
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid duration_seconds %q in profile %q", value, name)
+			}
+			p.DurationSeconds = seconds
+		}
+	}
+	return nil
+}
+
+// Profile returns the named profile. An empty name selects the profile
+// named by AWS_PROFILE, or else "default".
+func (c *SharedConfig) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("AWS_PROFILE")
+	}
+	if name == "" {
+		name = "default"
+	}
+	p := c.profiles[name]
+	if p == nil {
+		return nil, errors.New("Couldn't find profile in AWS credentials file")
+	}
+	return p, nil
+}
+
+// ProfileChain returns the profiles that must be visited to obtain the
+// credentials of the named profile. The first profile holds static
+// credentials, a CredentialProcess, a CredentialSource or a
+// WebIdentityTokenFile; each following profile names a role to be assumed
+// with the credentials obtained so far. The last profile is the named one.
+//
+// An error is returned if the source_profile settings form a cycle.
+func (c *SharedConfig) ProfileChain(name string) ([]*Profile, error) {
+	p, err := c.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	var chain []*Profile
+	visited := make(map[string]bool)
+	for {
+		if visited[p.Name] {
+			path := make([]string, 0, len(chain)+1)
+			for _, v := range chain {
+				path = append(path, v.Name)
+			}
+			path = append(path, p.Name)
+			return nil, fmt.Errorf("source_profile cycle in AWS config: %s", strings.Join(path, " -> "))
+		}
+		visited[p.Name] = true
+		chain = append(chain, p)
+
+		if p.RoleArn == "" || p.WebIdentityTokenFile != "" {
+			break
+		}
+		if p.CredentialSource != "" {
+			if p.SourceProfile != "" {
+				return nil, fmt.Errorf("profile %q sets both source_profile and credential_source", p.Name)
+			}
+			chain = append(chain, &Profile{Name: p.Name, CredentialSource: p.CredentialSource})
+			break
+		}
+		if p.SourceProfile == "" {
+			return nil, fmt.Errorf("profile %q has a role_arn but no source_profile or credential_source", p.Name)
+		}
+		if p.SourceProfile == p.Name && p.hasKeys() {
+			// A profile may assume a role using its own keys.
+			chain = append(chain, &Profile{
+				Name:         p.Name,
+				AccessKey:    p.AccessKey,
+				SecretKey:    p.SecretKey,
+				SessionToken: p.SessionToken,
+			})
+			break
+		}
+		source := c.profiles[p.SourceProfile]
+		if source == nil {
+			return nil, fmt.Errorf("source_profile %q of profile %q not found", p.SourceProfile, p.Name)
+		}
+		p = source
+	}
+
+	// Reverse so the chain starts from the source credentials.
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// SourceProvider returns a provider for the credentials of p, which must
+// be the first profile of a chain returned by ProfileChain.
+//
+// Web identity profiles are resolved through WebIdentityRoleProvider.
+func (c *SharedConfig) SourceProvider(p *Profile) (CredentialsProvider, error) {
+	if p.WebIdentityTokenFile != "" {
+		return webIdentityProvider(p.RoleArn, p.RoleSessionName, p.WebIdentityTokenFile), nil
+	}
+	if p.CredentialProcess != "" {
+		return ProcessProvider{Command: p.CredentialProcess}, nil
+	}
+	if p.CredentialSource != "" {
+		switch p.CredentialSource {
+		case "Environment":
+			return EnvProvider{}, nil
+		case "Ec2InstanceMetadata":
+			return InstanceMetadataProvider{}, nil
+		case "EcsContainer":
+			return ContainerProvider{}, nil
+		}
+		return nil, fmt.Errorf("unsupported credential_source %q in profile %q", p.CredentialSource, p.Name)
+	}
+	var err error
+	if p.AccessKey == "" {
+		err = errors.New("AWS_ACCESS_KEY_ID not found in environment in credentials file")
+	}
+	if p.SecretKey == "" {
+		err = errors.New("AWS_SECRET_ACCESS_KEY not found in credentials file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return StaticProvider{Credentials{
+		AccessKey: p.AccessKey,
+		SecretKey: p.SecretKey,
+		Token:     p.SessionToken,
+	}}, nil
+}
+
+// Region returns the default region of the named profile. The zero Region
+// is returned if the profile does not set one.
+func (c *SharedConfig) Region(name string) (Region, error) {
+	p, err := c.Profile(name)
+	if err != nil {
+		return Region{}, err
+	}
+	if p.Region == "" {
+		return Region{}, nil
+	}
+	region, err := RegionByName(p.Region)
+	if err != nil {
+		return Region{}, fmt.Errorf("%v in profile %q", err, p.Name)
+	}
+	return region, nil
+}
@@ -0,0 +1,228 @@
+package aws_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hughe/goamz/aws"
+	. "gopkg.in/check.v1"
+)
+
+var sharedCredentials = `
+[default]
+aws_access_key_id = access
+aws_secret_access_key = secret
+
+[temp]
+aws_access_key_id = tempaccess
+aws_secret_access_key = tempsecret
+aws_session_token = temptoken
+
+[self]
+aws_access_key_id = selfaccess
+aws_secret_access_key = selfsecret
+`
+
+var sharedConfig = `
+[default]
+region = eu-west-1
+
+[profile temp]
+region = us-west-2
+aws_access_key_id = ignored
+
+[profile configonly]
+aws_access_key_id = configaccess
+aws_secret_access_key = configsecret
+
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = default
+external_id = 123ABC
+mfa_serial = arn:aws:iam::123456789012:mfa/bob
+role_session_name = admin-session
+duration_seconds = 900
+region = ap-southeast-2
+
+[profile crossaccount]
+role_arn = arn:aws:iam::210987654321:role/deploy
+source_profile = admin
+
+[profile self]
+role_arn = arn:aws:iam::123456789012:role/self
+source_profile = self
+
+[profile fromenv]
+role_arn = arn:aws:iam::123456789012:role/env
+credential_source = Environment
+
+[profile loop1]
+role_arn = arn:aws:iam::123456789012:role/loop1
+source_profile = loop2
+
+[profile loop2]
+role_arn = arn:aws:iam::123456789012:role/loop2
+source_profile = loop1
+
+[profile missing]
+role_arn = arn:aws:iam::123456789012:role/missing
+source_profile = nowhere
+
+[profile badregion]
+region = mars-north-1
+`
+
+func writeSharedConfig(c *C) string {
+	d := c.MkDir()
+	err := os.Mkdir(filepath.Join(d, ".aws"), 0755)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(filepath.Join(d, ".aws", "credentials"), []byte(sharedCredentials), 0644)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(filepath.Join(d, ".aws", "config"), []byte(sharedConfig), 0644)
+	c.Assert(err, IsNil)
+	return d
+}
+
+func (s *S) loadSharedConfig(c *C) *aws.SharedConfig {
+	os.Clearenv()
+	os.Setenv("HOME", writeSharedConfig(c))
+	config, err := aws.LoadSharedConfig()
+	c.Assert(err, IsNil)
+	return config
+}
+
+func (s *S) TestSharedConfigProfile(c *C) {
+	config := s.loadSharedConfig(c)
+
+	p, err := config.Profile("")
+	c.Assert(err, IsNil)
+	c.Assert(p.Name, Equals, "default")
+	c.Assert(p.AccessKey, Equals, "access")
+	c.Assert(p.Region, Equals, "eu-west-1")
+
+	// The credentials file takes precedence over the config file.
+	p, err = config.Profile("temp")
+	c.Assert(err, IsNil)
+	c.Assert(p.AccessKey, Equals, "tempaccess")
+	c.Assert(p.SessionToken, Equals, "temptoken")
+	c.Assert(p.Region, Equals, "us-west-2")
+
+	p, err = config.Profile("admin")
+	c.Assert(err, IsNil)
+	c.Assert(*p, Equals, aws.Profile{
+		Name:            "admin",
+		Region:          "ap-southeast-2",
+		RoleArn:         "arn:aws:iam::123456789012:role/admin",
+		SourceProfile:   "default",
+		ExternalId:      "123ABC",
+		MFASerial:       "arn:aws:iam::123456789012:mfa/bob",
+		RoleSessionName: "admin-session",
+		DurationSeconds: 900,
+	})
+
+	_, err = config.Profile("nonexistent")
+	c.Assert(err, ErrorMatches, "Couldn't find profile in AWS credentials file")
+}
+
+func (s *S) TestSharedConfigAWSProfile(c *C) {
+	config := s.loadSharedConfig(c)
+	os.Setenv("AWS_PROFILE", "temp")
+	p, err := config.Profile("")
+	c.Assert(err, IsNil)
+	c.Assert(p.Name, Equals, "temp")
+}
+
+func (s *S) TestSharedConfigRegion(c *C) {
+	config := s.loadSharedConfig(c)
+
+	region, err := config.Region("temp")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, aws.USWest2.Name)
+
+	region, err = config.Region("configonly")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "")
+
+	_, err = config.Region("badregion")
+	c.Assert(err, ErrorMatches, `unknown region "mars-north-1" in profile "badregion"`)
+}
+
+func chainNames(chain []*aws.Profile) []string {
+	var names []string
+	for _, p := range chain {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func (s *S) TestSharedConfigProfileChain(c *C) {
+	config := s.loadSharedConfig(c)
+
+	chain, err := config.ProfileChain("default")
+	c.Assert(err, IsNil)
+	c.Assert(chainNames(chain), DeepEquals, []string{"default"})
+
+	chain, err = config.ProfileChain("crossaccount")
+	c.Assert(err, IsNil)
+	c.Assert(chainNames(chain), DeepEquals, []string{"default", "admin", "crossaccount"})
+
+	chain, err = config.ProfileChain("self")
+	c.Assert(err, IsNil)
+	c.Assert(chainNames(chain), DeepEquals, []string{"self", "self"})
+	c.Assert(chain[0].RoleArn, Equals, "")
+	c.Assert(chain[0].AccessKey, Equals, "selfaccess")
+
+	chain, err = config.ProfileChain("fromenv")
+	c.Assert(err, IsNil)
+	c.Assert(chainNames(chain), DeepEquals, []string{"fromenv", "fromenv"})
+	c.Assert(chain[0].CredentialSource, Equals, "Environment")
+}
+
+func (s *S) TestSharedConfigProfileChainErrors(c *C) {
+	config := s.loadSharedConfig(c)
+
+	_, err := config.ProfileChain("loop1")
+	c.Assert(err, ErrorMatches, "source_profile cycle in AWS config: loop1 -> loop2 -> loop1")
+
+	_, err = config.ProfileChain("missing")
+	c.Assert(err, ErrorMatches, `source_profile "nowhere" of profile "missing" not found`)
+}
+
+func (s *S) TestSharedConfigSourceProvider(c *C) {
+	config := s.loadSharedConfig(c)
+
+	chain, err := config.ProfileChain("fromenv")
+	c.Assert(err, IsNil)
+	p, err := config.SourceProvider(chain[0])
+	c.Assert(err, IsNil)
+	c.Assert(p, Equals, aws.EnvProvider{})
+
+	temp, err := config.Profile("temp")
+	c.Assert(err, IsNil)
+	p, err = config.SourceProvider(temp)
+	c.Assert(err, IsNil)
+	creds, err := p.Retrieve()
+	c.Assert(err, IsNil)
+	c.Assert(creds, Equals, aws.Credentials{AccessKey: "tempaccess", SecretKey: "tempsecret", Token: "temptoken"})
+}
+
+func (s *S) TestSharedAuthSessionTokenAndConfigFile(c *C) {
+	os.Clearenv()
+	os.Setenv("HOME", writeSharedConfig(c))
+
+	os.Setenv("AWS_PROFILE", "temp")
+	auth, err := aws.SharedAuth()
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "tempaccess")
+	c.Assert(auth.Token(), Equals, "temptoken")
+
+	os.Setenv("AWS_PROFILE", "configonly")
+	auth, err = aws.SharedAuth()
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "configaccess")
+
+	os.Setenv("AWS_PROFILE", "admin")
+	_, err = aws.SharedAuth()
+	c.Assert(err, ErrorMatches, `profile "admin" assumes role .*; use sts.ProfileAuth`)
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -32,11 +33,12 @@ func NewV2Signer(auth Auth, service ServiceInfo) (*V2Signer, error) {
 }
 
 func (s *V2Signer) Sign(method, path string, params map[string]string) {
-	params["AWSAccessKeyId"] = s.auth.AccessKey
+	auth := s.auth.Current()
+	params["AWSAccessKeyId"] = auth.AccessKey
 	params["SignatureVersion"] = "2"
 	params["SignatureMethod"] = "HmacSHA256"
-	if s.auth.Token() != "" {
-		params["SecurityToken"] = s.auth.Token()
+	if token := auth.Token(); token != "" {
+		params["SecurityToken"] = token
 	}
 
 	// AWS specifies that the parameters in a signed request must
@@ -53,7 +55,7 @@ func (s *V2Signer) Sign(method, path string, params map[string]string) {
 	}
 	joined := strings.Join(sarray, "&")
 	payload := method + "\n" + s.host + "\n" + path + "\n" + joined
-	hash := hmac.New(sha256.New, []byte(s.auth.SecretKey))
+	hash := hmac.New(sha256.New, []byte(auth.SecretKey))
 	hash.Write([]byte(payload))
 	signature := make([]byte, b64.EncodedLen(hash.Size()))
 	b64.Encode(signature, hash.Sum(nil))
@@ -89,8 +91,8 @@ func (s *Route53Signer) getCurrentDate() string {
 }
 
 // Creates the authorize signature based on the date stamp and secret key
-func (s *Route53Signer) getHeaderAuthorize(message string) string {
-	hmacSha256 := hmac.New(sha256.New, []byte(s.auth.SecretKey))
+func (s *Route53Signer) getHeaderAuthorize(auth Auth, message string) string {
+	hmacSha256 := hmac.New(sha256.New, []byte(auth.SecretKey))
 	hmacSha256.Write([]byte(message))
 	cryptedString := hmacSha256.Sum(nil)
 
@@ -100,9 +102,10 @@ func (s *Route53Signer) getHeaderAuthorize(message string) string {
 // Adds all the required headers for AWS Route53 API to the request
 // including the authorization
 func (s *Route53Signer) Sign(req *http.Request) {
+	auth := s.auth.Current()
 	date := s.getCurrentDate()
 	authHeader := fmt.Sprintf("AWS3-HTTPS AWSAccessKeyId=%s,Algorithm=%s,Signature=%s",
-		s.auth.AccessKey, "HmacSHA256", s.getHeaderAuthorize(date))
+		auth.AccessKey, "HmacSHA256", s.getHeaderAuthorize(auth, date))
 
 	req.Header.Set("Host", req.Host)
 	req.Header.Set("X-Amzn-Authorization", authHeader)
@@ -122,9 +125,32 @@ type V4Signer struct {
 
 /*
 Return a new instance of a V4Signer capable of signing AWS requests.
+
+Requests are signed with the signing name and region of the endpoint of
+serviceName in region (see Region.ResolveEndpoint), or with serviceName
+and the name of region if it cannot be resolved.
 */
 func NewV4Signer(auth Auth, serviceName string, region Region) *V4Signer {
-	return &V4Signer{auth: auth, serviceName: serviceName, region: region}
+	s := &V4Signer{auth: auth, serviceName: serviceName, region: region}
+	s.serviceName, s.region.Name = SigningScope(serviceName, region)
+	return s
+}
+
+// SigningScope returns the name and region with which requests for the
+// endpoint of service in region are signed with Signature Version 4: the
+// SigningName and SigningRegion of the endpoint, or service and the name
+// of region if the endpoint cannot be resolved or does not give them.
+func SigningScope(service string, region Region) (name, regionName string) {
+	name, regionName = service, region.Name
+	if endpoint, err := region.ResolveEndpoint(service); err == nil {
+		if endpoint.SigningName != "" {
+			name = endpoint.SigningName
+		}
+		if endpoint.SigningRegion != "" {
+			regionName = endpoint.SigningRegion
+		}
+	}
+	return
 }
 
 /*
@@ -140,6 +166,95 @@ The signed request will include a new "Authorization" header indicating that the
 Any changes to the request after signing the request will invalidate the signature.
 */
 func (s *V4Signer) Sign(req *http.Request) {
+	// Sign with a copy holding the current credentials so that a signer
+	// shared between goroutines is never modified.
+	signer := *s
+	signer.auth = s.auth.Current()
+	signer.sign(req)
+}
+
+// SignHandler returns a Handler for the Sign phase of a Pipeline that
+// signs requests with s, adding the X-Amz-Security-Token header if the
+// credentials have a session token. When a request is signed again for
+// another attempt, the date and signature of the previous attempt are
+// replaced.
+func (s *V4Signer) SignHandler() Handler {
+	return func(r *Request) error {
+		signer := *s
+		signer.auth = s.auth.Current()
+		req := r.HTTPRequest
+		if r.Attempts > 0 {
+			req.Header.Del("X-Amz-Date")
+			req.Header.Del("Authorization")
+		}
+		if token := signer.auth.Token(); token != "" {
+			req.Header.Set("X-Amz-Security-Token", token)
+		}
+		signer.sign(req)
+		return nil
+	}
+}
+
+// MaxPresignExpiry is the longest time for which a URL presigned with
+// Signature Version 4 may be valid.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// Presign returns the URL of req with a Signature Version 4 signature in
+// its query string, valid for expires, which must be between one second
+// and MaxPresignExpiry. The signature is dated from the X-Amz-Date or
+// Date header of req as for Sign, or the current time. The headers of
+// req, other than those dates, are signed and must be sent with the URL.
+// Presigned URLs for S3 do not sign the payload; for other services the
+// payload is taken to be empty.
+//
+// req is not modified.
+func (s *V4Signer) Presign(req *http.Request, expires time.Duration) (*url.URL, error) {
+	if expires < time.Second || expires > MaxPresignExpiry {
+		return nil, fmt.Errorf("presigned URL expiry %v not between 1s and %v", expires, MaxPresignExpiry)
+	}
+	signer := *s
+	signer.auth = s.auth.Current()
+
+	r := req.WithContext(req.Context())
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	u := *req.URL
+	r.URL = &u
+	t := signer.requestTime(r)
+	r.Header.Del("X-Amz-Date")
+	r.Header.Del("Date")
+	r.Header.Del("Authorization")
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+	r.Header.Set("host", host)
+
+	query := r.URL.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", signer.auth.AccessKey+"/"+signer.credentialScope(t))
+	query.Set("X-Amz-Date", t.Format(ISO8601BasicFormat))
+	query.Set("X-Amz-Expires", fmt.Sprint(int64(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", signer.signedHeaders(r.Header))
+	if token := signer.auth.Token(); token != "" {
+		query.Set("X-Amz-Security-Token", token)
+	}
+	query.Del("X-Amz-Signature")
+	r.URL.RawQuery = encodeQuery(query)
+
+	payloadHash := signer.hash("")
+	if signer.serviceName == "s3" {
+		payloadHash = "UNSIGNED-PAYLOAD"
+	}
+	creq := signer.canonicalRequestWithPayload(r, payloadHash)
+	signature := signer.signature(t, signer.stringToSign(t, creq))
+	r.URL.RawQuery += "&X-Amz-Signature=" + signature
+	return r.URL, nil
+}
+
+func (s *V4Signer) sign(req *http.Request) {
 	req.Header.Set("host", req.Host)                  // host header must be included as a signed header
 	t := s.requestTime(req)                           // Get requst time
 	creq := s.canonicalRequest(req)                   // Build canonical request
@@ -154,7 +269,7 @@ func (s *V4Signer) Sign(req *http.Request) {
 requestTime method will parse the time from the request "x-amz-date" or "date" headers.
 If the "x-amz-date" header is present, that will take priority over the "date" header.
 If neither header is defined or we are unable to parse either header as a valid date
-then we will create a new "x-amz-date" header with the current time.
+then we will create a new "x-amz-date" header with the current time of DefaultClock.
 */
 func (s *V4Signer) requestTime(req *http.Request) time.Time {
 
@@ -184,7 +299,7 @@ func (s *V4Signer) requestTime(req *http.Request) time.Time {
 	}
 
 	// Create a current time header to be used
-	t = time.Now().UTC()
+	t = Now().UTC()
 	req.Header.Set("x-amz-date", t.Format(ISO8601BasicFormat))
 	return t
 }
@@ -192,22 +307,26 @@ func (s *V4Signer) requestTime(req *http.Request) time.Time {
 /*
 canonicalRequest method creates the canonical request according to Task 1 of the AWS Signature Version 4 Signing Process. (http://goo.gl/eUUZ3S)
 
-    CanonicalRequest =
-      HTTPRequestMethod + '\n' +
-      CanonicalURI + '\n' +
-      CanonicalQueryString + '\n' +
-      CanonicalHeaders + '\n' +
-      SignedHeaders + '\n' +
-      HexEncode(Hash(Payload))
+	CanonicalRequest =
+	  HTTPRequestMethod + '\n' +
+	  CanonicalURI + '\n' +
+	  CanonicalQueryString + '\n' +
+	  CanonicalHeaders + '\n' +
+	  SignedHeaders + '\n' +
+	  HexEncode(Hash(Payload))
 */
 func (s *V4Signer) canonicalRequest(req *http.Request) string {
+	return s.canonicalRequestWithPayload(req, s.payloadHash(req))
+}
+
+func (s *V4Signer) canonicalRequestWithPayload(req *http.Request, payloadHash string) string {
 	c := new(bytes.Buffer)
 	fmt.Fprintf(c, "%s\n", req.Method)
 	fmt.Fprintf(c, "%s\n", s.canonicalURI(req.URL))
 	fmt.Fprintf(c, "%s\n", s.canonicalQueryString(req.URL))
 	fmt.Fprintf(c, "%s\n\n", s.canonicalHeaders(req.Header))
 	fmt.Fprintf(c, "%s\n", s.signedHeaders(req.Header))
-	fmt.Fprintf(c, "%s", s.payloadHash(req))
+	fmt.Fprintf(c, "%s", payloadHash)
 	return c.String()
 }
 
@@ -225,16 +344,15 @@ func (s *V4Signer) canonicalURI(u *url.URL) string {
 }
 
 func (s *V4Signer) canonicalQueryString(u *url.URL) string {
+	return encodeQuery(u.Query())
+}
+
+// encodeQuery encodes query in the form used in canonical requests.
+func encodeQuery(query url.Values) string {
 	var a []string
-	for k, vs := range u.Query() {
-		k = url.QueryEscape(k)
+	for k, vs := range query {
 		for _, v := range vs {
-			if v == "" {
-				a = append(a, k+"=")
-			} else {
-				v = url.QueryEscape(v)
-				a = append(a, k+"="+v)
-			}
+			a = append(a, Encode(k)+"="+Encode(v))
 		}
 	}
 	sort.Strings(a)
@@ -266,29 +384,32 @@ func (s *V4Signer) signedHeaders(h http.Header) string {
 }
 
 func (s *V4Signer) payloadHash(req *http.Request) string {
-	var b []byte
-	if req.Body == nil {
-		b = []byte("")
-	} else {
+	// Leave a nil body nil, so that the request can still be sent again
+	// (see rewindBody).
+	b := []byte{}
+	if req.Body != nil {
 		var err error
 		b, err = ioutil.ReadAll(req.Body)
 		if err != nil {
 			// TODO: I REALLY DON'T LIKE THIS PANIC!!!!
 			panic(err)
 		}
+		req.Body = ioutil.NopCloser(bytes.NewBuffer(b))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
 	}
-	req.Body = ioutil.NopCloser(bytes.NewBuffer(b))
 	return s.hash(string(b))
 }
 
 /*
 stringToSign method creates the string to sign accorting to Task 2 of the AWS Signature Version 4 Signing Process. (http://goo.gl/es1PAu)
 
-    StringToSign  =
-      Algorithm + '\n' +
-      RequestDate + '\n' +
-      CredentialScope + '\n' +
-      HexEncode(Hash(CanonicalRequest))
+	StringToSign  =
+	  Algorithm + '\n' +
+	  RequestDate + '\n' +
+	  CredentialScope + '\n' +
+	  HexEncode(Hash(CanonicalRequest))
 */
 func (s *V4Signer) stringToSign(t time.Time, creq string) string {
 	w := new(bytes.Buffer)
@@ -316,11 +437,11 @@ func (s *V4Signer) signature(t time.Time, sts string) string {
 /*
 derivedKey method derives a signing key to be used for signing a request.
 
-	kSecret = Your AWS Secret Access Key
-    kDate = HMAC("AWS4" + kSecret, Date)
-    kRegion = HMAC(kDate, Region)
-    kService = HMAC(kRegion, Service)
-    kSigning = HMAC(kService, "aws4_request")
+		kSecret = Your AWS Secret Access Key
+	    kDate = HMAC("AWS4" + kSecret, Date)
+	    kRegion = HMAC(kDate, Region)
+	    kService = HMAC(kRegion, Service)
+	    kSigning = HMAC(kService, "aws4_request")
 */
 func (s *V4Signer) derivedKey(t time.Time) []byte {
 	h := s.hmac([]byte("AWS4"+s.auth.SecretKey), []byte(t.Format(ISO8601BasicFormatShort)))
@@ -0,0 +1,180 @@
+package aws
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// A Call describes an API call made through a Pipeline, as seen by a
+// Tracer and a MetricsRecorder.
+type Call struct {
+	Service   string // the endpoint prefix of the service, e.g. "ec2"
+	Operation string // the action, e.g. "DescribeInstances"
+	Region    string // the region, or "" for global services
+
+	Start   time.Time
+	Latency time.Duration // from Start until the call ended
+
+	// Attempts is the number of times the request was sent, including
+	// the retries made by a ResilientTransport, and RetryReasons the
+	// reasons for each retry: the AWS error code of the response, its
+	// HTTP status if it has no code, or "timeout" or "network error".
+	Attempts     int
+	RetryReasons []string
+
+	StatusCode int    // the HTTP status of the last response, or 0
+	RequestID  string // the ID AWS gave the last request, if any
+	Err        error  // the error the call failed with, if any
+
+	mu       sync.Mutex
+	outcomes []string
+}
+
+// A Span traces a single API call.
+type Span interface {
+	// End is called when the call ends, with call complete.
+	End(call *Call)
+}
+
+// A Tracer starts a Span for each API call. The context it returns is
+// the context with which the call is made, so that it may carry the span
+// to the layers below, such as an instrumented http.RoundTripper.
+type Tracer interface {
+	StartSpan(ctx context.Context, call *Call) (context.Context, Span)
+}
+
+// A MetricsRecorder records the metrics of each API call when it ends.
+type MetricsRecorder interface {
+	RecordCall(call *Call)
+}
+
+// Telemetry traces API calls and records their metrics. It acts once
+// installed in a Pipeline; installed in DefaultPipeline, it sees the
+// calls of every service client:
+//
+//	t := &aws.Telemetry{Tracer: tracer, Metrics: metrics}
+//	t.Install(aws.DefaultPipeline)
+//
+// The package aws/telemetry has implementations of Tracer and
+// MetricsRecorder.
+type Telemetry struct {
+	Tracer  Tracer          // may be nil
+	Metrics MetricsRecorder // may be nil
+}
+
+type callKey struct{}
+
+// Install adds the middleware of t to p under the name "Telemetry": in
+// the Call phase, where it traces each call, and in the Send phase, where
+// it counts attempts.
+func (t *Telemetry) Install(p *Pipeline) {
+	p.Use(CallPhase, "Telemetry", func(next Handler) Handler {
+		return func(r *Request) error {
+			call := &Call{Service: r.Service, Operation: r.Operation, Region: r.Region, Start: time.Now()}
+			ctx := r.Context
+			var span Span
+			if t.Tracer != nil {
+				r.Context, span = t.Tracer.StartSpan(ctx, call)
+			}
+			r.Context = context.WithValue(r.Context, callKey{}, call)
+			err := next(r)
+			r.Context = ctx
+			call.end(r.HTTPResponse, err)
+			if span != nil {
+				span.End(call)
+			}
+			if t.Metrics != nil {
+				t.Metrics.RecordCall(call)
+			}
+			return err
+		}
+	})
+	p.Use(SendPhase, "Telemetry", func(next Handler) Handler {
+		return func(r *Request) error {
+			call, ok := r.Context.Value(callKey{}).(*Call)
+			if !ok {
+				return next(r)
+			}
+			ctx := r.Context
+			o := &callObserver{call: call}
+			r.Context = withAttemptObserver(ctx, o)
+			err := next(r)
+			r.Context = ctx
+			if o.attempts == 0 {
+				// The client did not report its attempts.
+				call.attempted(r.HTTPResponse, err)
+			}
+			return err
+		}
+	})
+}
+
+// Uninstall removes the middleware of t from p.
+func (t *Telemetry) Uninstall(p *Pipeline) {
+	p.Remove(CallPhase, "Telemetry")
+	p.Remove(SendPhase, "Telemetry")
+}
+
+// attempted notes the outcome of an attempt at the call.
+func (c *Call) attempted(res *http.Response, err error) {
+	var outcome string
+	switch {
+	case err != nil:
+		outcome = "network error"
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			outcome = "timeout"
+		}
+	case res.StatusCode >= 300:
+		if outcome = peekErrorCode(res); outcome == "" {
+			outcome = strconv.Itoa(res.StatusCode)
+		}
+	}
+	c.mu.Lock()
+	c.outcomes = append(c.outcomes, outcome)
+	c.mu.Unlock()
+}
+
+// end completes c with the outcome of the call.
+func (c *Call) end(res *http.Response, err error) {
+	c.Latency = time.Since(c.Start)
+	c.Err = err
+	if res != nil {
+		c.StatusCode = res.StatusCode
+		c.RequestID = requestID(res.Header)
+	}
+	c.mu.Lock()
+	c.Attempts = len(c.outcomes)
+	if c.Attempts > 1 {
+		c.RetryReasons = c.outcomes[:c.Attempts-1]
+	}
+	c.mu.Unlock()
+}
+
+// requestID returns the request ID in the headers of a response.
+func requestID(h http.Header) string {
+	for _, name := range []string{"X-Amzn-Requestid", "X-Amz-Request-Id"} {
+		if id := h.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// callObserver notes the attempts of a ResilientTransport at a call.
+type callObserver struct {
+	call     *Call
+	attempts int
+}
+
+func (o *callObserver) attempted(res *http.Response, err error) {
+	o.attempts++
+	o.call.attempted(res, err)
+}
+
+func (o *callObserver) retrying(ctx context.Context) error {
+	return nil
+}
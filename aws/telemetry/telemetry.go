@@ -0,0 +1,130 @@
+// Package telemetry adapts the tracing and metrics hooks of aws.Telemetry
+// to the standard library: API calls are traced to a log/slog logger, and
+// their metrics published with expvar. Attributes gives the attributes of
+// a call under the OpenTelemetry semantic conventions, for adapters to
+// other tracing and metrics systems.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/hughe/goamz/aws"
+)
+
+// An Attribute is a key-value pair describing an API call.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns the attributes of call, named after the OpenTelemetry
+// semantic conventions for AWS SDK calls where they apply.
+func Attributes(call *aws.Call) []Attribute {
+	attrs := []Attribute{
+		{"rpc.system", "aws-api"},
+		{"rpc.service", call.Service},
+		{"rpc.method", call.Operation},
+	}
+	if call.Region != "" {
+		attrs = append(attrs, Attribute{"cloud.region", call.Region})
+	}
+	if call.StatusCode != 0 {
+		attrs = append(attrs, Attribute{"http.response.status_code", call.StatusCode})
+	}
+	if call.RequestID != "" {
+		attrs = append(attrs, Attribute{"aws.request_id", call.RequestID})
+	}
+	attrs = append(attrs, Attribute{"aws.attempts", call.Attempts})
+	if len(call.RetryReasons) > 0 {
+		attrs = append(attrs, Attribute{"aws.retry_reasons", strings.Join(call.RetryReasons, ",")})
+	}
+	if call.Err != nil {
+		errType := "error"
+		var apiErr aws.APIError
+		if errors.As(call.Err, &apiErr) && apiErr.ErrorCode() != "" {
+			errType = apiErr.ErrorCode()
+		}
+		attrs = append(attrs, Attribute{"error.type", errType})
+	}
+	return attrs
+}
+
+// A SlogTracer is an aws.Tracer that logs each API call to Logger when it
+// ends: at Level if it succeeded, at slog.LevelWarn if it failed.
+type SlogTracer struct {
+	Logger *slog.Logger // slog.Default() if nil
+	Level  slog.Level
+}
+
+func (t *SlogTracer) StartSpan(ctx context.Context, call *aws.Call) (context.Context, aws.Span) {
+	return ctx, &slogSpan{t, ctx}
+}
+
+type slogSpan struct {
+	t   *SlogTracer
+	ctx context.Context
+}
+
+func (s *slogSpan) End(call *aws.Call) {
+	logger := s.t.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := s.t.Level
+	attrs := []slog.Attr{slog.Duration("latency", call.Latency)}
+	for _, a := range Attributes(call) {
+		attrs = append(attrs, slog.Any(a.Key, a.Value))
+	}
+	if call.Err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", call.Err.Error()))
+	}
+	logger.LogAttrs(s.ctx, level, "aws call", attrs...)
+}
+
+// ExpvarMetrics is an aws.MetricsRecorder that publishes, for each
+// operation of each service, the number of calls, failed calls, attempts
+// and retries, the retries for each reason, and the total latency in
+// milliseconds, as an expvar.Map:
+//
+//	{"ec2.DescribeInstances": {"calls": 3, "errors": 0, "attempts": 4,
+//	 "retries": 1, "retry.Throttling": 1, "latency_ms": 215}}
+type ExpvarMetrics struct {
+	Map *expvar.Map
+
+	mu sync.Mutex
+}
+
+// NewExpvarMetrics returns an ExpvarMetrics publishing its map under
+// name. Like expvar.Publish, it panics if name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{Map: expvar.NewMap(name)}
+}
+
+func (m *ExpvarMetrics) RecordCall(call *aws.Call) {
+	key := call.Service + "." + call.Operation
+	m.mu.Lock()
+	op, ok := m.Map.Get(key).(*expvar.Map)
+	if !ok {
+		op = new(expvar.Map).Init()
+		m.Map.Set(key, op)
+	}
+	m.mu.Unlock()
+	op.Add("calls", 1)
+	if call.Err != nil {
+		op.Add("errors", 1)
+	} else {
+		op.Add("errors", 0)
+	}
+	op.Add("attempts", int64(call.Attempts))
+	op.Add("retries", int64(len(call.RetryReasons)))
+	for _, reason := range call.RetryReasons {
+		op.Add("retry."+reason, 1)
+	}
+	op.Add("latency_ms", call.Latency.Milliseconds())
+}
@@ -0,0 +1,82 @@
+package telemetry_test
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/aws/telemetry"
+)
+
+func TestAttributes(t *testing.T) {
+	call := &aws.Call{
+		Service:      "ec2",
+		Operation:    "DescribeInstances",
+		Region:       "us-east-1",
+		Attempts:     3,
+		RetryReasons: []string{"Throttling", "timeout"},
+		StatusCode:   400,
+		RequestID:    "abc",
+		Err:          errors.New("failed"),
+	}
+	want := []telemetry.Attribute{
+		{"rpc.system", "aws-api"},
+		{"rpc.service", "ec2"},
+		{"rpc.method", "DescribeInstances"},
+		{"cloud.region", "us-east-1"},
+		{"http.response.status_code", 400},
+		{"aws.request_id", "abc"},
+		{"aws.attempts", 3},
+		{"aws.retry_reasons", "Throttling,timeout"},
+		{"error.type", "error"},
+	}
+	if got := telemetry.Attributes(call); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Got %v, want %v", got, want)
+	}
+}
+
+func TestSlogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := &telemetry.SlogTracer{Logger: slog.New(slog.NewTextHandler(&buf, nil)), Level: slog.LevelInfo}
+	call := &aws.Call{Service: "sqs", Operation: "SendMessage", Attempts: 1, Latency: time.Second}
+	_, span := tracer.StartSpan(nil, call)
+	span.End(call)
+	call.Err = errors.New("failed")
+	span.End(call)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Got log %q", buf.String())
+	}
+	for _, s := range []string{"level=INFO", `msg="aws call"`, "latency=1s", "rpc.service=sqs", "rpc.method=SendMessage"} {
+		if !strings.Contains(lines[0], s) {
+			t.Fatalf("Log line %q does not contain %q", lines[0], s)
+		}
+	}
+	if !strings.Contains(lines[1], "level=WARN") || !strings.Contains(lines[1], "error=failed") {
+		t.Fatalf("Got log line %q for a failed call", lines[1])
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := telemetry.NewExpvarMetrics("goamz_test")
+	m.RecordCall(&aws.Call{Service: "s3", Operation: "GetObject", Attempts: 1, Latency: 10 * time.Millisecond})
+	m.RecordCall(&aws.Call{Service: "s3", Operation: "GetObject", Attempts: 3, RetryReasons: []string{"SlowDown", "SlowDown"}, Latency: 20 * time.Millisecond, Err: errors.New("failed")})
+
+	op := m.Map.Get("s3.GetObject").(*expvar.Map)
+	want := map[string]int64{"calls": 2, "errors": 1, "attempts": 4, "retries": 2, "retry.SlowDown": 2, "latency_ms": 30}
+	for key, n := range want {
+		if got := op.Get(key).(*expvar.Int).Value(); got != n {
+			t.Errorf("Got %s %d, want %d", key, got, n)
+		}
+	}
+	if expvar.Get("goamz_test") != m.Map {
+		t.Fatal("The metrics are not published")
+	}
+}
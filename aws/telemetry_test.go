@@ -0,0 +1,103 @@
+package aws_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type spanKey struct{}
+
+type testTracer struct {
+	started, ended []*aws.Call
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, call *aws.Call) (context.Context, aws.Span) {
+	t.started = append(t.started, call)
+	return context.WithValue(ctx, spanKey{}, call), t
+}
+
+func (t *testTracer) End(call *aws.Call) {
+	t.ended = append(t.ended, call)
+}
+
+type testMetrics []*aws.Call
+
+func (m *testMetrics) RecordCall(call *aws.Call) {
+	*m = append(*m, call)
+}
+
+func TestTelemetry(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Amzn-Requestid", fmt.Sprint("req-", requests))
+		switch r.URL.Path {
+		case "/flaky":
+			if requests == 1 {
+				w.WriteHeader(503)
+				fmt.Fprint(w, "<Error><Code>ServiceUnavailable</Code></Error>")
+			}
+		case "/invalid":
+			w.WriteHeader(400)
+			fmt.Fprint(w, "<Error><Code>ValidationError</Code></Error>")
+		}
+	}))
+	defer ts.Close()
+
+	tracer, metrics := &testTracer{}, &testMetrics{}
+	p := &aws.Pipeline{}
+	(&aws.Telemetry{Tracer: tracer, Metrics: metrics}).Install(p)
+	client := aws.NewClient(&aws.ResilientTransport{Policy: &aws.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}})
+
+	invalid := errors.New("invalid")
+	run := func(path string) (*aws.Call, error) {
+		r := &aws.Request{Service: "svc", Operation: "Op", Region: "us-west-2", Client: client}
+		err := p.Run(r, aws.Handlers{
+			Build: func(r *aws.Request) (err error) {
+				if r.Context.Value(spanKey{}) == nil {
+					t.Errorf("The span is not in the context of the request")
+				}
+				r.HTTPRequest, err = http.NewRequest("GET", ts.URL+path, nil)
+				return err
+			},
+			Unmarshal: func(r *aws.Request) error {
+				r.HTTPResponse.Body.Close()
+				if r.HTTPResponse.StatusCode != 200 {
+					return invalid
+				}
+				return nil
+			},
+		})
+		return tracer.ended[len(tracer.ended)-1], err
+	}
+
+	call, err := run("/flaky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Service != "svc" || call.Operation != "Op" || call.Region != "us-west-2" || call.Start.IsZero() || call.Latency <= 0 {
+		t.Fatalf("Got call %#v", call)
+	}
+	if call.Attempts != 2 || !reflect.DeepEqual(call.RetryReasons, []string{"ServiceUnavailable"}) {
+		t.Fatalf("Got %d attempts, retried for %q", call.Attempts, call.RetryReasons)
+	}
+	if call.StatusCode != 200 || call.RequestID != "req-2" || call.Err != nil {
+		t.Fatalf("Got call %#v", call)
+	}
+
+	call, err = run("/invalid")
+	if err != invalid || call.Err != invalid || call.Attempts != 1 || call.RetryReasons != nil || call.StatusCode != 400 {
+		t.Fatalf("Got call %#v", call)
+	}
+
+	if len(tracer.started) != 2 || len(tracer.ended) != 2 || len(*metrics) != 2 || (*metrics)[1] != call {
+		t.Fatalf("Got %d spans started, %d ended and %d calls recorded", len(tracer.started), len(tracer.ended), len(*metrics))
+	}
+}
@@ -0,0 +1,629 @@
+package aws
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A CredentialStore holds the credentials with which a Verifier checks
+// request signatures.
+type CredentialStore interface {
+	// LookupCredentials returns the credentials of accessKey, and false
+	// if the access key is unknown.
+	LookupCredentials(accessKey string) (Credentials, bool)
+}
+
+// StaticCredentials is a CredentialStore holding a fixed set of
+// credentials, keyed by access key.
+type StaticCredentials map[string]Credentials
+
+// NewStaticCredentials returns StaticCredentials holding creds.
+func NewStaticCredentials(creds ...Credentials) StaticCredentials {
+	c := make(StaticCredentials, len(creds))
+	for _, cred := range creds {
+		c[cred.AccessKey] = cred
+	}
+	return c
+}
+
+func (c StaticCredentials) LookupCredentials(accessKey string) (Credentials, bool) {
+	cred, ok := c[accessKey]
+	return cred, ok
+}
+
+// Signature schemes recognised by a Verifier.
+const (
+	SchemeV4Header = "AWS4-HMAC-SHA256"       // Signature Version 4, Authorization header
+	SchemeV4Query  = "AWS4-HMAC-SHA256 query" // Signature Version 4, presigned URL
+	SchemeV2Query  = "V2"                     // Signature Version 2 parameters, as used by EC2, IAM, SQS, ...
+	SchemeS3Header = "S3 V2"                  // S3's Signature Version 2, Authorization header
+	SchemeS3Query  = "S3 V2 query"            // S3's Signature Version 2, signed URL
+)
+
+// A Verifier checks the signatures of requests received by a server
+// standing in for an AWS service, such as the test servers in this
+// project. It recognises Signature Version 4 in the Authorization header
+// or in the query string of a presigned URL, Signature Version 2
+// parameters in the query string or form body, and S3's own Signature
+// Version 2 header and query-string signatures.
+type Verifier struct {
+	Credentials CredentialStore
+
+	// Region and Service, if not empty, are the only region and service
+	// accepted in the credential scope of Signature Version 4 requests.
+	Region  string
+	Service string
+
+	// MaxSkew is how far the time at which a request was signed may be
+	// from the server's clock. The default is 15 minutes; it is not
+	// checked if negative.
+	MaxSkew time.Duration
+
+	// Now, if not nil, gives the server's time.
+	Now func() time.Time
+}
+
+// A SignatureError tells why a Verifier rejected the signature of a
+// request. Code is the error code AWS would respond with. For a signature
+// that does not match, StringToSign (and CanonicalRequest, for Signature
+// Version 4) show what the server signed, for comparison with what the
+// client signed.
+type SignatureError struct {
+	Code    string
+	Message string
+
+	Scheme           string
+	AccessKey        string
+	CanonicalRequest string
+	StringToSign     string
+	Signature        string // the signature sent with the request
+	Expected         string // the signature computed by the server
+
+	// ServerTime is the server's time, for RequestTimeTooSkewed errors.
+	ServerTime time.Time
+}
+
+func (e *SignatureError) Error() string {
+	msg := e.Code + ": " + e.Message
+	if e.Expected != "" {
+		msg += fmt.Sprintf(" (%s signature %q, expected %q; string to sign %q)", e.Scheme, e.Signature, e.Expected, e.StringToSign)
+	}
+	return msg
+}
+
+func (e *SignatureError) ErrorCode() string    { return e.Code }
+func (e *SignatureError) ErrorMessage() string { return e.Message }
+func (e *SignatureError) RequestID() string    { return "" }
+
+// Is reports whether e is of the class target, such as ErrAccessDenied.
+func (e *SignatureError) Is(target error) bool { return ErrorIs(e, target) }
+
+// HTTPStatusCode returns the HTTP status with which AWS responds to the
+// error.
+func (e *SignatureError) HTTPStatusCode() int {
+	switch e.Code {
+	case "IncompleteSignature", "AuthorizationQueryParametersError", "AuthorizationHeaderMalformed", "InvalidArgument", "InvalidRequest", "XAmzContentSHA256Mismatch":
+		return 400
+	}
+	return 403
+}
+
+// Verify checks the signature of req. It returns nil if the signature is
+// valid, and a *SignatureError if it is not. The body of req is read if
+// the signature covers it, and replaced by an equivalent reader.
+func (v *Verifier) Verify(req *http.Request) error {
+	auth := req.Header.Get("Authorization")
+	query := req.URL.Query()
+	switch {
+	case strings.HasPrefix(auth, "AWS4-HMAC-SHA256 "):
+		return v.verifyV4(req, auth)
+	case query.Get("X-Amz-Algorithm") != "":
+		return v.verifyV4Query(req, query)
+	case strings.HasPrefix(auth, "AWS "):
+		return v.verifyS3(req, auth)
+	case query.Get("Signature") != "" && query.Get("SignatureVersion") == "":
+		return v.verifyS3Query(req, query)
+	}
+	params, err := formParams(req)
+	if err != nil {
+		return err
+	}
+	if params.Get("SignatureVersion") != "" {
+		return v.verifyV2(req, params)
+	}
+	return &SignatureError{
+		Code:    "MissingAuthenticationToken",
+		Message: "Request is missing Authentication Token",
+	}
+}
+
+// A ServerConfig controls the signature checks of a server standing in
+// for an AWS service, such as the ec2test, elbtest and iamtest servers.
+type ServerConfig struct {
+	// Verifier, if not nil, checks the signature of every request. Requests
+	// whose signatures it rejects fail with the error code it reports.
+	Verifier *Verifier
+}
+
+// VerifyRequest checks the signature of req if c, which may be nil, has a
+// Verifier. A request it rejects is to be answered with the error
+// returned, whose HTTPStatusCode and Code are those AWS responds with.
+// For a request rejected for clock skew, the Date header of w is set to
+// the time of the verifier, from which clients learn their offset.
+func (c *ServerConfig) VerifyRequest(w http.ResponseWriter, req *http.Request) *SignatureError {
+	if c == nil || c.Verifier == nil {
+		return nil
+	}
+	switch err := c.Verifier.Verify(req).(type) {
+	case nil:
+		return nil
+	case *SignatureError:
+		if !err.ServerTime.IsZero() {
+			w.Header().Set("Date", err.ServerTime.UTC().Format(http.TimeFormat))
+		}
+		return err
+	default:
+		return &SignatureError{Code: "InvalidRequest", Message: err.Error()}
+	}
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+// lookup returns the credentials of accessKey, checking that the session
+// token sent with the request, if any, is theirs.
+func (v *Verifier) lookup(scheme, accessKey, token string) (Credentials, error) {
+	cred, ok := v.Credentials.LookupCredentials(accessKey)
+	if !ok {
+		return cred, &SignatureError{
+			Code:      "InvalidAccessKeyId",
+			Message:   "The AWS Access Key Id you provided does not exist in our records.",
+			Scheme:    scheme,
+			AccessKey: accessKey,
+		}
+	}
+	if token != cred.Token {
+		return cred, &SignatureError{
+			Code:      "InvalidToken",
+			Message:   "The provided token is malformed or otherwise invalid.",
+			Scheme:    scheme,
+			AccessKey: accessKey,
+		}
+	}
+	if !cred.Expiration.IsZero() && !v.now().Before(cred.Expiration) {
+		return cred, &SignatureError{
+			Code:      "ExpiredToken",
+			Message:   "The provided token has expired.",
+			Scheme:    scheme,
+			AccessKey: accessKey,
+		}
+	}
+	return cred, nil
+}
+
+// checkTime checks that a request signed at t is within MaxSkew of the
+// server's time.
+func (v *Verifier) checkTime(scheme string, t time.Time) error {
+	maxSkew := v.MaxSkew
+	if maxSkew == 0 {
+		maxSkew = 15 * time.Minute
+	}
+	now := v.now()
+	if maxSkew < 0 || (t.After(now.Add(-maxSkew)) && t.Before(now.Add(maxSkew))) {
+		return nil
+	}
+	return &SignatureError{
+		Code:       "RequestTimeTooSkewed",
+		Message:    "The difference between the request time and the current time is too large.",
+		Scheme:     scheme,
+		ServerTime: now,
+	}
+}
+
+// checkExpires checks that a presigned request expiring at t has not
+// expired.
+func (v *Verifier) checkExpires(scheme string, t time.Time) error {
+	if v.now().After(t) {
+		return &SignatureError{
+			Code:    "AccessDenied",
+			Message: "Request has expired",
+			Scheme:  scheme,
+		}
+	}
+	return nil
+}
+
+// match compares the signature of a request with the expected one.
+func match(e *SignatureError) error {
+	if hmac.Equal([]byte(e.Signature), []byte(e.Expected)) {
+		return nil
+	}
+	e.Code = "SignatureDoesNotMatch"
+	e.Message = "The request signature we calculated does not match the signature you provided."
+	return e
+}
+
+// verifyV4 verifies a Signature Version 4 Authorization header.
+func (v *Verifier) verifyV4(req *http.Request, auth string) error {
+	fields := make(map[string]string)
+	for _, f := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(f), "=", 2)
+		if len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+	date := req.Header.Get("X-Amz-Date")
+	if date == "" {
+		if t, err := http.ParseTime(req.Header.Get("Date")); err == nil {
+			date = t.UTC().Format(ISO8601BasicFormat)
+		}
+	}
+	// The payload hash sent in X-Amz-Content-Sha256 is signed in place
+	// of the body's, so it must be checked against the body, unless the
+	// payload is unsigned or signed in chunks.
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != "UNSIGNED-PAYLOAD" && !strings.HasPrefix(payloadHash, "STREAMING-") {
+		body, err := readBody(req)
+		if err != nil {
+			return err
+		}
+		sum := fmt.Sprintf("%x", sha256.Sum256(body))
+		if payloadHash != "" && payloadHash != sum {
+			return &SignatureError{
+				Code:      "XAmzContentSHA256Mismatch",
+				Message:   fmt.Sprintf("The provided 'x-amz-content-sha256' header %q does not match what was computed: %q.", payloadHash, sum),
+				Scheme:    SchemeV4Header,
+				AccessKey: strings.SplitN(fields["Credential"], "/", 2)[0],
+			}
+		}
+		payloadHash = sum
+	}
+	return v.checkV4(req, SchemeV4Header, fields["Credential"], fields["SignedHeaders"], fields["Signature"],
+		date, req.Header.Get("X-Amz-Security-Token"), req.URL.Query(), payloadHash)
+}
+
+// verifyV4Query verifies a URL presigned with Signature Version 4.
+func (v *Verifier) verifyV4Query(req *http.Request, query url.Values) error {
+	scheme := SchemeV4Query
+	if query.Get("X-Amz-Algorithm") != "AWS4-HMAC-SHA256" {
+		return &SignatureError{
+			Code:    "AuthorizationQueryParametersError",
+			Message: fmt.Sprintf("X-Amz-Algorithm %q is not supported", query.Get("X-Amz-Algorithm")),
+			Scheme:  scheme,
+		}
+	}
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil || expires < 1 || time.Duration(expires)*time.Second > MaxPresignExpiry {
+		return &SignatureError{
+			Code:    "AuthorizationQueryParametersError",
+			Message: "X-Amz-Expires must be a number of seconds between 1 and 604800",
+			Scheme:  scheme,
+		}
+	}
+	date := query.Get("X-Amz-Date")
+	signed := make(url.Values, len(query))
+	for k, vs := range query {
+		if k != "X-Amz-Signature" {
+			signed[k] = vs
+		}
+	}
+	payloadHash := fmt.Sprintf("%x", sha256.Sum256(nil))
+	if strings.Contains(query.Get("X-Amz-Credential"), "/s3/") {
+		payloadHash = "UNSIGNED-PAYLOAD"
+	}
+	err = v.checkV4(req, scheme, query.Get("X-Amz-Credential"), query.Get("X-Amz-SignedHeaders"), query.Get("X-Amz-Signature"),
+		date, query.Get("X-Amz-Security-Token"), signed, payloadHash)
+	if err != nil {
+		if e, ok := err.(*SignatureError); ok && e.Code == "RequestTimeTooSkewed" {
+			// The signing time of a presigned URL is only checked
+			// against its expiry.
+			err = nil
+		} else {
+			return err
+		}
+	}
+	t, _ := time.Parse(ISO8601BasicFormat, date)
+	return v.checkExpires(scheme, t.Add(time.Duration(expires)*time.Second))
+}
+
+// checkV4 checks a Signature Version 4 signature, made with the given
+// credential and signed headers, of a request dated date with the given
+// query parameters and payload hash.
+func (v *Verifier) checkV4(req *http.Request, scheme, credential, signedHeaders, signature, date, token string, query url.Values, payloadHash string) error {
+	malformed := func(msg string) error {
+		return &SignatureError{Code: "IncompleteSignature", Message: msg, Scheme: scheme}
+	}
+	if credential == "" || signedHeaders == "" || signature == "" {
+		return malformed("Credential, SignedHeaders and Signature are required")
+	}
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[4] != "aws4_request" {
+		return malformed(fmt.Sprintf("Credential %q is not of the form <key>/<date>/<region>/<service>/aws4_request", credential))
+	}
+	accessKey, day, region, service := scope[0], scope[1], scope[2], scope[3]
+	t, err := time.Parse(ISO8601BasicFormat, date)
+	if err != nil {
+		return malformed(fmt.Sprintf("X-Amz-Date %q is not a valid date", date))
+	}
+	if day != t.Format(ISO8601BasicFormatShort) {
+		return malformed(fmt.Sprintf("Credential date %q does not match X-Amz-Date %q", day, date))
+	}
+	if (v.Region != "" && region != v.Region) || (v.Service != "" && service != v.Service) {
+		return &SignatureError{
+			Code:      "SignatureDoesNotMatch",
+			Message:   fmt.Sprintf("Credential should be scoped to region %q and service %q, not %q and %q", v.Region, v.Service, region, service),
+			Scheme:    scheme,
+			AccessKey: accessKey,
+		}
+	}
+	cred, err := v.lookup(scheme, accessKey, token)
+	if err != nil {
+		return err
+	}
+
+	signer := &V4Signer{auth: Auth{AccessKey: accessKey, SecretKey: cred.SecretKey}, serviceName: service, region: Region{Name: region}}
+	header := make(http.Header)
+	names := strings.Split(signedHeaders, ";")
+	for _, name := range names {
+		switch name {
+		case "host":
+			header.Set(name, req.Host)
+		case "content-length":
+			header.Set(name, strconv.FormatInt(req.ContentLength, 10))
+		default:
+			header[name] = append([]string(nil), req.Header[http.CanonicalHeaderKey(name)]...)
+		}
+	}
+	r := &http.Request{Method: req.Method, URL: &url.URL{Path: req.URL.Path, RawPath: req.URL.RawPath, RawQuery: encodeQuery(query)}, Header: header}
+	creq := signer.canonicalRequestWithPayload(r, payloadHash)
+	sts := signer.stringToSign(t, creq)
+	if err := match(&SignatureError{
+		Scheme:           scheme,
+		AccessKey:        accessKey,
+		CanonicalRequest: creq,
+		StringToSign:     sts,
+		Signature:        signature,
+		Expected:         signer.signature(t, sts),
+	}); err != nil {
+		return err
+	}
+	if signer.signedHeaders(header) != signedHeaders {
+		return malformed(fmt.Sprintf("SignedHeaders %q are not sorted lower-case header names", signedHeaders))
+	}
+	return v.checkTime(scheme, t)
+}
+
+// verifyV2 verifies Signature Version 2 parameters.
+func (v *Verifier) verifyV2(req *http.Request, params url.Values) error {
+	scheme := SchemeV2Query
+	if params.Get("SignatureVersion") != "2" {
+		return &SignatureError{
+			Code:    "InvalidParameterValue",
+			Message: fmt.Sprintf("SignatureVersion %q is not supported", params.Get("SignatureVersion")),
+			Scheme:  scheme,
+		}
+	}
+	accessKey := params.Get("AWSAccessKeyId")
+	cred, err := v.lookup(scheme, accessKey, params.Get("SecurityToken"))
+	if err != nil {
+		return err
+	}
+	var keys, sarray []string
+	for k := range params {
+		if k != "Signature" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sarray = append(sarray, Encode(k)+"="+Encode(params.Get(k)))
+	}
+	path := req.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	sts := req.Method + "\n" + req.Host + "\n" + path + "\n" + strings.Join(sarray, "&")
+	var mac []byte
+	switch params.Get("SignatureMethod") {
+	case "HmacSHA256":
+		h := hmac.New(sha256.New, []byte(cred.SecretKey))
+		h.Write([]byte(sts))
+		mac = h.Sum(nil)
+	case "HmacSHA1":
+		h := hmac.New(sha1.New, []byte(cred.SecretKey))
+		h.Write([]byte(sts))
+		mac = h.Sum(nil)
+	default:
+		return &SignatureError{
+			Code:    "InvalidParameterValue",
+			Message: fmt.Sprintf("SignatureMethod %q is not supported", params.Get("SignatureMethod")),
+			Scheme:  scheme,
+		}
+	}
+	if err := match(&SignatureError{
+		Scheme:       scheme,
+		AccessKey:    accessKey,
+		StringToSign: sts,
+		Signature:    params.Get("Signature"),
+		Expected:     b64.EncodeToString(mac),
+	}); err != nil {
+		return err
+	}
+	if expires := params.Get("Expires"); expires != "" {
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			return &SignatureError{Code: "InvalidParameterValue", Message: "Expires is not a valid date", Scheme: scheme}
+		}
+		return v.checkExpires(scheme, t)
+	}
+	if t, err := time.Parse(time.RFC3339, params.Get("Timestamp")); err == nil {
+		return v.checkTime(scheme, t)
+	}
+	return nil
+}
+
+// s3ParamsToSign are the S3 subresources included in S3 Signature Version
+// 2 signatures.
+var s3ParamsToSign = map[string]bool{
+	"acl":                          true,
+	"delete":                       true,
+	"lifecycle":                    true,
+	"location":                     true,
+	"logging":                      true,
+	"notification":                 true,
+	"partNumber":                   true,
+	"policy":                       true,
+	"requestPayment":               true,
+	"response-cache-control":       true,
+	"response-content-disposition": true,
+	"response-content-encoding":    true,
+	"response-content-language":    true,
+	"response-content-type":        true,
+	"response-expires":             true,
+	"tagging":                      true,
+	"torrent":                      true,
+	"uploadId":                     true,
+	"uploads":                      true,
+	"versionId":                    true,
+	"versioning":                   true,
+	"versions":                     true,
+	"website":                      true,
+}
+
+// verifyS3 verifies an S3 Signature Version 2 Authorization header.
+func (v *Verifier) verifyS3(req *http.Request, auth string) error {
+	scheme := SchemeS3Header
+	i := strings.LastIndex(auth, ":")
+	if i < 0 {
+		return &SignatureError{Code: "InvalidArgument", Message: "Authorization header is invalid", Scheme: scheme}
+	}
+	accessKey, signature := auth[len("AWS "):i], auth[i+1:]
+	date := req.Header.Get("Date")
+	t, dateErr := http.ParseTime(date)
+	if req.Header.Get("X-Amz-Date") != "" {
+		date = ""
+		t, dateErr = http.ParseTime(req.Header.Get("X-Amz-Date"))
+	}
+	err := v.checkS3(req, scheme, accessKey, signature, date, req.Header.Get("X-Amz-Security-Token"))
+	if err != nil {
+		return err
+	}
+	if dateErr != nil {
+		return &SignatureError{Code: "AccessDenied", Message: "AWS authentication requires a valid Date or x-amz-date header", Scheme: scheme}
+	}
+	return v.checkTime(scheme, t)
+}
+
+// verifyS3Query verifies an S3 Signature Version 2 signed URL.
+func (v *Verifier) verifyS3Query(req *http.Request, query url.Values) error {
+	scheme := SchemeS3Query
+	expires, err := strconv.ParseInt(query.Get("Expires"), 10, 64)
+	if err != nil {
+		return &SignatureError{Code: "AccessDenied", Message: "Query-string authentication requires the Signature, Expires and AWSAccessKeyId parameters", Scheme: scheme}
+	}
+	token := query.Get("x-amz-security-token")
+	if token == "" {
+		token = req.Header.Get("X-Amz-Security-Token")
+	}
+	err = v.checkS3(req, scheme, query.Get("AWSAccessKeyId"), query.Get("Signature"), query.Get("Expires"), token)
+	if err != nil {
+		return err
+	}
+	return v.checkExpires(scheme, time.Unix(expires, 0))
+}
+
+// checkS3 checks an S3 Signature Version 2 signature of a request dated
+// date (or expiring at date, for a signed URL).
+func (v *Verifier) checkS3(req *http.Request, scheme, accessKey, signature, date, token string) error {
+	cred, err := v.lookup(scheme, accessKey, token)
+	if err != nil {
+		return err
+	}
+	var amz []string
+	for k, vs := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-amz-") {
+			amz = append(amz, k+":"+strings.Join(vs, ","))
+		}
+	}
+	sort.Slice(amz, func(i, j int) bool {
+		return strings.SplitN(amz[i], ":", 2)[0] < strings.SplitN(amz[j], ":", 2)[0]
+	})
+	var subresources []string
+	for k, vs := range req.URL.Query() {
+		if s3ParamsToSign[k] {
+			for _, v := range vs {
+				if v == "" {
+					subresources = append(subresources, k)
+				} else {
+					subresources = append(subresources, k+"="+v)
+				}
+			}
+		}
+	}
+	sort.Strings(subresources)
+	resource := req.URL.EscapedPath()
+	if len(subresources) > 0 {
+		resource += "?" + strings.Join(subresources, "&")
+	}
+	sts := req.Method + "\n" + req.Header.Get("Content-Md5") + "\n" + req.Header.Get("Content-Type") + "\n" + date + "\n"
+	if len(amz) > 0 {
+		sts += strings.Join(amz, "\n") + "\n"
+	}
+	sts += resource
+	h := hmac.New(sha1.New, []byte(cred.SecretKey))
+	h.Write([]byte(sts))
+	return match(&SignatureError{
+		Scheme:       scheme,
+		AccessKey:    accessKey,
+		StringToSign: sts,
+		Signature:    signature,
+		Expected:     b64.EncodeToString(h.Sum(nil)),
+	})
+}
+
+// formParams returns the query parameters of req and, for a form POST,
+// the parameters in its body, leaving the body to be read again.
+func formParams(req *http.Request) (url.Values, error) {
+	params := req.URL.Query()
+	if req.Method != "POST" || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return params, nil
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range form {
+		params[k] = append(params[k], vs...)
+	}
+	return params, nil
+}
+
+// readBody reads the body of req, replacing it with an equivalent reader.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, err
+}
@@ -0,0 +1,212 @@
+package aws_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var verifyTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func newVerifier() *aws.Verifier {
+	return &aws.Verifier{
+		Credentials: aws.NewStaticCredentials(
+			aws.Credentials{AccessKey: "access", SecretKey: "secret"},
+			aws.Credentials{AccessKey: "temporary", SecretKey: "secret", Token: "token"},
+		),
+		Now: func() time.Time { return verifyTime },
+	}
+}
+
+func checkCode(t *testing.T, err error, code string) *aws.SignatureError {
+	e, ok := err.(*aws.SignatureError)
+	if !ok || e.Code != code {
+		t.Fatalf("Got error %v, want %s", err, code)
+	}
+	return e
+}
+
+func newV4Request(body string) *http.Request {
+	req, _ := http.NewRequest("POST", "http://iam.amazonaws.com/a%20b/?Version=2010-05-08&Action=List", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Amz-Date", verifyTime.Format(aws.ISO8601BasicFormat))
+	return req
+}
+
+func TestVerifyV4Header(t *testing.T) {
+	v := newVerifier()
+	req := newV4Request("Marker=x")
+	aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "iam", aws.USEast).Sign(req)
+	if err := v.Verify(req); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unsigned headers may be added.
+	req.Header.Set("User-Agent", "test")
+	if err := v.Verify(req); err != nil {
+		t.Fatal(err)
+	}
+
+	v.Region = "eu-west-1"
+	checkCode(t, v.Verify(req), "SignatureDoesNotMatch")
+	v.Region = ""
+
+	req.Header.Set("Content-Type", "text/plain")
+	e := checkCode(t, v.Verify(req), "SignatureDoesNotMatch")
+	if !strings.Contains(e.CanonicalRequest, "content-type:text/plain\n") || e.Expected == "" || e.Signature == e.Expected {
+		t.Fatalf("Got diagnostics %#v", e)
+	}
+	if e.HTTPStatusCode() != 403 {
+		t.Fatalf("Got status %d", e.HTTPStatusCode())
+	}
+
+	// The body is covered by the signature, and left to be read again.
+	req = newV4Request("Marker=x")
+	aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "iam", aws.USEast).Sign(req)
+	req.Body = newV4Request("Marker=y").Body
+	checkCode(t, v.Verify(req), "SignatureDoesNotMatch")
+	req.ParseForm()
+	if req.PostForm.Get("Marker") != "y" {
+		t.Fatalf("Body was not restored: %v", req.PostForm)
+	}
+
+	req = newV4Request("")
+	aws.NewV4Signer(aws.Auth{AccessKey: "unknown", SecretKey: "secret"}, "iam", aws.USEast).Sign(req)
+	checkCode(t, v.Verify(req), "InvalidAccessKeyId")
+
+	req = newV4Request("")
+	aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "iam", aws.USEast).Sign(req)
+	v.Now = func() time.Time { return verifyTime.Add(20 * time.Minute) }
+	e = checkCode(t, v.Verify(req), "RequestTimeTooSkewed")
+	if !e.ServerTime.Equal(verifyTime.Add(20 * time.Minute)) {
+		t.Fatalf("Got server time %v", e.ServerTime)
+	}
+	v.MaxSkew = time.Hour
+	if err := v.Verify(req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyV4ContentSHA256(t *testing.T) {
+	v := newVerifier()
+	sign := func() *http.Request {
+		req := newV4Request("Marker=x")
+		req.Header.Set("X-Amz-Content-Sha256", fmt.Sprintf("%x", sha256.Sum256([]byte("Marker=x"))))
+		aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "s3", aws.USEast).Sign(req)
+		return req
+	}
+	if err := v.Verify(sign()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A body that does not match the signed hash is caught even though
+	// the signature, which covers only the hash, is valid.
+	req := sign()
+	req.Body = newV4Request("Marker=y").Body
+	e := checkCode(t, v.Verify(req), "XAmzContentSHA256Mismatch")
+	if e.AccessKey != "access" || e.HTTPStatusCode() != 400 {
+		t.Fatalf("Got diagnostics %#v", e)
+	}
+}
+
+func TestVerifyV4SessionToken(t *testing.T) {
+	v := newVerifier()
+	for _, token := range []string{"token", "other", ""} {
+		req := newV4Request("")
+		if token != "" {
+			req.Header.Set("X-Amz-Security-Token", token)
+		}
+		aws.NewV4Signer(aws.Auth{AccessKey: "temporary", SecretKey: "secret"}, "iam", aws.USEast).Sign(req)
+		err := v.Verify(req)
+		if token == "token" {
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		checkCode(t, err, "InvalidToken")
+	}
+}
+
+func TestVerifyV4Presigned(t *testing.T) {
+	v := newVerifier()
+	req, _ := http.NewRequest("GET", "http://examplebucket.s3.amazonaws.com/test.txt", nil)
+	req.Header.Set("X-Amz-Date", verifyTime.Format(aws.ISO8601BasicFormat))
+	u, err := aws.NewV4Signer(aws.Auth{AccessKey: "access", SecretKey: "secret"}, "s3", aws.USEast).Presign(req, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest("GET", u.String(), nil)
+	if err := v.Verify(req); err != nil {
+		t.Fatal(err)
+	}
+
+	// A presigned URL is good until it expires, however long after it
+	// was signed.
+	v.Now = func() time.Time { return verifyTime.Add(59 * time.Minute) }
+	if err := v.Verify(req); err != nil {
+		t.Fatal(err)
+	}
+	v.Now = func() time.Time { return verifyTime.Add(61 * time.Minute) }
+	checkCode(t, v.Verify(req), "AccessDenied")
+
+	req, _ = http.NewRequest("GET", strings.Replace(u.String(), "X-Amz-Expires=3600", "X-Amz-Expires=3601", 1), nil)
+	checkCode(t, newVerifier().Verify(req), "SignatureDoesNotMatch")
+	req, _ = http.NewRequest("GET", strings.Replace(u.String(), "X-Amz-Expires=3600", "X-Amz-Expires=604801", 1), nil)
+	checkCode(t, newVerifier().Verify(req), "AuthorizationQueryParametersError")
+}
+
+func TestVerifyV2(t *testing.T) {
+	var verr error
+	v := newVerifier()
+	v.Now = time.Now
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verr = v.Verify(r)
+		r.ParseForm()
+		if r.Form.Get("Action") != "DescribeThings" {
+			t.Errorf("Form was not restored: %v", r.Form)
+		}
+	}))
+	defer ts.Close()
+
+	for _, method := range []string{"GET", "POST"} {
+		for _, auth := range []aws.Auth{
+			{AccessKey: "access", SecretKey: "secret"},
+			*aws.NewAuth("temporary", "secret", "token", time.Time{}),
+		} {
+			s, err := aws.NewService(auth, aws.ServiceInfo{Endpoint: ts.URL, Signer: aws.V2Signature})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := s.Query(method, "/", map[string]string{"Action": "DescribeThings", "Filter": "a b+c"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if verr != nil {
+				t.Fatalf("%s with %s: %v", method, auth.AccessKey, verr)
+			}
+		}
+	}
+
+	s, _ := aws.NewService(aws.Auth{AccessKey: "access", SecretKey: "wrong"}, aws.ServiceInfo{Endpoint: ts.URL, Signer: aws.V2Signature})
+	resp, err := s.Query("POST", "/", map[string]string{"Action": "DescribeThings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	e := checkCode(t, verr, "SignatureDoesNotMatch")
+	if e.Scheme != aws.SchemeV2Query || !strings.HasPrefix(e.StringToSign, "POST\n"+ts.URL[len("http://"):]+"\n/\n") {
+		t.Fatalf("Got diagnostics %#v", e)
+	}
+}
+
+func TestVerifyUnsigned(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://ec2.amazonaws.com/?Action=DescribeInstances", nil)
+	checkCode(t, newVerifier().Verify(req), "MissingAuthenticationToken")
+}
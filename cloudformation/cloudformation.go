@@ -7,6 +7,7 @@
 package cloudformation
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
@@ -24,12 +25,16 @@ import (
 type CloudFormation struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 // New creates a new CloudFormation Client.
 func New(auth aws.Auth, region aws.Region) *CloudFormation {
 
-	return &CloudFormation{auth, region}
+	return &CloudFormation{auth, region.WithEndpoints(), nil}
 
 }
 
@@ -61,52 +66,60 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	RequestId string  `xml:"RequestId"`
 	Errors    []Error `xml:"Error"`
 }
 
-func (c *CloudFormation) query(params map[string]string, resp interface{}) error {
+func (c *CloudFormation) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	params["Version"] = "2010-05-15"
-
-	data := strings.NewReader(multimap(params).Encode())
-
-	hreq, err := http.NewRequest("POST", c.Region.CloudFormationEndpoint+"/", data)
-	if err != nil {
-		return err
-	}
-
-	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
-
-	token := c.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
-	}
-
-	signer := aws.NewV4Signer(c.Auth, "cloudformation", c.Region)
-	signer.Sign(hreq)
-
-	if debug {
-		log.Printf("%v -> {\n", hreq)
-	}
-	r, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		log.Printf("Error calling Amazon")
-		return err
-	}
-
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
-	}
-	if r.StatusCode != 200 {
-		return buildError(r)
+	data := multimap(params).Encode()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "cloudformation",
+		Region:      c.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: c.RateLimiter,
+	}
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("POST", c.Region.CloudFormationEndpoint+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+			return nil
+		},
+		Sign: aws.NewV4Signer(c.Auth, "cloudformation", c.Region).SignHandler(),
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				log.Printf("%v -> {\n", r.HTTPRequest)
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -164,12 +177,17 @@ type SimpleResp struct {
 //
 // See http://goo.gl/ZE6fOa for more details
 func (c *CloudFormation) CancelUpdateStack(stackName string) (resp *SimpleResp, err error) {
+	return c.CancelUpdateStackWithContext(context.Background(), stackName)
+}
+
+// CancelUpdateStackWithContext is like CancelUpdateStack but makes its requests with ctx.
+func (c *CloudFormation) CancelUpdateStackWithContext(ctx context.Context, stackName string) (resp *SimpleResp, err error) {
 	params := makeParams("CancelUpdateStack")
 
 	params["StackName"] = stackName
 
 	resp = new(SimpleResp)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -222,6 +240,12 @@ type CreateStackResponse struct {
 //
 // See http://goo.gl/yDZYuV for more details
 func (c *CloudFormation) CreateStack(options *CreateStackParams) (
+	resp *CreateStackResponse, err error) {
+	return c.CreateStackWithContext(context.Background(), options)
+}
+
+// CreateStackWithContext is like CreateStack but makes its requests with ctx.
+func (c *CloudFormation) CreateStackWithContext(ctx context.Context, options *CreateStackParams) (
 	resp *CreateStackResponse, err error) {
 	params := makeParams("CreateStack")
 
@@ -271,7 +295,7 @@ func (c *CloudFormation) CreateStack(options *CreateStackParams) (
 	}
 
 	resp = new(CreateStackResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -282,12 +306,17 @@ func (c *CloudFormation) CreateStack(options *CreateStackParams) (
 //
 // See http://goo.gl/CVMpxC for more details
 func (c *CloudFormation) DeleteStack(stackName string) (resp *SimpleResp, err error) {
+	return c.DeleteStackWithContext(context.Background(), stackName)
+}
+
+// DeleteStackWithContext is like DeleteStack but makes its requests with ctx.
+func (c *CloudFormation) DeleteStackWithContext(ctx context.Context, stackName string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteStack")
 
 	params["StackName"] = stackName
 
 	resp = new(SimpleResp)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -322,6 +351,12 @@ type DescribeStackEventsResponse struct {
 //
 // See http://goo.gl/zqj4Bz for more details
 func (c *CloudFormation) DescribeStackEvents(stackName string, nextToken string) (
+	resp *DescribeStackEventsResponse, err error) {
+	return c.DescribeStackEventsWithContext(context.Background(), stackName, nextToken)
+}
+
+// DescribeStackEventsWithContext is like DescribeStackEvents but makes its requests with ctx.
+func (c *CloudFormation) DescribeStackEventsWithContext(ctx context.Context, stackName string, nextToken string) (
 	resp *DescribeStackEventsResponse, err error) {
 	params := makeParams("DescribeStackEvents")
 
@@ -333,12 +368,33 @@ func (c *CloudFormation) DescribeStackEvents(stackName string, nextToken string)
 	}
 
 	resp = new(DescribeStackEventsResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeStackEventsPages returns a Pager over the pages of DescribeStackEvents results,
+// starting with the page of nextToken.
+func (c *CloudFormation) DescribeStackEventsPages(stackName string, nextToken string) *aws.Pager[*DescribeStackEventsResponse] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeStackEventsResponse, bool, error) {
+		resp, err := c.DescribeStackEventsWithContext(ctx, stackName, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeStackEventsAll returns every event of the stack, walking every page of DescribeStackEvents
+// results.
+func (c *CloudFormation) DescribeStackEventsAll(ctx context.Context, stackName string) ([]StackEvent, error) {
+	return aws.All(ctx, c.DescribeStackEventsPages(stackName, ""), func(resp *DescribeStackEventsResponse) []StackEvent {
+		return resp.StackEvents
+	})
+}
+
 // StackResourceDetail encapsulates the StackResourceDetail data type
 //
 // See http://goo.gl/flce6I for more details
@@ -371,6 +427,12 @@ type DescribeStackResourceResponse struct {
 //
 // See http://goo.gl/6pfPFs for more details
 func (c *CloudFormation) DescribeStackResource(stackName string, logicalResourceId string) (
+	resp *DescribeStackResourceResponse, err error) {
+	return c.DescribeStackResourceWithContext(context.Background(), stackName, logicalResourceId)
+}
+
+// DescribeStackResourceWithContext is like DescribeStackResource but makes its requests with ctx.
+func (c *CloudFormation) DescribeStackResourceWithContext(ctx context.Context, stackName string, logicalResourceId string) (
 	resp *DescribeStackResourceResponse, err error) {
 	params := makeParams("DescribeStackResource")
 
@@ -378,7 +440,7 @@ func (c *CloudFormation) DescribeStackResource(stackName string, logicalResource
 	params["LogicalResourceId"] = logicalResourceId
 
 	resp = new(DescribeStackResourceResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -417,6 +479,12 @@ type DescribeStackResourcesResponse struct {
 //
 // See http://goo.gl/YnY5rs for more details
 func (c *CloudFormation) DescribeStackResources(stackName, physicalResourceId, logicalResourceId string) (
+	resp *DescribeStackResourcesResponse, err error) {
+	return c.DescribeStackResourcesWithContext(context.Background(), stackName, physicalResourceId, logicalResourceId)
+}
+
+// DescribeStackResourcesWithContext is like DescribeStackResources but makes its requests with ctx.
+func (c *CloudFormation) DescribeStackResourcesWithContext(ctx context.Context, stackName, physicalResourceId, logicalResourceId string) (
 	resp *DescribeStackResourcesResponse, err error) {
 	params := makeParams("DescribeStackResources")
 
@@ -431,7 +499,7 @@ func (c *CloudFormation) DescribeStackResources(stackName, physicalResourceId, l
 	}
 
 	resp = new(DescribeStackResourcesResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -480,6 +548,12 @@ type DescribeStacksResponse struct {
 //
 // See http://goo.gl/UOLsXD for more information
 func (c *CloudFormation) DescribeStacks(stackName string, nextToken string) (
+	resp *DescribeStacksResponse, err error) {
+	return c.DescribeStacksWithContext(context.Background(), stackName, nextToken)
+}
+
+// DescribeStacksWithContext is like DescribeStacks but makes its requests with ctx.
+func (c *CloudFormation) DescribeStacksWithContext(ctx context.Context, stackName string, nextToken string) (
 	resp *DescribeStacksResponse, err error) {
 	params := makeParams("DescribeStacks")
 
@@ -491,12 +565,34 @@ func (c *CloudFormation) DescribeStacks(stackName string, nextToken string) (
 	}
 
 	resp = new(DescribeStacksResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// DescribeStacksPages returns a Pager over the pages of DescribeStacks results,
+// starting with the page of nextToken.
+func (c *CloudFormation) DescribeStacksPages(stackName string, nextToken string) *aws.Pager[*DescribeStacksResponse] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeStacksResponse, bool, error) {
+		resp, err := c.DescribeStacksWithContext(ctx, stackName, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeStacksAll returns the description of the stack, or of every stack if
+// stackName is empty, walking every page of DescribeStacks
+// results.
+func (c *CloudFormation) DescribeStacksAll(ctx context.Context, stackName string) ([]Stack, error) {
+	return aws.All(ctx, c.DescribeStacksPages(stackName, ""), func(resp *DescribeStacksResponse) []Stack {
+		return resp.Stacks
+	})
+}
+
 // EstimateTemplateCostResponse wraps a response returned by EstimateTemplateCost request
 //
 // See http://goo.gl/PD9hle for more information
@@ -511,6 +607,12 @@ type EstimateTemplateCostResponse struct {
 //
 // See http://goo.gl/PD9hle for more information
 func (c *CloudFormation) EstimateTemplateCost(parameters []Parameter, templateBody, templateUrl string) (
+	resp *EstimateTemplateCostResponse, err error) {
+	return c.EstimateTemplateCostWithContext(context.Background(), parameters, templateBody, templateUrl)
+}
+
+// EstimateTemplateCostWithContext is like EstimateTemplateCost but makes its requests with ctx.
+func (c *CloudFormation) EstimateTemplateCostWithContext(ctx context.Context, parameters []Parameter, templateBody, templateUrl string) (
 	resp *EstimateTemplateCostResponse, err error) {
 	params := makeParams("EstimateTemplateCost")
 
@@ -530,7 +632,7 @@ func (c *CloudFormation) EstimateTemplateCost(parameters []Parameter, templateBo
 	}
 
 	resp = new(EstimateTemplateCostResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -549,13 +651,19 @@ type GetStackPolicyResponse struct {
 //
 // See http://goo.gl/iZFSgy for more information
 func (c *CloudFormation) GetStackPolicy(stackName string) (
+	resp *GetStackPolicyResponse, err error) {
+	return c.GetStackPolicyWithContext(context.Background(), stackName)
+}
+
+// GetStackPolicyWithContext is like GetStackPolicy but makes its requests with ctx.
+func (c *CloudFormation) GetStackPolicyWithContext(ctx context.Context, stackName string) (
 	resp *GetStackPolicyResponse, err error) {
 	params := makeParams("GetStackPolicy")
 
 	params["StackName"] = stackName
 
 	resp = new(GetStackPolicyResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -579,13 +687,19 @@ type GetTemplateResponse struct {
 //
 // See http://goo.gl/GU59CB for more information
 func (c *CloudFormation) GetTemplate(stackName string) (
+	resp *GetTemplateResponse, err error) {
+	return c.GetTemplateWithContext(context.Background(), stackName)
+}
+
+// GetTemplateWithContext is like GetTemplate but makes its requests with ctx.
+func (c *CloudFormation) GetTemplateWithContext(ctx context.Context, stackName string) (
 	resp *GetTemplateResponse, err error) {
 	params := makeParams("GetTemplate")
 
 	params["StackName"] = stackName
 
 	resp = new(GetTemplateResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -623,6 +737,12 @@ type ListStackResourcesResponse struct {
 //
 // See http://goo.gl/JUCgLf for more details
 func (c *CloudFormation) ListStackResources(stackName, nextToken string) (
+	resp *ListStackResourcesResponse, err error) {
+	return c.ListStackResourcesWithContext(context.Background(), stackName, nextToken)
+}
+
+// ListStackResourcesWithContext is like ListStackResources but makes its requests with ctx.
+func (c *CloudFormation) ListStackResourcesWithContext(ctx context.Context, stackName, nextToken string) (
 	resp *ListStackResourcesResponse, err error) {
 	params := makeParams("ListStackResources")
 
@@ -633,12 +753,33 @@ func (c *CloudFormation) ListStackResources(stackName, nextToken string) (
 	}
 
 	resp = new(ListStackResourcesResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListStackResourcesPages returns a Pager over the pages of ListStackResources results,
+// starting with the page of nextToken.
+func (c *CloudFormation) ListStackResourcesPages(stackName, nextToken string) *aws.Pager[*ListStackResourcesResponse] {
+	return aws.NewPager(func(ctx context.Context) (*ListStackResourcesResponse, bool, error) {
+		resp, err := c.ListStackResourcesWithContext(ctx, stackName, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListStackResourcesAll returns the summaries of every resource of the stack, walking every page of ListStackResources
+// results.
+func (c *CloudFormation) ListStackResourcesAll(ctx context.Context, stackName string) ([]StackResourceSummary, error) {
+	return aws.All(ctx, c.ListStackResourcesPages(stackName, ""), func(resp *ListStackResourcesResponse) []StackResourceSummary {
+		return resp.StackResourceSummaries
+	})
+}
+
 // StackSummary encapsulates the StackSummary data type
 //
 // See http://goo.gl/35j3wf for more details
@@ -669,6 +810,12 @@ type ListStacksResponse struct {
 //
 // See http://goo.gl/UWi6nm for more details
 func (c *CloudFormation) ListStacks(stackStatusFilters []string, nextToken string) (
+	resp *ListStacksResponse, err error) {
+	return c.ListStacksWithContext(context.Background(), stackStatusFilters, nextToken)
+}
+
+// ListStacksWithContext is like ListStacks but makes its requests with ctx.
+func (c *CloudFormation) ListStacksWithContext(ctx context.Context, stackStatusFilters []string, nextToken string) (
 	resp *ListStacksResponse, err error) {
 	params := makeParams("ListStacks")
 
@@ -681,18 +828,46 @@ func (c *CloudFormation) ListStacks(stackStatusFilters []string, nextToken strin
 	}
 
 	resp = new(ListStacksResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListStacksPages returns a Pager over the pages of ListStacks results,
+// starting with the page of nextToken.
+func (c *CloudFormation) ListStacksPages(stackStatusFilters []string, nextToken string) *aws.Pager[*ListStacksResponse] {
+	return aws.NewPager(func(ctx context.Context) (*ListStacksResponse, bool, error) {
+		resp, err := c.ListStacksWithContext(ctx, stackStatusFilters, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListStacksAll returns the summaries of every stack whose status matches
+// stackStatusFilters, walking every page of ListStacks
+// results.
+func (c *CloudFormation) ListStacksAll(ctx context.Context, stackStatusFilters []string) ([]StackSummary, error) {
+	return aws.All(ctx, c.ListStacksPages(stackStatusFilters, ""), func(resp *ListStacksResponse) []StackSummary {
+		return resp.StackSummaries
+	})
+}
+
 // SetStackPolicy sets a stack policy for a specified stack.
 //
 // Required Params: stackName
 //
 // See http://goo.gl/iY9ohu for more information
 func (c *CloudFormation) SetStackPolicy(stackName, stackPolicyBody, stackPolicyUrl string) (
+	resp *SimpleResp, err error) {
+	return c.SetStackPolicyWithContext(context.Background(), stackName, stackPolicyBody, stackPolicyUrl)
+}
+
+// SetStackPolicyWithContext is like SetStackPolicy but makes its requests with ctx.
+func (c *CloudFormation) SetStackPolicyWithContext(ctx context.Context, stackName, stackPolicyBody, stackPolicyUrl string) (
 	resp *SimpleResp, err error) {
 	params := makeParams("SetStackPolicy")
 
@@ -706,7 +881,7 @@ func (c *CloudFormation) SetStackPolicy(stackName, stackPolicyBody, stackPolicyU
 	}
 
 	resp = new(SimpleResp)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -745,6 +920,12 @@ type UpdateStackResponse struct {
 //
 // See http://goo.gl/LvkhZq for more information
 func (c *CloudFormation) UpdateStack(options *UpdateStackParams) (
+	resp *UpdateStackResponse, err error) {
+	return c.UpdateStackWithContext(context.Background(), options)
+}
+
+// UpdateStackWithContext is like UpdateStack but makes its requests with ctx.
+func (c *CloudFormation) UpdateStackWithContext(ctx context.Context, options *UpdateStackParams) (
 	resp *UpdateStackResponse, err error) {
 	params := makeParams("UpdateStack")
 
@@ -788,7 +969,7 @@ func (c *CloudFormation) UpdateStack(options *UpdateStackParams) (
 	}
 
 	resp = new(UpdateStackResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -819,6 +1000,12 @@ type ValidateTemplateResponse struct {
 //
 // See http://goo.gl/OBhNzk for more information
 func (c *CloudFormation) ValidateTemplate(templateBody, templateUrl string) (
+	resp *ValidateTemplateResponse, err error) {
+	return c.ValidateTemplateWithContext(context.Background(), templateBody, templateUrl)
+}
+
+// ValidateTemplateWithContext is like ValidateTemplate but makes its requests with ctx.
+func (c *CloudFormation) ValidateTemplateWithContext(ctx context.Context, templateBody, templateUrl string) (
 	resp *ValidateTemplateResponse, err error) {
 	params := makeParams("ValidateTemplate")
 
@@ -830,7 +1017,7 @@ func (c *CloudFormation) ValidateTemplate(templateBody, templateUrl string) (
 	}
 
 	resp = new(ValidateTemplateResponse)
-	if err := c.query(params, resp); err != nil {
+	if err := c.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -1,6 +1,8 @@
 package cloudformation_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -242,6 +244,20 @@ func (s *S) TestDescribeStackEvents(c *C) {
 	c.Assert(resp, DeepEquals, expected)
 }
 
+func (s *S) TestDescribeStackEventsAll(c *C) {
+	testServer.Response(200, nil, strings.Replace(DescribeStackEventsResponse, "<NextToken/>", "<NextToken>page-2</NextToken>", 1))
+	testServer.Response(200, nil, DescribeStackEventsResponse)
+
+	events, err := s.cf.DescribeStackEventsAll(context.Background(), "MyStack")
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 6)
+
+	reqs := testServer.WaitRequests(2)
+	c.Assert(reqs[0].PostForm.Get("NextToken"), Equals, "")
+	c.Assert(reqs[1].PostForm.Get("NextToken"), Equals, "page-2")
+	c.Assert(reqs[1].PostForm.Get("StackName"), Equals, "MyStack")
+}
+
 func (s *S) TestDescribeStackResource(c *C) {
 	testServer.Response(200, nil, DescribeStackResourceResponse)
 
@@ -16,6 +16,7 @@
 package cloudwatch
 
 import (
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -187,11 +188,11 @@ func NewCloudWatch(auth aws.Auth, region aws.ServiceInfo) (*CloudWatch, error) {
 	}, nil
 }
 
-func (c *CloudWatch) query(method, path string, params map[string]string, resp interface{}) error {
+func (c *CloudWatch) query(ctx context.Context, method, path string, params map[string]string, resp interface{}) error {
 	// Add basic Cloudwatch param
 	params["Version"] = "2010-08-01"
 
-	r, err := c.Service.Query(method, path, params)
+	r, err := aws.QueryContext(ctx, c.Service, method, path, params)
 	if err != nil {
 		return err
 	}
@@ -209,6 +210,11 @@ func (c *CloudWatch) query(method, path string, params map[string]string, resp i
 // If the arguments are invalid or the server returns an error, the error will
 // be set and the other values undefined.
 func (c *CloudWatch) GetMetricStatistics(req *GetMetricStatisticsRequest) (result *GetMetricStatisticsResponse, err error) {
+	return c.GetMetricStatisticsWithContext(context.Background(), req)
+}
+
+// GetMetricStatisticsWithContext is like GetMetricStatistics but makes its requests with ctx.
+func (c *CloudWatch) GetMetricStatisticsWithContext(ctx context.Context, req *GetMetricStatisticsRequest) (result *GetMetricStatisticsResponse, err error) {
 	statisticsSet := sets.SSet(req.Statistics...)
 	// Kick out argument errors
 	switch {
@@ -255,7 +261,7 @@ func (c *CloudWatch) GetMetricStatistics(req *GetMetricStatisticsRequest) (resul
 		params[prefix] = d
 	}
 	result = new(GetMetricStatisticsResponse)
-	err = c.query("GET", "/", params, result)
+	err = c.query(ctx, "GET", "/", params, result)
 	return
 }
 
@@ -263,6 +269,11 @@ func (c *CloudWatch) GetMetricStatistics(req *GetMetricStatisticsRequest) (resul
 // Returned metrics can be used with GetMetricStatistics to obtain statistical data for a given metric.
 
 func (c *CloudWatch) ListMetrics(req *ListMetricsRequest) (result *ListMetricsResponse, err error) {
+	return c.ListMetricsWithContext(context.Background(), req)
+}
+
+// ListMetricsWithContext is like ListMetrics but makes its requests with ctx.
+func (c *CloudWatch) ListMetricsWithContext(ctx context.Context, req *ListMetricsRequest) (result *ListMetricsResponse, err error) {
 
 	// Serialize all the params
 	params := aws.MakeParams("ListMetrics")
@@ -278,14 +289,14 @@ func (c *CloudWatch) ListMetrics(req *ListMetricsRequest) (result *ListMetricsRe
 	}
 
 	result = new(ListMetricsResponse)
-	err = c.query("GET", "/", params, &result)
+	err = c.query(ctx, "GET", "/", params, &result)
 	metrics := result.ListMetricsResult.Metrics
 	if result.ListMetricsResult.NextToken != "" {
 		params = aws.MakeParams("ListMetrics")
 		params["NextToken"] = result.ListMetricsResult.NextToken
 		for result.ListMetricsResult.NextToken != "" && err == nil {
 			result = new(ListMetricsResponse)
-			err = c.query("GET", "/", params, &result)
+			err = c.query(ctx, "GET", "/", params, &result)
 			if err == nil {
 				newslice := make([]Metric, len(metrics)+len(result.ListMetricsResult.Metrics))
 				copy(newslice, metrics)
@@ -299,10 +310,20 @@ func (c *CloudWatch) ListMetrics(req *ListMetricsRequest) (result *ListMetricsRe
 }
 
 func (c *CloudWatch) PutMetricData(metrics []MetricDatum) (result *aws.BaseResponse, err error) {
-	return c.PutMetricDataNamespace(metrics, "")
+	return c.PutMetricDataWithContext(context.Background(), metrics)
+}
+
+// PutMetricDataWithContext is like PutMetricData but makes its requests with ctx.
+func (c *CloudWatch) PutMetricDataWithContext(ctx context.Context, metrics []MetricDatum) (result *aws.BaseResponse, err error) {
+	return c.PutMetricDataNamespaceWithContext(ctx, metrics, "")
 }
 
 func (c *CloudWatch) PutMetricDataNamespace(metrics []MetricDatum, namespace string) (result *aws.BaseResponse, err error) {
+	return c.PutMetricDataNamespaceWithContext(context.Background(), metrics, namespace)
+}
+
+// PutMetricDataNamespaceWithContext is like PutMetricDataNamespace but makes its requests with ctx.
+func (c *CloudWatch) PutMetricDataNamespaceWithContext(ctx context.Context, metrics []MetricDatum, namespace string) (result *aws.BaseResponse, err error) {
 	// Serialize the params
 	params := aws.MakeParams("PutMetricData")
 	if namespace != "" {
@@ -336,11 +357,16 @@ func (c *CloudWatch) PutMetricDataNamespace(metrics []MetricDatum, namespace str
 		}
 	}
 	result = new(aws.BaseResponse)
-	err = c.query("POST", "/", params, result)
+	err = c.query(ctx, "POST", "/", params, result)
 	return
 }
 
 func (c *CloudWatch) PutMetricAlarm(alarm *MetricAlarm) (result *aws.BaseResponse, err error) {
+	return c.PutMetricAlarmWithContext(context.Background(), alarm)
+}
+
+// PutMetricAlarmWithContext is like PutMetricAlarm but makes its requests with ctx.
+func (c *CloudWatch) PutMetricAlarmWithContext(ctx context.Context, alarm *MetricAlarm) (result *aws.BaseResponse, err error) {
 	// Serialize the params
 	params := aws.MakeParams("PutMetricAlarm")
 
@@ -399,6 +425,6 @@ func (c *CloudWatch) PutMetricAlarm(alarm *MetricAlarm) (result *aws.BaseRespons
 	}
 
 	result = new(aws.BaseResponse)
-	err = c.query("POST", "/", params, result)
+	err = c.query(ctx, "POST", "/", params, result)
 	return
 }
@@ -1,19 +1,22 @@
 package dynamodb
 
+import "context"
 import simplejson "github.com/bitly/go-simplejson"
 import (
-	"errors"
 	"github.com/hughe/goamz/aws"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"strings"
-	"time"
 )
 
 type Server struct {
 	Auth   aws.Auth
 	Region aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 /*
@@ -31,7 +34,14 @@ func NewQuery(queryParts []string) *Query {
 */
 
 // Specific error constants
-var ErrNotFound = errors.New("Item not found")
+var ErrNotFound error = notFoundError("Item not found")
+
+// notFoundError is the type of ErrNotFound, which matches aws.ErrNotFound
+// with errors.Is, so that aws.IsNotFound holds for it.
+type notFoundError string
+
+func (e notFoundError) Error() string        { return string(e) }
+func (e notFoundError) Is(target error) bool { return target == aws.ErrNotFound }
 
 // Error represents an error in an operation with Dynamodb (following goamz/s3)
 type Error struct {
@@ -39,17 +49,30 @@ type Error struct {
 	Status     string
 	Code       string // Dynamodb error code ("MalformedQueryString", ...)
 	Message    string // The human-oriented error message
+	RequestId  string // The x-amzn-RequestId of the response
 }
 
 func (e *Error) Error() string {
 	return e.Code + ": " + e.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (e *Error) ErrorCode() string    { return e.Code }
+func (e *Error) ErrorMessage() string { return e.Message }
+func (e *Error) HTTPStatusCode() int  { return e.StatusCode }
+func (e *Error) RequestID() string    { return e.RequestId }
+
+// Is reports whether e is of the class target, such as aws.ErrNotFound.
+func (e *Error) Is(target error) bool { return aws.ErrorIs(e, target) }
+
 func buildError(r *http.Response, jsonBody []byte) error {
 
 	ddbError := Error{
 		StatusCode: r.StatusCode,
 		Status:     r.Status,
+		RequestId:  r.Header.Get("X-Amzn-Requestid"),
 	}
 	// TODO return error if Unmarshal fails?
 
@@ -72,47 +95,55 @@ func buildError(r *http.Response, jsonBody []byte) error {
 	return &ddbError
 }
 
-func (s *Server) queryServer(target string, query *Query) ([]byte, error) {
-	data := strings.NewReader(query.String())
-	hreq, err := http.NewRequest("POST", s.Region.DynamoDBEndpoint+"/", data)
+func (s *Server) queryServer(ctx context.Context, target string, query *Query) ([]byte, error) {
+	data := query.String()
+	endpoint, err := s.Region.ResolveEndpoint("dynamodb")
 	if err != nil {
 		return nil, err
 	}
-
-	hreq.Header.Set("Content-Type", "application/x-amz-json-1.0")
-	hreq.Header.Set("X-Amz-Date", time.Now().UTC().Format(aws.ISO8601BasicFormat))
-	hreq.Header.Set("X-Amz-Target", target)
-
-	token := s.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
+	var body []byte
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "dynamodb",
+		Region:      s.Region.Name,
+		Operation:   target[strings.LastIndex(target, ".")+1:],
+		Data:        &body,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: s.RateLimiter,
 	}
-
-	signer := aws.NewV4Signer(s.Auth, "dynamodb", s.Region)
-	signer.Sign(hreq)
-
-	resp, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		log.Printf("Error calling Amazon")
-		return nil, err
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("POST", endpoint.URL+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-amz-json-1.0")
+			r.HTTPRequest.Header.Set("X-Amz-Target", target)
+			return nil
+		},
+		Sign: aws.NewV4Signer(s.Auth, "dynamodb", s.Region).SignHandler(),
+		Unmarshal: func(r *aws.Request) error {
+			body, err := ioutil.ReadAll(r.HTTPResponse.Body)
+			if err != nil {
+				log.Printf("Could not read response body")
+				return err
+			}
+
+			// http://docs.aws.amazon.com/amazondynamodb/latest/developerguide/ErrorHandling.html
+			// "A response code of 200 indicates the operation was successful."
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse, body)
+			}
+			*r.Data.(*[]byte) = body
+			return nil
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Could not read response body")
 		return nil, err
 	}
-
-	// http://docs.aws.amazon.com/amazondynamodb/latest/developerguide/ErrorHandling.html
-	// "A response code of 200 indicates the operation was successful."
-	if resp.StatusCode != 200 {
-		ddbErr := buildError(resp, body)
-		return nil, ddbErr
-	}
-
 	return body, nil
 }
 
@@ -1,7 +1,10 @@
 package dynamodb_test
 
 import (
+	"errors"
 	"flag"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -149,6 +152,27 @@ func setUpAuth(c *C) {
 	}
 }
 
+type ErrorSuite struct{}
+
+var _ = Suite(&ErrorSuite{})
+
+func (s *ErrorSuite) TestGetItemNotFound(c *C) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	server := &dynamodb.Server{
+		Auth:   aws.Auth{AccessKey: "access", SecretKey: "secret"},
+		Region: aws.Region{Name: "us-east-1", DynamoDBEndpoint: ts.URL},
+	}
+	table := server.NewTable("items", dynamodb.PrimaryKey{KeyAttribute: dynamodb.NewStringAttribute("id", "")})
+	_, err := table.GetItem(&dynamodb.Key{HashKey: "missing"})
+	c.Assert(err, Equals, dynamodb.ErrNotFound)
+	c.Assert(errors.Is(err, aws.ErrNotFound), Equals, true)
+	c.Assert(aws.IsNotFound(err), Equals, true)
+}
+
 func findTableByName(tables []string, name string) bool {
 	for _, t := range tables {
 		if t == name {
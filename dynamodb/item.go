@@ -1,5 +1,6 @@
 package dynamodb
 
+import "context"
 import simplejson "github.com/bitly/go-simplejson"
 import (
 	"errors"
@@ -42,10 +43,15 @@ func (batchWriteItem *BatchWriteItem) AddTable(t *Table, itemActions *map[string
 }
 
 func (batchGetItem *BatchGetItem) Execute() (map[string][]map[string]*Attribute, error) {
+	return batchGetItem.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext is like Execute but makes its requests with ctx.
+func (batchGetItem *BatchGetItem) ExecuteWithContext(ctx context.Context) (map[string][]map[string]*Attribute, error) {
 	q := NewEmptyQuery()
 	q.AddGetRequestItems(batchGetItem.Keys)
 
-	jsonResponse, err := batchGetItem.Server.queryServer("DynamoDB_20120810.BatchGetItem", q)
+	jsonResponse, err := batchGetItem.Server.queryServer(ctx, "DynamoDB_20120810.BatchGetItem", q)
 	if err != nil {
 		return nil, err
 	}
@@ -91,10 +97,15 @@ func (batchGetItem *BatchGetItem) Execute() (map[string][]map[string]*Attribute,
 }
 
 func (batchWriteItem *BatchWriteItem) Execute() (map[string]interface{}, error) {
+	return batchWriteItem.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext is like Execute but makes its requests with ctx.
+func (batchWriteItem *BatchWriteItem) ExecuteWithContext(ctx context.Context) (map[string]interface{}, error) {
 	q := NewEmptyQuery()
 	q.AddWriteRequestItems(batchWriteItem.ItemActions)
 
-	jsonResponse, err := batchWriteItem.Server.queryServer("DynamoDB_20120810.BatchWriteItem", q)
+	jsonResponse, err := batchWriteItem.Server.queryServer(ctx, "DynamoDB_20120810.BatchWriteItem", q)
 
 	if err != nil {
 		return nil, err
@@ -121,14 +132,24 @@ func (batchWriteItem *BatchWriteItem) Execute() (map[string]interface{}, error)
 }
 
 func (t *Table) GetItem(key *Key) (map[string]*Attribute, error) {
-	return t.getItem(key, false)
+	return t.GetItemWithContext(context.Background(), key)
+}
+
+// GetItemWithContext is like GetItem but makes its requests with ctx.
+func (t *Table) GetItemWithContext(ctx context.Context, key *Key) (map[string]*Attribute, error) {
+	return t.getItem(ctx, key, false)
 }
 
 func (t *Table) GetItemConsistent(key *Key, consistentRead bool) (map[string]*Attribute, error) {
-	return t.getItem(key, consistentRead)
+	return t.GetItemConsistentWithContext(context.Background(), key, consistentRead)
+}
+
+// GetItemConsistentWithContext is like GetItemConsistent but makes its requests with ctx.
+func (t *Table) GetItemConsistentWithContext(ctx context.Context, key *Key, consistentRead bool) (map[string]*Attribute, error) {
+	return t.getItem(ctx, key, consistentRead)
 }
 
-func (t *Table) getItem(key *Key, consistentRead bool) (map[string]*Attribute, error) {
+func (t *Table) getItem(ctx context.Context, key *Key, consistentRead bool) (map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKey(t, key)
 
@@ -136,7 +157,7 @@ func (t *Table) getItem(key *Key, consistentRead bool) (map[string]*Attribute, e
 		q.ConsistentRead(consistentRead)
 	}
 
-	jsonResponse, err := t.Server.queryServer(target("GetItem"), q)
+	jsonResponse, err := t.Server.queryServer(ctx, target("GetItem"), q)
 	if err != nil {
 		return nil, err
 	}
@@ -163,14 +184,24 @@ func (t *Table) getItem(key *Key, consistentRead bool) (map[string]*Attribute, e
 }
 
 func (t *Table) PutItem(hashKey string, rangeKey string, attributes []Attribute) (bool, error) {
-	return t.putItem(hashKey, rangeKey, attributes, nil)
+	return t.PutItemWithContext(context.Background(), hashKey, rangeKey, attributes)
+}
+
+// PutItemWithContext is like PutItem but makes its requests with ctx.
+func (t *Table) PutItemWithContext(ctx context.Context, hashKey string, rangeKey string, attributes []Attribute) (bool, error) {
+	return t.putItem(ctx, hashKey, rangeKey, attributes, nil)
 }
 
 func (t *Table) ConditionalPutItem(hashKey, rangeKey string, attributes, expected []Attribute) (bool, error) {
-	return t.putItem(hashKey, rangeKey, attributes, expected)
+	return t.ConditionalPutItemWithContext(context.Background(), hashKey, rangeKey, attributes, expected)
 }
 
-func (t *Table) putItem(hashKey, rangeKey string, attributes, expected []Attribute) (bool, error) {
+// ConditionalPutItemWithContext is like ConditionalPutItem but makes its requests with ctx.
+func (t *Table) ConditionalPutItemWithContext(ctx context.Context, hashKey, rangeKey string, attributes, expected []Attribute) (bool, error) {
+	return t.putItem(ctx, hashKey, rangeKey, attributes, expected)
+}
+
+func (t *Table) putItem(ctx context.Context, hashKey, rangeKey string, attributes, expected []Attribute) (bool, error) {
 	if len(attributes) == 0 {
 		return false, errors.New("At least one attribute is required.")
 	}
@@ -185,7 +216,7 @@ func (t *Table) putItem(hashKey, rangeKey string, attributes, expected []Attribu
 		q.AddExpected(expected)
 	}
 
-	jsonResponse, err := t.Server.queryServer(target("PutItem"), q)
+	jsonResponse, err := t.Server.queryServer(ctx, target("PutItem"), q)
 
 	if err != nil {
 		return false, err
@@ -199,7 +230,7 @@ func (t *Table) putItem(hashKey, rangeKey string, attributes, expected []Attribu
 	return true, nil
 }
 
-func (t *Table) deleteItem(key *Key, expected []Attribute) (bool, error) {
+func (t *Table) deleteItem(ctx context.Context, key *Key, expected []Attribute) (bool, error) {
 	q := NewQuery(t)
 	q.AddKey(t, key)
 
@@ -207,7 +238,7 @@ func (t *Table) deleteItem(key *Key, expected []Attribute) (bool, error) {
 		q.AddExpected(expected)
 	}
 
-	jsonResponse, err := t.Server.queryServer(target("DeleteItem"), q)
+	jsonResponse, err := t.Server.queryServer(ctx, target("DeleteItem"), q)
 
 	if err != nil {
 		return false, err
@@ -222,38 +253,78 @@ func (t *Table) deleteItem(key *Key, expected []Attribute) (bool, error) {
 }
 
 func (t *Table) DeleteItem(key *Key) (bool, error) {
-	return t.deleteItem(key, nil)
+	return t.DeleteItemWithContext(context.Background(), key)
+}
+
+// DeleteItemWithContext is like DeleteItem but makes its requests with ctx.
+func (t *Table) DeleteItemWithContext(ctx context.Context, key *Key) (bool, error) {
+	return t.deleteItem(ctx, key, nil)
 }
 
 func (t *Table) ConditionalDeleteItem(key *Key, expected []Attribute) (bool, error) {
-	return t.deleteItem(key, expected)
+	return t.ConditionalDeleteItemWithContext(context.Background(), key, expected)
+}
+
+// ConditionalDeleteItemWithContext is like ConditionalDeleteItem but makes its requests with ctx.
+func (t *Table) ConditionalDeleteItemWithContext(ctx context.Context, key *Key, expected []Attribute) (bool, error) {
+	return t.deleteItem(ctx, key, expected)
 }
 
 func (t *Table) AddAttributes(key *Key, attributes []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, nil, "ADD")
+	return t.AddAttributesWithContext(context.Background(), key, attributes)
+}
+
+// AddAttributesWithContext is like AddAttributes but makes its requests with ctx.
+func (t *Table) AddAttributesWithContext(ctx context.Context, key *Key, attributes []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, nil, "ADD")
 }
 
 func (t *Table) UpdateAttributes(key *Key, attributes []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, nil, "PUT")
+	return t.UpdateAttributesWithContext(context.Background(), key, attributes)
+}
+
+// UpdateAttributesWithContext is like UpdateAttributes but makes its requests with ctx.
+func (t *Table) UpdateAttributesWithContext(ctx context.Context, key *Key, attributes []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, nil, "PUT")
 }
 
 func (t *Table) DeleteAttributes(key *Key, attributes []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, nil, "DELETE")
+	return t.DeleteAttributesWithContext(context.Background(), key, attributes)
+}
+
+// DeleteAttributesWithContext is like DeleteAttributes but makes its requests with ctx.
+func (t *Table) DeleteAttributesWithContext(ctx context.Context, key *Key, attributes []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, nil, "DELETE")
 }
 
 func (t *Table) ConditionalAddAttributes(key *Key, attributes, expected []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, expected, "ADD")
+	return t.ConditionalAddAttributesWithContext(context.Background(), key, attributes, expected)
+}
+
+// ConditionalAddAttributesWithContext is like ConditionalAddAttributes but makes its requests with ctx.
+func (t *Table) ConditionalAddAttributesWithContext(ctx context.Context, key *Key, attributes, expected []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, expected, "ADD")
 }
 
 func (t *Table) ConditionalUpdateAttributes(key *Key, attributes, expected []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, expected, "PUT")
+	return t.ConditionalUpdateAttributesWithContext(context.Background(), key, attributes, expected)
+}
+
+// ConditionalUpdateAttributesWithContext is like ConditionalUpdateAttributes but makes its requests with ctx.
+func (t *Table) ConditionalUpdateAttributesWithContext(ctx context.Context, key *Key, attributes, expected []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, expected, "PUT")
 }
 
 func (t *Table) ConditionalDeleteAttributes(key *Key, attributes, expected []Attribute) (bool, error) {
-	return t.modifyAttributes(key, attributes, expected, "DELETE")
+	return t.ConditionalDeleteAttributesWithContext(context.Background(), key, attributes, expected)
+}
+
+// ConditionalDeleteAttributesWithContext is like ConditionalDeleteAttributes but makes its requests with ctx.
+func (t *Table) ConditionalDeleteAttributesWithContext(ctx context.Context, key *Key, attributes, expected []Attribute) (bool, error) {
+	return t.modifyAttributes(ctx, key, attributes, expected, "DELETE")
 }
 
-func (t *Table) modifyAttributes(key *Key, attributes, expected []Attribute, action string) (bool, error) {
+func (t *Table) modifyAttributes(ctx context.Context, key *Key, attributes, expected []Attribute, action string) (bool, error) {
 
 	if len(attributes) == 0 {
 		return false, errors.New("At least one attribute is required.")
@@ -267,7 +338,7 @@ func (t *Table) modifyAttributes(key *Key, attributes, expected []Attribute, act
 		q.AddExpected(expected)
 	}
 
-	jsonResponse, err := t.Server.queryServer(target("UpdateItem"), q)
+	jsonResponse, err := t.Server.queryServer(ctx, target("UpdateItem"), q)
 
 	if err != nil {
 		return false, err
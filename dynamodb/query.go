@@ -1,69 +1,110 @@
 package dynamodb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	simplejson "github.com/bitly/go-simplejson"
 )
 
 func (t *Table) Query(attributeComparisons []AttributeComparison) ([]map[string]*Attribute, error) {
+	return t.QueryWithContext(context.Background(), attributeComparisons)
+}
+
+// QueryWithContext is like Query but makes its requests with ctx.
+func (t *Table) QueryWithContext(ctx context.Context, attributeComparisons []AttributeComparison) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) QueryOnIndex(attributeComparisons []AttributeComparison, indexName string) ([]map[string]*Attribute, error) {
+	return t.QueryOnIndexWithContext(context.Background(), attributeComparisons, indexName)
+}
+
+// QueryOnIndexWithContext is like QueryOnIndex but makes its requests with ctx.
+func (t *Table) QueryOnIndexWithContext(ctx context.Context, attributeComparisons []AttributeComparison, indexName string) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddIndex(indexName)
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) QueryOnIndexDescending(attributeComparisons []AttributeComparison, indexName string) ([]map[string]*Attribute, error) {
+	return t.QueryOnIndexDescendingWithContext(context.Background(), attributeComparisons, indexName)
+}
+
+// QueryOnIndexDescendingWithContext is like QueryOnIndexDescending but makes its requests with ctx.
+func (t *Table) QueryOnIndexDescendingWithContext(ctx context.Context, attributeComparisons []AttributeComparison, indexName string) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddIndex(indexName)
 	q.ScanIndexDescending()
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) LimitedQuery(attributeComparisons []AttributeComparison, limit int64) ([]map[string]*Attribute, error) {
+	return t.LimitedQueryWithContext(context.Background(), attributeComparisons, limit)
+}
+
+// LimitedQueryWithContext is like LimitedQuery but makes its requests with ctx.
+func (t *Table) LimitedQueryWithContext(ctx context.Context, attributeComparisons []AttributeComparison, limit int64) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddLimit(limit)
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) LimitedQueryOnIndex(attributeComparisons []AttributeComparison, indexName string, limit int64) ([]map[string]*Attribute, error) {
+	return t.LimitedQueryOnIndexWithContext(context.Background(), attributeComparisons, indexName, limit)
+}
+
+// LimitedQueryOnIndexWithContext is like LimitedQueryOnIndex but makes its requests with ctx.
+func (t *Table) LimitedQueryOnIndexWithContext(ctx context.Context, attributeComparisons []AttributeComparison, indexName string, limit int64) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddIndex(indexName)
 	q.AddLimit(limit)
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) LimitedQueryDescending(attributeComparisons []AttributeComparison, limit int64) ([]map[string]*Attribute, error) {
+	return t.LimitedQueryDescendingWithContext(context.Background(), attributeComparisons, limit)
+}
+
+// LimitedQueryDescendingWithContext is like LimitedQueryDescending but makes its requests with ctx.
+func (t *Table) LimitedQueryDescendingWithContext(ctx context.Context, attributeComparisons []AttributeComparison, limit int64) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddLimit(limit)
 	q.ScanIndexDescending()
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) LimitedQueryOnIndexDescending(attributeComparisons []AttributeComparison, indexName string, limit int64) ([]map[string]*Attribute, error) {
+	return t.LimitedQueryOnIndexDescendingWithContext(context.Background(), attributeComparisons, indexName, limit)
+}
+
+// LimitedQueryOnIndexDescendingWithContext is like LimitedQueryOnIndexDescending but makes its requests with ctx.
+func (t *Table) LimitedQueryOnIndexDescendingWithContext(ctx context.Context, attributeComparisons []AttributeComparison, indexName string, limit int64) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddIndex(indexName)
 	q.AddLimit(limit)
 	q.ScanIndexDescending()
-	return runQuery(q, t)
+	return runQuery(ctx, q, t)
 }
 
 func (t *Table) CountQuery(attributeComparisons []AttributeComparison) (int64, error) {
+	return t.CountQueryWithContext(context.Background(), attributeComparisons)
+}
+
+// CountQueryWithContext is like CountQuery but makes its requests with ctx.
+func (t *Table) CountQueryWithContext(ctx context.Context, attributeComparisons []AttributeComparison) (int64, error) {
 	q := NewQuery(t)
 	q.AddKeyConditions(attributeComparisons)
 	q.AddSelect("COUNT")
-	jsonResponse, err := t.Server.queryServer("DynamoDB_20120810.Query", q)
+	jsonResponse, err := t.Server.queryServer(ctx, "DynamoDB_20120810.Query", q)
 	if err != nil {
 		return 0, err
 	}
@@ -80,8 +121,8 @@ func (t *Table) CountQuery(attributeComparisons []AttributeComparison) (int64, e
 	return itemCount, nil
 }
 
-func runQuery(q *Query, t *Table) ([]map[string]*Attribute, error) {
-	jsonResponse, err := t.Server.queryServer("DynamoDB_20120810.Query", q)
+func runQuery(ctx context.Context, q *Query, t *Table) ([]map[string]*Attribute, error) {
+	jsonResponse, err := t.Server.queryServer(ctx, "DynamoDB_20120810.Query", q)
 	if err != nil {
 		return nil, err
 	}
@@ -15,7 +15,7 @@ var _ = Suite(&QueryBuilderSuite{})
 
 func (s *QueryBuilderSuite) SetUpSuite(c *C) {
 	auth := &aws.Auth{AccessKey: "", SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
-	s.server = &dynamodb.Server{*auth, aws.USEast}
+	s.server = &dynamodb.Server{Auth: *auth, Region: aws.USEast}
 }
 
 func (s *QueryBuilderSuite) TestEmptyQuery(c *C) {
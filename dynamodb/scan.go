@@ -1,13 +1,19 @@
 package dynamodb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	simplejson "github.com/bitly/go-simplejson"
 )
 
 func (t *Table) FetchResults(query *Query) ([]map[string]*Attribute, error) {
-	jsonResponse, err := t.Server.queryServer(target("Scan"), query)
+	return t.FetchResultsWithContext(context.Background(), query)
+}
+
+// FetchResultsWithContext is like FetchResults but makes its requests with ctx.
+func (t *Table) FetchResultsWithContext(ctx context.Context, query *Query) ([]map[string]*Attribute, error) {
+	jsonResponse, err := t.Server.queryServer(ctx, target("Scan"), query)
 	if err != nil {
 		return nil, err
 	}
@@ -38,14 +44,24 @@ func (t *Table) FetchResults(query *Query) ([]map[string]*Attribute, error) {
 }
 
 func (t *Table) Scan(attributeComparisons []AttributeComparison) ([]map[string]*Attribute, error) {
+	return t.ScanWithContext(context.Background(), attributeComparisons)
+}
+
+// ScanWithContext is like Scan but makes its requests with ctx.
+func (t *Table) ScanWithContext(ctx context.Context, attributeComparisons []AttributeComparison) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddScanFilter(attributeComparisons)
-	return t.FetchResults(q)
+	return t.FetchResultsWithContext(ctx, q)
 }
 
 func (t *Table) ParallelScan(attributeComparisons []AttributeComparison, segment int, totalSegments int) ([]map[string]*Attribute, error) {
+	return t.ParallelScanWithContext(context.Background(), attributeComparisons, segment, totalSegments)
+}
+
+// ParallelScanWithContext is like ParallelScan but makes its requests with ctx.
+func (t *Table) ParallelScanWithContext(ctx context.Context, attributeComparisons []AttributeComparison, segment int, totalSegments int) ([]map[string]*Attribute, error) {
 	q := NewQuery(t)
 	q.AddScanFilter(attributeComparisons)
 	q.AddParallelScanConfiguration(segment, totalSegments)
-	return t.FetchResults(q)
+	return t.FetchResultsWithContext(ctx, q)
 }
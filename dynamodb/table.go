@@ -1,6 +1,7 @@
 package dynamodb
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -116,11 +117,16 @@ func (s *Server) NewTable(name string, key PrimaryKey) *Table {
 }
 
 func (s *Server) ListTables() ([]string, error) {
+	return s.ListTablesWithContext(context.Background())
+}
+
+// ListTablesWithContext is like ListTables but makes its requests with ctx.
+func (s *Server) ListTablesWithContext(ctx context.Context) ([]string, error) {
 	var tables []string
 
 	query := NewEmptyQuery()
 
-	jsonResponse, err := s.queryServer(target("ListTables"), query)
+	jsonResponse, err := s.queryServer(ctx, target("ListTables"), query)
 
 	if err != nil {
 		return nil, err
@@ -149,10 +155,15 @@ func (s *Server) ListTables() ([]string, error) {
 }
 
 func (s *Server) CreateTable(tableDescription TableDescriptionT) (string, error) {
+	return s.CreateTableWithContext(context.Background(), tableDescription)
+}
+
+// CreateTableWithContext is like CreateTable but makes its requests with ctx.
+func (s *Server) CreateTableWithContext(ctx context.Context, tableDescription TableDescriptionT) (string, error) {
 	query := NewEmptyQuery()
 	query.AddCreateRequestTable(tableDescription)
 
-	jsonResponse, err := s.queryServer(target("CreateTable"), query)
+	jsonResponse, err := s.queryServer(ctx, target("CreateTable"), query)
 
 	if err != nil {
 		return "unknown", err
@@ -168,10 +179,15 @@ func (s *Server) CreateTable(tableDescription TableDescriptionT) (string, error)
 }
 
 func (s *Server) DeleteTable(tableDescription TableDescriptionT) (string, error) {
+	return s.DeleteTableWithContext(context.Background(), tableDescription)
+}
+
+// DeleteTableWithContext is like DeleteTable but makes its requests with ctx.
+func (s *Server) DeleteTableWithContext(ctx context.Context, tableDescription TableDescriptionT) (string, error) {
 	query := NewEmptyQuery()
 	query.AddDeleteRequestTable(tableDescription)
 
-	jsonResponse, err := s.queryServer(target("DeleteTable"), query)
+	jsonResponse, err := s.queryServer(ctx, target("DeleteTable"), query)
 
 	if err != nil {
 		return "unknown", err
@@ -187,14 +203,24 @@ func (s *Server) DeleteTable(tableDescription TableDescriptionT) (string, error)
 }
 
 func (t *Table) DescribeTable() (*TableDescriptionT, error) {
-	return t.Server.DescribeTable(t.Name)
+	return t.DescribeTableWithContext(context.Background())
+}
+
+// DescribeTableWithContext is like DescribeTable but makes its requests with ctx.
+func (t *Table) DescribeTableWithContext(ctx context.Context) (*TableDescriptionT, error) {
+	return t.Server.DescribeTableWithContext(ctx, t.Name)
 }
 
 func (s *Server) DescribeTable(name string) (*TableDescriptionT, error) {
+	return s.DescribeTableWithContext(context.Background(), name)
+}
+
+// DescribeTableWithContext is like DescribeTable but makes its requests with ctx.
+func (s *Server) DescribeTableWithContext(ctx context.Context, name string) (*TableDescriptionT, error) {
 	q := NewEmptyQuery()
 	q.addTableByName(name)
 
-	jsonResponse, err := s.queryServer(target("DescribeTable"), q)
+	jsonResponse, err := s.queryServer(ctx, target("DescribeTable"), q)
 	if err != nil {
 		return nil, err
 	}
@@ -209,10 +235,15 @@ func (s *Server) DescribeTable(name string) (*TableDescriptionT, error) {
 }
 
 func (s *Server) UpdateTable(tableDescription TableDescriptionT) (string, error) {
+	return s.UpdateTableWithContext(context.Background(), tableDescription)
+}
+
+// UpdateTableWithContext is like UpdateTable but makes its requests with ctx.
+func (s *Server) UpdateTableWithContext(ctx context.Context, tableDescription TableDescriptionT) (string, error) {
 	query := NewEmptyQuery()
 	query.AddUpdateRequestTable(tableDescription)
 
-	jsonResponse, err := s.queryServer(target("UpdateTable"), query)
+	jsonResponse, err := s.queryServer(ctx, target("UpdateTable"), query)
 
 	if err != nil {
 		return "unknown", err
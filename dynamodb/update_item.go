@@ -1,5 +1,6 @@
 package dynamodb
 
+import "context"
 import simplejson "github.com/bitly/go-simplejson"
 
 /*
@@ -69,7 +70,12 @@ func (u *UpdateItem) ConditionExpression(expression string) *UpdateItem {
 
 // Execute this query.
 func (u *UpdateItem) Execute() (*UpdateResult, error) {
-	jsonResponse, err := u.table.Server.queryServer(target("UpdateItem"), u.query)
+	return u.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext is like Execute but makes its requests with ctx.
+func (u *UpdateItem) ExecuteWithContext(ctx context.Context) (*UpdateResult, error) {
+	jsonResponse, err := u.table.Server.queryServer(ctx, target("UpdateItem"), u.query)
 
 	if err != nil {
 		return nil, err
@@ -11,6 +11,7 @@
 package ec2
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/xml"
@@ -34,12 +35,17 @@ type EC2 struct {
 	aws.Auth
 	aws.Region
 	httpClient *http.Client
-	private    byte // Reserve the right of using private data.
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
+	private byte // Reserve the right of using private data.
 }
 
 // NewWithClient creates a new EC2 with a custom http client
 func NewWithClient(auth aws.Auth, region aws.Region, client *http.Client) *EC2 {
-	return &EC2{auth, region, client, 0}
+	return &EC2{auth, region.WithEndpoints(), client, nil, 0}
 }
 
 // New creates a new EC2.
@@ -115,6 +121,17 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 // For now a single error inst is being exposed. In the future it may be useful
 // to provide access to all of them, but rather than doing it as an array/slice,
 // use a *next pointer, so that it's backward compatible and it continues to be
@@ -124,11 +141,10 @@ type xmlErrors struct {
 	Errors    []Error `xml:"Errors>Error"`
 }
 
-var timeNow = time.Now
+var timeNow = aws.Now
 
-func (ec2 *EC2) query(params map[string]string, resp interface{}) error {
+func (ec2 *EC2) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	params["Version"] = "2014-02-01"
-	params["Timestamp"] = timeNow().In(time.UTC).Format(time.RFC3339)
 	endpoint, err := url.Parse(ec2.Region.EC2Endpoint)
 	if err != nil {
 		return err
@@ -136,26 +152,48 @@ func (ec2 *EC2) query(params map[string]string, resp interface{}) error {
 	if endpoint.Path == "" {
 		endpoint.Path = "/"
 	}
-	sign(ec2.Auth, "GET", endpoint.Path, params, endpoint.Host)
-	endpoint.RawQuery = multimap(params).Encode()
-	if debug {
-		log.Printf("get { %v } -> {\n", endpoint.String())
-	}
-	r, err := ec2.httpClient.Get(endpoint.String())
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
-	}
-	if r.StatusCode != 200 {
-		return buildError(r)
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "ec2",
+		Region:      ec2.Region.Name,
+		Operation:   params["Action"],
+		Client:      ec2.httpClient,
+		RateLimiter: ec2.RateLimiter,
+		Data:        resp,
+	}
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", endpoint.String(), nil)
+			return err
+		},
+		Sign: func(r *aws.Request) error {
+			signed := make(map[string]string, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = timeNow().In(time.UTC).Format(time.RFC3339)
+			sign(ec2.Auth.Current(), "GET", endpoint.Path, signed, endpoint.Host)
+			r.HTTPRequest.URL.RawQuery = multimap(signed).Encode()
+			if debug {
+				log.Printf("get { %v } -> {\n", r.HTTPRequest.URL)
+			}
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -411,6 +449,11 @@ type IamInstanceProfile struct {
 //
 // See http://goo.gl/Mcm3b for more details.
 func (ec2 *EC2) RunInstances(options *RunInstancesOptions) (resp *RunInstancesResp, err error) {
+	return ec2.RunInstancesWithContext(context.Background(), options)
+}
+
+// RunInstancesWithContext is like RunInstances but makes its requests with ctx.
+func (ec2 *EC2) RunInstancesWithContext(ctx context.Context, options *RunInstancesOptions) (resp *RunInstancesResp, err error) {
 	params := makeParams("RunInstances")
 	params["ImageId"] = options.ImageId
 	params["InstanceType"] = options.InstanceType
@@ -517,7 +560,7 @@ func (ec2 *EC2) RunInstances(options *RunInstancesOptions) (resp *RunInstancesRe
 	addBlockDeviceParams(params, options.BlockDevices)
 
 	resp = &RunInstancesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -571,10 +614,15 @@ type InstanceStateReason struct {
 //
 // See http://goo.gl/3BKHj for more details.
 func (ec2 *EC2) TerminateInstances(instIds []string) (resp *TerminateInstancesResp, err error) {
+	return ec2.TerminateInstancesWithContext(context.Background(), instIds)
+}
+
+// TerminateInstancesWithContext is like TerminateInstances but makes its requests with ctx.
+func (ec2 *EC2) TerminateInstancesWithContext(ctx context.Context, instIds []string) (resp *TerminateInstancesResp, err error) {
 	params := makeParams("TerminateInstances")
 	addParamsList(params, "InstanceId", instIds)
 	resp = &TerminateInstancesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -606,11 +654,16 @@ type Reservation struct {
 //
 // See http://goo.gl/4No7c for more details.
 func (ec2 *EC2) DescribeInstances(instIds []string, filter *Filter) (resp *DescribeInstancesResp, err error) {
+	return ec2.DescribeInstancesWithContext(context.Background(), instIds, filter)
+}
+
+// DescribeInstancesWithContext is like DescribeInstances but makes its requests with ctx.
+func (ec2 *EC2) DescribeInstancesWithContext(ctx context.Context, instIds []string, filter *Filter) (resp *DescribeInstancesResp, err error) {
 	params := makeParams("DescribeInstances")
 	addParamsList(params, "InstanceId", instIds)
 	filter.addParams(params)
 	resp = &DescribeInstancesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -692,6 +745,11 @@ type InstanceStatusDetails struct {
 //
 // See http://goo.gl/2FBTdS for more details.
 func (ec2 *EC2) DescribeInstanceStatus(options *DescribeInstanceStatusOptions, filter *Filter) (resp *DescribeInstanceStatusResp, err error) {
+	return ec2.DescribeInstanceStatusWithContext(context.Background(), options, filter)
+}
+
+// DescribeInstanceStatusWithContext is like DescribeInstanceStatus but makes its requests with ctx.
+func (ec2 *EC2) DescribeInstanceStatusWithContext(ctx context.Context, options *DescribeInstanceStatusOptions, filter *Filter) (resp *DescribeInstanceStatusResp, err error) {
 	params := makeParams("DescribeInstanceStatus")
 	if len(options.InstanceIds) > 0 {
 		addParamsList(params, "InstanceId", options.InstanceIds)
@@ -707,13 +765,40 @@ func (ec2 *EC2) DescribeInstanceStatus(options *DescribeInstanceStatusOptions, f
 	}
 	filter.addParams(params)
 	resp = &DescribeInstanceStatusResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
+// DescribeInstanceStatusPages returns a Pager over the pages of
+// DescribeInstanceStatus results, starting with the page of
+// options.NextToken.
+func (ec2 *EC2) DescribeInstanceStatusPages(options *DescribeInstanceStatusOptions, filter *Filter) *aws.Pager[*DescribeInstanceStatusResp] {
+	var next DescribeInstanceStatusOptions
+	if options != nil {
+		next = *options
+	}
+	return aws.NewPager(func(ctx context.Context) (*DescribeInstanceStatusResp, bool, error) {
+		resp, err := ec2.DescribeInstanceStatusWithContext(ctx, &next, filter)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// DescribeInstanceStatusAll returns the status of every instance matching
+// options and filter, walking every page of DescribeInstanceStatus
+// results.
+func (ec2 *EC2) DescribeInstanceStatusAll(ctx context.Context, options *DescribeInstanceStatusOptions, filter *Filter) ([]InstanceStatusItem, error) {
+	return aws.All(ctx, ec2.DescribeInstanceStatusPages(options, filter), func(resp *DescribeInstanceStatusResp) []InstanceStatusItem {
+		return resp.InstanceStatusSet
+	})
+}
+
 // ----------------------------------------------------------------------------
 // KeyPair management functions and types.
 
@@ -728,11 +813,16 @@ type CreateKeyPairResp struct {
 //
 // See http://goo.gl/0S6hV
 func (ec2 *EC2) CreateKeyPair(keyName string) (resp *CreateKeyPairResp, err error) {
+	return ec2.CreateKeyPairWithContext(context.Background(), keyName)
+}
+
+// CreateKeyPairWithContext is like CreateKeyPair but makes its requests with ctx.
+func (ec2 *EC2) CreateKeyPairWithContext(ctx context.Context, keyName string) (resp *CreateKeyPairResp, err error) {
 	params := makeParams("CreateKeyPair")
 	params["KeyName"] = keyName
 
 	resp = &CreateKeyPairResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err == nil {
 		resp.KeyFingerprint = strings.TrimSpace(resp.KeyFingerprint)
 	}
@@ -743,11 +833,16 @@ func (ec2 *EC2) CreateKeyPair(keyName string) (resp *CreateKeyPairResp, err erro
 //
 // See http://goo.gl/0bqok
 func (ec2 *EC2) DeleteKeyPair(name string) (resp *SimpleResp, err error) {
+	return ec2.DeleteKeyPairWithContext(context.Background(), name)
+}
+
+// DeleteKeyPairWithContext is like DeleteKeyPair but makes its requests with ctx.
+func (ec2 *EC2) DeleteKeyPairWithContext(ctx context.Context, name string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteKeyPair")
 	params["KeyName"] = name
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	return
 }
 
@@ -765,6 +860,11 @@ type Tag struct {
 //
 // See http://goo.gl/Vmkqc for more details
 func (ec2 *EC2) CreateTags(resourceIds []string, tags []Tag) (resp *SimpleResp, err error) {
+	return ec2.CreateTagsWithContext(context.Background(), resourceIds, tags)
+}
+
+// CreateTagsWithContext is like CreateTags but makes its requests with ctx.
+func (ec2 *EC2) CreateTagsWithContext(ctx context.Context, resourceIds []string, tags []Tag) (resp *SimpleResp, err error) {
 	params := makeParams("CreateTags")
 	addParamsList(params, "ResourceId", resourceIds)
 
@@ -774,7 +874,7 @@ func (ec2 *EC2) CreateTags(resourceIds []string, tags []Tag) (resp *SimpleResp,
 	}
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -801,10 +901,15 @@ type StopInstanceResp struct {
 //
 // See http://goo.gl/awKeF for more details.
 func (ec2 *EC2) StartInstances(ids ...string) (resp *StartInstanceResp, err error) {
+	return ec2.StartInstancesWithContext(context.Background(), ids...)
+}
+
+// StartInstancesWithContext is like StartInstances but makes its requests with ctx.
+func (ec2 *EC2) StartInstancesWithContext(ctx context.Context, ids ...string) (resp *StartInstanceResp, err error) {
 	params := makeParams("StartInstances")
 	addParamsList(params, "InstanceId", ids)
 	resp = &StartInstanceResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -815,10 +920,15 @@ func (ec2 *EC2) StartInstances(ids ...string) (resp *StartInstanceResp, err erro
 //
 // See http://goo.gl/436dJ for more details.
 func (ec2 *EC2) StopInstances(ids ...string) (resp *StopInstanceResp, err error) {
+	return ec2.StopInstancesWithContext(context.Background(), ids...)
+}
+
+// StopInstancesWithContext is like StopInstances but makes its requests with ctx.
+func (ec2 *EC2) StopInstancesWithContext(ctx context.Context, ids ...string) (resp *StopInstanceResp, err error) {
 	params := makeParams("StopInstances")
 	addParamsList(params, "InstanceId", ids)
 	resp = &StopInstanceResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -833,10 +943,15 @@ func (ec2 *EC2) StopInstances(ids ...string) (resp *StopInstanceResp, err error)
 //
 // See http://goo.gl/baoUf for more details.
 func (ec2 *EC2) RebootInstances(ids ...string) (resp *SimpleResp, err error) {
+	return ec2.RebootInstancesWithContext(context.Background(), ids...)
+}
+
+// RebootInstancesWithContext is like RebootInstances but makes its requests with ctx.
+func (ec2 *EC2) RebootInstancesWithContext(ctx context.Context, ids ...string) (resp *SimpleResp, err error) {
 	params := makeParams("RebootInstances")
 	addParamsList(params, "InstanceId", ids)
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -872,6 +987,11 @@ type ModifyInstanceResp struct {
 //
 // See http://goo.gl/icuXh5 for more details.
 func (ec2 *EC2) ModifyInstance(instId string, options *ModifyInstance) (resp *ModifyInstanceResp, err error) {
+	return ec2.ModifyInstanceWithContext(context.Background(), instId, options)
+}
+
+// ModifyInstanceWithContext is like ModifyInstance but makes its requests with ctx.
+func (ec2 *EC2) ModifyInstanceWithContext(ctx context.Context, instId string, options *ModifyInstance) (resp *ModifyInstanceResp, err error) {
 	params := makeParams("ModifyInstanceAttribute")
 	params["InstanceId"] = instId
 	addBlockDeviceParams(params, options.BlockDevices)
@@ -923,7 +1043,7 @@ func (ec2 *EC2) ModifyInstance(instId string, options *ModifyInstance) (resp *Mo
 	}
 
 	resp = &ModifyInstanceResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		resp = nil
 	}
@@ -977,6 +1097,11 @@ type RecurringCharge struct {
 //
 // See
 func (ec2 *EC2) DescribeReservedInstances(instIds []string, filter *Filter) (resp *DescribeReservedInstancesResponse, err error) {
+	return ec2.DescribeReservedInstancesWithContext(context.Background(), instIds, filter)
+}
+
+// DescribeReservedInstancesWithContext is like DescribeReservedInstances but makes its requests with ctx.
+func (ec2 *EC2) DescribeReservedInstancesWithContext(ctx context.Context, instIds []string, filter *Filter) (resp *DescribeReservedInstancesResponse, err error) {
 	params := makeParams("DescribeReservedInstances")
 
 	for i, id := range instIds {
@@ -985,7 +1110,7 @@ func (ec2 *EC2) DescribeReservedInstances(instIds []string, filter *Filter) (res
 	filter.addParams(params)
 
 	resp = &DescribeReservedInstancesResponse{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1141,6 +1266,11 @@ type CopyImageResp struct {
 //
 // See http://goo.gl/cxU41 for more details.
 func (ec2 *EC2) CreateImage(options *CreateImage) (resp *CreateImageResp, err error) {
+	return ec2.CreateImageWithContext(context.Background(), options)
+}
+
+// CreateImageWithContext is like CreateImage but makes its requests with ctx.
+func (ec2 *EC2) CreateImageWithContext(ctx context.Context, options *CreateImage) (resp *CreateImageResp, err error) {
 	params := makeParams("CreateImage")
 	params["InstanceId"] = options.InstanceId
 	params["Name"] = options.Name
@@ -1153,7 +1283,7 @@ func (ec2 *EC2) CreateImage(options *CreateImage) (resp *CreateImageResp, err er
 	addBlockDeviceParams(params, options.BlockDevices)
 
 	resp = &CreateImageResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1172,6 +1302,11 @@ func (ec2 *EC2) CreateImage(options *CreateImage) (resp *CreateImageResp, err er
 //
 // See http://goo.gl/SRBhW for more details.
 func (ec2 *EC2) Images(ids []string, filter *Filter) (resp *ImagesResp, err error) {
+	return ec2.ImagesWithContext(context.Background(), ids, filter)
+}
+
+// ImagesWithContext is like Images but makes its requests with ctx.
+func (ec2 *EC2) ImagesWithContext(ctx context.Context, ids []string, filter *Filter) (resp *ImagesResp, err error) {
 	params := makeParams("DescribeImages")
 	for i, id := range ids {
 		params["ImageId."+strconv.Itoa(i+1)] = id
@@ -1179,7 +1314,7 @@ func (ec2 *EC2) Images(ids []string, filter *Filter) (resp *ImagesResp, err erro
 	filter.addParams(params)
 
 	resp = &ImagesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1197,6 +1332,11 @@ func (ec2 *EC2) Images(ids []string, filter *Filter) (resp *ImagesResp, err erro
 //
 // See http://goo.gl/SRBhW for more details.
 func (ec2 *EC2) ImagesByOwners(ids []string, owners []string, filter *Filter) (resp *ImagesResp, err error) {
+	return ec2.ImagesByOwnersWithContext(context.Background(), ids, owners, filter)
+}
+
+// ImagesByOwnersWithContext is like ImagesByOwners but makes its requests with ctx.
+func (ec2 *EC2) ImagesByOwnersWithContext(ctx context.Context, ids []string, owners []string, filter *Filter) (resp *ImagesResp, err error) {
 	params := makeParams("DescribeImages")
 	for i, id := range ids {
 		params["ImageId."+strconv.Itoa(i+1)] = id
@@ -1208,7 +1348,7 @@ func (ec2 *EC2) ImagesByOwners(ids []string, owners []string, filter *Filter) (r
 	filter.addParams(params)
 
 	resp = &ImagesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1222,12 +1362,17 @@ func (ec2 *EC2) ImagesByOwners(ids []string, owners []string, filter *Filter) (r
 //
 // See http://goo.gl/bHO3zT for more details.
 func (ec2 *EC2) ImageAttribute(imageId, attribute string) (resp *ImageAttributeResp, err error) {
+	return ec2.ImageAttributeWithContext(context.Background(), imageId, attribute)
+}
+
+// ImageAttributeWithContext is like ImageAttribute but makes its requests with ctx.
+func (ec2 *EC2) ImageAttributeWithContext(ctx context.Context, imageId, attribute string) (resp *ImageAttributeResp, err error) {
 	params := makeParams("DescribeImageAttribute")
 	params["ImageId"] = imageId
 	params["Attribute"] = attribute
 
 	resp = &ImageAttributeResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1238,6 +1383,11 @@ func (ec2 *EC2) ImageAttribute(imageId, attribute string) (resp *ImageAttributeR
 //
 // See http://goo.gl/YUjO4G for more details.
 func (ec2 *EC2) ModifyImageAttribute(imageId string, options *ModifyImageAttribute) (resp *SimpleResp, err error) {
+	return ec2.ModifyImageAttributeWithContext(context.Background(), imageId, options)
+}
+
+// ModifyImageAttributeWithContext is like ModifyImageAttribute but makes its requests with ctx.
+func (ec2 *EC2) ModifyImageAttributeWithContext(ctx context.Context, imageId string, options *ModifyImageAttribute) (resp *SimpleResp, err error) {
 	params := makeParams("ModifyImageAttribute")
 	params["ImageId"] = imageId
 	if options.Description != "" {
@@ -1277,7 +1427,7 @@ func (ec2 *EC2) ModifyImageAttribute(imageId string, options *ModifyImageAttribu
 	}
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		resp = nil
 	}
@@ -1289,6 +1439,11 @@ func (ec2 *EC2) ModifyImageAttribute(imageId string, options *ModifyImageAttribu
 //
 // See: http://docs.aws.amazon.com/AWSEC2/latest/APIReference/ApiReference-query-RegisterImage.html
 func (ec2 *EC2) RegisterImage(options *RegisterImage) (resp *RegisterImageResp, err error) {
+	return ec2.RegisterImageWithContext(context.Background(), options)
+}
+
+// RegisterImageWithContext is like RegisterImage but makes its requests with ctx.
+func (ec2 *EC2) RegisterImageWithContext(ctx context.Context, options *RegisterImage) (resp *RegisterImageResp, err error) {
 	params := makeParams("RegisterImage")
 	params["Name"] = options.Name
 	if options.ImageLocation != "" {
@@ -1322,7 +1477,7 @@ func (ec2 *EC2) RegisterImage(options *RegisterImage) (resp *RegisterImageResp,
 	addBlockDeviceParams(params, options.BlockDevices)
 
 	resp = &RegisterImageResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1334,11 +1489,16 @@ func (ec2 *EC2) RegisterImage(options *RegisterImage) (resp *RegisterImageResp,
 //
 // See http://docs.aws.amazon.com/AWSEC2/latest/APIReference/ApiReference-query-DeregisterImage.html
 func (ec2 *EC2) DeregisterImage(imageId string) (resp *DeregisterImageResp, err error) {
+	return ec2.DeregisterImageWithContext(context.Background(), imageId)
+}
+
+// DeregisterImageWithContext is like DeregisterImage but makes its requests with ctx.
+func (ec2 *EC2) DeregisterImageWithContext(ctx context.Context, imageId string) (resp *DeregisterImageResp, err error) {
 	params := makeParams("DeregisterImage")
 	params["ImageId"] = imageId
 
 	resp = &DeregisterImageResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1350,6 +1510,11 @@ func (ec2 *EC2) DeregisterImage(imageId string) (resp *DeregisterImageResp, err
 //
 // See http://goo.gl/hQwPCK for more details.
 func (ec2 *EC2) CopyImage(options *CopyImage) (resp *CopyImageResp, err error) {
+	return ec2.CopyImageWithContext(context.Background(), options)
+}
+
+// CopyImageWithContext is like CopyImage but makes its requests with ctx.
+func (ec2 *EC2) CopyImageWithContext(ctx context.Context, options *CopyImage) (resp *CopyImageResp, err error) {
 	params := makeParams("CopyImage")
 
 	if options.SourceRegion != "" {
@@ -1373,7 +1538,7 @@ func (ec2 *EC2) CopyImage(options *CopyImage) (resp *CopyImageResp, err error) {
 	}
 
 	resp = &CopyImageResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1393,12 +1558,17 @@ type CreateSnapshotResp struct {
 //
 // See http://goo.gl/ttcda for more details.
 func (ec2 *EC2) CreateSnapshot(volumeId, description string) (resp *CreateSnapshotResp, err error) {
+	return ec2.CreateSnapshotWithContext(context.Background(), volumeId, description)
+}
+
+// CreateSnapshotWithContext is like CreateSnapshot but makes its requests with ctx.
+func (ec2 *EC2) CreateSnapshotWithContext(ctx context.Context, volumeId, description string) (resp *CreateSnapshotResp, err error) {
 	params := makeParams("CreateSnapshot")
 	params["VolumeId"] = volumeId
 	params["Description"] = description
 
 	resp = &CreateSnapshotResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1416,13 +1586,18 @@ func (ec2 *EC2) CreateSnapshot(volumeId, description string) (resp *CreateSnapsh
 //
 // See http://goo.gl/vwU1y for more details.
 func (ec2 *EC2) DeleteSnapshots(ids []string) (resp *SimpleResp, err error) {
+	return ec2.DeleteSnapshotsWithContext(context.Background(), ids)
+}
+
+// DeleteSnapshotsWithContext is like DeleteSnapshots but makes its requests with ctx.
+func (ec2 *EC2) DeleteSnapshotsWithContext(ctx context.Context, ids []string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteSnapshot")
 	for i, id := range ids {
 		params["SnapshotId."+strconv.Itoa(i+1)] = id
 	}
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1459,6 +1634,11 @@ type Snapshot struct {
 //
 // See http://goo.gl/ogJL4 for more details.
 func (ec2 *EC2) Snapshots(ids []string, filter *Filter) (resp *SnapshotsResp, err error) {
+	return ec2.SnapshotsWithContext(context.Background(), ids, filter)
+}
+
+// SnapshotsWithContext is like Snapshots but makes its requests with ctx.
+func (ec2 *EC2) SnapshotsWithContext(ctx context.Context, ids []string, filter *Filter) (resp *SnapshotsResp, err error) {
 	params := makeParams("DescribeSnapshots")
 	for i, id := range ids {
 		params["SnapshotId."+strconv.Itoa(i+1)] = id
@@ -1466,7 +1646,7 @@ func (ec2 *EC2) Snapshots(ids []string, filter *Filter) (resp *SnapshotsResp, er
 	filter.addParams(params)
 
 	resp = &SnapshotsResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1538,13 +1718,18 @@ type VolumesResp struct {
 
 // Attach a volume.
 func (ec2 *EC2) AttachVolume(volumeId string, instanceId string, device string) (resp *AttachVolumeResp, err error) {
+	return ec2.AttachVolumeWithContext(context.Background(), volumeId, instanceId, device)
+}
+
+// AttachVolumeWithContext is like AttachVolume but makes its requests with ctx.
+func (ec2 *EC2) AttachVolumeWithContext(ctx context.Context, volumeId string, instanceId string, device string) (resp *AttachVolumeResp, err error) {
 	params := makeParams("AttachVolume")
 	params["VolumeId"] = volumeId
 	params["InstanceId"] = instanceId
 	params["Device"] = device
 
 	resp = &AttachVolumeResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1554,6 +1739,11 @@ func (ec2 *EC2) AttachVolume(volumeId string, instanceId string, device string)
 
 // Create a new volume.
 func (ec2 *EC2) CreateVolume(options *CreateVolume) (resp *CreateVolumeResp, err error) {
+	return ec2.CreateVolumeWithContext(context.Background(), options)
+}
+
+// CreateVolumeWithContext is like CreateVolume but makes its requests with ctx.
+func (ec2 *EC2) CreateVolumeWithContext(ctx context.Context, options *CreateVolume) (resp *CreateVolumeResp, err error) {
 	params := makeParams("CreateVolume")
 	params["AvailabilityZone"] = options.AvailZone
 	if options.Size > 0 {
@@ -1573,7 +1763,7 @@ func (ec2 *EC2) CreateVolume(options *CreateVolume) (resp *CreateVolumeResp, err
 	}
 
 	resp = &CreateVolumeResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1582,11 +1772,16 @@ func (ec2 *EC2) CreateVolume(options *CreateVolume) (resp *CreateVolumeResp, err
 
 // Delete an EBS volume.
 func (ec2 *EC2) DeleteVolume(id string) (resp *SimpleResp, err error) {
+	return ec2.DeleteVolumeWithContext(context.Background(), id)
+}
+
+// DeleteVolumeWithContext is like DeleteVolume but makes its requests with ctx.
+func (ec2 *EC2) DeleteVolumeWithContext(ctx context.Context, id string) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteVolume")
 	params["VolumeId"] = id
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1595,11 +1790,16 @@ func (ec2 *EC2) DeleteVolume(id string) (resp *SimpleResp, err error) {
 
 // Detaches an EBS volume.
 func (ec2 *EC2) DetachVolume(id string) (resp *SimpleResp, err error) {
+	return ec2.DetachVolumeWithContext(context.Background(), id)
+}
+
+// DetachVolumeWithContext is like DetachVolume but makes its requests with ctx.
+func (ec2 *EC2) DetachVolumeWithContext(ctx context.Context, id string) (resp *SimpleResp, err error) {
 	params := makeParams("DetachVolume")
 	params["VolumeId"] = id
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1608,11 +1808,16 @@ func (ec2 *EC2) DetachVolume(id string) (resp *SimpleResp, err error) {
 
 // Finds or lists all volumes.
 func (ec2 *EC2) Volumes(volIds []string, filter *Filter) (resp *VolumesResp, err error) {
+	return ec2.VolumesWithContext(context.Background(), volIds, filter)
+}
+
+// VolumesWithContext is like Volumes but makes its requests with ctx.
+func (ec2 *EC2) VolumesWithContext(ctx context.Context, volIds []string, filter *Filter) (resp *VolumesResp, err error) {
 	params := makeParams("DescribeVolumes")
 	addParamsList(params, "VolumeId", volIds)
 	filter.addParams(params)
 	resp = &VolumesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1640,6 +1845,11 @@ type CreateSecurityGroupResp struct {
 //
 // See http://goo.gl/Eo7Yl for more details.
 func (ec2 *EC2) CreateSecurityGroup(group SecurityGroup) (resp *CreateSecurityGroupResp, err error) {
+	return ec2.CreateSecurityGroupWithContext(context.Background(), group)
+}
+
+// CreateSecurityGroupWithContext is like CreateSecurityGroup but makes its requests with ctx.
+func (ec2 *EC2) CreateSecurityGroupWithContext(ctx context.Context, group SecurityGroup) (resp *CreateSecurityGroupResp, err error) {
 	params := makeParams("CreateSecurityGroup")
 	params["GroupName"] = group.Name
 	params["GroupDescription"] = group.Description
@@ -1648,7 +1858,7 @@ func (ec2 *EC2) CreateSecurityGroup(group SecurityGroup) (resp *CreateSecurityGr
 	}
 
 	resp = &CreateSecurityGroupResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1731,6 +1941,11 @@ func SecurityGroupIds(ids ...string) []SecurityGroup {
 //
 // See http://goo.gl/k12Uy for more details.
 func (ec2 *EC2) SecurityGroups(groups []SecurityGroup, filter *Filter) (resp *SecurityGroupsResp, err error) {
+	return ec2.SecurityGroupsWithContext(context.Background(), groups, filter)
+}
+
+// SecurityGroupsWithContext is like SecurityGroups but makes its requests with ctx.
+func (ec2 *EC2) SecurityGroupsWithContext(ctx context.Context, groups []SecurityGroup, filter *Filter) (resp *SecurityGroupsResp, err error) {
 	params := makeParams("DescribeSecurityGroups")
 	i, j := 1, 1
 	for _, g := range groups {
@@ -1745,7 +1960,7 @@ func (ec2 *EC2) SecurityGroups(groups []SecurityGroup, filter *Filter) (resp *Se
 	filter.addParams(params)
 
 	resp = &SecurityGroupsResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1756,6 +1971,11 @@ func (ec2 *EC2) SecurityGroups(groups []SecurityGroup, filter *Filter) (resp *Se
 //
 // See http://goo.gl/QJJDO for more details.
 func (ec2 *EC2) DeleteSecurityGroup(group SecurityGroup) (resp *SimpleResp, err error) {
+	return ec2.DeleteSecurityGroupWithContext(context.Background(), group)
+}
+
+// DeleteSecurityGroupWithContext is like DeleteSecurityGroup but makes its requests with ctx.
+func (ec2 *EC2) DeleteSecurityGroupWithContext(ctx context.Context, group SecurityGroup) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteSecurityGroup")
 	if group.Id != "" {
 		params["GroupId"] = group.Id
@@ -1764,7 +1984,7 @@ func (ec2 *EC2) DeleteSecurityGroup(group SecurityGroup) (resp *SimpleResp, err
 	}
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1776,14 +1996,24 @@ func (ec2 *EC2) DeleteSecurityGroup(group SecurityGroup) (resp *SimpleResp, err
 //
 // See http://goo.gl/u2sDJ for more details.
 func (ec2 *EC2) AuthorizeSecurityGroup(group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
-	return ec2.authOrRevoke("AuthorizeSecurityGroupIngress", group, perms)
+	return ec2.AuthorizeSecurityGroupWithContext(context.Background(), group, perms)
+}
+
+// AuthorizeSecurityGroupWithContext is like AuthorizeSecurityGroup but makes its requests with ctx.
+func (ec2 *EC2) AuthorizeSecurityGroupWithContext(ctx context.Context, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
+	return ec2.authOrRevoke(ctx, "AuthorizeSecurityGroupIngress", group, perms)
 }
 
 // RevokeSecurityGroup revokes permissions from a group.
 //
 // See http://goo.gl/ZgdxA for more details.
 func (ec2 *EC2) RevokeSecurityGroup(group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
-	return ec2.authOrRevoke("RevokeSecurityGroupIngress", group, perms)
+	return ec2.RevokeSecurityGroupWithContext(context.Background(), group, perms)
+}
+
+// RevokeSecurityGroupWithContext is like RevokeSecurityGroup but makes its requests with ctx.
+func (ec2 *EC2) RevokeSecurityGroupWithContext(ctx context.Context, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
+	return ec2.authOrRevoke(ctx, "RevokeSecurityGroupIngress", group, perms)
 }
 
 // AuthorizeSecurityGroupEgress creates an allowance for instances within the
@@ -1791,17 +2021,27 @@ func (ec2 *EC2) RevokeSecurityGroup(group SecurityGroup, perms []IPPerm) (resp *
 //
 // See http://goo.gl/R91LXY for more details.
 func (ec2 *EC2) AuthorizeSecurityGroupEgress(group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
-	return ec2.authOrRevoke("AuthorizeSecurityGroupEgress", group, perms)
+	return ec2.AuthorizeSecurityGroupEgressWithContext(context.Background(), group, perms)
+}
+
+// AuthorizeSecurityGroupEgressWithContext is like AuthorizeSecurityGroupEgress but makes its requests with ctx.
+func (ec2 *EC2) AuthorizeSecurityGroupEgressWithContext(ctx context.Context, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
+	return ec2.authOrRevoke(ctx, "AuthorizeSecurityGroupEgress", group, perms)
 }
 
 // RevokeSecurityGroupEgress revokes egress permissions from a group
 //
 // see http://goo.gl/Zv4wh8
 func (ec2 *EC2) RevokeSecurityGroupEgress(group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
-	return ec2.authOrRevoke("RevokeSecurityGroupEgress", group, perms)
+	return ec2.RevokeSecurityGroupEgressWithContext(context.Background(), group, perms)
 }
 
-func (ec2 *EC2) authOrRevoke(op string, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
+// RevokeSecurityGroupEgressWithContext is like RevokeSecurityGroupEgress but makes its requests with ctx.
+func (ec2 *EC2) RevokeSecurityGroupEgressWithContext(ctx context.Context, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
+	return ec2.authOrRevoke(ctx, "RevokeSecurityGroupEgress", group, perms)
+}
+
+func (ec2 *EC2) authOrRevoke(ctx context.Context, op string, group SecurityGroup, perms []IPPerm) (resp *SimpleResp, err error) {
 	params := makeParams(op)
 	if group.Id != "" {
 		params["GroupId"] = group.Id
@@ -1831,7 +2071,7 @@ func (ec2 *EC2) authOrRevoke(op string, group SecurityGroup, perms []IPPerm) (re
 	}
 
 	resp = &SimpleResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1868,12 +2108,17 @@ type Address struct {
 //
 // See http://goo.gl/zW7J4p for more details.
 func (ec2 *EC2) DescribeAddresses(publicIps []string, allocationIds []string, filter *Filter) (resp *DescribeAddressesResp, err error) {
+	return ec2.DescribeAddressesWithContext(context.Background(), publicIps, allocationIds, filter)
+}
+
+// DescribeAddressesWithContext is like DescribeAddresses but makes its requests with ctx.
+func (ec2 *EC2) DescribeAddressesWithContext(ctx context.Context, publicIps []string, allocationIds []string, filter *Filter) (resp *DescribeAddressesResp, err error) {
 	params := makeParams("DescribeAddresses")
 	addParamsList(params, "PublicIp", publicIps)
 	addParamsList(params, "AllocationId", allocationIds)
 	filter.addParams(params)
 	resp = &DescribeAddressesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1903,10 +2148,15 @@ type AllocateAddressResp struct {
 //
 // See http://goo.gl/aLPmbm for more details
 func (ec2 *EC2) AllocateAddress(options *AllocateAddressOptions) (resp *AllocateAddressResp, err error) {
+	return ec2.AllocateAddressWithContext(context.Background(), options)
+}
+
+// AllocateAddressWithContext is like AllocateAddress but makes its requests with ctx.
+func (ec2 *EC2) AllocateAddressWithContext(ctx context.Context, options *AllocateAddressOptions) (resp *AllocateAddressResp, err error) {
 	params := makeParams("AllocateAddress")
 	params["Domain"] = options.Domain
 	resp = &AllocateAddressResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1927,6 +2177,11 @@ type ReleaseAddressResp struct {
 //
 // See http://goo.gl/Ciw2Z8 for more details
 func (ec2 *EC2) ReleaseAddress(publicIp, allocationId string) (resp *ReleaseAddressResp, err error) {
+	return ec2.ReleaseAddressWithContext(context.Background(), publicIp, allocationId)
+}
+
+// ReleaseAddressWithContext is like ReleaseAddress but makes its requests with ctx.
+func (ec2 *EC2) ReleaseAddressWithContext(ctx context.Context, publicIp, allocationId string) (resp *ReleaseAddressResp, err error) {
 	params := makeParams("ReleaseAddress")
 
 	if publicIp != "" {
@@ -1938,7 +2193,7 @@ func (ec2 *EC2) ReleaseAddress(publicIp, allocationId string) (resp *ReleaseAddr
 	}
 
 	resp = &ReleaseAddressResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1970,6 +2225,11 @@ type AssociateAddressResp struct {
 //
 // See http://goo.gl/hhj4z7 for more details
 func (ec2 *EC2) AssociateAddress(options *AssociateAddressOptions) (resp *AssociateAddressResp, err error) {
+	return ec2.AssociateAddressWithContext(context.Background(), options)
+}
+
+// AssociateAddressWithContext is like AssociateAddress but makes its requests with ctx.
+func (ec2 *EC2) AssociateAddressWithContext(ctx context.Context, options *AssociateAddressOptions) (resp *AssociateAddressResp, err error) {
 	params := makeParams("AssociateAddress")
 	params["InstanceId"] = options.InstanceId
 	if options.PublicIp != "" {
@@ -1989,7 +2249,7 @@ func (ec2 *EC2) AssociateAddress(options *AssociateAddressOptions) (resp *Associ
 	}
 
 	resp = &AssociateAddressResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -2009,6 +2269,11 @@ type DisassociateAddressResp struct {
 // AssociationId - Required for VPC
 // See http://goo.gl/Dapkuz for more details
 func (ec2 *EC2) DisassociateAddress(publicIp, associationId string) (resp *DisassociateAddressResp, err error) {
+	return ec2.DisassociateAddressWithContext(context.Background(), publicIp, associationId)
+}
+
+// DisassociateAddressWithContext is like DisassociateAddress but makes its requests with ctx.
+func (ec2 *EC2) DisassociateAddressWithContext(ctx context.Context, publicIp, associationId string) (resp *DisassociateAddressResp, err error) {
 	params := makeParams("DisassociateAddress")
 	if publicIp != "" {
 		params["PublicIp"] = publicIp
@@ -2018,7 +2283,7 @@ func (ec2 *EC2) DisassociateAddress(publicIp, associationId string) (resp *Disas
 	}
 
 	resp = &DisassociateAddressResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,10 @@
 package ec2_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/ec2"
@@ -168,6 +171,31 @@ func (s *S) TestRunInstancesExample(c *C) {
 	c.Assert(i2.Hypervisor, Equals, "xen")
 }
 
+func (s *S) TestRateLimiter(c *C) {
+	testServer.Responses(3, 200, nil, DescribeInstancesExample1)
+
+	client := ec2.NewWithClient(s.ec2.Auth, s.ec2.Region, testutil.DefaultClient)
+	client.RateLimiter = &aws.RateLimiter{
+		Services: map[string]aws.RateLimit{"ec2": {Rate: 20, Burst: 1}},
+	}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.DescribeInstances(nil, nil)
+		c.Assert(err, IsNil)
+	}
+	c.Assert(time.Since(start) >= 90*time.Millisecond, Equals, true)
+	testServer.WaitRequests(3)
+
+	// Other clients are not limited.
+	testServer.Responses(3, 200, nil, DescribeInstancesExample1)
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := s.ec2.DescribeInstances(nil, nil)
+		c.Assert(err, IsNil)
+	}
+	c.Assert(time.Since(start) < 50*time.Millisecond, Equals, true)
+}
+
 func (s *S) TestTerminateInstancesExample(c *C) {
 	testServer.Response(200, nil, TerminateInstancesExample)
 
@@ -305,6 +333,21 @@ func (s *S) TestDescribeInstanceStatusExample(c *C) {
 	c.Assert(i0.InstanceStatus.Details.ImpairedSince, Equals, "2010-08-17T01:15:18.000Z")
 }
 
+func (s *S) TestDescribeInstanceStatusPagesNilOptions(c *C) {
+	testServer.Responses(2, 200, nil, DescribeInstanceStatusExample)
+
+	pages := s.ec2.DescribeInstanceStatusPages(nil, nil)
+	c.Assert(pages.Next(context.Background()), Equals, true)
+	c.Assert(pages.Page().InstanceStatusSet, HasLen, 1)
+	req := testServer.WaitRequest()
+	c.Assert(req.Form["NextToken"], IsNil)
+
+	c.Assert(pages.Next(context.Background()), Equals, true)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["NextToken"], DeepEquals, []string{"exampleToken"})
+	c.Assert(pages.Err(), IsNil)
+}
+
 func (s *S) TestDescribeAddressesPublicIPExample(c *C) {
 	testServer.Response(200, nil, DescribeAddressesExample)
 
@@ -1140,3 +1183,12 @@ func (s *S) TestDescribeReservedInstancesExample(c *C) {
 	c.Assert(r0.ReservedInstanceId, Equals, "e5a2ff3b-7d14-494f-90af-0b5d0EXAMPLE")
 
 }
+
+func (s *S) TestDescribeInstancesWithContextCanceled(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp, err := s.ec2.DescribeInstancesWithContext(ctx, []string{"i-1"}, nil)
+	c.Assert(resp, IsNil)
+	c.Assert(errors.Is(err, context.Canceled), Equals, true)
+}
@@ -17,10 +17,11 @@ type LocalServer struct {
 	auth   aws.Auth
 	region aws.Region
 	srv    *ec2test.Server
+	config *ec2test.Config
 }
 
 func (s *LocalServer) SetUp(c *C) {
-	srv, err := ec2test.NewServer()
+	srv, err := ec2test.NewServerWithConfig(s.config)
 	c.Assert(err, IsNil)
 	c.Assert(srv, NotNil)
 
@@ -41,12 +42,40 @@ type LocalServerSuite struct {
 
 var _ = Suite(&LocalServerSuite{})
 
+var _ = Suite(&LocalServerSuite{
+	srv: LocalServer{
+		auth: aws.Auth{AccessKey: "access", SecretKey: "secret"},
+		config: &ec2test.Config{
+			Verifier: &aws.Verifier{
+				Credentials: aws.NewStaticCredentials(aws.Credentials{AccessKey: "access", SecretKey: "secret"}),
+			},
+		},
+	},
+})
+
 func (s *LocalServerSuite) SetUpSuite(c *C) {
 	s.srv.SetUp(c)
 	s.ServerTests.ec2 = ec2.NewWithClient(s.srv.auth, s.srv.region, testutil.DefaultClient)
 	s.clientTests.ec2 = ec2.NewWithClient(s.srv.auth, s.srv.region, testutil.DefaultClient)
 }
 
+func (s *LocalServerSuite) TestVerifyRejectsBadSignatures(c *C) {
+	if s.srv.config == nil {
+		c.Skip("server does not verify signatures")
+	}
+	auth := s.srv.auth
+	auth.SecretKey = "wrong"
+	_, err := ec2.NewWithClient(auth, s.srv.region, testutil.DefaultClient).DescribeInstances(nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.(*ec2.Error).StatusCode, Equals, 403)
+	c.Assert(err.(*ec2.Error).Code, Equals, "SignatureDoesNotMatch")
+
+	auth.AccessKey = "unknown"
+	_, err = ec2.NewWithClient(auth, s.srv.region, testutil.DefaultClient).DescribeInstances(nil, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.(*ec2.Error).Code, Equals, "InvalidAccessKeyId")
+}
+
 func (s *LocalServerSuite) TestRunAndTerminate(c *C) {
 	s.clientTests.TestRunAndTerminate(c)
 }
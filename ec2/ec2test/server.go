@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/ec2"
 	"io"
 	"net"
@@ -40,10 +41,14 @@ type Action struct {
 // - some virtual time stamp interface, so a client
 // can ask for all actions after a certain virtual time.
 
+// Config controls the behaviour of a Server.
+type Config = aws.ServerConfig
+
 // Server implements an EC2 simulator for use in testing.
 type Server struct {
 	url      string
 	listener net.Listener
+	config   *Config
 	mu       sync.Mutex
 	reqs     []*Action
 
@@ -216,7 +221,14 @@ func (srv *Server) newAction() *Action {
 
 // NewServer returns a new server.
 func NewServer() (*Server, error) {
+	return NewServerWithConfig(nil)
+}
+
+// NewServerWithConfig starts and returns a new server with the given
+// configuration, which may be nil.
+func NewServerWithConfig(config *Config) (*Server, error) {
 	srv := &Server{
+		config:               config,
 		instances:            make(map[string]*Instance),
 		groups:               make(map[string]*securityGroup),
 		reservations:         make(map[string]*reservation),
@@ -286,6 +298,10 @@ func (srv *Server) URL() string {
 
 // serveHTTP serves the EC2 protocol.
 func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := srv.config.VerifyRequest(w, req); err != nil {
+		writeError(w, &ec2.Error{StatusCode: err.HTTPStatusCode(), Code: err.Code, Message: err.Error()})
+		return
+	}
 	req.ParseForm()
 
 	a := srv.newAction()
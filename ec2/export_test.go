@@ -17,6 +17,6 @@ func FakeTime(fakeIt bool) {
 	if fakeIt {
 		timeNow = fixedTime
 	} else {
-		timeNow = time.Now
+		timeNow = aws.Now
 	}
 }
@@ -1,5 +1,7 @@
 package ec2
 
+import "context"
+
 // RouteTable describes a route table which contains a set of rules, called routes
 // that are used to determine where network traffic is directed.
 //
@@ -57,10 +59,15 @@ type CreateRouteTableResp struct {
 //
 // See http://goo.gl/V9h6gE for more details..
 func (ec2 *EC2) CreateRouteTable(vpcId string) (resp *CreateRouteTableResp, err error) {
+	return ec2.CreateRouteTableWithContext(context.Background(), vpcId)
+}
+
+// CreateRouteTableWithContext is like CreateRouteTable but makes its requests with ctx.
+func (ec2 *EC2) CreateRouteTableWithContext(ctx context.Context, vpcId string) (resp *CreateRouteTableResp, err error) {
 	params := makeParams("CreateRouteTable")
 	params["VpcId"] = vpcId
 	resp = &CreateRouteTableResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -79,11 +86,16 @@ type DescribeRouteTablesResp struct {
 //
 // See http://goo.gl/S0RVos for more details.
 func (ec2 *EC2) DescribeRouteTables(routeTableIds []string, filter *Filter) (resp *DescribeRouteTablesResp, err error) {
+	return ec2.DescribeRouteTablesWithContext(context.Background(), routeTableIds, filter)
+}
+
+// DescribeRouteTablesWithContext is like DescribeRouteTables but makes its requests with ctx.
+func (ec2 *EC2) DescribeRouteTablesWithContext(ctx context.Context, routeTableIds []string, filter *Filter) (resp *DescribeRouteTablesResp, err error) {
 	params := makeParams("DescribeRouteTables")
 	addParamsList(params, "RouteTableId", routeTableIds)
 	filter.addParams(params)
 	resp = &DescribeRouteTablesResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -108,11 +120,16 @@ type AssociateRouteTableResp struct {
 //
 // See http://goo.gl/bfnONU for more details.
 func (ec2 *EC2) AssociateRouteTable(routeTableId, subnetId string) (resp *AssociateRouteTableResp, err error) {
+	return ec2.AssociateRouteTableWithContext(context.Background(), routeTableId, subnetId)
+}
+
+// AssociateRouteTableWithContext is like AssociateRouteTable but makes its requests with ctx.
+func (ec2 *EC2) AssociateRouteTableWithContext(ctx context.Context, routeTableId, subnetId string) (resp *AssociateRouteTableResp, err error) {
 	params := makeParams("AssociateRouteTable")
 	params["RouteTableId"] = routeTableId
 	params["SubnetId"] = subnetId
 	resp = &AssociateRouteTableResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -131,10 +148,15 @@ type DisassociateRouteTableResp struct {
 //
 // See http://goo.gl/A4NJum for more details.
 func (ec2 *EC2) DisassociateRouteTable(associationId string) (resp *DisassociateRouteTableResp, err error) {
+	return ec2.DisassociateRouteTableWithContext(context.Background(), associationId)
+}
+
+// DisassociateRouteTableWithContext is like DisassociateRouteTable but makes its requests with ctx.
+func (ec2 *EC2) DisassociateRouteTableWithContext(ctx context.Context, associationId string) (resp *DisassociateRouteTableResp, err error) {
 	params := makeParams("DisassociateRouteTable")
 	params["AssociationId"] = associationId
 	resp = &DisassociateRouteTableResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -153,11 +175,16 @@ type ReplaceRouteTableAssociationResp struct {
 //
 // See http://goo.gl/kiit8j for more details.
 func (ec2 *EC2) ReplaceRouteTableAssociation(associationId, routeTableId string) (resp *ReplaceRouteTableAssociationResp, err error) {
+	return ec2.ReplaceRouteTableAssociationWithContext(context.Background(), associationId, routeTableId)
+}
+
+// ReplaceRouteTableAssociationWithContext is like ReplaceRouteTableAssociation but makes its requests with ctx.
+func (ec2 *EC2) ReplaceRouteTableAssociationWithContext(ctx context.Context, associationId, routeTableId string) (resp *ReplaceRouteTableAssociationResp, err error) {
 	params := makeParams("ReplaceRouteTableAssociation")
 	params["AssociationId"] = associationId
 	params["RouteTableId"] = routeTableId
 	resp = &ReplaceRouteTableAssociationResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -178,10 +205,15 @@ type DeleteRouteTableResp struct {
 //
 // See http://goo.gl/crHxT2 for more details.
 func (ec2 *EC2) DeleteRouteTable(routeTableId string) (resp *DeleteRouteTableResp, err error) {
+	return ec2.DeleteRouteTableWithContext(context.Background(), routeTableId)
+}
+
+// DeleteRouteTableWithContext is like DeleteRouteTable but makes its requests with ctx.
+func (ec2 *EC2) DeleteRouteTableWithContext(ctx context.Context, routeTableId string) (resp *DeleteRouteTableResp, err error) {
 	params := makeParams("DeleteRouteTable")
 	params["RouteTableId"] = routeTableId
 	resp = &DeleteRouteTableResp{}
-	err = ec2.query(params, resp)
+	err = ec2.query(ctx, params, resp)
 	if err != nil {
 		return nil, err
 	}
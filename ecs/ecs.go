@@ -9,6 +9,7 @@
 package ecs
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
@@ -30,11 +31,15 @@ var timeNow = time.Now
 type ECS struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 // New creates a new ECS Client.
 func New(auth aws.Auth, region aws.Region) *ECS {
-	return &ECS{auth, region}
+	return &ECS{auth, region.WithEndpoints(), nil}
 }
 
 // ----------------------------------------------------------------------------
@@ -63,51 +68,60 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	RequestId string  `xml:"RequestId"`
 	Errors    []Error `xml:"Error"`
 }
 
-func (e *ECS) query(params map[string]string, resp interface{}) error {
+func (e *ECS) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	params["Version"] = "2014-11-13"
-	data := strings.NewReader(multimap(params).Encode())
-
-	hreq, err := http.NewRequest("POST", e.Region.ECSEndpoint+"/", data)
-	if err != nil {
-		return err
-	}
-
-	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
-
-	token := e.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
-	}
-
-	signer := aws.NewV4Signer(e.Auth, "ecs", e.Region)
-	signer.Sign(hreq)
-
-	if debug {
-		log.Printf("%v -> {\n", hreq)
+	data := multimap(params).Encode()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "ecs",
+		Region:      e.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: e.RateLimiter,
+	}
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("POST", e.Region.ECSEndpoint+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+			return nil
+		},
+		Sign: aws.NewV4Signer(e.Auth, "ecs", e.Region).SignHandler(),
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				log.Printf("%v -> {\n", r.HTTPRequest)
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	r, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		log.Printf("Error calling Amazon %v", err)
-		return err
-	}
-
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
-	}
-	if r.StatusCode != 200 {
-		return buildError(r)
-	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -181,6 +195,11 @@ type CreateClusterResp struct {
 // CreateCluster creates a new Amazon ECS cluster. By default, your account
 // will receive a default cluster when you launch your first container instance
 func (e *ECS) CreateCluster(req *CreateClusterReq) (resp *CreateClusterResp, err error) {
+	return e.CreateClusterWithContext(context.Background(), req)
+}
+
+// CreateClusterWithContext is like CreateCluster but makes its requests with ctx.
+func (e *ECS) CreateClusterWithContext(ctx context.Context, req *CreateClusterReq) (resp *CreateClusterResp, err error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -189,7 +208,7 @@ func (e *ECS) CreateCluster(req *CreateClusterReq) (resp *CreateClusterResp, err
 	params["clusterName"] = req.ClusterName
 
 	resp = new(CreateClusterResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -232,6 +251,12 @@ type DeregisterContainerInstanceResp struct {
 
 // DeregisterContainerInstance deregisters an Amazon ECS container instance from the specified cluster
 func (e *ECS) DeregisterContainerInstance(req *DeregisterContainerInstanceReq) (
+	resp *DeregisterContainerInstanceResp, err error) {
+	return e.DeregisterContainerInstanceWithContext(context.Background(), req)
+}
+
+// DeregisterContainerInstanceWithContext is like DeregisterContainerInstance but makes its requests with ctx.
+func (e *ECS) DeregisterContainerInstanceWithContext(ctx context.Context, req *DeregisterContainerInstanceReq) (
 	resp *DeregisterContainerInstanceResp, err error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -246,7 +271,7 @@ func (e *ECS) DeregisterContainerInstance(req *DeregisterContainerInstanceReq) (
 	}
 
 	resp = new(DeregisterContainerInstanceResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -301,6 +326,12 @@ type DeregisterTaskDefinitionResp struct {
 
 // DeregisterTaskDefinition deregisters the specified task definition
 func (e *ECS) DeregisterTaskDefinition(req *DeregisterTaskDefinitionReq) (
+	*DeregisterTaskDefinitionResp, error) {
+	return e.DeregisterTaskDefinitionWithContext(context.Background(), req)
+}
+
+// DeregisterTaskDefinitionWithContext is like DeregisterTaskDefinition but makes its requests with ctx.
+func (e *ECS) DeregisterTaskDefinitionWithContext(ctx context.Context, req *DeregisterTaskDefinitionReq) (
 	*DeregisterTaskDefinitionResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -310,7 +341,7 @@ func (e *ECS) DeregisterTaskDefinition(req *DeregisterTaskDefinitionReq) (
 	params["taskDefinition"] = req.TaskDefinition
 
 	resp := new(DeregisterTaskDefinitionResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -336,6 +367,11 @@ type DescribeClustersResp struct {
 
 // DescribeClusters describes one or more of your clusters
 func (e *ECS) DescribeClusters(req *DescribeClustersReq) (*DescribeClustersResp, error) {
+	return e.DescribeClustersWithContext(context.Background(), req)
+}
+
+// DescribeClustersWithContext is like DescribeClusters but makes its requests with ctx.
+func (e *ECS) DescribeClustersWithContext(ctx context.Context, req *DescribeClustersReq) (*DescribeClustersResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -346,7 +382,7 @@ func (e *ECS) DescribeClusters(req *DescribeClustersReq) (*DescribeClustersResp,
 	}
 
 	resp := new(DescribeClustersResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -368,6 +404,12 @@ type DescribeContainerInstancesResp struct {
 // DescribeContainerInstances describes Amazon EC2 Container Service container instances
 // Returns metadata about registered and remaining resources on each container instance requested
 func (e *ECS) DescribeContainerInstances(req *DescribeContainerInstancesReq) (
+	*DescribeContainerInstancesResp, error) {
+	return e.DescribeContainerInstancesWithContext(context.Background(), req)
+}
+
+// DescribeContainerInstancesWithContext is like DescribeContainerInstances but makes its requests with ctx.
+func (e *ECS) DescribeContainerInstancesWithContext(ctx context.Context, req *DescribeContainerInstancesReq) (
 	*DescribeContainerInstancesResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -382,7 +424,7 @@ func (e *ECS) DescribeContainerInstances(req *DescribeContainerInstancesReq) (
 	}
 
 	resp := new(DescribeContainerInstancesResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -401,6 +443,12 @@ type DescribeTaskDefinitionResp struct {
 
 // DescribeTaskDefinition describes a task definition
 func (e *ECS) DescribeTaskDefinition(req *DescribeTaskDefinitionReq) (
+	*DescribeTaskDefinitionResp, error) {
+	return e.DescribeTaskDefinitionWithContext(context.Background(), req)
+}
+
+// DescribeTaskDefinitionWithContext is like DescribeTaskDefinition but makes its requests with ctx.
+func (e *ECS) DescribeTaskDefinitionWithContext(ctx context.Context, req *DescribeTaskDefinitionReq) (
 	*DescribeTaskDefinitionResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -410,7 +458,7 @@ func (e *ECS) DescribeTaskDefinition(req *DescribeTaskDefinitionReq) (
 	params["taskDefinition"] = req.TaskDefinition
 
 	resp := new(DescribeTaskDefinitionResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -472,6 +520,11 @@ type DescribeTasksResp struct {
 
 // DescribeTasks describes a task definition
 func (e *ECS) DescribeTasks(req *DescribeTasksReq) (*DescribeTasksResp, error) {
+	return e.DescribeTasksWithContext(context.Background(), req)
+}
+
+// DescribeTasksWithContext is like DescribeTasks but makes its requests with ctx.
+func (e *ECS) DescribeTasksWithContext(ctx context.Context, req *DescribeTasksReq) (*DescribeTasksResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -485,7 +538,7 @@ func (e *ECS) DescribeTasks(req *DescribeTasksReq) (*DescribeTasksResp, error) {
 	}
 
 	resp := new(DescribeTasksResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -505,6 +558,12 @@ type DiscoverPollEndpointResp struct {
 // DiscoverPollEndpoint returns an endpoint for the Amazon EC2 Container Service agent
 // to poll for updates
 func (e *ECS) DiscoverPollEndpoint(req *DiscoverPollEndpointReq) (
+	*DiscoverPollEndpointResp, error) {
+	return e.DiscoverPollEndpointWithContext(context.Background(), req)
+}
+
+// DiscoverPollEndpointWithContext is like DiscoverPollEndpoint but makes its requests with ctx.
+func (e *ECS) DiscoverPollEndpointWithContext(ctx context.Context, req *DiscoverPollEndpointReq) (
 	*DiscoverPollEndpointResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -516,7 +575,7 @@ func (e *ECS) DiscoverPollEndpoint(req *DiscoverPollEndpointReq) (
 	}
 
 	resp := new(DiscoverPollEndpointResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -537,6 +596,12 @@ type ListClustersResp struct {
 
 // ListClusters returns a list of existing clusters
 func (e *ECS) ListClusters(req *ListClustersReq) (
+	*ListClustersResp, error) {
+	return e.ListClustersWithContext(context.Background(), req)
+}
+
+// ListClustersWithContext is like ListClusters but makes its requests with ctx.
+func (e *ECS) ListClustersWithContext(ctx context.Context, req *ListClustersReq) (
 	*ListClustersResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -551,12 +616,37 @@ func (e *ECS) ListClusters(req *ListClustersReq) (
 	}
 
 	resp := new(ListClustersResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListClustersPages returns a Pager over the pages of ListClusters results,
+// starting with the page of req.NextToken. A nil req lists without
+// filters.
+func (e *ECS) ListClustersPages(req *ListClustersReq) *aws.Pager[*ListClustersResp] {
+	var next ListClustersReq
+	if req != nil {
+		next = *req
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListClustersResp, bool, error) {
+		resp, err := e.ListClustersWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListClustersAll returns the ARNs of every cluster, walking every page of ListClusters results.
+func (e *ECS) ListClustersAll(ctx context.Context, req *ListClustersReq) ([]string, error) {
+	return aws.All(ctx, e.ListClustersPages(req), func(resp *ListClustersResp) []string {
+		return resp.ClusterArns
+	})
+}
+
 // ListContainerInstancesReq encapsulates ListContainerInstances req params
 type ListContainerInstancesReq struct {
 	Cluster    string
@@ -573,6 +663,12 @@ type ListContainerInstancesResp struct {
 
 // ListContainerInstances returns a list of container instances in a specified cluster.
 func (e *ECS) ListContainerInstances(req *ListContainerInstancesReq) (
+	*ListContainerInstancesResp, error) {
+	return e.ListContainerInstancesWithContext(context.Background(), req)
+}
+
+// ListContainerInstancesWithContext is like ListContainerInstances but makes its requests with ctx.
+func (e *ECS) ListContainerInstancesWithContext(ctx context.Context, req *ListContainerInstancesReq) (
 	*ListContainerInstancesResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -590,12 +686,37 @@ func (e *ECS) ListContainerInstances(req *ListContainerInstancesReq) (
 	}
 
 	resp := new(ListContainerInstancesResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListContainerInstancesPages returns a Pager over the pages of ListContainerInstances results,
+// starting with the page of req.NextToken. A nil req lists without
+// filters.
+func (e *ECS) ListContainerInstancesPages(req *ListContainerInstancesReq) *aws.Pager[*ListContainerInstancesResp] {
+	var next ListContainerInstancesReq
+	if req != nil {
+		next = *req
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListContainerInstancesResp, bool, error) {
+		resp, err := e.ListContainerInstancesWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListContainerInstancesAll returns the ARNs of every container instance in req.Cluster, walking every page of ListContainerInstances results.
+func (e *ECS) ListContainerInstancesAll(ctx context.Context, req *ListContainerInstancesReq) ([]string, error) {
+	return aws.All(ctx, e.ListContainerInstancesPages(req), func(resp *ListContainerInstancesResp) []string {
+		return resp.ContainerInstanceArns
+	})
+}
+
 // ListTaskDefinitionsReq encapsulates ListTaskDefinitions req params
 type ListTaskDefinitionsReq struct {
 	FamilyPrefix string
@@ -612,6 +733,12 @@ type ListTaskDefinitionsResp struct {
 
 // ListTaskDefinitions Returns a list of task definitions that are registered to your account.
 func (e *ECS) ListTaskDefinitions(req *ListTaskDefinitionsReq) (
+	*ListTaskDefinitionsResp, error) {
+	return e.ListTaskDefinitionsWithContext(context.Background(), req)
+}
+
+// ListTaskDefinitionsWithContext is like ListTaskDefinitions but makes its requests with ctx.
+func (e *ECS) ListTaskDefinitionsWithContext(ctx context.Context, req *ListTaskDefinitionsReq) (
 	*ListTaskDefinitionsResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -629,12 +756,37 @@ func (e *ECS) ListTaskDefinitions(req *ListTaskDefinitionsReq) (
 	}
 
 	resp := new(ListTaskDefinitionsResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListTaskDefinitionsPages returns a Pager over the pages of ListTaskDefinitions results,
+// starting with the page of req.NextToken. A nil req lists without
+// filters.
+func (e *ECS) ListTaskDefinitionsPages(req *ListTaskDefinitionsReq) *aws.Pager[*ListTaskDefinitionsResp] {
+	var next ListTaskDefinitionsReq
+	if req != nil {
+		next = *req
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListTaskDefinitionsResp, bool, error) {
+		resp, err := e.ListTaskDefinitionsWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListTaskDefinitionsAll returns the ARNs of every task definition matching req, walking every page of ListTaskDefinitions results.
+func (e *ECS) ListTaskDefinitionsAll(ctx context.Context, req *ListTaskDefinitionsReq) ([]string, error) {
+	return aws.All(ctx, e.ListTaskDefinitionsPages(req), func(resp *ListTaskDefinitionsResp) []string {
+		return resp.TaskDefinitionArns
+	})
+}
+
 // ListTasksReq encapsulates ListTasks req params
 type ListTasksReq struct {
 	Cluster           string
@@ -655,6 +807,12 @@ type ListTasksResp struct {
 // You can filter the results by family name or by a particular container instance
 // with the family and containerInstance parameters.
 func (e *ECS) ListTasks(req *ListTasksReq) (
+	*ListTasksResp, error) {
+	return e.ListTasksWithContext(context.Background(), req)
+}
+
+// ListTasksWithContext is like ListTasks but makes its requests with ctx.
+func (e *ECS) ListTasksWithContext(ctx context.Context, req *ListTasksReq) (
 	*ListTasksResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -678,12 +836,37 @@ func (e *ECS) ListTasks(req *ListTasksReq) (
 	}
 
 	resp := new(ListTasksResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// ListTasksPages returns a Pager over the pages of ListTasks results,
+// starting with the page of req.NextToken. A nil req lists without
+// filters.
+func (e *ECS) ListTasksPages(req *ListTasksReq) *aws.Pager[*ListTasksResp] {
+	var next ListTasksReq
+	if req != nil {
+		next = *req
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListTasksResp, bool, error) {
+		resp, err := e.ListTasksWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListTasksAll returns the ARNs of every task matching req, walking every page of ListTasks results.
+func (e *ECS) ListTasksAll(ctx context.Context, req *ListTasksReq) ([]string, error) {
+	return aws.All(ctx, e.ListTasksPages(req), func(resp *ListTasksResp) []string {
+		return resp.TaskArns
+	})
+}
+
 // RegisterContainerInstanceReq encapsulates RegisterContainerInstance req params
 type RegisterContainerInstanceReq struct {
 	Cluster                           string
@@ -701,6 +884,12 @@ type RegisterContainerInstanceResp struct {
 // RegisterContainerInstance registers an Amazon EC2 instance into the specified cluster.
 // This instance will become available to place containers on.
 func (e *ECS) RegisterContainerInstance(req *RegisterContainerInstanceReq) (
+	resp *RegisterContainerInstanceResp, err error) {
+	return e.RegisterContainerInstanceWithContext(context.Background(), req)
+}
+
+// RegisterContainerInstanceWithContext is like RegisterContainerInstance but makes its requests with ctx.
+func (e *ECS) RegisterContainerInstanceWithContext(ctx context.Context, req *RegisterContainerInstanceReq) (
 	resp *RegisterContainerInstanceResp, err error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -730,7 +919,7 @@ func (e *ECS) RegisterContainerInstance(req *RegisterContainerInstanceReq) (
 	}
 
 	resp = new(RegisterContainerInstanceResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -750,6 +939,12 @@ type RegisterTaskDefinitionResp struct {
 
 // RegisterTaskDefinition registers a new task definition from the supplied family and containerDefinitions.
 func (e *ECS) RegisterTaskDefinition(req *RegisterTaskDefinitionReq) (
+	resp *RegisterTaskDefinitionResp, err error) {
+	return e.RegisterTaskDefinitionWithContext(context.Background(), req)
+}
+
+// RegisterTaskDefinitionWithContext is like RegisterTaskDefinition but makes its requests with ctx.
+func (e *ECS) RegisterTaskDefinitionWithContext(ctx context.Context, req *RegisterTaskDefinitionReq) (
 	resp *RegisterTaskDefinitionResp, err error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -788,7 +983,7 @@ func (e *ECS) RegisterTaskDefinition(req *RegisterTaskDefinitionReq) (
 	}
 
 	resp = new(RegisterTaskDefinitionResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -813,6 +1008,11 @@ type RunTaskResp struct {
 // If you want to use your own scheduler or place a task on a specific container instance,
 // use StartTask instead.
 func (e *ECS) RunTask(req *RunTaskReq) (*RunTaskResp, error) {
+	return e.RunTaskWithContext(context.Background(), req)
+}
+
+// RunTaskWithContext is like RunTask but makes its requests with ctx.
+func (e *ECS) RunTaskWithContext(ctx context.Context, req *RunTaskReq) (*RunTaskResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -837,7 +1037,7 @@ func (e *ECS) RunTask(req *RunTaskReq) (*RunTaskResp, error) {
 	}
 
 	resp := new(RunTaskResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -862,6 +1062,11 @@ type StartTaskResp struct {
 // container instance or instances. If you want to use the default Amazon ECS scheduler
 // to place your task, use RunTask instead.
 func (e *ECS) StartTask(req *StartTaskReq) (*StartTaskResp, error) {
+	return e.StartTaskWithContext(context.Background(), req)
+}
+
+// StartTaskWithContext is like StartTask but makes its requests with ctx.
+func (e *ECS) StartTaskWithContext(ctx context.Context, req *StartTaskReq) (*StartTaskResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -885,7 +1090,7 @@ func (e *ECS) StartTask(req *StartTaskReq) (*StartTaskResp, error) {
 	}
 
 	resp := new(StartTaskResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -905,6 +1110,11 @@ type StopTaskResp struct {
 
 // StopTask stops a running task
 func (e *ECS) StopTask(req *StopTaskReq) (*StopTaskResp, error) {
+	return e.StopTaskWithContext(context.Background(), req)
+}
+
+// StopTaskWithContext is like StopTask but makes its requests with ctx.
+func (e *ECS) StopTaskWithContext(ctx context.Context, req *StopTaskReq) (*StopTaskResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
 	}
@@ -918,7 +1128,7 @@ func (e *ECS) StopTask(req *StopTaskReq) (*StopTaskResp, error) {
 	}
 
 	resp := new(StopTaskResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -945,6 +1155,12 @@ type SubmitContainerStateChangeResp struct {
 // Note: This action is only used by the Amazon EC2 Container Service agent,
 // and it is not intended for use outside of the agent.
 func (e *ECS) SubmitContainerStateChange(req *SubmitContainerStateChangeReq) (
+	*SubmitContainerStateChangeResp, error) {
+	return e.SubmitContainerStateChangeWithContext(context.Background(), req)
+}
+
+// SubmitContainerStateChangeWithContext is like SubmitContainerStateChange but makes its requests with ctx.
+func (e *ECS) SubmitContainerStateChangeWithContext(ctx context.Context, req *SubmitContainerStateChangeReq) (
 	*SubmitContainerStateChangeResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -975,7 +1191,7 @@ func (e *ECS) SubmitContainerStateChange(req *SubmitContainerStateChangeReq) (
 	params["exitCode"] = strconv.Itoa(int(req.ExitCode))
 
 	resp := new(SubmitContainerStateChangeResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -999,6 +1215,12 @@ type SubmitTaskStateChangeResp struct {
 // Note: This action is only used by the Amazon EC2 Container Service agent,
 // and it is not intended for use outside of the agent.
 func (e *ECS) SubmitTaskStateChange(req *SubmitTaskStateChangeReq) (
+	*SubmitTaskStateChangeResp, error) {
+	return e.SubmitTaskStateChangeWithContext(context.Background(), req)
+}
+
+// SubmitTaskStateChangeWithContext is like SubmitTaskStateChange but makes its requests with ctx.
+func (e *ECS) SubmitTaskStateChangeWithContext(ctx context.Context, req *SubmitTaskStateChangeReq) (
 	*SubmitTaskStateChangeResp, error) {
 	if req == nil {
 		return nil, fmt.Errorf("The req params cannot be nil")
@@ -1020,7 +1242,7 @@ func (e *ECS) SubmitTaskStateChange(req *SubmitTaskStateChangeReq) (
 	}
 
 	resp := new(SubmitTaskStateChangeResp)
-	if err := e.query(params, resp); err != nil {
+	if err := e.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
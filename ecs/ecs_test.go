@@ -1,6 +1,8 @@
 package ecs
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -369,6 +371,33 @@ func (s *S) TestListClusters(c *C) {
 	c.Assert(resp.RequestId, Equals, "8d798a29-f083-11e1-bdfb-cb223EXAMPLE")
 }
 
+func (s *S) TestListClustersAll(c *C) {
+	testServer.Response(200, nil, ListClustersResponse)
+	testServer.Response(200, nil, strings.Replace(ListClustersResponse, "<nextToken>token_UUID</nextToken>", "", 1))
+	arns, err := s.ecs.ListClustersAll(context.Background(), &ListClustersReq{MaxResults: 2})
+	c.Assert(err, IsNil)
+	c.Assert(arns, HasLen, 4)
+
+	reqs := testServer.WaitRequests(2)
+	c.Assert(reqs[0].PostForm.Get("nextToken"), Equals, "")
+	c.Assert(reqs[1].PostForm.Get("nextToken"), Equals, "token_UUID")
+	c.Assert(reqs[1].PostForm.Get("maxResults"), Equals, "2")
+}
+
+func (s *S) TestListClustersPagesCanceled(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testServer.Response(200, nil, ListClustersResponse)
+	p := s.ecs.ListClustersPages(nil)
+	c.Assert(p.Next(ctx), Equals, true)
+	c.Assert(p.Page().NextToken, Equals, "token_UUID")
+	testServer.WaitRequest()
+
+	cancel()
+	c.Assert(p.Next(ctx), Equals, false)
+	c.Assert(p.Page(), IsNil)
+	c.Assert(p.Err(), Equals, context.Canceled)
+}
+
 func (s *S) TestListContainerInstances(c *C) {
 	testServer.Response(200, nil, ListContainerInstancesResponse)
 	req := &ListContainerInstancesReq{
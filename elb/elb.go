@@ -2,6 +2,7 @@
 package elb
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net/http"
@@ -17,10 +18,14 @@ import (
 type ELB struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 func New(auth aws.Auth, region aws.Region) *ELB {
-	return &ELB{auth, region}
+	return &ELB{auth, region.WithEndpoints(), nil}
 }
 
 // The CreateLoadBalancer type encapsulates options for the respective request in AWS.
@@ -62,9 +67,14 @@ type SimpleResp struct {
 //
 // See http://goo.gl/4QFKi for more details.
 func (elb *ELB) CreateLoadBalancer(options *CreateLoadBalancer) (resp *CreateLoadBalancerResp, err error) {
+	return elb.CreateLoadBalancerWithContext(context.Background(), options)
+}
+
+// CreateLoadBalancerWithContext is like CreateLoadBalancer but makes its requests with ctx.
+func (elb *ELB) CreateLoadBalancerWithContext(ctx context.Context, options *CreateLoadBalancer) (resp *CreateLoadBalancerResp, err error) {
 	params := makeCreateParams(options)
 	resp = new(CreateLoadBalancerResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return
@@ -74,12 +84,17 @@ func (elb *ELB) CreateLoadBalancer(options *CreateLoadBalancer) (resp *CreateLoa
 //
 // See http://goo.gl/sDmPp for more details.
 func (elb *ELB) DeleteLoadBalancer(name string) (resp *SimpleResp, err error) {
+	return elb.DeleteLoadBalancerWithContext(context.Background(), name)
+}
+
+// DeleteLoadBalancerWithContext is like DeleteLoadBalancer but makes its requests with ctx.
+func (elb *ELB) DeleteLoadBalancerWithContext(ctx context.Context, name string) (resp *SimpleResp, err error) {
 	params := map[string]string{
 		"Action":           "DeleteLoadBalancer",
 		"LoadBalancerName": name,
 	}
 	resp = new(SimpleResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -93,6 +108,11 @@ type RegisterInstancesResp struct {
 //
 // See http://goo.gl/x9hru for more details.
 func (elb *ELB) RegisterInstancesWithLoadBalancer(instanceIds []string, lbName string) (resp *RegisterInstancesResp, err error) {
+	return elb.RegisterInstancesWithLoadBalancerWithContext(context.Background(), instanceIds, lbName)
+}
+
+// RegisterInstancesWithLoadBalancerWithContext is like RegisterInstancesWithLoadBalancer but makes its requests with ctx.
+func (elb *ELB) RegisterInstancesWithLoadBalancerWithContext(ctx context.Context, instanceIds []string, lbName string) (resp *RegisterInstancesResp, err error) {
 	// TODO: change params order and use ..., e.g (lbName string, instanceIds ...string)
 	params := map[string]string{
 		"Action":           "RegisterInstancesWithLoadBalancer",
@@ -103,7 +123,7 @@ func (elb *ELB) RegisterInstancesWithLoadBalancer(instanceIds []string, lbName s
 		params[key] = instanceId
 	}
 	resp = new(RegisterInstancesResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -113,6 +133,11 @@ func (elb *ELB) RegisterInstancesWithLoadBalancer(instanceIds []string, lbName s
 //
 // See http://goo.gl/Hgo4U for more details.
 func (elb *ELB) DeregisterInstancesFromLoadBalancer(instanceIds []string, lbName string) (resp *SimpleResp, err error) {
+	return elb.DeregisterInstancesFromLoadBalancerWithContext(context.Background(), instanceIds, lbName)
+}
+
+// DeregisterInstancesFromLoadBalancerWithContext is like DeregisterInstancesFromLoadBalancer but makes its requests with ctx.
+func (elb *ELB) DeregisterInstancesFromLoadBalancerWithContext(ctx context.Context, instanceIds []string, lbName string) (resp *SimpleResp, err error) {
 	// TODO: change params order and use ..., e.g (lbName string, instanceIds ...string)
 	params := map[string]string{
 		"Action":           "DeregisterInstancesFromLoadBalancer",
@@ -123,7 +148,7 @@ func (elb *ELB) DeregisterInstancesFromLoadBalancer(instanceIds []string, lbName
 		params[key] = instanceId
 	}
 	resp = new(SimpleResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -157,13 +182,18 @@ type LoadBalancerDescription struct {
 //
 // See http://goo.gl/wofJA for more details.
 func (elb *ELB) DescribeLoadBalancers(names ...string) (*DescribeLoadBalancerResp, error) {
+	return elb.DescribeLoadBalancersWithContext(context.Background(), names...)
+}
+
+// DescribeLoadBalancersWithContext is like DescribeLoadBalancers but makes its requests with ctx.
+func (elb *ELB) DescribeLoadBalancersWithContext(ctx context.Context, names ...string) (*DescribeLoadBalancerResp, error) {
 	params := map[string]string{"Action": "DescribeLoadBalancers"}
 	for i, name := range names {
 		index := fmt.Sprintf("LoadBalancerNames.member.%d", i+1)
 		params[index] = name
 	}
 	resp := new(DescribeLoadBalancerResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -232,6 +262,11 @@ type InstanceState struct {
 //
 // See http://goo.gl/ovIB1 for more information.
 func (elb *ELB) DescribeInstanceHealth(lbName string, instanceIds ...string) (*DescribeInstanceHealthResp, error) {
+	return elb.DescribeInstanceHealthWithContext(context.Background(), lbName, instanceIds...)
+}
+
+// DescribeInstanceHealthWithContext is like DescribeInstanceHealth but makes its requests with ctx.
+func (elb *ELB) DescribeInstanceHealthWithContext(ctx context.Context, lbName string, instanceIds ...string) (*DescribeInstanceHealthResp, error) {
 	params := map[string]string{
 		"Action":           "DescribeInstanceHealth",
 		"LoadBalancerName": lbName,
@@ -241,7 +276,7 @@ func (elb *ELB) DescribeInstanceHealth(lbName string, instanceIds ...string) (*D
 		params[key] = iId
 	}
 	resp := new(DescribeInstanceHealthResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -255,6 +290,11 @@ type HealthCheckResp struct {
 //
 // See http://goo.gl/2HE6a for more information
 func (elb *ELB) ConfigureHealthCheck(lbName string, healthCheck *HealthCheck) (*HealthCheckResp, error) {
+	return elb.ConfigureHealthCheckWithContext(context.Background(), lbName, healthCheck)
+}
+
+// ConfigureHealthCheckWithContext is like ConfigureHealthCheck but makes its requests with ctx.
+func (elb *ELB) ConfigureHealthCheckWithContext(ctx context.Context, lbName string, healthCheck *HealthCheck) (*HealthCheckResp, error) {
 	params := map[string]string{
 		"Action":                         "ConfigureHealthCheck",
 		"LoadBalancerName":               lbName,
@@ -265,7 +305,7 @@ func (elb *ELB) ConfigureHealthCheck(lbName string, healthCheck *HealthCheck) (*
 		"HealthCheck.UnhealthyThreshold": strconv.Itoa(healthCheck.UnhealthyThreshold),
 	}
 	resp := new(HealthCheckResp)
-	if err := elb.query(params, resp); err != nil {
+	if err := elb.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -277,6 +317,11 @@ func (elb *ELB) ConfigureHealthCheck(lbName string, healthCheck *HealthCheck) (*
 //
 // See http://goo.gl/6JW4Wf for the rest of the details
 func (elb *ELB) AddTags(elbName string, tags map[string]string) (*SimpleResp, error) {
+	return elb.AddTagsWithContext(context.Background(), elbName, tags)
+}
+
+// AddTagsWithContext is like AddTags but makes its requests with ctx.
+func (elb *ELB) AddTagsWithContext(ctx context.Context, elbName string, tags map[string]string) (*SimpleResp, error) {
 	var sortedKeys []string
 	params := make(map[string]string)
 	response := &SimpleResp{}
@@ -297,7 +342,7 @@ func (elb *ELB) AddTags(elbName string, tags map[string]string) (*SimpleResp, er
 	params["Action"] = "AddTags"
 	params["LoadBalancerNames.member.1"] = elbName
 
-	if err := elb.query(params, response); err != nil {
+	if err := elb.query(ctx, params, response); err != nil {
 		return nil, err
 	}
 
@@ -311,6 +356,11 @@ func (elb *ELB) AddTags(elbName string, tags map[string]string) (*SimpleResp, er
 // see http://goo.gl/ochFqo for more details
 
 func (elb *ELB) RemoveTags(elbName string, tagKeys []string) (*SimpleResp, error) {
+	return elb.RemoveTagsWithContext(context.Background(), elbName, tagKeys)
+}
+
+// RemoveTagsWithContext is like RemoveTags but makes its requests with ctx.
+func (elb *ELB) RemoveTagsWithContext(ctx context.Context, elbName string, tagKeys []string) (*SimpleResp, error) {
 	response := &SimpleResp{}
 	params := make(map[string]string)
 
@@ -321,41 +371,47 @@ func (elb *ELB) RemoveTags(elbName string, tagKeys []string) (*SimpleResp, error
 		params[fmt.Sprintf("Tags.member.%d.Key", i+1)] = tagKey
 	}
 
-	if err := elb.query(params, response); err != nil {
+	if err := elb.query(ctx, params, response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
-func (elb *ELB) query(params map[string]string, resp interface{}) error {
+func (elb *ELB) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	params["Version"] = "2012-06-01"
 	params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
-	data := strings.NewReader(multimap(params).Encode())
-	hreq, err := http.NewRequest("GET", elb.Region.ELBEndpoint+"/", data)
-	if err != nil {
-		return err
-	}
-
-	hreq.URL.RawQuery = multimap(params).Encode()
-	token := elb.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
-	}
-
-	signer := aws.NewV4Signer(elb.Auth, "elasticloadbalancing", elb.Region)
-	signer.Sign(hreq)
-
-	r, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode != 200 {
-		return buildError(r)
-	}
-	return xml.NewDecoder(r.Body).Decode(resp)
+	data := multimap(params).Encode()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "elasticloadbalancing",
+		Region:      elb.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: elb.RateLimiter,
+	}
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", elb.Region.ELBEndpoint+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.URL.RawQuery = data
+			return nil
+		},
+		Sign: aws.NewV4Signer(elb.Auth, "elasticloadbalancing", elb.Region).SignHandler(),
+		Unmarshal: func(r *aws.Request) error {
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
+	}
+	return err
 }
 
 // Error encapsulates an error returned by ELB.
@@ -376,6 +432,17 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return "" }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	Errors []Error `xml:"Error"`
 }
@@ -23,6 +23,7 @@ func (s *S) SetUpSuite(c *C) {
 
 func (s *S) TestCreateLoadBalancer(c *C) {
 	testServer.PrepareResponse(200, nil, CreateLoadBalancer)
+	testServer.PrepareResponse(200, nil, DeleteLoadBalancer) // for the deferred delete
 	createLB := &elb.CreateLoadBalancer{
 		Name:              "testlb",
 		AvailabilityZones: []string{"us-east-1a", "us-east-1b"},
@@ -54,6 +55,7 @@ func (s *S) TestCreateLoadBalancer(c *C) {
 
 func (s *S) TestCreateLoadBalancerWithSubnetsAndMoreListeners(c *C) {
 	testServer.PrepareResponse(200, nil, CreateLoadBalancer)
+	testServer.PrepareResponse(200, nil, DeleteLoadBalancer) // for the deferred delete
 	createLB := &elb.CreateLoadBalancer{
 		Name: "testlb",
 		Listeners: []elb.Listener{
@@ -12,10 +12,11 @@ type LocalServer struct {
 	auth   aws.Auth
 	region aws.Region
 	srv    *elbtest.Server
+	config *elbtest.Config
 }
 
 func (s *LocalServer) SetUp(c *C) {
-	srv, err := elbtest.NewServer()
+	srv, err := elbtest.NewServerWithConfig(s.config)
 	c.Assert(err, IsNil)
 	c.Assert(srv, NotNil)
 	s.srv = srv
@@ -60,12 +61,40 @@ func (s *AmazonServerSuite) SetUpSuite(c *C) {
 
 var _ = Suite(&LocalServerSuite{})
 
+var _ = Suite(&LocalServerSuite{
+	srv: LocalServer{
+		auth: aws.Auth{AccessKey: "access", SecretKey: "secret"},
+		config: &elbtest.Config{
+			Verifier: &aws.Verifier{
+				Credentials: aws.NewStaticCredentials(aws.Credentials{AccessKey: "access", SecretKey: "secret"}),
+			},
+		},
+	},
+})
+
 func (s *LocalServerSuite) SetUpSuite(c *C) {
 	s.srv.SetUp(c)
 	s.ServerTests.elb = elb.New(s.srv.auth, s.srv.region)
 	s.clientTests.elb = elb.New(s.srv.auth, s.srv.region)
 }
 
+func (s *LocalServerSuite) TestVerifyRejectsBadSignatures(c *C) {
+	if s.srv.config == nil {
+		c.Skip("server does not verify signatures")
+	}
+	auth := s.srv.auth
+	auth.SecretKey = "wrong"
+	_, err := elb.New(auth, s.srv.region).DescribeLoadBalancers()
+	c.Assert(err, NotNil)
+	c.Assert(err.(*elb.Error).StatusCode, Equals, 403)
+	c.Assert(err.(*elb.Error).Code, Equals, "SignatureDoesNotMatch")
+
+	auth.AccessKey = "unknown"
+	_, err = elb.New(auth, s.srv.region).DescribeLoadBalancers()
+	c.Assert(err, NotNil)
+	c.Assert(err.(*elb.Error).Code, Equals, "InvalidAccessKeyId")
+}
+
 func (s *LocalServerSuite) TestCreateLoadBalancer(c *C) {
 	s.clientTests.TestCreateAndDeleteLoadBalancer(c)
 }
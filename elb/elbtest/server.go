@@ -6,6 +6,7 @@ package elbtest
 import (
 	"encoding/xml"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/elb"
 	"net"
 	"net/http"
@@ -16,10 +17,14 @@ import (
 	"sync"
 )
 
+// Config controls the behaviour of a Server.
+type Config = aws.ServerConfig
+
 // Server implements an ELB simulator for use in testing.
 type Server struct {
 	url            string
 	listener       net.Listener
+	config         *Config
 	mutex          sync.Mutex
 	reqId          int
 	lbs            map[string]*elb.LoadBalancerDescription
@@ -31,6 +36,12 @@ type Server struct {
 
 // Starts and returns a new server
 func NewServer() (*Server, error) {
+	return NewServerWithConfig(nil)
+}
+
+// NewServerWithConfig starts and returns a new server with the given
+// configuration, which may be nil.
+func NewServerWithConfig(config *Config) (*Server, error) {
 	l, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		return nil, fmt.Errorf("cannot listen on localhost: %v", err)
@@ -38,6 +49,7 @@ func NewServer() (*Server, error) {
 	srv := &Server{
 		listener:       l,
 		url:            "http://" + l.Addr().String(),
+		config:         config,
 		lbs:            make(map[string]*elb.LoadBalancerDescription),
 		instanceStates: make(map[string][]*elb.InstanceState),
 	}
@@ -71,6 +83,10 @@ func (srv *Server) error(w http.ResponseWriter, err *elb.Error) {
 }
 
 func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := srv.config.VerifyRequest(w, req); err != nil {
+		srv.error(w, &elb.Error{StatusCode: err.HTTPStatusCode(), Code: err.Code, Message: err.Error()})
+		return
+	}
 	req.ParseForm()
 	srv.mutex.Lock()
 	defer srv.mutex.Unlock()
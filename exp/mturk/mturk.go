@@ -14,6 +14,7 @@
 package mturk
 
 import (
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -23,12 +24,15 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 )
 
 type MTurk struct {
 	aws.Auth
 	URL *url.URL
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 func New(auth aws.Auth, sandbox bool) *MTurk {
@@ -60,6 +64,17 @@ func (err *Error) Error() string {
 	return err.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 // The request stanza included in several response types, for example
 // in a "CreateHITResponse".  http://goo.gl/qGeKf
 type xmlRequest struct {
@@ -275,56 +290,56 @@ API.  Currently only supports "external" questions (see "HIT" struct above).
 
 Here are the detailed description for the parameters:
 
-  title         Required. A title should be short and descriptive about the
-                kind of task the HIT contains. On the Amazon Mechanical Turk
-                web site, the HIT title appears in search results, and
-                everywhere the HIT is mentioned.
-  description   Required. A description includes detailed information about the
-                kind of task the HIT contains. On the Amazon Mechanical Turk
-                web site, the HIT description appears in the expanded view of
-                search results, and in the HIT and assignment screens. A good
-                description gives the user enough information to evaluate the
-                HIT before accepting it.
-  question      Required. The data the person completing the HIT uses to produce
-                the results. Consstraints: Must be a QuestionForm data structure,
-                an ExternalQuestion data structure, or an HTMLQuestion data
-                structure. The XML question data must not be larger than 64
-                kilobytes (65,535 bytes) in size, including whitespace.
-  reward        Required. The amount of money the Requester will pay a Worker
-                for successfully completing the HIT.
-  assignmentDurationInSeconds   Required. The amount of time, in seconds, that
-                                a Worker has to complete the HIT after accepting
-                                it. If a Worker does not complete the assignment
-                                within the specified duration, the assignment is
-                                considered abandoned.  If the HIT is still
-                                active (that is, its lifetime has not elapsed),
-                                the assignment becomes available for other users
-                                to find and accept. Valid Values: any integer
-                                between 30 (30 seconds) and 31536000 (365 days).
-  lifetimeInSeconds     Required. An amount of time, in seconds, after which the
-                        HIT is no longer available for users to accept. After
-                        the lifetime of the HIT elapses, the HIT no longer
-                        appears in HIT searches, even if not all of the
-                        assignments for the HIT have been accepted. Valid Values:
-                        any integer between 30 (30 seconds) and 31536000 (365 days).
-  keywords              One or more words or phrases that describe the HIT,
-                        separated by commas. These words are used in searches to
-                        find HITs. Constraints: cannot be more than 1,000
-                        characters.
-  maxAssignments        The number of times the HIT can be accepted and completed
-                        before the HIT becomes unavailable. Valid Values: any
-                        integer between 1 and 1000000000 (1 billion). Default: 1
-  qualificationRequirement    A condition that a Worker's Qualifications must
-                              meet before the Worker is allowed to accept and
-                              complete the HIT. Constraints: no more than 10
-                              QualificationRequirement for each HIT.
-  requesterAnnotation   An arbitrary data field. The RequesterAnnotation
-                        parameter lets your application attach arbitrary data to
-                        the HIT for tracking purposes.  For example, the
-                        RequesterAnnotation parameter could be an identifier
-                        internal to the Requester's application that corresponds
-                        with the HIT. Constraints: must not be longer than 255
-                        characters in length.
+	title         Required. A title should be short and descriptive about the
+	              kind of task the HIT contains. On the Amazon Mechanical Turk
+	              web site, the HIT title appears in search results, and
+	              everywhere the HIT is mentioned.
+	description   Required. A description includes detailed information about the
+	              kind of task the HIT contains. On the Amazon Mechanical Turk
+	              web site, the HIT description appears in the expanded view of
+	              search results, and in the HIT and assignment screens. A good
+	              description gives the user enough information to evaluate the
+	              HIT before accepting it.
+	question      Required. The data the person completing the HIT uses to produce
+	              the results. Consstraints: Must be a QuestionForm data structure,
+	              an ExternalQuestion data structure, or an HTMLQuestion data
+	              structure. The XML question data must not be larger than 64
+	              kilobytes (65,535 bytes) in size, including whitespace.
+	reward        Required. The amount of money the Requester will pay a Worker
+	              for successfully completing the HIT.
+	assignmentDurationInSeconds   Required. The amount of time, in seconds, that
+	                              a Worker has to complete the HIT after accepting
+	                              it. If a Worker does not complete the assignment
+	                              within the specified duration, the assignment is
+	                              considered abandoned.  If the HIT is still
+	                              active (that is, its lifetime has not elapsed),
+	                              the assignment becomes available for other users
+	                              to find and accept. Valid Values: any integer
+	                              between 30 (30 seconds) and 31536000 (365 days).
+	lifetimeInSeconds     Required. An amount of time, in seconds, after which the
+	                      HIT is no longer available for users to accept. After
+	                      the lifetime of the HIT elapses, the HIT no longer
+	                      appears in HIT searches, even if not all of the
+	                      assignments for the HIT have been accepted. Valid Values:
+	                      any integer between 30 (30 seconds) and 31536000 (365 days).
+	keywords              One or more words or phrases that describe the HIT,
+	                      separated by commas. These words are used in searches to
+	                      find HITs. Constraints: cannot be more than 1,000
+	                      characters.
+	maxAssignments        The number of times the HIT can be accepted and completed
+	                      before the HIT becomes unavailable. Valid Values: any
+	                      integer between 1 and 1000000000 (1 billion). Default: 1
+	qualificationRequirement    A condition that a Worker's Qualifications must
+	                            meet before the Worker is allowed to accept and
+	                            complete the HIT. Constraints: no more than 10
+	                            QualificationRequirement for each HIT.
+	requesterAnnotation   An arbitrary data field. The RequesterAnnotation
+	                      parameter lets your application attach arbitrary data to
+	                      the HIT for tracking purposes.  For example, the
+	                      RequesterAnnotation parameter could be an identifier
+	                      internal to the Requester's application that corresponds
+	                      with the HIT. Constraints: must not be longer than 255
+	                      characters in length.
 
 Reference:
 http://docs.aws.amazon.com/AWSMechTurk/latest/AWSMturkAPI/ApiReference_CreateHITOperation.html
@@ -339,6 +354,20 @@ func (mt *MTurk) CreateHIT(
 	maxAssignments uint,
 	qualificationRequirement *QualificationRequirement,
 	requesterAnnotation string) (h *HIT, err error) {
+	return mt.CreateHITWithContext(context.Background(), title, description, question, reward, assignmentDurationInSeconds, lifetimeInSeconds, keywords, maxAssignments, qualificationRequirement, requesterAnnotation)
+}
+
+// CreateHITWithContext is like CreateHIT but makes its requests with ctx.
+func (mt *MTurk) CreateHITWithContext(ctx context.Context,
+	title, description string,
+	question interface{},
+	reward Price,
+	assignmentDurationInSeconds,
+	lifetimeInSeconds uint,
+	keywords string,
+	maxAssignments uint,
+	qualificationRequirement *QualificationRequirement,
+	requesterAnnotation string) (h *HIT, err error) {
 
 	params := make(map[string]string)
 	params["Title"] = title
@@ -369,7 +398,7 @@ func (mt *MTurk) CreateHIT(
 	}
 
 	var response CreateHITResponse
-	err = mt.query(params, "CreateHIT", &response)
+	err = mt.query(ctx, params, "CreateHIT", &response)
 	if err == nil {
 		h = &response.HIT
 	}
@@ -382,6 +411,11 @@ func (mt *MTurk) CreateHIT(
 // "maxAssignments" or "requesterAnnotation" are the zero value for
 // their types, they will not be included in the request.
 func (mt *MTurk) CreateHITOfType(hitTypeId string, q ExternalQuestion, lifetimeInSeconds uint, maxAssignments uint, requesterAnnotation string) (h *HIT, err error) {
+	return mt.CreateHITOfTypeWithContext(context.Background(), hitTypeId, q, lifetimeInSeconds, maxAssignments, requesterAnnotation)
+}
+
+// CreateHITOfTypeWithContext is like CreateHITOfType but makes its requests with ctx.
+func (mt *MTurk) CreateHITOfTypeWithContext(ctx context.Context, hitTypeId string, q ExternalQuestion, lifetimeInSeconds uint, maxAssignments uint, requesterAnnotation string) (h *HIT, err error) {
 	params := make(map[string]string)
 	params["HITTypeId"] = hitTypeId
 	params["Question"], err = xmlEncode(&q)
@@ -397,7 +431,7 @@ func (mt *MTurk) CreateHITOfType(hitTypeId string, q ExternalQuestion, lifetimeI
 	}
 
 	var response CreateHITResponse
-	err = mt.query(params, "CreateHIT", &response)
+	err = mt.query(ctx, params, "CreateHIT", &response)
 	if err == nil {
 		h = &response.HIT
 	}
@@ -406,10 +440,15 @@ func (mt *MTurk) CreateHITOfType(hitTypeId string, q ExternalQuestion, lifetimeI
 
 // Get the Assignments for a HIT.
 func (mt *MTurk) GetAssignmentsForHIT(hitId string) (r *Assignment, err error) {
+	return mt.GetAssignmentsForHITWithContext(context.Background(), hitId)
+}
+
+// GetAssignmentsForHITWithContext is like GetAssignmentsForHIT but makes its requests with ctx.
+func (mt *MTurk) GetAssignmentsForHITWithContext(ctx context.Context, hitId string) (r *Assignment, err error) {
 	params := make(map[string]string)
 	params["HITId"] = hitId
 	var response GetAssignmentsForHITResponse
-	err = mt.query(params, "GetAssignmentsForHIT", &response)
+	err = mt.query(ctx, params, "GetAssignmentsForHIT", &response)
 	if err == nil {
 		r = &response.GetAssignmentsForHITResult.Assignment
 	}
@@ -419,9 +458,14 @@ func (mt *MTurk) GetAssignmentsForHIT(hitId string) (r *Assignment, err error) {
 // Corresponds to "SearchHITs" operation of Mechanical Turk. http://goo.gl/PskcX
 // Currenlty supports none of the optional parameters.
 func (mt *MTurk) SearchHITs() (s *SearchHITsResult, err error) {
+	return mt.SearchHITsWithContext(context.Background())
+}
+
+// SearchHITsWithContext is like SearchHITs but makes its requests with ctx.
+func (mt *MTurk) SearchHITsWithContext(ctx context.Context) (s *SearchHITsResult, err error) {
 	params := make(map[string]string)
 	var response SearchHITsResponse
-	err = mt.query(params, "SearchHITs", &response)
+	err = mt.query(ctx, params, "SearchHITs", &response)
 	if err == nil {
 		s = &response.SearchHITsResult
 	}
@@ -432,32 +476,45 @@ func (mt *MTurk) SearchHITs() (s *SearchHITsResult, err error) {
 // adds the signature to the "params" map and sends the request
 // to the server.  It then unmarshals the response in to the "resp"
 // parameter using xml.Unmarshal()
-func (mt *MTurk) query(params map[string]string, operation string, resp interface{}) error {
+func (mt *MTurk) query(ctx context.Context, params map[string]string, operation string, resp interface{}) error {
 	service := "AWSMechanicalTurkRequester"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05Z")
-
-	params["AWSAccessKeyId"] = mt.Auth.AccessKey
 	params["Service"] = service
-	params["Timestamp"] = timestamp
 	params["Operation"] = operation
 
-	// make a copy
-	url := *mt.URL
-
-	sign(mt.Auth, service, operation, timestamp, params)
-	url.RawQuery = multimap(params).Encode()
-	r, err := http.Get(url.String())
-	if err != nil {
-		return err
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "mturk",
+		Operation:   operation,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: mt.RateLimiter,
+		Data:        resp,
 	}
-	//dump, _ := httputil.DumpResponse(r, true)
-	//println("DUMP:\n", string(dump))
-	if r.StatusCode != 200 {
-		return errors.New(fmt.Sprintf("%d: unexpected status code", r.StatusCode))
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", mt.URL.String(), nil)
+			return err
+		},
+		Sign: func(r *aws.Request) error {
+			timestamp := aws.Now().UTC().Format("2006-01-02T15:04:05Z")
+			auth := mt.Auth.Current()
+			params["AWSAccessKeyId"] = auth.AccessKey
+			params["Timestamp"] = timestamp
+			sign(auth, service, operation, timestamp, params)
+			r.HTTPRequest.URL.RawQuery = multimap(params).Encode()
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			//dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+			//println("DUMP:\n", string(dump))
+			if r.HTTPResponse.StatusCode != 200 {
+				return errors.New(fmt.Sprintf("%d: unexpected status code", r.HTTPResponse.StatusCode))
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	dec := xml.NewDecoder(r.Body)
-	err = dec.Decode(resp)
-	r.Body.Close()
 	return err
 }
 
@@ -21,6 +21,7 @@ package sdb
 //
 
 import (
+	"context"
 	"encoding/xml"
 	"github.com/hughe/goamz/aws"
 	"log"
@@ -37,12 +38,17 @@ const debug = false
 type SDB struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
 	private byte // Reserve the right of using private data.
 }
 
 // New creates a new SDB.
 func New(auth aws.Auth, region aws.Region) *SDB {
-	return &SDB{auth, region, 0}
+	return &SDB{auth, region.WithEndpoints(), nil, 0}
 }
 
 // The Domain type represents a collection of items that are described
@@ -94,7 +100,12 @@ type ListDomainsResp struct {
 //
 // See http://goo.gl/Dsw15 for more details.
 func (sdb *SDB) ListDomains() (resp *ListDomainsResp, err error) {
-	return sdb.ListDomainsN(0, "")
+	return sdb.ListDomainsWithContext(context.Background())
+}
+
+// ListDomainsWithContext is like ListDomains but makes its requests with ctx.
+func (sdb *SDB) ListDomainsWithContext(ctx context.Context) (resp *ListDomainsResp, err error) {
+	return sdb.ListDomainsNWithContext(ctx, 0, "")
 }
 
 // ListDomainsN lists domains in sdb up to maxDomains.
@@ -102,6 +113,11 @@ func (sdb *SDB) ListDomains() (resp *ListDomainsResp, err error) {
 //
 // See http://goo.gl/Dsw15 for more details.
 func (sdb *SDB) ListDomainsN(maxDomains int, nextToken string) (resp *ListDomainsResp, err error) {
+	return sdb.ListDomainsNWithContext(context.Background(), maxDomains, nextToken)
+}
+
+// ListDomainsNWithContext is like ListDomainsN but makes its requests with ctx.
+func (sdb *SDB) ListDomainsNWithContext(ctx context.Context, maxDomains int, nextToken string) (resp *ListDomainsResp, err error) {
 	params := makeParams("ListDomains")
 	if maxDomains != 0 {
 		params["MaxNumberOfDomains"] = []string{strconv.Itoa(maxDomains)}
@@ -110,10 +126,31 @@ func (sdb *SDB) ListDomainsN(maxDomains int, nextToken string) (resp *ListDomain
 		params["NextToken"] = []string{nextToken}
 	}
 	resp = &ListDomainsResp{}
-	err = sdb.query(nil, nil, params, nil, resp)
+	err = sdb.query(ctx, nil, nil, params, nil, resp)
 	return
 }
 
+// ListDomainsNPages returns a Pager over the pages of ListDomainsN results,
+// starting with the page of nextToken.
+func (sdb *SDB) ListDomainsNPages(maxDomains int, nextToken string) *aws.Pager[*ListDomainsResp] {
+	return aws.NewPager(func(ctx context.Context) (*ListDomainsResp, bool, error) {
+		resp, err := sdb.ListDomainsNWithContext(ctx, maxDomains, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListDomainsNAll returns the names of every domain, walking every page of ListDomainsN
+// results.
+func (sdb *SDB) ListDomainsNAll(ctx context.Context) ([]string, error) {
+	return aws.All(ctx, sdb.ListDomainsNPages(0, ""), func(resp *ListDomainsResp) []string {
+		return resp.Domains
+	})
+}
+
 // --- SelectExpression
 
 // Response to a Select request.
@@ -132,13 +169,18 @@ type SelectResp struct {
 //
 // See http://goo.gl/GTsSZ for more details.
 func (sdb *SDB) Select(expr string, consistent bool) (resp *SelectResp, err error) {
+	return sdb.SelectWithContext(context.Background(), expr, consistent)
+}
+
+// SelectWithContext is like Select but makes its requests with ctx.
+func (sdb *SDB) SelectWithContext(ctx context.Context, expr string, consistent bool) (resp *SelectResp, err error) {
 	resp = &SelectResp{}
 	params := makeParams("Select")
 	params["SelectExpression"] = []string{expr}
 	if consistent {
 		params["ConsistentRead"] = []string{"true"}
 	}
-	err = sdb.query(nil, nil, params, nil, resp)
+	err = sdb.query(ctx, nil, nil, params, nil, resp)
 	return
 }
 
@@ -151,9 +193,14 @@ func (sdb *SDB) Select(expr string, consistent bool) (resp *SelectResp, err erro
 //
 // See http://goo.gl/jDjGH for more details.
 func (domain *Domain) CreateDomain() (resp *SimpleResp, err error) {
+	return domain.CreateDomainWithContext(context.Background())
+}
+
+// CreateDomainWithContext is like CreateDomain but makes its requests with ctx.
+func (domain *Domain) CreateDomainWithContext(ctx context.Context) (resp *SimpleResp, err error) {
 	params := makeParams("CreateDomain")
 	resp = &SimpleResp{}
-	err = domain.SDB.query(domain, nil, params, nil, resp)
+	err = domain.SDB.query(ctx, domain, nil, params, nil, resp)
 	return
 }
 
@@ -161,9 +208,14 @@ func (domain *Domain) CreateDomain() (resp *SimpleResp, err error) {
 //
 // See http://goo.gl/S0dCL for more details.
 func (domain *Domain) DeleteDomain() (resp *SimpleResp, err error) {
+	return domain.DeleteDomainWithContext(context.Background())
+}
+
+// DeleteDomainWithContext is like DeleteDomain but makes its requests with ctx.
+func (domain *Domain) DeleteDomainWithContext(ctx context.Context) (resp *SimpleResp, err error) {
 	params := makeParams("DeleteDomain")
 	resp = &SimpleResp{}
-	err = domain.SDB.query(domain, nil, params, nil, resp)
+	err = domain.SDB.query(ctx, domain, nil, params, nil, resp)
 	return
 }
 
@@ -210,6 +262,11 @@ func (pa *PutAttrs) IfMissing(name string) {
 //
 // See http://goo.gl/yTAV4 for more details.
 func (item *Item) PutAttrs(attrs *PutAttrs) (resp *SimpleResp, err error) {
+	return item.PutAttrsWithContext(context.Background(), attrs)
+}
+
+// PutAttrsWithContext is like PutAttrs but makes its requests with ctx.
+func (item *Item) PutAttrsWithContext(ctx context.Context, attrs *PutAttrs) (resp *SimpleResp, err error) {
 	params := makeParams("PutAttributes")
 	resp = &SimpleResp{}
 
@@ -242,7 +299,7 @@ func (item *Item) PutAttrs(attrs *PutAttrs) (resp *SimpleResp, err error) {
 		expectedNum++
 	}
 
-	err = item.query(params, nil, resp)
+	err = item.query(ctx, params, nil, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -264,6 +321,11 @@ type AttrsResp struct {
 //
 // See http://goo.gl/45X1M for more details.
 func (item *Item) Attrs(names []string, consistent bool) (resp *AttrsResp, err error) {
+	return item.AttrsWithContext(context.Background(), names, consistent)
+}
+
+// AttrsWithContext is like Attrs but makes its requests with ctx.
+func (item *Item) AttrsWithContext(ctx context.Context, names []string, consistent bool) (resp *AttrsResp, err error) {
 	params := makeParams("GetAttributes")
 	params["ItemName"] = []string{item.Name}
 	if consistent {
@@ -276,7 +338,7 @@ func (item *Item) Attrs(names []string, consistent bool) (resp *AttrsResp, err e
 	}
 
 	resp = &AttrsResp{}
-	err = item.query(params, nil, resp)
+	err = item.query(ctx, params, nil, resp)
 	if err != nil {
 		return nil, err
 	}
@@ -300,6 +362,17 @@ func (err *Error) Error() string {
 	return err.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 // SimpleResp represents a response to an SDB request which on success
 // will return no other information besides ResponseMetadata.
 type SimpleResp struct {
@@ -323,15 +396,15 @@ func buildError(r *http.Response) error {
 // ----------------------------------------------------------------------------
 // Request dispatching logic.
 
-func (item *Item) query(params url.Values, headers http.Header, resp interface{}) error {
-	return item.Domain.SDB.query(item.Domain, item, params, headers, resp)
+func (item *Item) query(ctx context.Context, params url.Values, headers http.Header, resp interface{}) error {
+	return item.Domain.SDB.query(ctx, item.Domain, item, params, headers, resp)
 }
 
-func (domain *Domain) query(item *Item, params url.Values, headers http.Header, resp interface{}) error {
-	return domain.SDB.query(domain, item, params, headers, resp)
+func (domain *Domain) query(ctx context.Context, item *Item, params url.Values, headers http.Header, resp interface{}) error {
+	return domain.SDB.query(ctx, domain, item, params, headers, resp)
 }
 
-func (sdb *SDB) query(domain *Domain, item *Item, params url.Values, headers http.Header, resp interface{}) error {
+func (sdb *SDB) query(ctx context.Context, domain *Domain, item *Item, params url.Values, headers http.Header, resp interface{}) error {
 	// all SimpleDB operations have path="/"
 	method := "GET"
 	path := "/"
@@ -346,7 +419,6 @@ func (sdb *SDB) query(domain *Domain, item *Item, params url.Values, headers htt
 
 	// setup some default parameters
 	params["Version"] = []string{"2009-04-15"}
-	params["Timestamp"] = []string{time.Now().UTC().Format(time.RFC3339)}
 
 	// set the DomainName param (every request must have one)
 	if domain != nil {
@@ -364,45 +436,62 @@ func (sdb *SDB) query(domain *Domain, item *Item, params url.Values, headers htt
 		return err
 	}
 	headers["Host"] = []string{u.Host}
-	sign(sdb.Auth, method, path, params, headers)
-
 	u.Path = path
-	if len(params) > 0 {
-		u.RawQuery = params.Encode()
-	}
-	req := http.Request{
-		URL:        u,
-		Method:     method,
-		ProtoMajor: 1,
-		ProtoMinor: 1,
-		Close:      true,
-		Header:     headers,
-	}
 
-	if v, ok := headers["Content-Length"]; ok {
-		req.ContentLength, _ = strconv.ParseInt(v[0], 10, 64)
-		delete(headers, "Content-Length")
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "sdb",
+		Region:      sdb.Region.Name,
+		Operation:   params.Get("Action"),
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: sdb.RateLimiter,
 	}
-
-	r, err := http.DefaultClient.Do(&req)
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
-	}
-
-	// status code is always 200 when successful (since we're always doing a GET)
-	if r.StatusCode != 200 {
-		return buildError(r)
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) error {
+			r.HTTPRequest = &http.Request{
+				URL:        u,
+				Method:     method,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Close:      true,
+				Header:     headers,
+			}
+			if v, ok := headers["Content-Length"]; ok {
+				r.HTTPRequest.ContentLength, _ = strconv.ParseInt(v[0], 10, 64)
+				delete(headers, "Content-Length")
+			}
+			return nil
+		},
+		Sign: func(r *aws.Request) error {
+			signed := make(url.Values, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = []string{aws.Now().UTC().Format(time.RFC3339)}
+			sign(sdb.Auth.Current(), method, path, signed, r.HTTPRequest.Header)
+			r.HTTPRequest.URL.RawQuery = signed.Encode()
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+
+			// status code is always 200 when successful (since we're always doing a GET)
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+
+			// everything was fine, so unmarshal the XML and return what it's err is (if any)
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-
-	// everything was fine, so unmarshal the XML and return what it's err is (if any)
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -2,8 +2,10 @@
 package ses
 
 import (
+	"context"
 	"encoding/xml"
 	"github.com/hughe/goamz/aws"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -15,17 +17,26 @@ type SES struct {
 	auth   aws.Auth
 	region aws.Region
 	client *http.Client
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 // Initializes a pointer to an SES struct which can be used
 // to perform SES API calls.
 func NewSES(auth aws.Auth, region aws.Region) *SES {
-	ses := SES{auth, region, nil}
+	ses := SES{auth, region.WithEndpoints(), nil, nil}
 	return &ses
 }
 
 // Sends an email to the specifications stored in the Email struct.
 func (ses *SES) SendEmail(email *Email) error {
+	return ses.SendEmailWithContext(context.Background(), email)
+}
+
+// SendEmailWithContext is like SendEmail but makes its requests with ctx.
+func (ses *SES) SendEmailWithContext(ctx context.Context, email *Email) error {
 	data := make(url.Values)
 
 	index := 0
@@ -93,49 +104,68 @@ func (ses *SES) SendEmail(email *Email) error {
 		data.Add("Source", email.source)
 	}
 
-	return ses.doPost("SendEmail", data)
+	return ses.doPost(ctx, "SendEmail", data)
 }
 
 // Do an SES POST action.
-func (ses *SES) doPost(action string, data url.Values) error {
-	req := http.Request{
-		Method:     "POST",
-		ProtoMajor: 1,
-		ProtoMinor: 1,
-		Close:      true,
-		Header:     http.Header{}}
-
+func (ses *SES) doPost(ctx context.Context, action string, data url.Values) error {
 	URL, err := url.Parse(ses.region.SESEndpoint)
 	if err != nil {
 		return err
 	}
 	URL.Path = "/"
 
-	req.URL = URL
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	sign(ses.auth, "POST", req.Header)
-
-	data.Add("AWSAccessKeyId", ses.auth.AccessKey)
-	data.Add("Action", action)
-
-	body := data.Encode()
-	req.Header.Add("Content-Length", strconv.Itoa(len(body)))
-	req.Body = ioutil.NopCloser(strings.NewReader(body))
-
 	if ses.client == nil {
-		ses.client = &http.Client{}
+		ses.client = aws.UntimedRetryingClient
 	}
 
-	resp, err := ses.client.Do(&req)
-	if err != nil {
-		return err
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "email",
+		Region:      ses.region.Name,
+		Operation:   action,
+		Client:      ses.client,
+		RateLimiter: ses.RateLimiter,
 	}
-	if resp.StatusCode > 204 {
-		defer resp.Body.Close()
-		return buildError(resp)
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) error {
+			r.HTTPRequest = &http.Request{
+				Method:     "POST",
+				URL:        URL,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Close:      true,
+				Header:     http.Header{}}
+			r.HTTPRequest.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+			return nil
+		},
+		Sign: func(r *aws.Request) error {
+			req := r.HTTPRequest
+			auth := ses.auth.Current()
+			sign(auth, "POST", req.Header)
+
+			data.Set("AWSAccessKeyId", auth.AccessKey)
+			data.Set("Action", action)
+
+			body := data.Encode()
+			req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+			req.Body = ioutil.NopCloser(strings.NewReader(body))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(body)), nil
+			}
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if r.HTTPResponse.StatusCode > 204 {
+				return buildError(r.HTTPResponse)
+			}
+			return nil
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-
-	return nil
+	return err
 }
 
 func buildError(r *http.Response) *SESError {
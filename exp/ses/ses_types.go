@@ -1,6 +1,8 @@
 // ses_types
 package ses
 
+import "github.com/hughe/goamz/aws"
+
 // Private internal representation of message body.
 type Body struct {
 	Html Content
@@ -49,6 +51,17 @@ func (err *SESError) Error() string {
 	return err.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *SESError) ErrorCode() string    { return err.Code }
+func (err *SESError) ErrorMessage() string { return err.Message }
+func (err *SESError) HTTPStatusCode() int  { return 0 }
+func (err *SESError) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *SESError) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 // Returns a pointer to an empty but initialized Email.
 func NewEmail() *Email {
 	return &Email{
@@ -7,7 +7,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/hughe/goamz/aws"
-	"time"
 )
 
 const (
@@ -16,7 +15,7 @@ const (
 
 // Sign SES request as dictated by Amazon's Version 3 signature method.
 func sign(auth aws.Auth, method string, headers map[string][]string) {
-	date := time.Now().UTC().Format(AMZ_DATE_STYLE)
+	date := aws.Now().UTC().Format(AMZ_DATE_STYLE)
 	h := hmac.New(sha256.New, []byte(auth.SecretKey))
 	h.Write([]byte(date))
 	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
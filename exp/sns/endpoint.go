@@ -1,8 +1,11 @@
 package sns
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+
+	"github.com/hughe/goamz/aws"
 )
 
 type DeleteEndpointResponse struct {
@@ -33,6 +36,7 @@ type PlatformEndpoints struct {
 
 type ListEndpointsByPlatformApplicationResponse struct {
 	Endpoints []PlatformEndpoints `xml:"ListEndpointsByPlatformApplicationResult>Endpoints>member"`
+	NextToken string              `xml:"ListEndpointsByPlatformApplicationResult>NextToken"`
 	ResponseMetadata
 }
 
@@ -49,12 +53,17 @@ type SetEndpointAttributesResponse struct {
 //
 // See http://goo.gl/9SlUD9 for more details.
 func (sns *SNS) DeleteEndpoint(endpointArn string) (resp *DeleteEndpointResponse, err error) {
+	return sns.DeleteEndpointWithContext(context.Background(), endpointArn)
+}
+
+// DeleteEndpointWithContext is like DeleteEndpoint but makes its requests with ctx.
+func (sns *SNS) DeleteEndpointWithContext(ctx context.Context, endpointArn string) (resp *DeleteEndpointResponse, err error) {
 	resp = &DeleteEndpointResponse{}
 	params := makeParams("DeleteEndpoint")
 
 	params["EndpointArn"] = endpointArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 }
@@ -63,13 +72,18 @@ func (sns *SNS) DeleteEndpoint(endpointArn string) (resp *DeleteEndpointResponse
 //
 // See http://goo.gl/c8E5X1 for more details.
 func (sns *SNS) GetEndpointAttributes(endpointArn string) (resp *GetEndpointAttributesResponse, err error) {
+	return sns.GetEndpointAttributesWithContext(context.Background(), endpointArn)
+}
+
+// GetEndpointAttributesWithContext is like GetEndpointAttributes but makes its requests with ctx.
+func (sns *SNS) GetEndpointAttributesWithContext(ctx context.Context, endpointArn string) (resp *GetEndpointAttributesResponse, err error) {
 	resp = &GetEndpointAttributesResponse{}
 
 	params := makeParams("GetEndpointAttributes")
 
 	params["EndpointArn"] = endpointArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 }
@@ -78,6 +92,11 @@ func (sns *SNS) GetEndpointAttributes(endpointArn string) (resp *GetEndpointAttr
 //
 // See http://goo.gl/4tnngi for more details.
 func (sns *SNS) CreatePlatformEndpoint(options *PlatformEndpointOpt) (resp *CreatePlatformEndpointResponse, err error) {
+	return sns.CreatePlatformEndpointWithContext(context.Background(), options)
+}
+
+// CreatePlatformEndpointWithContext is like CreatePlatformEndpoint but makes its requests with ctx.
+func (sns *SNS) CreatePlatformEndpointWithContext(ctx context.Context, options *PlatformEndpointOpt) (resp *CreatePlatformEndpointResponse, err error) {
 
 	resp = &CreatePlatformEndpointResponse{}
 	params := makeParams("CreatePlatformEndpoint")
@@ -89,7 +108,7 @@ func (sns *SNS) CreatePlatformEndpoint(options *PlatformEndpointOpt) (resp *Crea
 		params["CustomUserData"] = options.CustomUserData
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 }
@@ -98,6 +117,11 @@ func (sns *SNS) CreatePlatformEndpoint(options *PlatformEndpointOpt) (resp *Crea
 //
 // See http://goo.gl/L7ioyR for more detail.
 func (sns *SNS) ListEndpointsByPlatformApplication(platformApplicationArn, nextToken string) (resp *ListEndpointsByPlatformApplicationResponse, err error) {
+	return sns.ListEndpointsByPlatformApplicationWithContext(context.Background(), platformApplicationArn, nextToken)
+}
+
+// ListEndpointsByPlatformApplicationWithContext is like ListEndpointsByPlatformApplication but makes its requests with ctx.
+func (sns *SNS) ListEndpointsByPlatformApplicationWithContext(ctx context.Context, platformApplicationArn, nextToken string) (resp *ListEndpointsByPlatformApplicationResponse, err error) {
 	resp = &ListEndpointsByPlatformApplicationResponse{}
 
 	params := makeParams("ListEndpointsByPlatformApplication")
@@ -108,15 +132,41 @@ func (sns *SNS) ListEndpointsByPlatformApplication(platformApplicationArn, nextT
 		params["NextToken"] = nextToken
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 
 }
 
+// ListEndpointsByPlatformApplicationPages returns a Pager over the pages of ListEndpointsByPlatformApplication results,
+// starting with the page of nextToken.
+func (sns *SNS) ListEndpointsByPlatformApplicationPages(platformApplicationArn, nextToken string) *aws.Pager[*ListEndpointsByPlatformApplicationResponse] {
+	return aws.NewPager(func(ctx context.Context) (*ListEndpointsByPlatformApplicationResponse, bool, error) {
+		resp, err := sns.ListEndpointsByPlatformApplicationWithContext(ctx, platformApplicationArn, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListEndpointsByPlatformApplicationAll returns every endpoint of the platform application, walking every page of ListEndpointsByPlatformApplication
+// results.
+func (sns *SNS) ListEndpointsByPlatformApplicationAll(ctx context.Context, platformApplicationArn string) ([]PlatformEndpoints, error) {
+	return aws.All(ctx, sns.ListEndpointsByPlatformApplicationPages(platformApplicationArn, ""), func(resp *ListEndpointsByPlatformApplicationResponse) []PlatformEndpoints {
+		return resp.Endpoints
+	})
+}
+
 // SetEndpointAttributes
 //
 // See http://goo.gl/GTktCj for more detail.
 func (sns *SNS) SetEndpointAttributes(options *SetEndpointAttributesOpt) (resp *SetEndpointAttributesResponse, err error) {
+	return sns.SetEndpointAttributesWithContext(context.Background(), options)
+}
+
+// SetEndpointAttributesWithContext is like SetEndpointAttributes but makes its requests with ctx.
+func (sns *SNS) SetEndpointAttributesWithContext(ctx context.Context, options *SetEndpointAttributesOpt) (resp *SetEndpointAttributesResponse, err error) {
 	resp = &SetEndpointAttributesResponse{}
 	params := makeParams("SetEndpointAttributes")
 
@@ -127,6 +177,6 @@ func (sns *SNS) SetEndpointAttributes(options *SetEndpointAttributesOpt) (resp *
 		params[fmt.Sprintf("Attributes.entry.%s.value", strconv.Itoa(i+1))] = attr.Value
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
@@ -1,6 +1,7 @@
 package sns
 
 import (
+	"context"
 	"strconv"
 )
 
@@ -17,6 +18,11 @@ type AddPermissionResponse struct {
 //
 // See http://goo.gl/mbY4a for more details.
 func (sns *SNS) AddPermission(permissions []Permission, Label, TopicArn string) (resp *AddPermissionResponse, err error) {
+	return sns.AddPermissionWithContext(context.Background(), permissions, Label, TopicArn)
+}
+
+// AddPermissionWithContext is like AddPermission but makes its requests with ctx.
+func (sns *SNS) AddPermissionWithContext(ctx context.Context, permissions []Permission, Label, TopicArn string) (resp *AddPermissionResponse, err error) {
 	resp = &AddPermissionResponse{}
 	params := makeParams("AddPermission")
 
@@ -28,7 +34,7 @@ func (sns *SNS) AddPermission(permissions []Permission, Label, TopicArn string)
 	params["Label"] = Label
 	params["TopicArn"] = TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -40,12 +46,17 @@ type RemovePermissionResponse struct {
 //
 // See http://goo.gl/wGl5j for more details.
 func (sns *SNS) RemovePermission(Label, TopicArn string) (resp *RemovePermissionResponse, err error) {
+	return sns.RemovePermissionWithContext(context.Background(), Label, TopicArn)
+}
+
+// RemovePermissionWithContext is like RemovePermission but makes its requests with ctx.
+func (sns *SNS) RemovePermissionWithContext(ctx context.Context, Label, TopicArn string) (resp *RemovePermissionResponse, err error) {
 	resp = &RemovePermissionResponse{}
 	params := makeParams("RemovePermission")
 
 	params["Label"] = Label
 	params["TopicArn"] = TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
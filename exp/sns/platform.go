@@ -1,8 +1,11 @@
 package sns
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+
+	"github.com/hughe/goamz/aws"
 )
 
 type CreatePlatformApplicationResponse struct {
@@ -40,7 +43,7 @@ type PlatformApplication struct {
 }
 
 type ListPlatformApplicationsResponse struct {
-	NextToken            string
+	NextToken            string                `xml:"ListPlatformApplicationsResult>NextToken"`
 	PlatformApplications []PlatformApplication `xml:"ListPlatformApplicationsResult>PlatformApplications>member"`
 	ResponseMetadata
 }
@@ -50,6 +53,11 @@ type ListPlatformApplicationsResponse struct {
 // See http://goo.gl/Mbbl6Z for more details.
 
 func (sns *SNS) CreatePlatformApplication(options *PlatformApplicationOpt) (resp *CreatePlatformApplicationResponse, err error) {
+	return sns.CreatePlatformApplicationWithContext(context.Background(), options)
+}
+
+// CreatePlatformApplicationWithContext is like CreatePlatformApplication but makes its requests with ctx.
+func (sns *SNS) CreatePlatformApplicationWithContext(ctx context.Context, options *PlatformApplicationOpt) (resp *CreatePlatformApplicationResponse, err error) {
 	resp = &CreatePlatformApplicationResponse{}
 	params := makeParams("CreatePlatformApplication")
 
@@ -61,7 +69,7 @@ func (sns *SNS) CreatePlatformApplication(options *PlatformApplicationOpt) (resp
 		params[fmt.Sprintf("Attributes.entry.%s.value", strconv.Itoa(i+1))] = attr.Value
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 
@@ -71,13 +79,18 @@ func (sns *SNS) CreatePlatformApplication(options *PlatformApplicationOpt) (resp
 //
 // See http://goo.gl/6GB3DN for more details.
 func (sns *SNS) DeletePlatformApplication(platformApplicationArn string) (resp *DeletePlatformApplicationResponse, err error) {
+	return sns.DeletePlatformApplicationWithContext(context.Background(), platformApplicationArn)
+}
+
+// DeletePlatformApplicationWithContext is like DeletePlatformApplication but makes its requests with ctx.
+func (sns *SNS) DeletePlatformApplicationWithContext(ctx context.Context, platformApplicationArn string) (resp *DeletePlatformApplicationResponse, err error) {
 	resp = &DeletePlatformApplicationResponse{}
 
 	params := makeParams("DeletePlatformApplication")
 
 	params["PlatformApplicationArn"] = platformApplicationArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 }
@@ -86,6 +99,11 @@ func (sns *SNS) DeletePlatformApplication(platformApplicationArn string) (resp *
 //
 // See http://goo.gl/GswJ8I for more details.
 func (sns *SNS) GetPlatformApplicationAttributes(platformApplicationArn, nextToken string) (resp *GetPlatformApplicationAttributesResponse, err error) {
+	return sns.GetPlatformApplicationAttributesWithContext(context.Background(), platformApplicationArn, nextToken)
+}
+
+// GetPlatformApplicationAttributesWithContext is like GetPlatformApplicationAttributes but makes its requests with ctx.
+func (sns *SNS) GetPlatformApplicationAttributesWithContext(ctx context.Context, platformApplicationArn, nextToken string) (resp *GetPlatformApplicationAttributesResponse, err error) {
 	resp = &GetPlatformApplicationAttributesResponse{}
 
 	params := makeParams("GetPlatformApplicationAttributes")
@@ -96,7 +114,7 @@ func (sns *SNS) GetPlatformApplicationAttributes(platformApplicationArn, nextTok
 		params["NextToken"] = nextToken
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 
 	return
 }
@@ -105,6 +123,11 @@ func (sns *SNS) GetPlatformApplicationAttributes(platformApplicationArn, nextTok
 //
 // See http://goo.gl/vQ3ooV for more detail.
 func (sns *SNS) ListPlatformApplications(nextToken string) (resp *ListPlatformApplicationsResponse, err error) {
+	return sns.ListPlatformApplicationsWithContext(context.Background(), nextToken)
+}
+
+// ListPlatformApplicationsWithContext is like ListPlatformApplications but makes its requests with ctx.
+func (sns *SNS) ListPlatformApplicationsWithContext(ctx context.Context, nextToken string) (resp *ListPlatformApplicationsResponse, err error) {
 	resp = &ListPlatformApplicationsResponse{}
 	params := makeParams("ListPlatformApplications")
 
@@ -112,14 +135,40 @@ func (sns *SNS) ListPlatformApplications(nextToken string) (resp *ListPlatformAp
 		params["NextToken"] = nextToken
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
+// ListPlatformApplicationsPages returns a Pager over the pages of ListPlatformApplications results,
+// starting with the page of nextToken.
+func (sns *SNS) ListPlatformApplicationsPages(nextToken string) *aws.Pager[*ListPlatformApplicationsResponse] {
+	return aws.NewPager(func(ctx context.Context) (*ListPlatformApplicationsResponse, bool, error) {
+		resp, err := sns.ListPlatformApplicationsWithContext(ctx, nextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		nextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListPlatformApplicationsAll returns every platform application, walking every page of ListPlatformApplications
+// results.
+func (sns *SNS) ListPlatformApplicationsAll(ctx context.Context) ([]PlatformApplication, error) {
+	return aws.All(ctx, sns.ListPlatformApplicationsPages(""), func(resp *ListPlatformApplicationsResponse) []PlatformApplication {
+		return resp.PlatformApplications
+	})
+}
+
 // SetPlatformApplicationAttributes
 //
 // See http://goo.gl/RWnzzb for more detail.
 func (sns *SNS) SetPlatformApplicationAttributes(options *SetPlatformApplicationAttributesOpt) (resp *SetPlatformApplicationAttributesResponse, err error) {
+	return sns.SetPlatformApplicationAttributesWithContext(context.Background(), options)
+}
+
+// SetPlatformApplicationAttributesWithContext is like SetPlatformApplicationAttributes but makes its requests with ctx.
+func (sns *SNS) SetPlatformApplicationAttributesWithContext(ctx context.Context, options *SetPlatformApplicationAttributesOpt) (resp *SetPlatformApplicationAttributesResponse, err error) {
 	resp = &SetPlatformApplicationAttributesResponse{}
 	params := makeParams("SetPlatformApplicationAttributes")
 
@@ -130,6 +179,6 @@ func (sns *SNS) SetPlatformApplicationAttributes(options *SetPlatformApplication
 		params[fmt.Sprintf("Attributes.entry.%s.value", strconv.Itoa(i+1))] = attr.Value
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
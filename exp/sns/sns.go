@@ -16,6 +16,7 @@ package sns
 // BUG(niemeyer): Package needs documentation.
 
 import (
+	"context"
 	"encoding/xml"
 	"net/http"
 	"net/url"
@@ -28,6 +29,11 @@ import (
 type SNS struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
 	private byte // Reserve the right of using private data.
 }
 
@@ -42,7 +48,7 @@ type ResponseMetadata struct {
 }
 
 func New(auth aws.Auth, region aws.Region) *SNS {
-	return &SNS{auth, region, 0}
+	return &SNS{auth, region.WithEndpoints(), nil, 0}
 }
 
 func makeParams(action string) map[string]string {
@@ -62,30 +68,62 @@ func (err *Error) Error() string {
 	return err.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	RequestId string
 	Errors    []Error `xml:"Errors>Error"`
 }
 
-func (sns *SNS) query(params map[string]string, resp interface{}) error {
-	params["Timestamp"] = time.Now().UTC().Format(time.RFC3339)
+func (sns *SNS) query(ctx context.Context, params map[string]string, resp interface{}) error {
 	u, err := url.Parse(sns.Region.SNSEndpoint)
 	if err != nil {
 		return err
 	}
 
-	sign(sns.Auth, "GET", "/", params, u.Host)
-	u.RawQuery = multimap(params).Encode()
-	r, err := http.Get(u.String())
-	if err != nil {
-		return err
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "sns",
+		Region:      sns.Region.Name,
+		Operation:   params["Action"],
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: sns.RateLimiter,
+		Data:        resp,
 	}
-	defer r.Body.Close()
-
-	if r.StatusCode != 200 {
-		return buildError(r)
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", u.String(), nil)
+			return err
+		},
+		Sign: func(r *aws.Request) error {
+			signed := make(map[string]string, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = aws.Now().UTC().Format(time.RFC3339)
+			sign(sns.Auth.Current(), "GET", "/", signed, u.Host)
+			r.HTTPRequest.URL.RawQuery = multimap(signed).Encode()
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
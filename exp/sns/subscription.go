@@ -1,5 +1,11 @@
 package sns
 
+import (
+	"context"
+
+	"github.com/hughe/goamz/aws"
+)
+
 type Subscription struct {
 	Endpoint        string
 	Owner           string
@@ -10,7 +16,7 @@ type Subscription struct {
 
 type ListSubscriptionsResp struct {
 	Subscriptions []Subscription `xml:"ListSubscriptionsResult>Subscriptions>member"`
-	NextToken     string
+	NextToken     string         `xml:"ListSubscriptionsResult>NextToken"`
 	ResponseMetadata
 }
 
@@ -49,6 +55,7 @@ type ConfirmSubscriptionOpt struct {
 
 type ListSubscriptionByTopicResponse struct {
 	Subscriptions []Subscription `xml:"ListSubscriptionsByTopicResult>Subscriptions>member"`
+	NextToken     string         `xml:"ListSubscriptionsByTopicResult>NextToken"`
 	ResponseMetadata
 }
 
@@ -61,6 +68,11 @@ type ListSubscriptionByTopicOpt struct {
 //
 // See http://goo.gl/AY2D8 for more details.
 func (sns *SNS) Publish(options *PublishOpt) (resp *PublishResp, err error) {
+	return sns.PublishWithContext(context.Background(), options)
+}
+
+// PublishWithContext is like Publish but makes its requests with ctx.
+func (sns *SNS) PublishWithContext(ctx context.Context, options *PublishOpt) (resp *PublishResp, err error) {
 	resp = &PublishResp{}
 	params := makeParams("Publish")
 
@@ -84,7 +96,7 @@ func (sns *SNS) Publish(options *PublishOpt) (resp *PublishResp, err error) {
 		params["TargetArn"] = options.TargetArn
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -92,6 +104,11 @@ func (sns *SNS) Publish(options *PublishOpt) (resp *PublishResp, err error) {
 //
 // See http://goo.gl/c3iGS for more details.
 func (sns *SNS) Subscribe(Endpoint, Protocol, TopicArn string) (resp *SubscribeResponse, err error) {
+	return sns.SubscribeWithContext(context.Background(), Endpoint, Protocol, TopicArn)
+}
+
+// SubscribeWithContext is like Subscribe but makes its requests with ctx.
+func (sns *SNS) SubscribeWithContext(ctx context.Context, Endpoint, Protocol, TopicArn string) (resp *SubscribeResponse, err error) {
 	resp = &SubscribeResponse{}
 	params := makeParams("Subscribe")
 
@@ -99,7 +116,7 @@ func (sns *SNS) Subscribe(Endpoint, Protocol, TopicArn string) (resp *SubscribeR
 	params["Protocol"] = Protocol
 	params["TopicArn"] = TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -107,12 +124,17 @@ func (sns *SNS) Subscribe(Endpoint, Protocol, TopicArn string) (resp *SubscribeR
 //
 // See http://goo.gl/4l5Ge for more details.
 func (sns *SNS) Unsubscribe(SubscriptionArn string) (resp *UnsubscribeResponse, err error) {
+	return sns.UnsubscribeWithContext(context.Background(), SubscriptionArn)
+}
+
+// UnsubscribeWithContext is like Unsubscribe but makes its requests with ctx.
+func (sns *SNS) UnsubscribeWithContext(ctx context.Context, SubscriptionArn string) (resp *UnsubscribeResponse, err error) {
 	resp = &UnsubscribeResponse{}
 	params := makeParams("Unsubscribe")
 
 	params["SubscriptionArn"] = SubscriptionArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -120,6 +142,11 @@ func (sns *SNS) Unsubscribe(SubscriptionArn string) (resp *UnsubscribeResponse,
 //
 // See http://goo.gl/3hXzH for more details.
 func (sns *SNS) ConfirmSubscription(options *ConfirmSubscriptionOpt) (resp *ConfirmSubscriptionResponse, err error) {
+	return sns.ConfirmSubscriptionWithContext(context.Background(), options)
+}
+
+// ConfirmSubscriptionWithContext is like ConfirmSubscription but makes its requests with ctx.
+func (sns *SNS) ConfirmSubscriptionWithContext(ctx context.Context, options *ConfirmSubscriptionOpt) (resp *ConfirmSubscriptionResponse, err error) {
 	resp = &ConfirmSubscriptionResponse{}
 	params := makeParams("ConfirmSubscription")
 
@@ -130,7 +157,7 @@ func (sns *SNS) ConfirmSubscription(options *ConfirmSubscriptionOpt) (resp *Conf
 	params["Token"] = options.Token
 	params["TopicArn"] = options.TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -138,19 +165,50 @@ func (sns *SNS) ConfirmSubscription(options *ConfirmSubscriptionOpt) (resp *Conf
 //
 // See http://goo.gl/k3aGn for more details.
 func (sns *SNS) ListSubscriptions(NextToken *string) (resp *ListSubscriptionsResp, err error) {
+	return sns.ListSubscriptionsWithContext(context.Background(), NextToken)
+}
+
+// ListSubscriptionsWithContext is like ListSubscriptions but makes its requests with ctx.
+func (sns *SNS) ListSubscriptionsWithContext(ctx context.Context, NextToken *string) (resp *ListSubscriptionsResp, err error) {
 	resp = &ListSubscriptionsResp{}
 	params := makeParams("ListSubscriptions")
 	if NextToken != nil {
 		params["NextToken"] = *NextToken
 	}
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
+// ListSubscriptionsPages returns a Pager over the pages of ListSubscriptions results,
+// starting with the page of NextToken.
+func (sns *SNS) ListSubscriptionsPages(NextToken *string) *aws.Pager[*ListSubscriptionsResp] {
+	return aws.NewPager(func(ctx context.Context) (*ListSubscriptionsResp, bool, error) {
+		resp, err := sns.ListSubscriptionsWithContext(ctx, NextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		NextToken = &resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListSubscriptionsAll returns every subscription, walking every page of ListSubscriptions
+// results.
+func (sns *SNS) ListSubscriptionsAll(ctx context.Context) ([]Subscription, error) {
+	return aws.All(ctx, sns.ListSubscriptionsPages(nil), func(resp *ListSubscriptionsResp) []Subscription {
+		return resp.Subscriptions
+	})
+}
+
 // ListSubscriptionByTopic
 //
 // See http://goo.gl/LaVcC for more details.
 func (sns *SNS) ListSubscriptionByTopic(options *ListSubscriptionByTopicOpt) (resp *ListSubscriptionByTopicResponse, err error) {
+	return sns.ListSubscriptionByTopicWithContext(context.Background(), options)
+}
+
+// ListSubscriptionByTopicWithContext is like ListSubscriptionByTopic but makes its requests with ctx.
+func (sns *SNS) ListSubscriptionByTopicWithContext(ctx context.Context, options *ListSubscriptionByTopicOpt) (resp *ListSubscriptionByTopicResponse, err error) {
 	resp = &ListSubscriptionByTopicResponse{}
 	params := makeParams("ListSbubscriptionByTopic")
 
@@ -160,6 +218,32 @@ func (sns *SNS) ListSubscriptionByTopic(options *ListSubscriptionByTopicOpt) (re
 
 	params["TopicArn"] = options.TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
+
+// ListSubscriptionByTopicPages returns a Pager over the pages of
+// ListSubscriptionByTopic results, starting with the page of
+// options.NextToken.
+func (sns *SNS) ListSubscriptionByTopicPages(options *ListSubscriptionByTopicOpt) *aws.Pager[*ListSubscriptionByTopicResponse] {
+	var next ListSubscriptionByTopicOpt
+	if options != nil {
+		next = *options
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListSubscriptionByTopicResponse, bool, error) {
+		resp, err := sns.ListSubscriptionByTopicWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.NextToken = resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListSubscriptionByTopicAll returns every subscription to the topic
+// options.TopicArn, walking every page of ListSubscriptionByTopic results.
+func (sns *SNS) ListSubscriptionByTopicAll(ctx context.Context, options *ListSubscriptionByTopicOpt) ([]Subscription, error) {
+	return aws.All(ctx, sns.ListSubscriptionByTopicPages(options), func(resp *ListSubscriptionByTopicResponse) []Subscription {
+		return resp.Subscriptions
+	})
+}
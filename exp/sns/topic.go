@@ -1,7 +1,10 @@
 package sns
 
 import (
+	"context"
 	"errors"
+
+	"github.com/hughe/goamz/aws"
 )
 
 type Topic struct {
@@ -11,7 +14,7 @@ type Topic struct {
 
 type ListTopicsResp struct {
 	Topics    []Topic `xml:"ListTopicsResult>Topics>member"`
-	NextToken string
+	NextToken string  `xml:"ListTopicsResult>NextToken"`
 	ResponseMetadata
 }
 
@@ -37,27 +40,58 @@ type SetTopicAttributesResponse struct {
 //
 // See http://goo.gl/lfrMK for more details.
 func (sns *SNS) ListTopics(NextToken *string) (resp *ListTopicsResp, err error) {
+	return sns.ListTopicsWithContext(context.Background(), NextToken)
+}
+
+// ListTopicsWithContext is like ListTopics but makes its requests with ctx.
+func (sns *SNS) ListTopicsWithContext(ctx context.Context, NextToken *string) (resp *ListTopicsResp, err error) {
 	resp = &ListTopicsResp{}
 	params := makeParams("ListTopics")
 	if NextToken != nil {
 		params["NextToken"] = *NextToken
 	}
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	for i, _ := range resp.Topics {
 		resp.Topics[i].SNS = sns
 	}
 	return
 }
 
+// ListTopicsPages returns a Pager over the pages of ListTopics results,
+// starting with the page of NextToken.
+func (sns *SNS) ListTopicsPages(NextToken *string) *aws.Pager[*ListTopicsResp] {
+	return aws.NewPager(func(ctx context.Context) (*ListTopicsResp, bool, error) {
+		resp, err := sns.ListTopicsWithContext(ctx, NextToken)
+		if err != nil {
+			return nil, false, err
+		}
+		NextToken = &resp.NextToken
+		return resp, resp.NextToken != "", nil
+	})
+}
+
+// ListTopicsAll returns every topic, walking every page of ListTopics
+// results.
+func (sns *SNS) ListTopicsAll(ctx context.Context) ([]Topic, error) {
+	return aws.All(ctx, sns.ListTopicsPages(nil), func(resp *ListTopicsResp) []Topic {
+		return resp.Topics
+	})
+}
+
 // CreateTopic
 //
 // See http://goo.gl/m9aAt for more details.
 func (sns *SNS) CreateTopic(Name string) (resp *CreateTopicResp, err error) {
+	return sns.CreateTopicWithContext(context.Background(), Name)
+}
+
+// CreateTopicWithContext is like CreateTopic but makes its requests with ctx.
+func (sns *SNS) CreateTopicWithContext(ctx context.Context, Name string) (resp *CreateTopicResp, err error) {
 	resp = &CreateTopicResp{}
 	params := makeParams("CreateTopic")
 	params["Name"] = Name
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	resp.Topic.SNS = sns
 	return
 }
@@ -66,10 +100,15 @@ func (sns *SNS) CreateTopic(Name string) (resp *CreateTopicResp, err error) {
 //
 // Helper function for deleting a topic
 func (topic *Topic) Delete() (resp *DeleteTopicResp, err error) {
+	return topic.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext is like Delete but makes its requests with ctx.
+func (topic *Topic) DeleteWithContext(ctx context.Context) (resp *DeleteTopicResp, err error) {
 	resp = &DeleteTopicResp{}
 	params := makeParams("DeleteTopic")
 	params["TopicArn"] = topic.TopicArn
-	err = topic.SNS.query(params, resp)
+	err = topic.SNS.query(ctx, params, resp)
 	return
 }
 
@@ -77,10 +116,15 @@ func (topic *Topic) Delete() (resp *DeleteTopicResp, err error) {
 //
 // See http://goo.gl/WXRoX for more details.
 func (sns *SNS) GetTopicAttributes(TopicArn string) (resp *GetTopicAttributesResp, err error) {
+	return sns.GetTopicAttributesWithContext(context.Background(), TopicArn)
+}
+
+// GetTopicAttributesWithContext is like GetTopicAttributes but makes its requests with ctx.
+func (sns *SNS) GetTopicAttributesWithContext(ctx context.Context, TopicArn string) (resp *GetTopicAttributesResp, err error) {
 	resp = &GetTopicAttributesResp{}
 	params := makeParams("GetTopicAttributes")
 	params["TopicArn"] = TopicArn
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
 
@@ -88,6 +132,11 @@ func (sns *SNS) GetTopicAttributes(TopicArn string) (resp *GetTopicAttributesRes
 //
 // See http://goo.gl/oVYW7 for more details.
 func (sns *SNS) SetTopicAttributes(AttributeName, AttributeValue, TopicArn string) (resp *SetTopicAttributesResponse, err error) {
+	return sns.SetTopicAttributesWithContext(context.Background(), AttributeName, AttributeValue, TopicArn)
+}
+
+// SetTopicAttributesWithContext is like SetTopicAttributes but makes its requests with ctx.
+func (sns *SNS) SetTopicAttributesWithContext(ctx context.Context, AttributeName, AttributeValue, TopicArn string) (resp *SetTopicAttributesResponse, err error) {
 	resp = &SetTopicAttributesResponse{}
 	params := makeParams("SetTopicAttributes")
 
@@ -99,6 +148,6 @@ func (sns *SNS) SetTopicAttributes(AttributeName, AttributeValue, TopicArn strin
 	params["AttributeValue"] = AttributeValue
 	params["TopicArn"] = TopicArn
 
-	err = sns.query(params, resp)
+	err = sns.query(ctx, params, resp)
 	return
 }
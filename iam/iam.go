@@ -3,7 +3,10 @@
 package iam
 
 import (
+	"context"
 	"encoding/xml"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -18,6 +21,10 @@ type IAM struct {
 	aws.Auth
 	aws.Region
 	httpClient *http.Client
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 // New creates a new IAM instance.
@@ -26,56 +33,73 @@ func New(auth aws.Auth, region aws.Region) *IAM {
 }
 
 func NewWithClient(auth aws.Auth, region aws.Region, httpClient *http.Client) *IAM {
-	return &IAM{auth, region, httpClient}
+	return &IAM{auth, region.WithEndpoints(), httpClient, nil}
 }
 
-func (iam *IAM) query(params map[string]string, resp interface{}) error {
-	params["Version"] = "2010-05-08"
-	params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
-	endpoint, err := url.Parse(iam.IAMEndpoint)
-	if err != nil {
-		return err
-	}
-	sign(iam.Auth, "GET", "/", params, endpoint.Host)
-	endpoint.RawQuery = multimap(params).Encode()
-	r, err := iam.httpClient.Get(endpoint.String())
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode > 200 {
-		return buildError(r)
-	}
+func (iam *IAM) query(ctx context.Context, params map[string]string, resp interface{}) error {
+	return iam.doQuery(ctx, "GET", params, resp)
+}
 
-	return xml.NewDecoder(r.Body).Decode(resp)
+func (iam *IAM) postQuery(ctx context.Context, params map[string]string, resp interface{}) error {
+	return iam.doQuery(ctx, "POST", params, resp)
 }
 
-func (iam *IAM) postQuery(params map[string]string, resp interface{}) error {
+// doQuery makes a request with params signed with Signature Version 2,
+// in the query string of a GET or the form body of a POST.
+func (iam *IAM) doQuery(ctx context.Context, method string, params map[string]string, resp interface{}) error {
 	endpoint, err := url.Parse(iam.IAMEndpoint)
 	if err != nil {
 		return err
 	}
 	params["Version"] = "2010-05-08"
-	params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
-	sign(iam.Auth, "POST", "/", params, endpoint.Host)
-	encoded := multimap(params).Encode()
-	body := strings.NewReader(encoded)
-	req, err := http.NewRequest("POST", endpoint.String(), body)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Host", endpoint.Host)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
-	r, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode > 200 {
-		return buildError(r)
-	}
-	return xml.NewDecoder(r.Body).Decode(resp)
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "iam",
+		Region:      iam.Region.Name,
+		Operation:   params["Action"],
+		Client:      iam.httpClient,
+		RateLimiter: iam.RateLimiter,
+		Data:        resp,
+	}
+	err = aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest(method, endpoint.String(), nil)
+			return err
+		},
+		Sign: func(r *aws.Request) error {
+			signed := make(map[string]string, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = aws.Now().In(time.UTC).Format(time.RFC3339)
+			sign(iam.Auth.Current(), method, "/", signed, endpoint.Host)
+			encoded := multimap(signed).Encode()
+			hreq := r.HTTPRequest
+			if method == "GET" {
+				hreq.URL.RawQuery = encoded
+				return nil
+			}
+			hreq.Body = ioutil.NopCloser(strings.NewReader(encoded))
+			hreq.ContentLength = int64(len(encoded))
+			hreq.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(encoded)), nil
+			}
+			hreq.Header.Set("Host", endpoint.Host)
+			hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			hreq.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if r.HTTPResponse.StatusCode > 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
+	})
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
+	}
+	return err
 }
 
 func buildError(r *http.Response) error {
@@ -124,13 +148,18 @@ type User struct {
 //
 // See http://goo.gl/JS9Gz for more details.
 func (iam *IAM) CreateUser(name, path string) (*CreateUserResp, error) {
+	return iam.CreateUserWithContext(context.Background(), name, path)
+}
+
+// CreateUserWithContext is like CreateUser but makes its requests with ctx.
+func (iam *IAM) CreateUserWithContext(ctx context.Context, name, path string) (*CreateUserResp, error) {
 	params := map[string]string{
 		"Action":   "CreateUser",
 		"Path":     path,
 		"UserName": name,
 	}
 	resp := new(CreateUserResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -148,12 +177,17 @@ type GetUserResp struct {
 //
 // See http://goo.gl/ZnzRN for more details.
 func (iam *IAM) GetUser(name string) (*GetUserResp, error) {
+	return iam.GetUserWithContext(context.Background(), name)
+}
+
+// GetUserWithContext is like GetUser but makes its requests with ctx.
+func (iam *IAM) GetUserWithContext(ctx context.Context, name string) (*GetUserResp, error) {
 	params := map[string]string{
 		"Action":   "GetUser",
 		"UserName": name,
 	}
 	resp := new(GetUserResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -163,12 +197,17 @@ func (iam *IAM) GetUser(name string) (*GetUserResp, error) {
 //
 // See http://goo.gl/jBuCG for more details.
 func (iam *IAM) DeleteUser(name string) (*SimpleResp, error) {
+	return iam.DeleteUserWithContext(context.Background(), name)
+}
+
+// DeleteUserWithContext is like DeleteUser but makes its requests with ctx.
+func (iam *IAM) DeleteUserWithContext(ctx context.Context, name string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":   "DeleteUser",
 		"UserName": name,
 	}
 	resp := new(SimpleResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -201,6 +240,11 @@ type Group struct {
 //
 // See http://goo.gl/n7NNQ for more details.
 func (iam *IAM) CreateGroup(name string, path string) (*CreateGroupResp, error) {
+	return iam.CreateGroupWithContext(context.Background(), name, path)
+}
+
+// CreateGroupWithContext is like CreateGroup but makes its requests with ctx.
+func (iam *IAM) CreateGroupWithContext(ctx context.Context, name string, path string) (*CreateGroupResp, error) {
 	params := map[string]string{
 		"Action":    "CreateGroup",
 		"GroupName": name,
@@ -209,7 +253,7 @@ func (iam *IAM) CreateGroup(name string, path string) (*CreateGroupResp, error)
 		params["Path"] = path
 	}
 	resp := new(CreateGroupResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -230,6 +274,11 @@ type GroupsResp struct {
 //
 // See http://goo.gl/W2TRj for more details.
 func (iam *IAM) Groups(pathPrefix string) (*GroupsResp, error) {
+	return iam.GroupsWithContext(context.Background(), pathPrefix)
+}
+
+// GroupsWithContext is like Groups but makes its requests with ctx.
+func (iam *IAM) GroupsWithContext(ctx context.Context, pathPrefix string) (*GroupsResp, error) {
 	params := map[string]string{
 		"Action": "ListGroups",
 	}
@@ -237,7 +286,7 @@ func (iam *IAM) Groups(pathPrefix string) (*GroupsResp, error) {
 		params["PathPrefix"] = pathPrefix
 	}
 	resp := new(GroupsResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -247,12 +296,17 @@ func (iam *IAM) Groups(pathPrefix string) (*GroupsResp, error) {
 //
 // See http://goo.gl/d5i2i for more details.
 func (iam *IAM) DeleteGroup(name string) (*SimpleResp, error) {
+	return iam.DeleteGroupWithContext(context.Background(), name)
+}
+
+// DeleteGroupWithContext is like DeleteGroup but makes its requests with ctx.
+func (iam *IAM) DeleteGroupWithContext(ctx context.Context, name string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":    "DeleteGroup",
 		"GroupName": name,
 	}
 	resp := new(SimpleResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -280,12 +334,17 @@ type AccessKey struct {
 //
 // See http://goo.gl/L46Py for more details.
 func (iam *IAM) CreateAccessKey(userName string) (*CreateAccessKeyResp, error) {
+	return iam.CreateAccessKeyWithContext(context.Background(), userName)
+}
+
+// CreateAccessKeyWithContext is like CreateAccessKey but makes its requests with ctx.
+func (iam *IAM) CreateAccessKeyWithContext(ctx context.Context, userName string) (*CreateAccessKeyResp, error) {
 	params := map[string]string{
 		"Action":   "CreateAccessKey",
 		"UserName": userName,
 	}
 	resp := new(CreateAccessKeyResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -306,6 +365,11 @@ type AccessKeysResp struct {
 //
 // See http://goo.gl/Vjozx for more details.
 func (iam *IAM) AccessKeys(userName string) (*AccessKeysResp, error) {
+	return iam.AccessKeysWithContext(context.Background(), userName)
+}
+
+// AccessKeysWithContext is like AccessKeys but makes its requests with ctx.
+func (iam *IAM) AccessKeysWithContext(ctx context.Context, userName string) (*AccessKeysResp, error) {
 	params := map[string]string{
 		"Action": "ListAccessKeys",
 	}
@@ -313,7 +377,7 @@ func (iam *IAM) AccessKeys(userName string) (*AccessKeysResp, error) {
 		params["UserName"] = userName
 	}
 	resp := new(AccessKeysResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -326,6 +390,11 @@ func (iam *IAM) AccessKeys(userName string) (*AccessKeysResp, error) {
 //
 // See http://goo.gl/hPGhw for more details.
 func (iam *IAM) DeleteAccessKey(id, userName string) (*SimpleResp, error) {
+	return iam.DeleteAccessKeyWithContext(context.Background(), id, userName)
+}
+
+// DeleteAccessKeyWithContext is like DeleteAccessKey but makes its requests with ctx.
+func (iam *IAM) DeleteAccessKeyWithContext(ctx context.Context, id, userName string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":      "DeleteAccessKey",
 		"AccessKeyId": id,
@@ -334,7 +403,7 @@ func (iam *IAM) DeleteAccessKey(id, userName string) (*SimpleResp, error) {
 		params["UserName"] = userName
 	}
 	resp := new(SimpleResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -361,13 +430,18 @@ type UserPolicy struct {
 //
 // See http://goo.gl/BH04O for more details.
 func (iam *IAM) GetUserPolicy(userName, policyName string) (*GetUserPolicyResp, error) {
+	return iam.GetUserPolicyWithContext(context.Background(), userName, policyName)
+}
+
+// GetUserPolicyWithContext is like GetUserPolicy but makes its requests with ctx.
+func (iam *IAM) GetUserPolicyWithContext(ctx context.Context, userName, policyName string) (*GetUserPolicyResp, error) {
 	params := map[string]string{
 		"Action":     "GetUserPolicy",
 		"UserName":   userName,
 		"PolicyName": policyName,
 	}
 	resp := new(GetUserPolicyResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -378,6 +452,11 @@ func (iam *IAM) GetUserPolicy(userName, policyName string) (*GetUserPolicyResp,
 //
 // See http://goo.gl/ldCO8 for more details.
 func (iam *IAM) PutUserPolicy(userName, policyName, policyDocument string) (*SimpleResp, error) {
+	return iam.PutUserPolicyWithContext(context.Background(), userName, policyName, policyDocument)
+}
+
+// PutUserPolicyWithContext is like PutUserPolicy but makes its requests with ctx.
+func (iam *IAM) PutUserPolicyWithContext(ctx context.Context, userName, policyName, policyDocument string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":         "PutUserPolicy",
 		"UserName":       userName,
@@ -385,7 +464,7 @@ func (iam *IAM) PutUserPolicy(userName, policyName, policyDocument string) (*Sim
 		"PolicyDocument": policyDocument,
 	}
 	resp := new(SimpleResp)
-	if err := iam.postQuery(params, resp); err != nil {
+	if err := iam.postQuery(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -395,13 +474,18 @@ func (iam *IAM) PutUserPolicy(userName, policyName, policyDocument string) (*Sim
 //
 // See http://goo.gl/7Jncn for more details.
 func (iam *IAM) DeleteUserPolicy(userName, policyName string) (*SimpleResp, error) {
+	return iam.DeleteUserPolicyWithContext(context.Background(), userName, policyName)
+}
+
+// DeleteUserPolicyWithContext is like DeleteUserPolicy but makes its requests with ctx.
+func (iam *IAM) DeleteUserPolicyWithContext(ctx context.Context, userName, policyName string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":     "DeleteUserPolicy",
 		"PolicyName": policyName,
 		"UserName":   userName,
 	}
 	resp := new(SimpleResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -418,13 +502,18 @@ type AddUserToGroupResp struct {
 //
 // See http://goo.gl/ZnzRN for more details.
 func (iam *IAM) AddUserToGroup(name, group string) (*AddUserToGroupResp, error) {
+	return iam.AddUserToGroupWithContext(context.Background(), name, group)
+}
+
+// AddUserToGroupWithContext is like AddUserToGroup but makes its requests with ctx.
+func (iam *IAM) AddUserToGroupWithContext(ctx context.Context, name, group string) (*AddUserToGroupResp, error) {
 
 	params := map[string]string{
 		"Action":    "AddUserToGroup",
 		"GroupName": group,
 		"UserName":  name}
 	resp := new(AddUserToGroupResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -442,11 +531,16 @@ type ListAccountAliasesResp struct {
 //
 // See http://goo.gl/MMN79v for more details.
 func (iam *IAM) ListAccountAliases() (*ListAccountAliasesResp, error) {
+	return iam.ListAccountAliasesWithContext(context.Background())
+}
+
+// ListAccountAliasesWithContext is like ListAccountAliases but makes its requests with ctx.
+func (iam *IAM) ListAccountAliasesWithContext(ctx context.Context) (*ListAccountAliasesResp, error) {
 	params := map[string]string{
 		"Action": "ListAccountAliases",
 	}
 	resp := new(ListAccountAliasesResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -463,12 +557,17 @@ type CreateAccountAliasResp struct {
 //
 // See http://goo.gl/oU5C4H for more details.
 func (iam *IAM) CreateAccountAlias(alias string) (*CreateAccountAliasResp, error) {
+	return iam.CreateAccountAliasWithContext(context.Background(), alias)
+}
+
+// CreateAccountAliasWithContext is like CreateAccountAlias but makes its requests with ctx.
+func (iam *IAM) CreateAccountAliasWithContext(ctx context.Context, alias string) (*CreateAccountAliasResp, error) {
 	params := map[string]string{
 		"Action":       "CreateAccountAlias",
 		"AccountAlias": alias,
 	}
 	resp := new(CreateAccountAliasResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -485,12 +584,17 @@ type DeleteAccountAliasResp struct {
 //
 // See http://goo.gl/hKalgg for more details.
 func (iam *IAM) DeleteAccountAlias(alias string) (*DeleteAccountAliasResp, error) {
+	return iam.DeleteAccountAliasWithContext(context.Background(), alias)
+}
+
+// DeleteAccountAliasWithContext is like DeleteAccountAlias but makes its requests with ctx.
+func (iam *IAM) DeleteAccountAliasWithContext(ctx context.Context, alias string) (*DeleteAccountAliasResp, error) {
 	params := map[string]string{
 		"Action":       "DeleteAccountAlias",
 		"AccountAlias": alias,
 	}
 	resp := new(DeleteAccountAliasResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -541,6 +645,12 @@ type UploadServerCertificateParams struct {
 //
 // See http://goo.gl/bomzce for more details.
 func (iam *IAM) UploadServerCertificate(options *UploadServerCertificateParams) (
+	*UploadServerCertificateResponse, error) {
+	return iam.UploadServerCertificateWithContext(context.Background(), options)
+}
+
+// UploadServerCertificateWithContext is like UploadServerCertificate but makes its requests with ctx.
+func (iam *IAM) UploadServerCertificateWithContext(ctx context.Context, options *UploadServerCertificateParams) (
 	*UploadServerCertificateResponse, error) {
 	params := map[string]string{
 		"Action":                "UploadServerCertificate",
@@ -556,7 +666,7 @@ func (iam *IAM) UploadServerCertificate(options *UploadServerCertificateParams)
 	}
 
 	resp := new(UploadServerCertificateResponse)
-	if err := iam.postQuery(params, resp); err != nil {
+	if err := iam.postQuery(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -579,9 +689,16 @@ type ListServerCertificatesResp struct {
 	ServerCertificates []ServerCertificateMetadata `xml:"ListServerCertificatesResult>ServerCertificateMetadataList>member>ServerCertificateMetadata"`
 	RequestId          string                      `xml:"ResponseMetadata>RequestId"`
 	IsTruncated        bool                        `xml:"ListServerCertificatesResult>IsTruncated"`
+	Marker             string                      `xml:"ListServerCertificatesResult>Marker"`
 }
 
 func (iam *IAM) ListServerCertificates(options *ListServerCertificatesParams) (
+	*ListServerCertificatesResp, error) {
+	return iam.ListServerCertificatesWithContext(context.Background(), options)
+}
+
+// ListServerCertificatesWithContext is like ListServerCertificates but makes its requests with ctx.
+func (iam *IAM) ListServerCertificatesWithContext(ctx context.Context, options *ListServerCertificatesParams) (
 	*ListServerCertificatesResp, error) {
 	params := map[string]string{
 		"Action": "ListServerCertificates",
@@ -596,24 +713,55 @@ func (iam *IAM) ListServerCertificates(options *ListServerCertificatesParams) (
 	}
 
 	resp := new(ListServerCertificatesResp)
-	if err := iam.query(params, resp); err != nil {
+	if err := iam.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 
 	return resp, nil
 }
 
+// ListServerCertificatesPages returns a Pager over the pages of
+// ListServerCertificates results, starting after options.Marker.
+func (iam *IAM) ListServerCertificatesPages(options *ListServerCertificatesParams) *aws.Pager[*ListServerCertificatesResp] {
+	var next ListServerCertificatesParams
+	if options != nil {
+		next = *options
+	}
+	return aws.NewPager(func(ctx context.Context) (*ListServerCertificatesResp, bool, error) {
+		resp, err := iam.ListServerCertificatesWithContext(ctx, &next)
+		if err != nil {
+			return nil, false, err
+		}
+		next.Marker = resp.Marker
+		return resp, resp.IsTruncated && resp.Marker != "", nil
+	})
+}
+
+// ListServerCertificatesAll returns the metadata of every server
+// certificate under options.PathPrefix, walking every page of
+// ListServerCertificates results.
+func (iam *IAM) ListServerCertificatesAll(ctx context.Context, options *ListServerCertificatesParams) ([]ServerCertificateMetadata, error) {
+	return aws.All(ctx, iam.ListServerCertificatesPages(options), func(resp *ListServerCertificatesResp) []ServerCertificateMetadata {
+		return resp.ServerCertificates
+	})
+}
+
 // DeleteServerCertificate deletes the specified server certificate.
 //
 // See http://goo.gl/W4nmxQ for more details.
 func (iam *IAM) DeleteServerCertificate(serverCertificateName string) (*SimpleResp, error) {
+	return iam.DeleteServerCertificateWithContext(context.Background(), serverCertificateName)
+}
+
+// DeleteServerCertificateWithContext is like DeleteServerCertificate but makes its requests with ctx.
+func (iam *IAM) DeleteServerCertificateWithContext(ctx context.Context, serverCertificateName string) (*SimpleResp, error) {
 	params := map[string]string{
 		"Action":                "DeleteServerCertificate",
 		"ServerCertificateName": serverCertificateName,
 	}
 
 	resp := new(SimpleResp)
-	if err := iam.postQuery(params, resp); err != nil {
+	if err := iam.postQuery(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -641,3 +789,14 @@ func (e *Error) Error() string {
 	}
 	return prefix + e.Message
 }
+
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (e *Error) ErrorCode() string    { return e.Code }
+func (e *Error) ErrorMessage() string { return e.Message }
+func (e *Error) HTTPStatusCode() int  { return e.StatusCode }
+func (e *Error) RequestID() string    { return "" }
+
+// Is reports whether e is of the class target, such as aws.ErrNotFound.
+func (e *Error) Is(target error) bool { return aws.ErrorIs(e, target) }
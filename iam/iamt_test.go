@@ -12,10 +12,11 @@ type LocalServer struct {
 	auth   aws.Auth
 	region aws.Region
 	srv    *iamtest.Server
+	config *iamtest.Config
 }
 
 func (s *LocalServer) SetUp(c *C) {
-	srv, err := iamtest.NewServer()
+	srv, err := iamtest.NewServerWithConfig(s.config)
 	c.Assert(err, IsNil)
 	c.Assert(srv, NotNil)
 
@@ -33,7 +34,35 @@ type LocalServerSuite struct {
 
 var _ = Suite(&LocalServerSuite{})
 
+var _ = Suite(&LocalServerSuite{
+	srv: LocalServer{
+		auth: aws.Auth{AccessKey: "access", SecretKey: "secret"},
+		config: &iamtest.Config{
+			Verifier: &aws.Verifier{
+				Credentials: aws.NewStaticCredentials(aws.Credentials{AccessKey: "access", SecretKey: "secret"}),
+			},
+		},
+	},
+})
+
 func (s *LocalServerSuite) SetUpSuite(c *C) {
 	s.srv.SetUp(c)
 	s.ClientTests.iam = iam.New(s.srv.auth, s.srv.region)
 }
+
+func (s *LocalServerSuite) TestVerifyRejectsBadSignatures(c *C) {
+	if s.srv.config == nil {
+		c.Skip("server does not verify signatures")
+	}
+	auth := s.srv.auth
+	auth.SecretKey = "wrong"
+	_, err := iam.New(auth, s.srv.region).GetUser("gopher")
+	c.Assert(err, NotNil)
+	c.Assert(err.(*iam.Error).StatusCode, Equals, 403)
+	c.Assert(err.(*iam.Error).Code, Equals, "SignatureDoesNotMatch")
+
+	auth.AccessKey = "unknown"
+	_, err = iam.New(auth, s.srv.region).GetUser("gopher")
+	c.Assert(err, NotNil)
+	c.Assert(err.(*iam.Error).Code, Equals, "InvalidAccessKeyId")
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/iam"
 	"net"
 	"net/http"
@@ -21,11 +22,15 @@ type action struct {
 	reqId string
 }
 
+// Config controls the behaviour of a Server.
+type Config = aws.ServerConfig
+
 // Server implements an IAM simulator for use in tests.
 type Server struct {
 	reqId        int
 	url          string
 	listener     net.Listener
+	config       *Config
 	users        []iam.User
 	groups       []iam.Group
 	accessKeys   []iam.AccessKey
@@ -34,6 +39,12 @@ type Server struct {
 }
 
 func NewServer() (*Server, error) {
+	return NewServerWithConfig(nil)
+}
+
+// NewServerWithConfig starts and returns a new server with the given
+// configuration, which may be nil.
+func NewServerWithConfig(config *Config) (*Server, error) {
 	l, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		return nil, fmt.Errorf("cannot listen on localhost: %v", err)
@@ -41,6 +52,7 @@ func NewServer() (*Server, error) {
 	srv := &Server{
 		listener: l,
 		url:      "http://" + l.Addr().String(),
+		config:   config,
 	}
 	go http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		srv.serveHTTP(w, req)
@@ -72,6 +84,10 @@ func (srv *Server) error(w http.ResponseWriter, err *iam.Error) {
 }
 
 func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := srv.config.VerifyRequest(w, req); err != nil {
+		srv.error(w, &iam.Error{StatusCode: err.HTTPStatusCode(), Code: err.Code, Message: err.Error()})
+		return
+	}
 	req.ParseForm()
 	srv.mutex.Lock()
 	defer srv.mutex.Unlock()
@@ -1,6 +1,7 @@
 package rds
 
 import (
+	"context"
 	"encoding/xml"
 	"github.com/hughe/goamz/aws"
 	"log"
@@ -22,7 +23,7 @@ type RDS struct {
 
 // New creates a new RDS Client.
 func New(auth aws.Auth, region aws.Region) (*RDS, error) {
-	service, err := aws.NewService(auth, region.RDSEndpoint)
+	service, err := aws.NewService(auth, region.WithEndpoints().RDSEndpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -35,11 +36,11 @@ func New(auth aws.Auth, region aws.Region) (*RDS, error) {
 // Request dispatching logic.
 
 // query dispatches a request to the RDS API signed with a version 2 signature
-func (rds *RDS) query(method, path string, params map[string]string, resp interface{}) error {
+func (rds *RDS) query(ctx context.Context, method, path string, params map[string]string, resp interface{}) error {
 	// Add basic RDS param
 	params["Version"] = ApiVersion
 
-	r, err := rds.Service.Query(method, path, params)
+	r, err := aws.QueryContext(ctx, rds.Service, method, path, params)
 	if err != nil {
 		return err
 	}
@@ -76,6 +77,11 @@ type DescribeDBInstancesResponse struct {
 //
 // See http://goo.gl/lzZMyz for more details.
 func (rds *RDS) DescribeDBInstances(id string, maxRecords int, marker string) (*DescribeDBInstancesResponse, error) {
+	return rds.DescribeDBInstancesWithContext(context.Background(), id, maxRecords, marker)
+}
+
+// DescribeDBInstancesWithContext is like DescribeDBInstances but makes its requests with ctx.
+func (rds *RDS) DescribeDBInstancesWithContext(ctx context.Context, id string, maxRecords int, marker string) (*DescribeDBInstancesResponse, error) {
 
 	params := aws.MakeParams("DescribeDBInstances")
 
@@ -91,6 +97,28 @@ func (rds *RDS) DescribeDBInstances(id string, maxRecords int, marker string) (*
 	}
 
 	resp := &DescribeDBInstancesResponse{}
-	err := rds.query("POST", "/", params, resp)
+	err := rds.query(ctx, "POST", "/", params, resp)
 	return resp, err
 }
+
+// DescribeDBInstancesPages returns a Pager over the pages of DescribeDBInstances results,
+// starting after marker.
+func (rds *RDS) DescribeDBInstancesPages(id string, maxRecords int, marker string) *aws.Pager[*DescribeDBInstancesResponse] {
+	return aws.NewPager(func(ctx context.Context) (*DescribeDBInstancesResponse, bool, error) {
+		resp, err := rds.DescribeDBInstancesWithContext(ctx, id, maxRecords, marker)
+		if err != nil {
+			return nil, false, err
+		}
+		marker = resp.Marker
+		return resp, resp.Marker != "", nil
+	})
+}
+
+// DescribeDBInstancesAll returns the description of the database instance id, or of
+// every instance if id is empty, walking every page of DescribeDBInstances
+// results.
+func (rds *RDS) DescribeDBInstancesAll(ctx context.Context, id string) ([]DBInstance, error) {
+	return aws.All(ctx, rds.DescribeDBInstancesPages(id, 0, ""), func(resp *DescribeDBInstancesResponse) []DBInstance {
+		return resp.DBInstances
+	})
+}
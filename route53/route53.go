@@ -2,6 +2,7 @@ package route53
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"github.com/hughe/goamz/aws"
@@ -14,6 +15,10 @@ type Route53 struct {
 	Endpoint string
 	Signer   *aws.Route53Signer
 	Service  *aws.Service
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
 }
 
 const route53_host = "https://route53.amazonaws.com"
@@ -163,49 +168,64 @@ type DeleteHostedZoneResponse struct {
 // with the required authentication and headers based on the Auth.
 //
 // Automatically decodes the response into the the result interface
-func (r *Route53) query(method string, path string, body io.Reader, result interface{}) error {
-	var err error
-
-	// Create the POST request and sign the headers
-	req, err := http.NewRequest(method, path, body)
-	r.Signer.Sign(req)
-
-	// Send the request and capture the response
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	if method == "POST" {
-		defer req.Body.Close()
+func (r *Route53) query(ctx context.Context, operation, method string, path string, body io.Reader, result interface{}) error {
+	req := &aws.Request{
+		Context:     ctx,
+		Service:     "route53",
+		Operation:   operation,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: r.RateLimiter,
+		Data:        result,
 	}
-
-	if res.StatusCode != 201 && res.StatusCode != 200 {
-		err = r.Service.BuildError(res)
-		return err
+	err := aws.DefaultPipeline.Run(req, aws.Handlers{
+		// Create the request and sign the headers
+		Build: func(req *aws.Request) (err error) {
+			req.HTTPRequest, err = http.NewRequest(method, path, body)
+			return err
+		},
+		Sign: func(req *aws.Request) error {
+			r.Signer.Sign(req.HTTPRequest)
+			return nil
+		},
+		Unmarshal: func(req *aws.Request) error {
+			res := req.HTTPResponse
+			if res.StatusCode != 201 && res.StatusCode != 200 {
+				return r.Service.BuildError(res)
+			}
+			return xml.NewDecoder(res.Body).Decode(req.Data)
+		},
+	})
+	if req.HTTPResponse != nil {
+		req.HTTPResponse.Body.Close()
 	}
-
-	err = xml.NewDecoder(res.Body).Decode(result)
-
 	return err
 }
 
 // CreateHostedZone send a creation request to the AWS Route53 API
 func (r *Route53) CreateHostedZone(hostedZoneReq *CreateHostedZoneRequest) (*CreateHostedZoneResponse, error) {
+	return r.CreateHostedZoneWithContext(context.Background(), hostedZoneReq)
+}
+
+// CreateHostedZoneWithContext is like CreateHostedZone but makes its requests with ctx.
+func (r *Route53) CreateHostedZoneWithContext(ctx context.Context, hostedZoneReq *CreateHostedZoneRequest) (*CreateHostedZoneResponse, error) {
 	xmlBytes, err := xml.Marshal(hostedZoneReq)
 	if err != nil {
 		return nil, err
 	}
 
 	result := new(CreateHostedZoneResponse)
-	err = r.query("POST", r.Endpoint, bytes.NewBuffer(xmlBytes), result)
+	err = r.query(ctx, "CreateHostedZone", "POST", r.Endpoint, bytes.NewBuffer(xmlBytes), result)
 
 	return result, err
 }
 
 // ChangeResourceRecordSet send a change resource record request to the AWS Route53 API
 func (r *Route53) ChangeResourceRecordSet(req *ChangeResourceRecordSetsRequest, zoneId string) (*ChangeResourceRecordSetsResponse, error) {
+	return r.ChangeResourceRecordSetWithContext(context.Background(), req, zoneId)
+}
+
+// ChangeResourceRecordSetWithContext is like ChangeResourceRecordSet but makes its requests with ctx.
+func (r *Route53) ChangeResourceRecordSetWithContext(ctx context.Context, req *ChangeResourceRecordSetsRequest, zoneId string) (*ChangeResourceRecordSetsResponse, error) {
 	xmlBytes, err := xml.Marshal(req)
 	if err != nil {
 		return nil, err
@@ -214,13 +234,18 @@ func (r *Route53) ChangeResourceRecordSet(req *ChangeResourceRecordSetsRequest,
 
 	result := new(ChangeResourceRecordSetsResponse)
 	path := fmt.Sprintf("%s/%s/rrset", r.Endpoint, zoneId)
-	err = r.query("POST", path, bytes.NewBuffer(xmlBytes), result)
+	err = r.query(ctx, "ChangeResourceRecordSets", "POST", path, bytes.NewBuffer(xmlBytes), result)
 
 	return result, err
 }
 
 // ListedHostedZones fetches a collection of HostedZones through the AWS Route53 API
 func (r *Route53) ListHostedZones(marker string, maxItems int) (result *ListHostedZonesResponse, err error) {
+	return r.ListHostedZonesWithContext(context.Background(), marker, maxItems)
+}
+
+// ListHostedZonesWithContext is like ListHostedZones but makes its requests with ctx.
+func (r *Route53) ListHostedZonesWithContext(ctx context.Context, marker string, maxItems int) (result *ListHostedZonesResponse, err error) {
 	path := ""
 
 	if marker == "" {
@@ -230,25 +255,56 @@ func (r *Route53) ListHostedZones(marker string, maxItems int) (result *ListHost
 	}
 
 	result = new(ListHostedZonesResponse)
-	err = r.query("GET", path, nil, result)
+	err = r.query(ctx, "ListHostedZones", "GET", path, nil, result)
 
 	return
 }
 
+// ListHostedZonesPages returns a Pager over the pages of ListHostedZones
+// results, starting after marker.
+func (r *Route53) ListHostedZonesPages(marker string, maxItems int) *aws.Pager[*ListHostedZonesResponse] {
+	return aws.NewPager(func(ctx context.Context) (*ListHostedZonesResponse, bool, error) {
+		resp, err := r.ListHostedZonesWithContext(ctx, marker, maxItems)
+		if err != nil {
+			return nil, false, err
+		}
+		marker = resp.NextMarker
+		return resp, resp.IsTruncated && marker != "", nil
+	})
+}
+
+// ListHostedZonesAll returns every hosted zone, walking every page of
+// ListHostedZones results.
+func (r *Route53) ListHostedZonesAll(ctx context.Context) ([]HostedZone, error) {
+	return aws.All(ctx, r.ListHostedZonesPages("", 100), func(resp *ListHostedZonesResponse) []HostedZone {
+		return resp.HostedZones
+	})
+}
+
 // GetHostedZone fetches a particular hostedzones DelegationSet by id
 func (r *Route53) GetHostedZone(id string) (result *GetHostedZoneResponse, err error) {
+	return r.GetHostedZoneWithContext(context.Background(), id)
+}
+
+// GetHostedZoneWithContext is like GetHostedZone but makes its requests with ctx.
+func (r *Route53) GetHostedZoneWithContext(ctx context.Context, id string) (result *GetHostedZoneResponse, err error) {
 	result = new(GetHostedZoneResponse)
-	err = r.query("GET", fmt.Sprintf("%s/%v", r.Endpoint, id), nil, result)
+	err = r.query(ctx, "GetHostedZone", "GET", fmt.Sprintf("%s/%v", r.Endpoint, id), nil, result)
 
 	return
 }
 
 // DeleteHostedZone deletes the hosted zone with the given id
 func (r *Route53) DeleteHostedZone(id string) (result *DeleteHostedZoneResponse, err error) {
+	return r.DeleteHostedZoneWithContext(context.Background(), id)
+}
+
+// DeleteHostedZoneWithContext is like DeleteHostedZone but makes its requests with ctx.
+func (r *Route53) DeleteHostedZoneWithContext(ctx context.Context, id string) (result *DeleteHostedZoneResponse, err error) {
 	path := fmt.Sprintf("%s/%s", r.Endpoint, id)
 
 	result = new(DeleteHostedZoneResponse)
-	err = r.query("DELETE", path, nil, result)
+	err = r.query(ctx, "DeleteHostedZone", "DELETE", path, nil, result)
 
 	return
 }
@@ -0,0 +1,200 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ErrNoCheckpoint is returned by ResumeUpload when there is no upload to
+// resume.
+var ErrNoCheckpoint = errors.New("s3: no upload checkpoint")
+
+// ErrUploadNotFound is returned by ResumeUpload when the upload to resume
+// no longer exists, having been completed or aborted.
+var ErrUploadNotFound = errors.New("s3: multipart upload not found")
+
+// An UploadCheckpoint is the saved progress of a multipart upload made by
+// an Uploader, from which the upload can be resumed.
+type UploadCheckpoint struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadId string `json:"uploadId"`
+
+	// PartSize is the Uploader.PartSize the upload started with, and
+	// Size the length of the content, or -1 if it was not known; the
+	// sizes of the parts follow from them.
+	PartSize int64 `json:"partSize"`
+	Size     int64 `json:"size"`
+
+	// Parts are the parts sent, ordered by part number.
+	Parts []CheckpointPart `json:"parts"`
+}
+
+// A CheckpointPart is a part sent by an upload.
+type CheckpointPart struct {
+	N    int    `json:"n"`
+	ETag string `json:"etag"`
+	Size int64  `json:"size"`
+	MD5  string `json:"md5"` // hex-encoded
+}
+
+// setPart adds p to c, in place of any part with the same number.
+func (c *UploadCheckpoint) setPart(p CheckpointPart) {
+	i := sort.Search(len(c.Parts), func(i int) bool { return c.Parts[i].N >= p.N })
+	if i < len(c.Parts) && c.Parts[i].N == p.N {
+		c.Parts[i] = p
+		return
+	}
+	c.Parts = append(c.Parts, CheckpointPart{})
+	copy(c.Parts[i+1:], c.Parts[i:])
+	c.Parts[i] = p
+}
+
+// A CheckpointStore holds the checkpoint of an upload.
+type CheckpointStore interface {
+	// LoadCheckpoint returns the checkpoint saved, or nil if there is
+	// none.
+	LoadCheckpoint() (*UploadCheckpoint, error)
+
+	// SaveCheckpoint saves c, in place of any checkpoint saved before.
+	// It is called as each part is sent.
+	SaveCheckpoint(c *UploadCheckpoint) error
+
+	// DeleteCheckpoint deletes the checkpoint saved, once the upload
+	// is complete.
+	DeleteCheckpoint() error
+}
+
+// A CheckpointFile is a CheckpointStore keeping the checkpoint as JSON in
+// the file it names.
+type CheckpointFile string
+
+func (f CheckpointFile) LoadCheckpoint() (*UploadCheckpoint, error) {
+	data, err := ioutil.ReadFile(string(f))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &UploadCheckpoint{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("s3: reading upload checkpoint %s: %v", f, err)
+	}
+	return c, nil
+}
+
+// SaveCheckpoint writes c to a temporary file that then replaces f, so
+// that f is never left half written.
+func (f CheckpointFile) SaveCheckpoint(c *UploadCheckpoint) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(string(f)), filepath.Base(string(f))+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), string(f))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (f CheckpointFile) DeleteCheckpoint() error {
+	err := os.Remove(string(f))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// ResumeUpload resumes the upload saved in u.Checkpoint, reading the
+// content from r again from the start. It returns ErrNoCheckpoint if
+// there is no upload to resume.
+//
+// The parts that were sent are listed, and those whose size and ETag
+// match the checkpoint, and whose MD5 sum matches the content read from
+// r, are kept; the others are sent again, along with the parts that were
+// not sent. Parts sent without having made it to the checkpoint are kept
+// if their ETag is the MD5 sum of their content.
+func (u *Uploader) ResumeUpload(r io.Reader) error {
+	return u.ResumeUploadWithContext(context.Background(), r)
+}
+
+// ResumeUploadWithContext is like ResumeUpload but makes its requests
+// with ctx.
+func (u *Uploader) ResumeUploadWithContext(ctx context.Context, r io.Reader) error {
+	if u.Checkpoint == nil {
+		return ErrNoCheckpoint
+	}
+	c, err := u.Checkpoint.LoadCheckpoint()
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return ErrNoCheckpoint
+	}
+	if c.Bucket != u.Bucket.Name {
+		return fmt.Errorf("s3: upload checkpoint is for bucket %s, not %s", c.Bucket, u.Bucket.Name)
+	}
+
+	multis, _, err := u.Bucket.ListMultiWithContext(ctx, c.Key, "")
+	if err != nil && !hasCode(err, "NoSuchUpload") {
+		return err
+	}
+	var m *Multi
+	for _, multi := range multis {
+		if multi.Key == c.Key && multi.UploadId == c.UploadId {
+			m = multi
+		}
+	}
+	if m == nil {
+		return fmt.Errorf("%w: upload %s of %s", ErrUploadNotFound, c.UploadId, c.Key)
+	}
+	parts, err := m.ListPartsWithContext(ctx)
+	if hasCode(err, "NoSuchUpload") {
+		return fmt.Errorf("%w: upload %s of %s", ErrUploadNotFound, c.UploadId, c.Key)
+	}
+	if err != nil {
+		return err
+	}
+
+	up := &upload{
+		m:          m,
+		partSize:   c.PartSize,
+		total:      c.Size,
+		uploaded:   make(map[int]Part),
+		store:      u.Checkpoint,
+		checkpoint: c,
+	}
+	for _, part := range parts {
+		up.uploaded[part.N] = part
+	}
+	// Forget the parts in the checkpoint that were not sent after all.
+	kept := c.Parts[:0]
+	for _, p := range c.Parts {
+		if part, ok := up.uploaded[p.N]; ok && part.ETag == p.ETag && part.Size == p.Size {
+			kept = append(kept, p)
+		}
+	}
+	c.Parts = kept
+	return u.finish(ctx, up, r)
+}
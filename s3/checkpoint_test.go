@@ -0,0 +1,114 @@
+package s3_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hughe/goamz/s3"
+	. "gopkg.in/check.v1"
+)
+
+func (s *S) TestUploadCheckpoint(c *C) {
+	s.DisableRetries()
+
+	testServer.Response(200, nil, InitMultiResultDump)
+	testServer.Response(200, map[string]string{"ETag": `"etag1"`}, "")
+	testServer.Response(500, nil, InternalErrorDump)
+
+	file := s3.CheckpointFile(filepath.Join(c.MkDir(), "upload.json"))
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.PartSize = 5
+	u.Concurrency = 1
+	u.PartRetries = -1
+	u.Checkpoint = file
+	err := u.Upload("multi", strings.NewReader("part1part2"), "text/plain", s3.Private)
+	c.Assert(err, NotNil)
+
+	// The upload is not aborted.
+	reqs := testServer.WaitRequests(3)
+	c.Assert(reqs[2].Method, Equals, "PUT")
+
+	cp, err := file.LoadCheckpoint()
+	c.Assert(err, IsNil)
+	c.Assert(cp.Bucket, Equals, "sample")
+	c.Assert(cp.Key, Equals, "multi")
+	c.Assert(cp.UploadId, Matches, "JNbR_[A-Za-z0-9.]+QQ--")
+	c.Assert(cp.PartSize, Equals, int64(5))
+	c.Assert(cp.Size, Equals, int64(10))
+	c.Assert(cp.Parts, DeepEquals, []s3.CheckpointPart{
+		{N: 1, ETag: `"etag1"`, Size: 5, MD5: "ffc88b4ca90a355f8ddba6b2c3b2af5c"},
+	})
+}
+
+func (s *S) TestResumeUpload(c *C) {
+	file := s3.CheckpointFile(filepath.Join(c.MkDir(), "upload.json"))
+	err := file.SaveCheckpoint(&s3.UploadCheckpoint{
+		Bucket:   "sample",
+		Key:      "multi1",
+		UploadId: "iUVug89pPvSswrikD",
+		PartSize: 5,
+		Size:     -1,
+		Parts: []s3.CheckpointPart{
+			{N: 1, ETag: `"ffc88b4ca90a355f8ddba6b2c3b2af5c"`, Size: 5, MD5: "ffc88b4ca90a355f8ddba6b2c3b2af5c"},
+			{N: 2, ETag: `"d067a0fa9dc61a6e7195ca99696b5a89"`, Size: 5, MD5: "d067a0fa9dc61a6e7195ca99696b5a89"},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	testServer.Response(200, nil, ListMultiResultDump)
+	testServer.Response(200, nil, ListPartsResultDump1)
+	testServer.Response(200, nil, ListPartsResultDump2)
+	testServer.Response(200, map[string]string{"ETag": `"etag2"`}, "")
+	testServer.Response(200, map[string]string{"ETag": `"etag4"`}, "")
+	testServer.Response(200, nil, "")
+
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.Concurrency = 1
+	u.Checkpoint = file
+	// Part 1 was sent, part 2 was sent with other content, and part 3
+	// was sent but not saved to the checkpoint.
+	err = u.ResumeUpload(strings.NewReader("part1partXpart3last"))
+	c.Assert(err, IsNil)
+
+	reqs := testServer.WaitRequests(6)
+	c.Assert(reqs[0].Method, Equals, "GET")
+	c.Assert(reqs[0].Form["uploads"], DeepEquals, []string{""})
+	c.Assert(reqs[0].Form["prefix"], DeepEquals, []string{"multi1"})
+	for _, req := range reqs[1:3] {
+		c.Assert(req.Method, Equals, "GET")
+		c.Assert(req.URL.Path, Equals, "/sample/multi1")
+		c.Assert(req.Form.Get("uploadId"), Equals, "iUVug89pPvSswrikD")
+	}
+	for i, body := range []string{"partX", "last"} {
+		req := reqs[3+i]
+		c.Assert(req.Method, Equals, "PUT")
+		c.Assert(req.Form["partNumber"], DeepEquals, []string{string('2' + rune(2*i))})
+		c.Assert(readAll(req.Body), Equals, body)
+	}
+	c.Assert(reqs[5].Method, Equals, "POST")
+	c.Assert(reqs[5].Form.Get("uploadId"), Equals, "iUVug89pPvSswrikD")
+	body := readAll(reqs[5].Body)
+	for _, etag := range []string{"ffc88b4ca90a355f8ddba6b2c3b2af5c", "etag2", "49dcd91231f801159e893fb5c6674985", "etag4"} {
+		c.Assert(strings.Contains(body, etag), Equals, true, Commentf("%s", body))
+	}
+
+	// The checkpoint goes once the upload is complete.
+	_, err = os.Stat(string(file))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *S) TestResumeUploadNotFound(c *C) {
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.Checkpoint = s3.CheckpointFile(filepath.Join(c.MkDir(), "upload.json"))
+	err := u.ResumeUpload(strings.NewReader(""))
+	c.Assert(err, Equals, s3.ErrNoCheckpoint)
+
+	err = u.Checkpoint.SaveCheckpoint(&s3.UploadCheckpoint{Bucket: "sample", Key: "multi1", UploadId: "gone"})
+	c.Assert(err, IsNil)
+	testServer.Response(200, nil, ListMultiResultDump)
+	err = u.ResumeUpload(strings.NewReader(""))
+	c.Assert(errors.Is(err, s3.ErrUploadNotFound), Equals, true, Commentf("%v", err))
+	testServer.WaitRequest()
+}
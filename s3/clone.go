@@ -1,5 +1,9 @@
 package s3
 
+import "github.com/hughe/goamz/aws"
+
+import "context"
+
 type ListClonesInProgressResult struct {
 	ClonesInProgress []CloneInProgress // array of UUIDs
 }
@@ -11,14 +15,20 @@ type CloneInProgress struct {
 
 // Note: This will list all in-progress clone jobs across ALL buckets
 func (s3 *S3) ListClones() (clones []CloneInProgress, err error) {
+	return s3.ListClonesWithContext(context.Background())
+}
+
+// ListClonesWithContext is like ListClones but makes its requests with ctx.
+func (s3 *S3) ListClonesWithContext(ctx context.Context) (clones []CloneInProgress, err error) {
 	params := map[string][]string{
 		"x-storreduce-clones-in-progress": []string{""},
 	}
 	var resp ListClonesInProgressResult
-	for attempt := s3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, s3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "GET",
-			params: params,
+			method:  "GET",
+			params:  params,
+			context: ctx,
 		}
 		req.rootCloneOp = true
 		err = s3.query(req, &resp)
@@ -38,14 +48,20 @@ type CloneBucketResp struct {
 }
 
 func (b *Bucket) StartClone(srcBucket string) (cloneBucketResp *CloneBucketResp, err error) {
+	return b.StartCloneWithContext(context.Background(), srcBucket)
+}
+
+// StartCloneWithContext is like StartClone but makes its requests with ctx.
+func (b *Bucket) StartCloneWithContext(ctx context.Context, srcBucket string) (cloneBucketResp *CloneBucketResp, err error) {
 	params := map[string][]string{
 		"x-storreduce-clone": []string{srcBucket},
 	}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "PUT",
-			params: params,
-			bucket: b.Name,
+			method:  "PUT",
+			params:  params,
+			bucket:  b.Name,
+			context: ctx,
 		}
 		req.rootCloneOp = true
 		cloneBucketResp = &CloneBucketResp{}
@@ -62,13 +78,19 @@ func (b *Bucket) StartClone(srcBucket string) (cloneBucketResp *CloneBucketResp,
 }
 
 func (s3 *S3) CompleteClone(cloneId string) (err error) {
+	return s3.CompleteCloneWithContext(context.Background(), cloneId)
+}
+
+// CompleteCloneWithContext is like CompleteClone but makes its requests with ctx.
+func (s3 *S3) CompleteCloneWithContext(ctx context.Context, cloneId string) (err error) {
 	params := map[string][]string{
 		"x-storreduce-complete-clone": []string{cloneId},
 	}
-	for attempt := s3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, s3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "PUT",
-			params: params,
+			method:  "PUT",
+			params:  params,
+			context: ctx,
 		}
 		req.rootCloneOp = true
 
@@ -85,13 +107,19 @@ func (s3 *S3) CompleteClone(cloneId string) (err error) {
 }
 
 func (s3 *S3) AbortClone(cloneId string) (err error) {
+	return s3.AbortCloneWithContext(context.Background(), cloneId)
+}
+
+// AbortCloneWithContext is like AbortClone but makes its requests with ctx.
+func (s3 *S3) AbortCloneWithContext(ctx context.Context, cloneId string) (err error) {
 	params := map[string][]string{
 		"x-storreduce-abort-clone": []string{cloneId},
 	}
-	for attempt := s3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, s3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "DELETE",
-			params: params,
+			method:  "DELETE",
+			params:  params,
+			context: ctx,
 		}
 		req.rootCloneOp = true
 
@@ -108,13 +136,19 @@ func (s3 *S3) AbortClone(cloneId string) (err error) {
 }
 
 func (s3 *S3) CompleteAbortClone(cloneId string) (err error) {
+	return s3.CompleteAbortCloneWithContext(context.Background(), cloneId)
+}
+
+// CompleteAbortCloneWithContext is like CompleteAbortClone but makes its requests with ctx.
+func (s3 *S3) CompleteAbortCloneWithContext(ctx context.Context, cloneId string) (err error) {
 	params := map[string][]string{
 		"x-storreduce-complete-abort-clone": []string{cloneId},
 	}
-	for attempt := s3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, s3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "DELETE",
-			params: params,
+			method:  "DELETE",
+			params:  params,
+			context: ctx,
 		}
 		req.rootCloneOp = true
 		err = s3.query(req, nil)
@@ -141,13 +175,19 @@ type StatusReport struct {
 }
 
 func (s3 *S3) GetCloneStatus(cloneId string) (resp *StatusReport, err error) {
+	return s3.GetCloneStatusWithContext(context.Background(), cloneId)
+}
+
+// GetCloneStatusWithContext is like GetCloneStatus but makes its requests with ctx.
+func (s3 *S3) GetCloneStatusWithContext(ctx context.Context, cloneId string) (resp *StatusReport, err error) {
 	params := map[string][]string{
 		"x-storreduce-clone-status": []string{cloneId},
 	}
-	for attempt := s3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, s3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "GET",
-			params: params,
+			method:  "GET",
+			params:  params,
+			context: ctx,
 		}
 		resp = &StatusReport{}
 		req.rootCloneOp = true
@@ -0,0 +1,165 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"io"
+	"sync"
+)
+
+// ErrObjectChanged is returned when an object is replaced while it is
+// being read in several requests.
+var ErrObjectChanged = errors.New("s3: object changed while being read")
+
+// DefaultDownloadPartSize is the size of the ranges a Downloader gets if
+// its PartSize is zero.
+const DefaultDownloadPartSize = 5 << 20
+
+// DefaultDownloadConcurrency is the number of ranges a Downloader gets at
+// once if its Concurrency is zero.
+const DefaultDownloadConcurrency = 5
+
+// A Downloader downloads objects with ranged GETs, getting several ranges
+// at once.
+//
+// The object is pinned to the version first seen by its ETag, sent with
+// each GET in an If-Match header, so that an object replaced during a
+// download fails it with ErrObjectChanged rather than leaving a mix of
+// versions. A range that fails to download is got again, after a wait
+// chosen as by aws.DefaultRetryPolicy, up to PartRetries times.
+type Downloader struct {
+	Bucket *Bucket
+
+	// PartSize is the size of the ranges, or DefaultDownloadPartSize if
+	// 0.
+	PartSize int64
+
+	// Concurrency is the number of ranges got at once, or
+	// DefaultDownloadConcurrency if 0.
+	Concurrency int
+
+	// PartRetries is the number of times a range that failed to
+	// download is got again, or DefaultPartRetries if 0. If negative,
+	// ranges are not got again, beyond the retries of
+	// Bucket.S3.AttemptStrategy.
+	PartRetries int
+}
+
+// NewDownloader returns a Downloader from b with the default settings.
+func NewDownloader(b *Bucket) *Downloader {
+	return &Downloader{Bucket: b}
+}
+
+// Download downloads the object at path to w, returning its size.
+func (d *Downloader) Download(w io.WriterAt, path string) (n int64, err error) {
+	return d.DownloadWithContext(context.Background(), w, path)
+}
+
+// DownloadWithContext is like Download but makes its requests with ctx.
+func (d *Downloader) DownloadWithContext(ctx context.Context, w io.WriterAt, path string) (n int64, err error) {
+	resp, err := d.Bucket.HeadWithContext(ctx, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	size, etag := resp.ContentLength, resp.Header.Get("ETag")
+	if size < 0 {
+		return 0, fmt.Errorf("s3: size of %s unknown", path)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	concurrency := d.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDownloadConcurrency
+	}
+	partSize := d.PartSize
+	if partSize <= 0 {
+		partSize = DefaultDownloadPartSize
+	}
+
+	var (
+		mu      sync.Mutex
+		written int64
+		failure error
+	)
+	offsets := make(chan int64)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range offsets {
+				length := partSize
+				if offset+length > size {
+					length = size - offset
+				}
+				err := d.getRange(ctx, w, path, etag, offset, length)
+				mu.Lock()
+				if err == nil {
+					written += length
+				} else if failure == nil {
+					failure = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+Ranges:
+	for offset := int64(0); offset < size; offset += partSize {
+		select {
+		case offsets <- offset:
+		case <-ctx.Done():
+			break Ranges
+		}
+	}
+	close(offsets)
+	wg.Wait()
+
+	if failure == nil && ctx.Err() != nil {
+		// The parent context is done.
+		failure = ctx.Err()
+	}
+	if failure != nil {
+		return written, failure
+	}
+	if written != size {
+		return written, fmt.Errorf("s3: downloaded %d of the %d bytes of %s", written, size, path)
+	}
+	return size, nil
+}
+
+// getRange writes length bytes of the object at path with ETag etag,
+// from offset, to w at the same offset, trying again if it fails, after
+// a wait, up to d.PartRetries times.
+func (d *Downloader) getRange(ctx context.Context, w io.WriterAt, path, etag string, offset, length int64) error {
+	retries := d.PartRetries
+	if retries == 0 {
+		retries = DefaultPartRetries
+	}
+	for try := 0; ; try++ {
+		err := d.tryRange(ctx, w, path, etag, offset, length)
+		if err == nil || try >= retries || ctx.Err() != nil || errors.Is(err, ErrObjectChanged) || aws.IsNotFound(err) || aws.IsAccessDenied(err) {
+			return err
+		}
+		if err := retryWait(ctx, try); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *Downloader) tryRange(ctx context.Context, w io.WriterAt, path, etag string, offset, length int64) error {
+	resp, err := d.Bucket.getRange(ctx, path, offset, length, etag)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	n, err := io.Copy(io.NewOffsetWriter(w, offset), io.LimitReader(resp.Body, length))
+	if err == nil && n != length {
+		err = fmt.Errorf("s3: got %d of the %d bytes of %s at offset %d", n, length, path, offset)
+	}
+	return err
+}
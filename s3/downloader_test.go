@@ -0,0 +1,98 @@
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/s3"
+	. "gopkg.in/check.v1"
+)
+
+var PreconditionFailedDump = `
+<?xml version="1.0" encoding="UTF-8"?>
+<Error>
+  <Code>PreconditionFailed</Code>
+  <Message>At least one of the pre-conditions you specified did not hold</Message>
+  <Condition>If-Match</Condition>
+  <RequestId>3F1B667FAD71C3D8</RequestId>
+</Error>
+`
+
+func (s *S) TestDownloaderRetriesRange(c *C) {
+	s.DisableRetries()
+
+	testServer.Response(200, map[string]string{"Content-Length": "10", "ETag": `"etag"`}, "")
+	testServer.Response(500, nil, InternalErrorDump)
+	testServer.Response(206, nil, "01234")
+	testServer.Response(206, nil, "56789")
+
+	f, err := os.Create(filepath.Join(c.MkDir(), "object"))
+	c.Assert(err, IsNil)
+	defer f.Close()
+	d := s3.NewDownloader(s.s3.Bucket("sample"))
+	d.PartSize = 5
+	d.Concurrency = 1
+	n, err := d.Download(f, "object")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(10))
+	data, err := ioutil.ReadFile(f.Name())
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "0123456789")
+
+	reqs := testServer.WaitRequests(4)
+	c.Assert(reqs[0].Method, Equals, "HEAD")
+	for i, r := range []string{"bytes=0-4", "bytes=0-4", "bytes=5-9"} {
+		req := reqs[i+1]
+		c.Assert(req.Method, Equals, "GET")
+		c.Assert(req.URL.Path, Equals, "/sample/object")
+		c.Assert(req.Header.Get("Range"), Equals, r)
+		c.Assert(req.Header.Get("If-Match"), Equals, `"etag"`)
+	}
+}
+
+func (s *S) TestDownloaderWaitsBeforeRetryingRange(c *C) {
+	s.DisableRetries()
+	defer func(base, max time.Duration) {
+		aws.DefaultRetryPolicy.BaseDelay, aws.DefaultRetryPolicy.MaxDelay = base, max
+	}(aws.DefaultRetryPolicy.BaseDelay, aws.DefaultRetryPolicy.MaxDelay)
+	aws.DefaultRetryPolicy.BaseDelay = time.Hour
+	aws.DefaultRetryPolicy.MaxDelay = time.Hour
+
+	testServer.Response(200, map[string]string{"Content-Length": "10", "ETag": `"etag"`}, "")
+	testServer.Response(503, nil, SlowDownDump)
+
+	f, err := os.Create(filepath.Join(c.MkDir(), "object"))
+	c.Assert(err, IsNil)
+	defer f.Close()
+	d := s3.NewDownloader(s.s3.Bucket("sample"))
+	d.PartSize = 10
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = d.DownloadWithContext(ctx, f, "object")
+	c.Assert(err, Equals, context.DeadlineExceeded)
+	c.Assert(time.Since(start) < 5*time.Second, Equals, true)
+
+	reqs := testServer.WaitRequests(2)
+	c.Assert(reqs[0].Method, Equals, "HEAD")
+	c.Assert(reqs[1].Header.Get("Range"), Equals, "bytes=0-9")
+}
+
+func (s *S) TestDownloaderObjectChanged(c *C) {
+	testServer.Response(200, map[string]string{"Content-Length": "10", "ETag": `"etag"`}, "")
+	testServer.Response(412, nil, PreconditionFailedDump)
+
+	f, err := os.Create(filepath.Join(c.MkDir(), "object"))
+	c.Assert(err, IsNil)
+	defer f.Close()
+	d := s3.NewDownloader(s.s3.Bucket("sample"))
+	d.Concurrency = 1
+	_, err = d.Download(f, "object")
+	c.Assert(errors.Is(err, s3.ErrObjectChanged), Equals, true, Commentf("%v", err))
+	testServer.WaitRequests(2)
+}
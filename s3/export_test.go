@@ -15,3 +15,7 @@ func SetListPartsMax(n int) {
 func SetListMultiMax(n int) {
 	listMultiMax = n
 }
+
+func UploaderPartSize(u *Uploader, n int, total int64) int64 {
+	return partSize(u.PartSize, n, total)
+}
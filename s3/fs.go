@@ -0,0 +1,278 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FS is an fs.FS of the objects in a bucket under a prefix, for code that
+// takes file systems, such as template loaders, http.FileServer (through
+// http.FS) and testing/fstest.
+//
+// The key of the file at a path is the prefix followed by the path.
+// Directories are the common prefixes of keys delimited by "/", so a
+// directory exists as long as there are keys in it, or there is an
+// object whose key ends with "/" as a marker. A key that is also a
+// common prefix is a file, and keys that do not make valid paths, such
+// as those with empty elements, cannot be reached.
+//
+// Files are ObjectReaders, which can seek and read at random; Stat and
+// ReadDir are served with HEAD requests and delimited lists.
+type FS struct {
+	ctx    context.Context
+	bucket *Bucket
+	prefix string // "" or ending with "/"
+}
+
+// FS returns a file system of the objects in b under prefix; if prefix is
+// not "" and does not end with "/", "/" is added to it.
+func (b *Bucket) FS(prefix string) *FS {
+	return b.FSWithContext(context.Background(), prefix)
+}
+
+// FSWithContext is like FS but the file system makes its requests with
+// ctx.
+func (b *Bucket) FSWithContext(ctx context.Context, prefix string) *FS {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &FS{ctx: ctx, bucket: b, prefix: prefix}
+}
+
+var errIsDir = errors.New("is a directory")
+
+// Open opens the file or directory name.
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		resp, err := f.head(name)
+		if err == nil {
+			return &fsFile{newObjectReader(f.ctx, f.bucket, f.prefix+name, resp), fileInfo(name, resp)}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		if err := f.dirExists(name); err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	return &fsDir{fsys: f, name: name}, nil
+}
+
+// Stat returns a FileInfo describing the file or directory name.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		resp, err := f.head(name)
+		if err == nil {
+			return fileInfo(name, resp), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+		if err := f.dirExists(name); err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+	}
+	return dirInfo(name), nil
+}
+
+// ReadDir reads the directory name, returning its entries sorted by
+// name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries, exists, err := f.list(name)
+	if err == nil && !exists {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+// Sub returns the file system of the objects under the directory dir.
+func (f *FS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return f, nil
+	}
+	return &FS{ctx: f.ctx, bucket: f.bucket, prefix: f.prefix + dir + "/"}, nil
+}
+
+// head HEADs the object of the file name. The error is fs.ErrNotExist if
+// there is no such object.
+func (f *FS) head(name string) (*http.Response, error) {
+	resp, err := f.bucket.HeadWithContext(f.ctx, f.prefix+name, nil)
+	if e, ok := err.(*Error); ok && e.StatusCode == 404 {
+		return nil, fs.ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.ContentLength < 0 {
+		return nil, fmt.Errorf("s3: size of %s unknown", f.prefix+name)
+	}
+	return resp, nil
+}
+
+// dirExists returns nil if there is a directory name, and
+// fs.ErrNotExist if not.
+func (f *FS) dirExists(name string) error {
+	resp, err := f.bucket.ListWithContext(f.ctx, f.prefix+name+"/", "/", "", 1)
+	if err != nil {
+		return err
+	}
+	if len(resp.Contents) == 0 && len(resp.CommonPrefixes) == 0 {
+		return fs.ErrNotExist
+	}
+	return nil
+}
+
+// list returns the entries of the directory name, sorted by name, and
+// whether it exists.
+func (f *FS) list(name string) (entries []fs.DirEntry, exists bool, err error) {
+	prefix := f.prefix
+	if name != "." {
+		prefix += name + "/"
+	}
+	files := make(map[string]bool)
+	var dirs []string
+	p := f.bucket.ListPages(prefix, "/", "", 0)
+	for p.Next(f.ctx) {
+		for _, k := range p.Page().Contents {
+			exists = true
+			base := k.Key[len(prefix):]
+			if validName(base) {
+				files[base] = true
+				entries = append(entries, fs.FileInfoToDirEntry(keyInfo(base, k)))
+			}
+		}
+		for _, cp := range p.Page().CommonPrefixes {
+			exists = true
+			dirs = append(dirs, strings.TrimSuffix(cp[len(prefix):], "/"))
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, false, err
+	}
+	for _, dir := range dirs {
+		if validName(dir) && !files[dir] {
+			entries = append(entries, fs.FileInfoToDirEntry(dirInfo(dir)))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, exists || name == ".", nil
+}
+
+// validName reports whether name is a valid name of a directory entry.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.Contains(name, "/")
+}
+
+// fsInfo implements fs.FileInfo.
+type fsInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func fileInfo(name string, resp *http.Response) *fsInfo {
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return &fsInfo{name: path.Base(name), size: resp.ContentLength, modTime: modTime.UTC()}
+}
+
+func keyInfo(name string, k Key) *fsInfo {
+	// Listings give times to the millisecond, HEAD to the second.
+	modTime, _ := time.Parse(time.RFC3339, k.LastModified)
+	return &fsInfo{name: name, size: k.Size, modTime: modTime.UTC().Truncate(time.Second)}
+}
+
+func dirInfo(name string) *fsInfo {
+	return &fsInfo{name: path.Base(name), dir: true}
+}
+
+func (i *fsInfo) Name() string       { return i.name }
+func (i *fsInfo) Size() int64        { return i.size }
+func (i *fsInfo) ModTime() time.Time { return i.modTime }
+func (i *fsInfo) IsDir() bool        { return i.dir }
+func (i *fsInfo) Sys() interface{}   { return nil }
+
+func (i *fsInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// fsFile is a file of an FS.
+type fsFile struct {
+	*ObjectReader
+	info *fsInfo
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// fsDir is a directory of an FS. Its entries are listed when first read.
+type fsDir struct {
+	fsys    *FS
+	name    string
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return dirInfo(d.name), nil
+}
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errIsDir}
+}
+
+func (d *fsDir) Close() error {
+	return nil
+}
+
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, _, err := d.fsys.list(d.name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
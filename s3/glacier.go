@@ -2,9 +2,11 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"net/http"
 	"regexp"
 	"time"
@@ -43,6 +45,11 @@ func (t Tier) String() string {
 }
 
 func (b *Bucket) RestoreObject(key string, days uint, tier Tier) (started bool, err error) {
+	return b.RestoreObjectWithContext(context.Background(), key, days, tier)
+}
+
+// RestoreObjectWithContext is like RestoreObject but makes its requests with ctx.
+func (b *Bucket) RestoreObjectWithContext(ctx context.Context, key string, days uint, tier Tier) (started bool, err error) {
 	if !b.S3.v4sign {
 		return false, errors.New("SigV4 only")
 	}
@@ -63,13 +70,14 @@ func (b *Bucket) RestoreObject(key string, days uint, tier Tier) (started bool,
 		"restore": {""},
 	}
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "POST",
 			bucket:  b.Name,
 			path:    key,
 			params:  params,
 			payload: bytes.NewReader(data),
+			context: ctx,
 		}
 		var statusCode int
 		statusCode, err = b.S3.queryWithStatus(req, nil)
@@ -140,7 +148,12 @@ func (r *RestoreStatus) ParseAmzRestore(s string) (err error) {
 }
 
 func (b *Bucket) GetRestoreStatus(key string) (status RestoreStatus, err error) {
-	resp, err := b.Head(key, nil)
+	return b.GetRestoreStatusWithContext(context.Background(), key)
+}
+
+// GetRestoreStatusWithContext is like GetRestoreStatus but makes its requests with ctx.
+func (b *Bucket) GetRestoreStatusWithContext(ctx context.Context, key string) (status RestoreStatus, err error) {
+	resp, err := b.HeadWithContext(ctx, key, nil)
 	if err != nil {
 		return status, err
 	}
@@ -2,16 +2,23 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/xml"
 	"errors"
+	"github.com/hughe/goamz/aws"
 	"strconv"
 
 	"github.com/hughe/goamz/s3/lifecycle"
 )
 
 func (b *Bucket) GetLifecycle() (result *lifecycle.Configuration, err error) {
+	return b.GetLifecycleWithContext(context.Background())
+}
+
+// GetLifecycleWithContext is like GetLifecycle but makes its requests with ctx.
+func (b *Bucket) GetLifecycleWithContext(ctx context.Context) (result *lifecycle.Configuration, err error) {
 	if !b.S3.v4sign {
 		return nil, errors.New("SigV4 only")
 	}
@@ -22,10 +29,11 @@ func (b *Bucket) GetLifecycle() (result *lifecycle.Configuration, err error) {
 
 	result = &lifecycle.Configuration{}
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			bucket: b.Name,
-			params: params,
+			bucket:  b.Name,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, result)
 		if !ShouldRetry(err) {
@@ -40,6 +48,11 @@ func (b *Bucket) GetLifecycle() (result *lifecycle.Configuration, err error) {
 }
 
 func (b *Bucket) PutLifecycle(lc *lifecycle.Configuration) (err error) {
+	return b.PutLifecycleWithContext(context.Background(), lc)
+}
+
+// PutLifecycleWithContext is like PutLifecycle but makes its requests with ctx.
+func (b *Bucket) PutLifecycleWithContext(ctx context.Context, lc *lifecycle.Configuration) (err error) {
 	if !b.S3.v4sign {
 		return errors.New("SigV4 only")
 	}
@@ -67,13 +80,14 @@ func (b *Bucket) PutLifecycle(lc *lifecycle.Configuration) (err error) {
 	// Can't use PutBucketSubresource because it does not provide set
 	// the Content-MD5 header, or retry.
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "PUT",
 			headers: headers,
 			bucket:  b.Name,
 			params:  params,
 			payload: bytes.NewReader(data),
+			context: ctx,
 		}
 		err = b.S3.query(req, nil)
 		if ShouldRetry(err) && attempt.HasNext() {
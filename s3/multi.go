@@ -2,12 +2,14 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"io"
 	"sort"
 	"strconv"
@@ -55,17 +57,23 @@ type listMultiResp struct {
 //
 // See http://goo.gl/ePioY for details.
 func (b *Bucket) ListMulti(prefix, delim string) (multis []*Multi, prefixes []string, err error) {
+	return b.ListMultiWithContext(context.Background(), prefix, delim)
+}
+
+// ListMultiWithContext is like ListMulti but makes its requests with ctx.
+func (b *Bucket) ListMultiWithContext(ctx context.Context, prefix, delim string) (multis []*Multi, prefixes []string, err error) {
 	params := map[string][]string{
 		"uploads":     {""},
 		"max-uploads": {strconv.FormatInt(int64(listMultiMax), 10)},
 		"prefix":      {prefix},
 		"delimiter":   {delim},
 	}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "GET",
-			bucket: b.Name,
-			params: params,
+			method:  "GET",
+			bucket:  b.Name,
+			params:  params,
+			context: ctx,
 		}
 		var resp listMultiResp
 		err = b.S3.query(req, &resp)
@@ -86,7 +94,7 @@ func (b *Bucket) ListMulti(prefix, delim string) (multis []*Multi, prefixes []st
 		}
 		params["key-marker"] = []string{resp.NextKeyMarker}
 		params["upload-id-marker"] = []string{resp.NextUploadIdMarker}
-		attempt = b.S3.AttemptStrategy.Start() // Last request worked.
+		attempt = aws.StartContext(ctx, b.S3.AttemptStrategy) // Last request worked.
 	}
 	panic("unreachable")
 }
@@ -95,7 +103,12 @@ func (b *Bucket) ListMulti(prefix, delim string) (multis []*Multi, prefixes []st
 // inside b. If a multipart upload exists for key, it is returned,
 // otherwise a new multipart upload is initiated with contType and perm.
 func (b *Bucket) Multi(key, contType string, perm ACL) (*Multi, error) {
-	return b.MultiWithOptions(key, contType, perm, MultiOptions{})
+	return b.MultiWithContext(context.Background(), key, contType, perm)
+}
+
+// MultiWithContext is like Multi but makes its requests with ctx.
+func (b *Bucket) MultiWithContext(ctx context.Context, key, contType string, perm ACL) (*Multi, error) {
+	return b.MultiWithOptionsWithContext(ctx, key, contType, perm, MultiOptions{})
 }
 
 // Multi returns a multipart upload handler for the provided key
@@ -103,7 +116,12 @@ func (b *Bucket) Multi(key, contType string, perm ACL) (*Multi, error) {
 // otherwise a new multipart upload is initiated with contType and perm.
 // Options can be used to send specific headesrs (e.g. to request Server-Side Encryption)
 func (b *Bucket) MultiWithOptions(key, contType string, perm ACL, options MultiOptions) (*Multi, error) {
-	multis, _, err := b.ListMulti(key, "")
+	return b.MultiWithOptionsWithContext(context.Background(), key, contType, perm, options)
+}
+
+// MultiWithOptionsWithContext is like MultiWithOptions but makes its requests with ctx.
+func (b *Bucket) MultiWithOptionsWithContext(ctx context.Context, key, contType string, perm ACL, options MultiOptions) (*Multi, error) {
+	multis, _, err := b.ListMultiWithContext(ctx, key, "")
 	if err != nil && !hasCode(err, "NoSuchUpload") {
 		return nil, err
 	}
@@ -112,7 +130,7 @@ func (b *Bucket) MultiWithOptions(key, contType string, perm ACL, options MultiO
 			return m, nil
 		}
 	}
-	return b.InitMultiWithOptions(key, contType, perm, options)
+	return b.InitMultiWithOptionsWithContext(ctx, key, contType, perm, options)
 }
 
 // InitMulti initializes a new multipart upload at the provided
@@ -120,7 +138,12 @@ func (b *Bucket) MultiWithOptions(key, contType string, perm ACL, options MultiO
 //
 // See http://goo.gl/XP8kL for details.
 func (b *Bucket) InitMulti(key string, contType string, perm ACL) (*Multi, error) {
-	return b.InitMultiWithOptions(key, contType, perm, MultiOptions{})
+	return b.InitMultiWithContext(context.Background(), key, contType, perm)
+}
+
+// InitMultiWithContext is like InitMulti but makes its requests with ctx.
+func (b *Bucket) InitMultiWithContext(ctx context.Context, key string, contType string, perm ACL) (*Multi, error) {
+	return b.InitMultiWithOptionsWithContext(ctx, key, contType, perm, MultiOptions{})
 }
 
 // InitMulti initializes a new multipart upload at the provided
@@ -129,6 +152,11 @@ func (b *Bucket) InitMulti(key string, contType string, perm ACL) (*Multi, error
 //
 // See http://goo.gl/XP8kL for details.
 func (b *Bucket) InitMultiWithOptions(key string, contType string, perm ACL, options MultiOptions) (*Multi, error) {
+	return b.InitMultiWithOptionsWithContext(context.Background(), key, contType, perm, options)
+}
+
+// InitMultiWithOptionsWithContext is like InitMultiWithOptions but makes its requests with ctx.
+func (b *Bucket) InitMultiWithOptionsWithContext(ctx context.Context, key string, contType string, perm ACL, options MultiOptions) (*Multi, error) {
 	headers := map[string][]string{
 		"Content-Type":   {contType},
 		"Content-Length": {"0"},
@@ -146,12 +174,13 @@ func (b *Bucket) InitMultiWithOptions(key string, contType string, perm ACL, opt
 		path:    key,
 		headers: headers,
 		params:  params,
+		context: ctx,
 	}
 	var err error
 	var resp struct {
 		UploadId string `xml:"UploadId"`
 	}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		err = b.S3.query(req, &resp)
 		if !ShouldRetry(err) {
 			break
@@ -168,14 +197,19 @@ func (b *Bucket) InitMultiWithOptions(key string, contType string, perm ACL, opt
 //
 // See http://goo.gl/pqZer for details.
 func (m *Multi) PutPart(n int, r io.ReadSeeker) (Part, error) {
+	return m.PutPartWithContext(context.Background(), n, r)
+}
+
+// PutPartWithContext is like PutPart but makes its requests with ctx.
+func (m *Multi) PutPartWithContext(ctx context.Context, n int, r io.ReadSeeker) (Part, error) {
 	partSize, _, md5b64, err := seekerInfo(r)
 	if err != nil {
 		return Part{}, err
 	}
-	return m.putPart(n, r, partSize, md5b64)
+	return m.putPart(ctx, n, r, partSize, md5b64)
 }
 
-func (m *Multi) putPart(n int, r io.ReadSeeker, partSize int64, md5b64 string) (Part, error) {
+func (m *Multi) putPart(ctx context.Context, n int, r io.ReadSeeker, partSize int64, md5b64 string) (Part, error) {
 	headers := map[string][]string{
 		"Content-Length": {strconv.FormatInt(partSize, 10)},
 		"Content-MD5":    {md5b64},
@@ -185,7 +219,7 @@ func (m *Multi) putPart(n int, r io.ReadSeeker, partSize int64, md5b64 string) (
 		"partNumber": {strconv.FormatInt(int64(n), 10)},
 	}
 	var err error
-	for attempt := m.Bucket.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy); attempt.Next(err); {
 		_, err = r.Seek(0, 0)
 		if err != nil {
 			return Part{}, err
@@ -197,6 +231,7 @@ func (m *Multi) putPart(n int, r io.ReadSeeker, partSize int64, md5b64 string) (
 			headers: headers,
 			params:  params,
 			payload: r,
+			context: ctx,
 		}
 		err = m.Bucket.S3.prepare(req)
 		if err != nil {
@@ -228,6 +263,11 @@ type CopyPartResult struct {
 // n is the part to upload (first part is number 1)
 // IMPORTANT NOTE: rangeEnd is the last byte *included* in the range, not the offset after the range.
 func (m *Multi) PutPartCopy(n int, source string, rangeStart, rangeEnd int64) (Part, error) {
+	return m.PutPartCopyWithContext(context.Background(), n, source, rangeStart, rangeEnd)
+}
+
+// PutPartCopyWithContext is like PutPartCopy but makes its requests with ctx.
+func (m *Multi) PutPartCopyWithContext(ctx context.Context, n int, source string, rangeStart, rangeEnd int64) (Part, error) {
 	sourceRange := fmt.Sprintf("bytes=%d-%d", rangeStart, rangeEnd)
 
 	headers := map[string][]string{
@@ -239,13 +279,14 @@ func (m *Multi) PutPartCopy(n int, source string, rangeStart, rangeEnd int64) (P
 		"partNumber": {strconv.FormatInt(int64(n), 10)},
 	}
 	var err error
-	for attempt := m.Bucket.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "PUT",
 			bucket:  m.Bucket.Name,
 			path:    m.Key,
 			headers: headers,
 			params:  params,
+			context: ctx,
 		}
 		result := &CopyPartResult{}
 
@@ -312,6 +353,11 @@ var listPartsMax = 1000
 //
 // See http://goo.gl/ePioY for details.
 func (m *Multi) ListParts() ([]Part, error) {
+	return m.ListPartsWithContext(context.Background())
+}
+
+// ListPartsWithContext is like ListParts but makes its requests with ctx.
+func (m *Multi) ListPartsWithContext(ctx context.Context) ([]Part, error) {
 	params := map[string][]string{
 		"uploadId":  {m.UploadId},
 		"max-parts": {strconv.FormatInt(int64(listPartsMax), 10)},
@@ -320,12 +366,13 @@ func (m *Multi) ListParts() ([]Part, error) {
 		err   error
 		parts partSlice
 	)
-	for attempt := m.Bucket.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "GET",
-			bucket: m.Bucket.Name,
-			path:   m.Key,
-			params: params,
+			method:  "GET",
+			bucket:  m.Bucket.Name,
+			path:    m.Key,
+			params:  params,
+			context: ctx,
 		}
 		var resp listPartsResp
 		err = m.Bucket.S3.query(req, &resp)
@@ -341,7 +388,7 @@ func (m *Multi) ListParts() ([]Part, error) {
 			return parts, nil
 		}
 		params["part-number-marker"] = []string{resp.NextPartNumberMarker}
-		attempt = m.Bucket.S3.AttemptStrategy.Start() // Last request worked.
+		attempt = aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy) // Last request worked.
 	}
 	panic("unreachable")
 }
@@ -358,7 +405,12 @@ type ReaderAtSeeker interface {
 // new content.
 // PutAll returns all the parts of m (reused or not).
 func (m *Multi) PutAll(r ReaderAtSeeker, partSize int64) ([]Part, error) {
-	old, err := m.ListParts()
+	return m.PutAllWithContext(context.Background(), r, partSize)
+}
+
+// PutAllWithContext is like PutAll but makes its requests with ctx.
+func (m *Multi) PutAllWithContext(ctx context.Context, r ReaderAtSeeker, partSize int64) ([]Part, error) {
+	old, err := m.ListPartsWithContext(ctx)
 	if err != nil && !hasCode(err, "NoSuchUpload") {
 		return nil, err
 	}
@@ -395,7 +447,7 @@ NextSection:
 		}
 
 		// Part wasn't found or doesn't match. Send it.
-		part, err := m.putPart(current, section, partSize, md5b64)
+		part, err := m.putPart(ctx, current, section, partSize, md5b64)
 		if err != nil {
 			return nil, err
 		}
@@ -426,6 +478,11 @@ func (p completeParts) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 //
 // See http://goo.gl/2Z7Tw for details.
 func (m *Multi) Complete(parts []Part) error {
+	return m.CompleteWithContext(context.Background(), parts)
+}
+
+// CompleteWithContext is like Complete but makes its requests with ctx.
+func (m *Multi) CompleteWithContext(ctx context.Context, parts []Part) error {
 	params := map[string][]string{
 		"uploadId": {m.UploadId},
 	}
@@ -438,13 +495,14 @@ func (m *Multi) Complete(parts []Part) error {
 	if err != nil {
 		return err
 	}
-	for attempt := m.Bucket.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "POST",
 			bucket:  m.Bucket.Name,
 			path:    m.Key,
 			params:  params,
 			payload: bytes.NewReader(data),
+			context: ctx,
 		}
 		err = m.Bucket.S3.query(req, nil)
 		if ShouldRetry(err) && attempt.HasNext() {
@@ -471,16 +529,22 @@ func (m *Multi) Complete(parts []Part) error {
 //
 // See http://goo.gl/dnyJw for details.
 func (m *Multi) Abort() error {
+	return m.AbortWithContext(context.Background())
+}
+
+// AbortWithContext is like Abort but makes its requests with ctx.
+func (m *Multi) AbortWithContext(ctx context.Context) error {
 	params := map[string][]string{
 		"uploadId": {m.UploadId},
 	}
 	var err error
-	for attempt := m.Bucket.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, m.Bucket.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "DELETE",
-			bucket: m.Bucket.Name,
-			path:   m.Key,
-			params: params,
+			method:  "DELETE",
+			bucket:  m.Bucket.Name,
+			path:    m.Key,
+			params:  params,
+			context: ctx,
 		}
 		err = m.Bucket.S3.query(req, nil)
 		if ShouldRetry(err) && attempt.HasNext() {
@@ -0,0 +1,213 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// DefaultReadAhead is the ReadAhead of the ObjectReaders returned by
+// Bucket.Open.
+const DefaultReadAhead = 1 << 20
+
+// An ObjectReader reads an object from an S3 bucket at random with ranged
+// GETs. It implements io.ReadSeekCloser and io.ReaderAt, so that objects
+// can be read by code that needs random access, such as archive/zip.
+//
+// Reads are served from a buffer, filled with ReadAhead bytes at a time,
+// or more for larger reads. The object is pinned to the version first
+// seen by its ETag, so that if it is replaced, reads fail with
+// ErrObjectChanged rather than returning a mix of versions.
+//
+// ReadAt may be called concurrently, and concurrent calls make their
+// GETs in parallel, while Read and Seek, which share the offset of the
+// reader, may not.
+type ObjectReader struct {
+	// ReadAhead is the number of bytes the buffer is filled with. It
+	// may be changed before reading.
+	ReadAhead int
+
+	ctx    context.Context
+	bucket *Bucket
+	path   string
+	size   int64
+	etag   string
+	offset int64 // of Read and Seek
+
+	mu     sync.Mutex
+	buf    []byte
+	bufOff int64
+	closed bool
+}
+
+// Open opens the object at path for reading.
+func (b *Bucket) Open(path string) (*ObjectReader, error) {
+	return b.OpenWithContext(context.Background(), path)
+}
+
+// OpenWithContext is like Open but the reader makes its requests with
+// ctx.
+func (b *Bucket) OpenWithContext(ctx context.Context, path string) (*ObjectReader, error) {
+	resp, err := b.HeadWithContext(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.ContentLength < 0 {
+		return nil, fmt.Errorf("s3: size of %s unknown", path)
+	}
+	return newObjectReader(ctx, b, path, resp), nil
+}
+
+// newObjectReader returns a reader of the object at path, given the
+// response to a HEAD request for it.
+func newObjectReader(ctx context.Context, b *Bucket, path string, resp *http.Response) *ObjectReader {
+	return &ObjectReader{
+		ReadAhead: DefaultReadAhead,
+		ctx:       ctx,
+		bucket:    b,
+		path:      path,
+		size:      resp.ContentLength,
+		etag:      resp.Header.Get("ETag"),
+	}
+}
+
+// Size returns the size of the object.
+func (o *ObjectReader) Size() int64 {
+	return o.size
+}
+
+// ETag returns the ETag of the object read.
+func (o *ObjectReader) ETag() string {
+	return o.etag
+}
+
+func (o *ObjectReader) Read(p []byte) (int, error) {
+	n, err := o.ReadAt(p, o.offset)
+	o.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (o *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.New("s3: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("s3: negative position")
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	o.mu.Lock()
+	closed := o.closed
+	o.mu.Unlock()
+	if closed {
+		return 0, os.ErrClosed
+	}
+	if off < 0 {
+		return 0, errors.New("s3: negative offset")
+	}
+	n := 0
+	for n < len(p) && off < o.size {
+		copied, err := o.readBuffer(p[n:], off)
+		if err != nil {
+			return n, err
+		}
+		if copied == 0 {
+			want := len(p) - n
+			if int64(want) > o.size-off {
+				want = int(o.size - off)
+			}
+			if want >= o.ReadAhead {
+				// Too large to go through the buffer.
+				if err := o.get(p[n:n+want], off); err != nil {
+					return n, err
+				}
+				copied = want
+			} else {
+				buf, err := o.fill(off)
+				if err != nil {
+					return n, err
+				}
+				copied = copy(p[n:], buf)
+			}
+		}
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readBuffer copies into p what the buffer holds of the object from off,
+// if anything.
+func (o *ObjectReader) readBuffer(p []byte, off int64) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return 0, os.ErrClosed
+	}
+	if off < o.bufOff || off >= o.bufOff+int64(len(o.buf)) {
+		return 0, nil
+	}
+	return copy(p, o.buf[off-o.bufOff:]), nil
+}
+
+// fill gets up to ReadAhead bytes from off, without holding o.mu so that
+// other reads are not held up, and makes them the buffer.
+func (o *ObjectReader) fill(off int64) ([]byte, error) {
+	size := int64(o.ReadAhead)
+	if size > o.size-off {
+		size = o.size - off
+	}
+	buf := make([]byte, size)
+	if err := o.get(buf, off); err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	if !o.closed {
+		o.buf, o.bufOff = buf, off
+	}
+	o.mu.Unlock()
+	return buf, nil
+}
+
+// get reads len(p) bytes of the object from off into p.
+func (o *ObjectReader) get(p []byte, off int64) error {
+	resp, err := o.bucket.getRange(o.ctx, o.path, off, int64(len(p)), o.etag)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadFull(resp.Body, p)
+	return err
+}
+
+// Close releases the buffer of o. Reading o after it is closed fails.
+func (o *ObjectReader) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return os.ErrClosed
+	}
+	o.closed = true
+	o.buf = nil
+	return nil
+}
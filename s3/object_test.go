@@ -0,0 +1,83 @@
+package s3_test
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/hughe/goamz/testutil"
+	. "gopkg.in/check.v1"
+)
+
+func (s *S) TestOpenReadAhead(c *C) {
+	testServer.Response(200, map[string]string{"Content-Length": "10", "ETag": `"etag"`}, "")
+	testServer.Response(206, nil, "01234567")
+	testServer.Response(206, nil, "89")
+
+	o, err := s.s3.Bucket("sample").Open("object")
+	c.Assert(err, IsNil)
+	defer o.Close()
+	c.Assert(o.Size(), Equals, int64(10))
+	c.Assert(o.ETag(), Equals, `"etag"`)
+	o.ReadAhead = 8
+
+	buf := make([]byte, 3)
+	for _, want := range []string{"012", "345"} {
+		n, err := io.ReadFull(o, buf)
+		c.Assert(err, IsNil)
+		c.Assert(string(buf[:n]), Equals, want)
+	}
+	rest, err := ioutil.ReadAll(o)
+	c.Assert(err, IsNil)
+	c.Assert(string(rest), Equals, "6789")
+
+	reqs := testServer.WaitRequests(3)
+	c.Assert(reqs[0].Method, Equals, "HEAD")
+	for i, r := range []string{"bytes=0-7", "bytes=8-9"} {
+		c.Assert(reqs[i+1].Method, Equals, "GET")
+		c.Assert(reqs[i+1].Header.Get("Range"), Equals, r)
+		c.Assert(reqs[i+1].Header.Get("If-Match"), Equals, `"etag"`)
+	}
+}
+
+func (s *S) TestOpenConcurrentReadAt(c *C) {
+	var (
+		mu             sync.Mutex
+		inFlight, most int
+	)
+	testServer.Response(200, map[string]string{"Content-Length": "10", "ETag": `"etag"`}, "")
+	testServer.ResponseFunc(2, func(path string) testutil.Response {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return testutil.Response{Status: 206, Body: "01234"}
+	})
+
+	o, err := s.s3.Bucket("sample").Open("object")
+	c.Assert(err, IsNil)
+	defer o.Close()
+	o.ReadAhead = 2
+
+	// Reads that miss the buffer do not wait for each other.
+	var wg sync.WaitGroup
+	for _, off := range []int64{0, 5} {
+		wg.Add(1)
+		go func(off int64) {
+			defer wg.Done()
+			buf := make([]byte, 5)
+			n, err := o.ReadAt(buf, off)
+			c.Check(err, IsNil)
+			c.Check(string(buf[:n]), Equals, "01234")
+		}(off)
+	}
+	wg.Wait()
+	c.Assert(most, Equals, 2)
+}
@@ -77,6 +77,10 @@ type S3 struct {
 	// AttemptStrategy is the attempt strategy used for requests.
 	aws.AttemptStrategy
 
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
 	// Reserve the right of using private data.
 	private byte
 
@@ -148,6 +152,7 @@ func New(auth aws.Auth, region aws.Region, client ...*http.Client) *S3 {
 	if len(client) > 0 {
 		httpclient = client[0]
 	}
+	region = region.WithEndpoints()
 	s3 := &S3{
 		Auth:            auth,
 		Region:          region,
@@ -228,6 +233,11 @@ func addACLHeader(headers map[string][]string, perm ACL) {
 //
 // See http://goo.gl/ndjnR for details.
 func (b *Bucket) PutBucket(perm ACL) error {
+	return b.PutBucketWithContext(context.Background(), perm)
+}
+
+// PutBucketWithContext is like PutBucket but makes its requests with ctx.
+func (b *Bucket) PutBucketWithContext(ctx context.Context, perm ACL) error {
 	headers := make(map[string][]string)
 	addACLHeader(headers, perm)
 
@@ -237,6 +247,7 @@ func (b *Bucket) PutBucket(perm ACL) error {
 		path:    "/",
 		headers: headers,
 		payload: b.locationConstraint(),
+		context: ctx,
 	}
 	return b.S3.query(req, nil)
 }
@@ -246,11 +257,17 @@ func (b *Bucket) PutBucket(perm ACL) error {
 //
 // See http://goo.gl/GoBrY for details.
 func (b *Bucket) DelBucket() (err error) {
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	return b.DelBucketWithContext(context.Background())
+}
+
+// DelBucketWithContext is like DelBucket but makes its requests with ctx.
+func (b *Bucket) DelBucketWithContext(ctx context.Context) (err error) {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "DELETE",
-			bucket: b.Name,
-			path:   "/",
+			method:  "DELETE",
+			bucket:  b.Name,
+			path:    "/",
+			context: ctx,
 		}
 		err = b.S3.query(req, nil)
 		if !ShouldRetry(err) {
@@ -264,7 +281,12 @@ func (b *Bucket) DelBucket() (err error) {
 //
 // See http://goo.gl/isCO7 for details.
 func (b *Bucket) Get(path string) (data []byte, err error) {
-	body, err := b.GetReader(path)
+	return b.GetWithContext(context.Background(), path)
+}
+
+// GetWithContext is like Get but makes its requests with ctx.
+func (b *Bucket) GetWithContext(ctx context.Context, path string) (data []byte, err error) {
+	body, err := b.GetReaderWithContext(ctx, path)
 	defer func() {
 		if body != nil {
 			body.Close()
@@ -282,19 +304,83 @@ func (b *Bucket) Get(path string) (data []byte, err error) {
 // It is the caller's responsibility to call Close on rc when
 // finished reading.
 func (b *Bucket) GetReader(path string) (rc io.ReadCloser, err error) {
-	resp, err := b.GetResponse(path)
+	return b.GetReaderWithContext(context.Background(), path)
+}
+
+// GetReaderWithContext is like GetReader but makes its requests with ctx.
+func (b *Bucket) GetReaderWithContext(ctx context.Context, path string) (rc io.ReadCloser, err error) {
+	resp, err := b.GetResponseWithContext(ctx, path)
+	if resp != nil {
+		return resp.Body, err
+	}
+	return nil, err
+}
+
+// GetRange retrieves length bytes of an object from an S3 bucket,
+// starting at offset, returning the body of the HTTP response.
+// If length is zero, no request is made and rc is empty; a negative
+// length is an error.
+// It is the caller's responsibility to call Close on rc when
+// finished reading.
+func (b *Bucket) GetRange(path string, offset, length int64) (rc io.ReadCloser, err error) {
+	return b.GetRangeWithContext(context.Background(), path, offset, length)
+}
+
+// GetRangeWithContext is like GetRange but makes its requests with ctx.
+func (b *Bucket) GetRangeWithContext(ctx context.Context, path string, offset, length int64) (rc io.ReadCloser, err error) {
+	resp, err := b.getRange(ctx, path, offset, length, "")
 	if resp != nil {
 		return resp.Body, err
 	}
 	return nil, err
 }
 
+// getRange retrieves a range of an object, as GetRange does, provided
+// that its ETag is etag, unless etag is "". If the object has another
+// ETag, the error returned is ErrObjectChanged.
+func (b *Bucket) getRange(ctx context.Context, path string, offset, length int64, etag string) (*http.Response, error) {
+	if length < 0 {
+		return nil, fmt.Errorf("s3: invalid length %d of range of %s", length, path)
+	}
+	if length == 0 {
+		// There is no header for an empty range.
+		return &http.Response{
+			Status:     "206 Partial Content",
+			StatusCode: http.StatusPartialContent,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		}, nil
+	}
+	r := fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	headers := http.Header{"Range": {r}}
+	if etag != "" {
+		headers.Set("If-Match", etag)
+	}
+	resp, err := b.GetResponseWithHeadersAndContext(ctx, path, headers, true)
+	if etag != "" && hasCode(err, "PreconditionFailed") {
+		return nil, fmt.Errorf("%w: %s: %w", ErrObjectChanged, path, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3: GET of range %s of %s returned %s", r, path, resp.Status)
+	}
+	return resp, nil
+}
+
 // GetResponse retrieves an object from an S3 bucket,
 // returning the HTTP response.
 // It is the caller's responsibility to call Close on rc when
 // finished reading
 func (b *Bucket) GetResponse(path string) (resp *http.Response, err error) {
-	return b.GetResponseWithHeaders(path, make(http.Header))
+	return b.GetResponseWithContext(context.Background(), path)
+}
+
+// GetResponseWithContext is like GetResponse but makes its requests with ctx.
+func (b *Bucket) GetResponseWithContext(ctx context.Context, path string) (resp *http.Response, err error) {
+	return b.GetResponseWithHeadersAndContext(ctx, path, make(http.Header), true)
 }
 
 // GetResponseWithHeaders retrieves an object from an S3 bucket
@@ -306,7 +392,7 @@ func (b *Bucket) GetResponseWithHeaders(
 	path string,
 	headers map[string][]string,
 ) (resp *http.Response, err error) {
-	return b.doGetResponseWithHeaders(nil, path, headers, 0, true)
+	return b.doGetResponseWithHeaders(context.Background(), path, headers, 0, true)
 }
 
 // If attemptRetry is true then errors will be retried using the AttemptStrategy set on b.S3.
@@ -337,7 +423,7 @@ func (b *Bucket) doGetResponseWithHeaders(
 		strategy = &aws.OneAttemptStrategy{}
 	}
 
-	for attempt := strategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, strategy); attempt.Next(err); {
 		req := &request{
 			bucket:  b.Name,
 			path:    path,
@@ -364,11 +450,17 @@ func (b *Bucket) doGetResponseWithHeaders(
 
 // Exists checks whether or not an object exists on an S3 bucket using a HEAD request.
 func (b *Bucket) Exists(path string) (exists bool, err error) {
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	return b.ExistsWithContext(context.Background(), path)
+}
+
+// ExistsWithContext is like Exists but makes its requests with ctx.
+func (b *Bucket) ExistsWithContext(ctx context.Context, path string) (exists bool, err error) {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "HEAD",
-			bucket: b.Name,
-			path:   path,
+			method:  "HEAD",
+			bucket:  b.Name,
+			path:    path,
+			context: ctx,
 		}
 		err = b.S3.prepare(req)
 		if err != nil {
@@ -400,13 +492,19 @@ func (b *Bucket) Exists(path string) (exists bool, err error) {
 // Head HEADs an object in the S3 bucket, returns the response with
 // no body see http://bit.ly/17K1ylI
 func (b *Bucket) Head(path string, headers map[string][]string) (*http.Response, error) {
+	return b.HeadWithContext(context.Background(), path, headers)
+}
+
+// HeadWithContext is like Head but makes its requests with ctx.
+func (b *Bucket) HeadWithContext(ctx context.Context, path string, headers map[string][]string) (*http.Response, error) {
 	var err error
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "HEAD",
 			bucket:  b.Name,
 			path:    path,
 			headers: headers,
+			context: ctx,
 		}
 		err = b.S3.prepare(req)
 		if err != nil {
@@ -429,12 +527,22 @@ func (b *Bucket) Head(path string, headers map[string][]string) (*http.Response,
 //
 // See http://goo.gl/FEBPD for details.
 func (b *Bucket) Put(path string, data []byte, contType string, perm ACL, options Options) error {
-	body := bytes.NewBuffer(data)
-	return b.PutReader(path, body, int64(len(data)), contType, perm, options)
+	return b.PutWithContext(context.Background(), path, data, contType, perm, options)
+}
+
+// PutWithContext is like Put but makes its requests with ctx.
+func (b *Bucket) PutWithContext(ctx context.Context, path string, data []byte, contType string, perm ACL, options Options) error {
+	body := bytes.NewReader(data)
+	return b.PutReaderWithContext(ctx, path, body, int64(len(data)), contType, perm, options)
 }
 
 // PutCopy puts a copy of an object given by the key path into bucket b using b.Path as the target key
 func (b *Bucket) PutCopy(path string, perm ACL, options CopyOptions, source string) (*CopyObjectResult, error) {
+	return b.PutCopyWithContext(context.Background(), path, perm, options, source)
+}
+
+// PutCopyWithContext is like PutCopy but makes its requests with ctx.
+func (b *Bucket) PutCopyWithContext(ctx context.Context, path string, perm ACL, options CopyOptions, source string) (*CopyObjectResult, error) {
 	headers := map[string][]string{
 		"x-amz-copy-source": {source},
 	}
@@ -446,6 +554,7 @@ func (b *Bucket) PutCopy(path string, perm ACL, options CopyOptions, source stri
 		bucket:  b.Name,
 		path:    path,
 		headers: headers,
+		context: ctx,
 	}
 	resp := &CopyObjectResult{}
 	err := b.S3.query(req, resp)
@@ -460,13 +569,23 @@ PutHeader - like Put, inserts an object into the S3 bucket.
 Instead of Content-Type string, pass in custom headers to override defaults.
 */
 func (b *Bucket) PutHeader(path string, data []byte, customHeaders map[string][]string, perm ACL) error {
-	body := bytes.NewBuffer(data)
-	return b.PutReaderHeader(path, body, int64(len(data)), customHeaders, perm)
+	return b.PutHeaderWithContext(context.Background(), path, data, customHeaders, perm)
+}
+
+// PutHeaderWithContext is like PutHeader but makes its requests with ctx.
+func (b *Bucket) PutHeaderWithContext(ctx context.Context, path string, data []byte, customHeaders map[string][]string, perm ACL) error {
+	body := bytes.NewReader(data)
+	return b.PutReaderHeaderWithContext(ctx, path, body, int64(len(data)), customHeaders, perm)
 }
 
 // PutReader inserts an object into the S3 bucket by consuming data
 // from r until EOF.
 func (b *Bucket) PutReader(path string, r io.Reader, length int64, contType string, perm ACL, options Options) error {
+	return b.PutReaderWithContext(context.Background(), path, r, length, contType, perm, options)
+}
+
+// PutReaderWithContext is like PutReader but makes its requests with ctx.
+func (b *Bucket) PutReaderWithContext(ctx context.Context, path string, r io.Reader, length int64, contType string, perm ACL, options Options) error {
 	headers := map[string][]string{
 		"Content-Length": {strconv.FormatInt(length, 10)},
 		"Content-Type":   {contType},
@@ -480,6 +599,7 @@ func (b *Bucket) PutReader(path string, r io.Reader, length int64, contType stri
 		path:    path,
 		headers: headers,
 		payload: r,
+		context: ctx,
 	}
 	return b.S3.query(req, nil)
 }
@@ -493,7 +613,7 @@ func (b *Bucket) PutReaderHeader(path string, r io.Reader, length int64, customH
 }
 
 func (b *Bucket) PutReaderHeaderTimeout(path string, r io.Reader, length int64, customHeaders map[string][]string, perm ACL, timeout time.Duration) error {
-	return b.doPutReaderHeaderTimeout(nil, path, r, length, customHeaders, perm, timeout)
+	return b.doPutReaderHeaderTimeout(context.Background(), path, r, length, customHeaders, perm, timeout)
 }
 
 func (b *Bucket) PutReaderHeaderWithContext(ctx context.Context, path string, r io.Reader, length int64, customHeaders map[string][]string, perm ACL) error {
@@ -624,6 +744,11 @@ type WebsiteConfiguration struct {
 }
 
 func (b *Bucket) PutBucketWebsite(configuration WebsiteConfiguration) error {
+	return b.PutBucketWebsiteWithContext(context.Background(), configuration)
+}
+
+// PutBucketWebsiteWithContext is like PutBucketWebsite but makes its requests with ctx.
+func (b *Bucket) PutBucketWebsiteWithContext(ctx context.Context, configuration WebsiteConfiguration) error {
 
 	doc, err := xml.Marshal(configuration)
 	if err != nil {
@@ -632,10 +757,15 @@ func (b *Bucket) PutBucketWebsite(configuration WebsiteConfiguration) error {
 
 	buf := makeXmlBuffer(doc)
 
-	return b.PutBucketSubresource("website", buf, int64(buf.Len()))
+	return b.PutBucketSubresourceWithContext(ctx, "website", buf, int64(buf.Len()))
 }
 
 func (b *Bucket) PutBucketSubresource(subresource string, r io.Reader, length int64) error {
+	return b.PutBucketSubresourceWithContext(context.Background(), subresource, r, length)
+}
+
+// PutBucketSubresourceWithContext is like PutBucketSubresource but makes its requests with ctx.
+func (b *Bucket) PutBucketSubresourceWithContext(ctx context.Context, subresource string, r io.Reader, length int64) error {
 	headers := map[string][]string{
 		"Content-Length": {strconv.FormatInt(length, 10)},
 	}
@@ -646,6 +776,7 @@ func (b *Bucket) PutBucketSubresource(subresource string, r io.Reader, length in
 		headers: headers,
 		payload: r,
 		params:  url.Values{subresource: {""}},
+		context: ctx,
 	}
 
 	return b.S3.query(req, nil)
@@ -655,10 +786,16 @@ func (b *Bucket) PutBucketSubresource(subresource string, r io.Reader, length in
 //
 // See http://goo.gl/APeTt for details.
 func (b *Bucket) Del(path string) error {
+	return b.DelWithContext(context.Background(), path)
+}
+
+// DelWithContext is like Del but makes its requests with ctx.
+func (b *Bucket) DelWithContext(ctx context.Context, path string) error {
 	req := &request{
-		method: "DELETE",
-		bucket: b.Name,
-		path:   path,
+		method:  "DELETE",
+		bucket:  b.Name,
+		path:    path,
+		context: ctx,
 	}
 	return b.S3.query(req, nil)
 }
@@ -677,6 +814,11 @@ type Object struct {
 //
 // See http://goo.gl/jx6cWK for details.
 func (b *Bucket) DelMulti(objects Delete) error {
+	return b.DelMultiWithContext(context.Background(), objects)
+}
+
+// DelMultiWithContext is like DelMulti but makes its requests with ctx.
+func (b *Bucket) DelMultiWithContext(ctx context.Context, objects Delete) error {
 	doc, err := xml.Marshal(objects)
 	if err != nil {
 		return err
@@ -701,6 +843,7 @@ func (b *Bucket) DelMulti(objects Delete) error {
 		bucket:  b.Name,
 		headers: headers,
 		payload: buf,
+		context: ctx,
 	}
 
 	return b.S3.query(req, nil)
@@ -813,6 +956,11 @@ type Key struct {
 //
 // See http://goo.gl/YjQTc for details.
 func (b *Bucket) List(prefix, delim, marker string, max int) (result *ListResp, err error) {
+	return b.ListWithContext(context.Background(), prefix, delim, marker, max)
+}
+
+// ListWithContext is like List but makes its requests with ctx.
+func (b *Bucket) ListWithContext(ctx context.Context, prefix, delim, marker string, max int) (result *ListResp, err error) {
 	params := map[string][]string{
 		"prefix":    {prefix},
 		"delimiter": {delim},
@@ -822,10 +970,11 @@ func (b *Bucket) List(prefix, delim, marker string, max int) (result *ListResp,
 		params["max-keys"] = []string{strconv.FormatInt(int64(max), 10)}
 	}
 	result = &ListResp{}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			bucket: b.Name,
-			params: params,
+			bucket:  b.Name,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, result)
 		if !ShouldRetry(err) {
@@ -838,7 +987,44 @@ func (b *Bucket) List(prefix, delim, marker string, max int) (result *ListResp,
 	return result, nil
 }
 
+// ListPages returns a Pager over the pages of List results, starting
+// after marker.
+func (b *Bucket) ListPages(prefix, delim, marker string, max int) *aws.Pager[*ListResp] {
+	return aws.NewPager(func(ctx context.Context) (*ListResp, bool, error) {
+		resp, err := b.ListWithContext(ctx, prefix, delim, marker, max)
+		if err != nil {
+			return nil, false, err
+		}
+		// S3 only sends NextMarker along with a delimiter; otherwise the
+		// next page starts after the last key or common prefix.
+		marker = resp.NextMarker
+		if marker == "" {
+			if n := len(resp.Contents); n > 0 {
+				marker = resp.Contents[n-1].Key
+			}
+			if n := len(resp.CommonPrefixes); n > 0 && resp.CommonPrefixes[n-1] > marker {
+				marker = resp.CommonPrefixes[n-1]
+			}
+		}
+		return resp, resp.IsTruncated && marker != "", nil
+	})
+}
+
+// ListAll returns every key in the bucket that begins with prefix and
+// is not grouped into a common prefix by delim, walking every page of
+// List results.
+func (b *Bucket) ListAll(ctx context.Context, prefix, delim string) ([]Key, error) {
+	return aws.All(ctx, b.ListPages(prefix, delim, "", 0), func(resp *ListResp) []Key {
+		return resp.Contents
+	})
+}
+
 func (b *Bucket) ListV2(prefix, delim, continuationToken, startAfter string, max int, fetch_owner bool) (result *ListRespV2, err error) {
+	return b.ListV2WithContext(context.Background(), prefix, delim, continuationToken, startAfter, max, fetch_owner)
+}
+
+// ListV2WithContext is like ListV2 but makes its requests with ctx.
+func (b *Bucket) ListV2WithContext(ctx context.Context, prefix, delim, continuationToken, startAfter string, max int, fetch_owner bool) (result *ListRespV2, err error) {
 	params := map[string][]string{
 		"list-type": []string{"2"},
 		"prefix":    {prefix},
@@ -857,10 +1043,11 @@ func (b *Bucket) ListV2(prefix, delim, continuationToken, startAfter string, max
 		params["fetch-owner"] = []string{"true"}
 	}
 	result = &ListRespV2{}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			bucket: b.Name,
-			params: params,
+			bucket:  b.Name,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, result)
 		if !ShouldRetry(err) {
@@ -873,17 +1060,41 @@ func (b *Bucket) ListV2(prefix, delim, continuationToken, startAfter string, max
 	return result, nil
 }
 
+// ListV2Pages returns a Pager over the pages of ListV2 results, starting
+// with the page of continuationToken.
+func (b *Bucket) ListV2Pages(prefix, delim, continuationToken, startAfter string, max int, fetch_owner bool) *aws.Pager[*ListRespV2] {
+	return aws.NewPager(func(ctx context.Context) (*ListRespV2, bool, error) {
+		resp, err := b.ListV2WithContext(ctx, prefix, delim, continuationToken, startAfter, max, fetch_owner)
+		if err != nil {
+			return nil, false, err
+		}
+		continuationToken = resp.NextContinuationToken
+		return resp, resp.IsTruncated && continuationToken != "", nil
+	})
+}
+
+// ListV2All returns every key in the bucket that begins with prefix and
+// is not grouped into a common prefix by delim, walking every page of
+// ListV2 results.
+func (b *Bucket) ListV2All(ctx context.Context, prefix, delim string) ([]Key, error) {
+	return aws.All(ctx, b.ListV2Pages(prefix, delim, "", "", 0, false), func(resp *ListRespV2) []Key {
+		return resp.Contents
+	})
+}
+
 // The VersionsResp type holds the results of a list bucket Versions operation.
 type VersionsResp struct {
-	Name            string
-	Prefix          string
-	KeyMarker       string
-	VersionIdMarker string
-	MaxKeys         int
-	Delimiter       string
-	IsTruncated     bool
-	Versions        []Version
-	CommonPrefixes  []string `xml:">Prefix"`
+	Name                string
+	Prefix              string
+	KeyMarker           string
+	VersionIdMarker     string
+	NextKeyMarker       string
+	NextVersionIdMarker string
+	MaxKeys             int
+	Delimiter           string
+	IsTruncated         bool
+	Versions            []Version
+	CommonPrefixes      []string `xml:">Prefix"`
 }
 
 // The Version type represents an object version stored in an S3 bucket.
@@ -901,6 +1112,11 @@ type Version struct {
 }
 
 func (b *Bucket) Versions(prefix, delim, keyMarker string, versionIdMarker string, max int) (result *VersionsResp, err error) {
+	return b.VersionsWithContext(context.Background(), prefix, delim, keyMarker, versionIdMarker, max)
+}
+
+// VersionsWithContext is like Versions but makes its requests with ctx.
+func (b *Bucket) VersionsWithContext(ctx context.Context, prefix, delim, keyMarker string, versionIdMarker string, max int) (result *VersionsResp, err error) {
 	params := map[string][]string{
 		"versions":  {""},
 		"prefix":    {prefix},
@@ -918,10 +1134,11 @@ func (b *Bucket) Versions(prefix, delim, keyMarker string, versionIdMarker strin
 		params["max-keys"] = []string{strconv.FormatInt(int64(max), 10)}
 	}
 	result = &VersionsResp{}
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			bucket: b.Name,
-			params: params,
+			bucket:  b.Name,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, result)
 		if !ShouldRetry(err) {
@@ -934,28 +1151,41 @@ func (b *Bucket) Versions(prefix, delim, keyMarker string, versionIdMarker strin
 	return result, nil
 }
 
+// VersionsPages returns a Pager over the pages of Versions results,
+// starting after keyMarker and versionIdMarker.
+func (b *Bucket) VersionsPages(prefix, delim, keyMarker string, versionIdMarker string, max int) *aws.Pager[*VersionsResp] {
+	return aws.NewPager(func(ctx context.Context) (*VersionsResp, bool, error) {
+		resp, err := b.VersionsWithContext(ctx, prefix, delim, keyMarker, versionIdMarker, max)
+		if err != nil {
+			return nil, false, err
+		}
+		keyMarker, versionIdMarker = resp.NextKeyMarker, resp.NextVersionIdMarker
+		return resp, resp.IsTruncated && keyMarker != "", nil
+	})
+}
+
+// VersionsAll returns every version of the keys in the bucket that begin
+// with prefix and are not grouped into a common prefix by delim, walking
+// every page of Versions results.
+func (b *Bucket) VersionsAll(ctx context.Context, prefix, delim string) ([]Version, error) {
+	return aws.All(ctx, b.VersionsPages(prefix, delim, "", "", 0), func(resp *VersionsResp) []Version {
+		return resp.Versions
+	})
+}
+
 // Returns a mapping of all key names in this bucket to Key objects
 func (b *Bucket) GetBucketContents() (*map[string]Key, error) {
+	return b.GetBucketContentsWithContext(context.Background())
+}
+
+// GetBucketContentsWithContext is like GetBucketContents but makes its requests with ctx.
+func (b *Bucket) GetBucketContentsWithContext(ctx context.Context) (*map[string]Key, error) {
 	bucket_contents := map[string]Key{}
-	prefix := ""
-	path_separator := ""
-	marker := ""
-	for {
-		contents, err := b.List(prefix, path_separator, marker, 1000)
-		if err != nil {
-			return &bucket_contents, err
-		}
-		for _, key := range contents.Contents {
-			bucket_contents[key.Key] = key
-		}
-		if contents.IsTruncated {
-			marker = contents.NextMarker
-		} else {
-			break
-		}
+	keys, err := b.ListAll(ctx, "", "")
+	for _, key := range keys {
+		bucket_contents[key.Key] = key
 	}
-
-	return &bucket_contents, nil
+	return &bucket_contents, err
 }
 
 // URL returns a non-signed URL that allows retriving the
@@ -980,7 +1210,14 @@ func (b *Bucket) URL(path string) string {
 
 // SignedURL returns a signed URL that allows anyone holding the URL
 // to retrieve the object at path. The signature is valid until expires.
+//
+// If the S3 value was created with NewV4, the URL is presigned with
+// Signature Version 4, whose signatures are valid for no more than
+// aws.MaxPresignExpiry; a later expires is brought forward to that limit.
 func (b *Bucket) SignedURL(path string, expires time.Time) string {
+	if b.S3.v4sign {
+		return b.presignedURL("GET", path, http.Header{}, expires)
+	}
 	req := &request{
 		bucket: b.Name,
 		path:   path,
@@ -990,11 +1227,12 @@ func (b *Bucket) SignedURL(path string, expires time.Time) string {
 	if err != nil {
 		panic(err)
 	}
+	b.S3.signV2(req)
 	u, err := req.url()
 	if err != nil {
 		panic(err)
 	}
-	if b.S3.Auth.Token() != "" {
+	if _, ok := req.headers["X-Amz-Security-Token"]; ok {
 		return u.String() + "&x-amz-security-token=" + url.QueryEscape(req.headers["X-Amz-Security-Token"][0])
 	} else {
 		return u.String()
@@ -1005,14 +1243,25 @@ func (b *Bucket) SignedURL(path string, expires time.Time) string {
 // to upload the object at path. The signature is valid until expires.
 // contenttype is a string like image/png
 // path is the resource name in s3 terminalogy like images/ali.png [obviously exclusing the bucket name itself]
+//
+// If the S3 value was created with NewV4, the URL is presigned with
+// Signature Version 4 as for SignedURL, and the upload must be made with
+// the given content type.
 func (b *Bucket) UploadSignedURL(path, method, content_type string, expires time.Time) string {
 	expire_date := expires.Unix()
 	if method != "POST" {
 		method = "PUT"
 	}
+	if b.S3.v4sign {
+		header := http.Header{}
+		if content_type != "" {
+			header.Set("Content-Type", content_type)
+		}
+		return b.presignedURL(method, path, header, expires)
+	}
 	stringToSign := method + "\n\n" + content_type + "\n" + strconv.FormatInt(expire_date, 10) + "\n/" + b.Name + "/" + path
 	fmt.Println("String to sign:\n", stringToSign)
-	a := b.S3.Auth
+	a := b.S3.Auth.Current()
 	secretKey := a.SecretKey
 	accessId := a.AccessKey
 	mac := hmac.New(sha1.New, []byte(secretKey))
@@ -1039,12 +1288,55 @@ func (b *Bucket) UploadSignedURL(path, method, content_type string, expires time
 	return signedurl.String()
 }
 
+// presignedURL returns a URL for a method request on the object at path,
+// presigned with Signature Version 4 until expires, or until
+// aws.MaxPresignExpiry from now if that is sooner. The headers in header
+// are signed, and must be sent with the request.
+func (b *Bucket) presignedURL(method, path string, header http.Header, expires time.Time) string {
+	req := &request{
+		method: method,
+		bucket: b.Name,
+		path:   path,
+	}
+	err := b.S3.prepare(req)
+	if err != nil {
+		panic(err)
+	}
+	u, err := req.url()
+	if err != nil {
+		panic(err)
+	}
+	u.RawQuery = ""
+	hreq := &http.Request{
+		Method: method,
+		URL:    u,
+		Host:   u.Host,
+		Header: header,
+	}
+	validFor := time.Until(expires).Round(time.Second)
+	if validFor < time.Second {
+		// Date the signature so that it is already out of date.
+		hreq.Header.Set("X-Amz-Date", expires.Add(-time.Second).UTC().Format(aws.ISO8601BasicFormat))
+		validFor = time.Second
+	}
+	if validFor > aws.MaxPresignExpiry {
+		validFor = aws.MaxPresignExpiry
+	}
+	signed, err := aws.NewV4Signer(b.S3.Auth, "s3", b.S3.Region).Presign(hreq, validFor)
+	if err != nil {
+		log.Println("ERROR presigning url for S3", err)
+		return ""
+	}
+	return signed.String()
+}
+
 // PostFormArgs returns the action and input fields needed to allow anonymous
 // uploads to a bucket within the expiration limit
 func (b *Bucket) PostFormArgs(path string, expires time.Time, redirect string) (action string, fields map[string]string) {
+	auth := b.Auth.Current()
 	conditions := make([]string, 0)
 	fields = map[string]string{
-		"AWSAccessKeyId": b.Auth.AccessKey,
+		"AWSAccessKeyId": auth.AccessKey,
 		"key":            path,
 	}
 
@@ -1061,7 +1353,7 @@ func (b *Bucket) PostFormArgs(path string, expires time.Time, redirect string) (
 	policy64 := base64.StdEncoding.EncodeToString([]byte(policy))
 	fields["policy"] = policy64
 
-	signer := hmac.New(sha1.New, []byte(b.Auth.SecretKey))
+	signer := hmac.New(sha1.New, []byte(auth.SecretKey))
 	signer.Write([]byte(policy64))
 	fields["signature"] = base64.StdEncoding.EncodeToString(signer.Sum(nil))
 
@@ -1094,6 +1386,71 @@ func (req *request) url() (*url.URL, error) {
 	return u, nil
 }
 
+// operation returns the name of the S3 operation made by req, as well as
+// can be told from its method, path, parameters and headers.
+func (req *request) operation() string {
+	isObject := req.signpath != "/"+req.bucket && req.signpath != "/"+req.bucket+"/"
+	has := func(param string) bool {
+		_, ok := req.params[param]
+		return ok
+	}
+	switch {
+	case req.bucket == "":
+		return "ListBuckets"
+	case !isObject:
+		switch req.method {
+		case "PUT":
+			return "CreateBucket"
+		case "DELETE":
+			return "DeleteBucket"
+		case "HEAD":
+			return "HeadBucket"
+		case "POST":
+			if has("delete") {
+				return "DeleteObjects"
+			}
+		case "GET":
+			switch {
+			case has("uploads"):
+				return "ListMultipartUploads"
+			case has("location"):
+				return "GetBucketLocation"
+			}
+			return "ListObjects"
+		}
+	case req.method == "GET":
+		if has("uploadId") {
+			return "ListParts"
+		}
+		return "GetObject"
+	case req.method == "HEAD":
+		return "HeadObject"
+	case req.method == "PUT":
+		switch {
+		case has("partNumber") && req.headers.Get("X-Amz-Copy-Source") != "":
+			return "UploadPartCopy"
+		case has("partNumber"):
+			return "UploadPart"
+		case req.headers.Get("X-Amz-Copy-Source") != "":
+			return "CopyObject"
+		}
+		return "PutObject"
+	case req.method == "POST":
+		switch {
+		case has("uploads"):
+			return "CreateMultipartUpload"
+		case has("uploadId"):
+			return "CompleteMultipartUpload"
+		}
+	case req.method == "DELETE":
+		if has("uploadId") {
+			return "AbortMultipartUpload"
+		}
+		return "DeleteObject"
+	}
+	return req.method
+}
+
 // query prepares and runs the req request.
 // If resp is not nil, the XML data contained in the response
 // body will be unmarshalled on it.
@@ -1157,11 +1514,17 @@ type ListAllMyBucketsResult struct {
 
 // List all buckets at the endpoint
 func (s3 *S3) ListBuckets(endpoint string) (ListAllMyBucketsResult, error) {
+	return s3.ListBucketsWithContext(context.Background(), endpoint)
+}
+
+// ListBucketsWithContext is like ListBuckets but makes its requests with ctx.
+func (s3 *S3) ListBucketsWithContext(ctx context.Context, endpoint string) (ListAllMyBucketsResult, error) {
 	req := request{
 		method:  "GET",
 		bucket:  "",
 		path:    "/",
 		baseurl: endpoint,
+		context: ctx,
 	}
 
 	s3.prepare(&req)
@@ -1173,8 +1536,6 @@ func (s3 *S3) ListBuckets(endpoint string) (ListAllMyBucketsResult, error) {
 
 // prepare sets up req to be delivered to S3.
 func (s3 *S3) prepare(req *request) error {
-	var signpath = req.path
-
 	if !req.prepared {
 		req.prepared = true
 		if req.method == "" {
@@ -1194,7 +1555,7 @@ func (s3 *S3) prepare(req *request) error {
 		if !strings.HasPrefix(req.path, "/") {
 			req.path = "/" + req.path
 		}
-		signpath = req.path
+		signpath := req.path
 
 		if req.bucket == "" && req.baseurl == "" {
 			req.baseurl = s3.Region.S3Endpoint
@@ -1215,6 +1576,7 @@ func (s3 *S3) prepare(req *request) error {
 			}
 			signpath = "/" + req.bucket + signpath
 		}
+		req.signpath = (&url.URL{Path: signpath}).String()
 	}
 
 	// Always sign again as it's not clear how far the
@@ -1223,63 +1585,104 @@ func (s3 *S3) prepare(req *request) error {
 	if err != nil {
 		return fmt.Errorf("bad S3 endpoint URL %q: %v", req.baseurl, err)
 	}
-	reqSignpathSpaceFix := (&url.URL{Path: signpath}).String()
 	req.headers["Host"] = []string{u.Host}
 
+	req.headers["Date"] = []string{requestDate()}
+
+	auth := s3.Auth.Current()
+	if token := auth.Token(); token != "" {
+		req.headers["X-Amz-Security-Token"] = []string{token}
+	}
+	return nil
+}
+
+// requestDate returns the time of aws.DefaultClock in the format of the
+// Date header.
+func requestDate() string {
 	// Use GMT instead of UTC as the time zone.
 	// All the examples in the S3 documentation use GMT.
 	// Some S3-compatible storage providers (e.g Cloudian) require GMT
 	// rather than UTC (as of June 2015)
-	gmtDate := time.Now().In(time.FixedZone("GMT", 0)).Format(time.RFC1123)
-	req.headers["Date"] = []string{gmtDate}
+	return aws.Now().In(time.FixedZone("GMT", 0)).Format(time.RFC1123)
+}
 
-	if s3.Auth.Token() != "" {
-		req.headers["X-Amz-Security-Token"] = []string{s3.Auth.Token()}
-	}
-	if !s3.v4sign {
-		sign(s3.Auth, req.method, reqSignpathSpaceFix, req.params, req.headers)
-	}
-	return nil
+// signV2 signs req, set up by prepare, with Signature Version 2.
+func (s3 *S3) signV2(req *request) {
+	sign(s3.Auth.Current(), req.method, req.signpath, req.params, req.headers)
 }
 
 // run sends req and returns the http response from the server.
 // If resp is not nil, the XML data contained in the response
 // body will be unmarshalled on it.
 func (s3 *S3) run(req *request, resp interface{}) (*http.Response, error) {
-	u, err := req.url()
-	if err != nil {
-		return nil, err
-	}
-
-	var hreq *http.Request
-	if req.method == "DELETE" && (req.params["x-storreduce-abort-clone"] != nil ||
-		req.params["x-storreduce-complete-abort-clone"] != nil) {
-		hreq, err = http.NewRequest("DELETE", u.String(), nil)
+	build := func(r *aws.Request) error {
+		u, err := req.url()
 		if err != nil {
-			return nil, err
+			return err
 		}
-	} else {
-		hreq = &http.Request{
-			URL:        u,
-			Host:       u.Host,
-			Method:     req.method,
-			ProtoMajor: 1,
-			ProtoMinor: 1,
-			Header:     req.headers,
+
+		var hreq *http.Request
+		if req.method == "DELETE" && (req.params["x-storreduce-abort-clone"] != nil ||
+			req.params["x-storreduce-complete-abort-clone"] != nil) {
+			hreq, err = http.NewRequest("DELETE", u.String(), nil)
+			if err != nil {
+				return err
+			}
+		} else {
+			hreq = &http.Request{
+				URL:        u,
+				Host:       u.Host,
+				Method:     req.method,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     req.headers,
+			}
 		}
-	}
 
-	if v, ok := req.headers["Content-Length"]; ok {
-		hreq.ContentLength, _ = strconv.ParseInt(v[0], 10, 64)
-		delete(req.headers, "Content-Length")
-	}
+		if v, ok := req.headers["Content-Length"]; ok {
+			hreq.ContentLength, _ = strconv.ParseInt(v[0], 10, 64)
+			delete(req.headers, "Content-Length")
+		}
 
-	if req.payload != nil {
-		hreq.Body = ioutil.NopCloser(req.payload)
+		if req.payload != nil {
+			hreq.Body = ioutil.NopCloser(req.payload)
+			if seeker, ok := req.payload.(io.Seeker); ok {
+				// Let the pipeline send the payload again.
+				if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+					hreq.GetBody = func() (io.ReadCloser, error) {
+						if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+							return nil, err
+						}
+						return ioutil.NopCloser(req.payload), nil
+					}
+				}
+			}
+		}
+		if s3.client == nil {
+			// Close should be set to true here as the logic below constructs a transport
+			// for every http request so connection pooling doesn't work.
+			// We can at least be kind to the down stream server by letting them know
+			// we won't be reusing the connection. This code should be yanked a some point.
+			hreq.Close = true
+		}
+		r.HTTPRequest = hreq
+		return nil
 	}
 
-	if s3.v4sign {
-		s3.signer.Sign(hreq)
+	sign := func(r *aws.Request) error {
+		if r.Attempts > 0 {
+			// Date the request afresh, as it may have been rejected for
+			// clock skew.
+			r.HTTPRequest.Header.Set("Date", requestDate())
+			r.HTTPRequest.Header.Del("X-Amz-Date")
+			r.HTTPRequest.Header.Del("Authorization")
+		}
+		if s3.v4sign {
+			s3.signer.Sign(r.HTTPRequest)
+		} else {
+			s3.signV2(req)
+		}
+		return nil
 	}
 
 	var httpClient *http.Client
@@ -1295,21 +1698,7 @@ func (s3 *S3) run(req *request, resp interface{}) (*http.Response, error) {
 			return nil, errors.New("Timeouts must be specified using contexts when " +
 				"using a custom HTTP client.")
 		}
-
-		if req.context != nil {
-			hreq = hreq.WithContext(req.context)
-		}
 	} else {
-		// Close should be set to true here as the logic below constructs a transport
-		// for every http request so connection pooling doesn't work.
-		// We can at least be kind to the down stream server by letting them know
-		// we won't be reusing the connection. This code should be yanked a some point.
-		hreq.Close = true
-
-		if req.context != nil {
-			return nil, errors.New("Contexts are not supported when using the internal HTTP client.")
-		}
-
 		httpClient = &http.Client{
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
@@ -1358,51 +1747,67 @@ func (s3 *S3) run(req *request, resp interface{}) (*http.Response, error) {
 		}
 	}
 
-	if Debug {
-		log.Printf("%s GoAMZ Running S3 Request: %s %s %s",
-			time.Now().UTC().Format("2006/01/02 15:04:05.000"),
-			hreq.Method, hreq.URL, hreq.Header)
-	}
-
-	startTime := time.Now()
-	hresp, err := httpClient.Do(hreq)
-	dt := time.Since(startTime)
-
-	srLog := func(ierr error) {
-		if SRRequestLogger != nil {
-			SRRequestLogger(req.context, hreq, hresp, dt, ierr)
+	var dt time.Duration
+	send := func(r *aws.Request) (err error) {
+		hreq := r.HTTPRequest
+		if Debug {
+			log.Printf("%s GoAMZ Running S3 Request: %s %s %s",
+				time.Now().UTC().Format("2006/01/02 15:04:05.000"),
+				hreq.Method, hreq.URL, hreq.Header)
 		}
-	}
-
-	if err != nil {
-		srLog(err)
-		return nil, err
-	}
-
-	if Debug {
-		log.Printf("%s GoAMZ Response to S3 Request: %s %s %s %s %s %#v",
-			time.Now().UTC().Format("2006/01/02 15:04:05.000"),
-			hreq.Method, hreq.URL, dt, hresp.Status, hresp.Header, err)
-	}
 
-	// Allow for any 2xx series status code; else, build an error.s
-	if hresp.StatusCode < 200 || hresp.StatusCode >= 300 {
-		defer hresp.Body.Close()
-		err = buildError(hresp)
-		srLog(err)
-		return nil, err
+		startTime := time.Now()
+		r.HTTPResponse, err = r.Client.Do(hreq.WithContext(r.Context))
+		dt = time.Since(startTime)
+		return err
 	}
 
-	if resp != nil {
-		err = xml.NewDecoder(hresp.Body).Decode(resp)
-		hresp.Body.Close()
+	unmarshal := func(r *aws.Request) error {
+		hreq, hresp := r.HTTPRequest, r.HTTPResponse
 		if Debug {
-			log.Printf("goamz.s3> decoded xml into %#v", resp)
+			log.Printf("%s GoAMZ Response to S3 Request: %s %s %s %s %s",
+				time.Now().UTC().Format("2006/01/02 15:04:05.000"),
+				hreq.Method, hreq.URL, dt, hresp.Status, hresp.Header)
 		}
-	}
 
-	srLog(err)
+		// Allow for any 2xx series status code; else, build an error.s
+		if hresp.StatusCode < 200 || hresp.StatusCode >= 300 {
+			return buildError(hresp)
+		}
 
+		if r.Data != nil {
+			err := xml.NewDecoder(hresp.Body).Decode(r.Data)
+			hresp.Body.Close()
+			if Debug {
+				log.Printf("goamz.s3> decoded xml into %#v", r.Data)
+			}
+			return err
+		}
+		return nil
+	}
+
+	r := &aws.Request{
+		Context:     req.context,
+		Service:     "s3",
+		Region:      s3.Region.Name,
+		Operation:   req.operation(),
+		Client:      httpClient,
+		RateLimiter: s3.RateLimiter,
+		Data:        resp,
+	}
+	err := aws.DefaultPipeline.Run(r, aws.Handlers{
+		Build:     build,
+		Sign:      sign,
+		Send:      send,
+		Unmarshal: unmarshal,
+	})
+	hresp := r.HTTPResponse
+	if SRRequestLogger != nil {
+		SRRequestLogger(req.context, r.HTTPRequest, hresp, dt, err)
+	}
+	if hresp == nil || hresp.StatusCode < 200 || hresp.StatusCode >= 300 {
+		return nil, err
+	}
 	return hresp, err
 }
 
@@ -1421,6 +1826,17 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (e *Error) ErrorCode() string    { return e.Code }
+func (e *Error) ErrorMessage() string { return e.Message }
+func (e *Error) HTTPStatusCode() int  { return e.StatusCode }
+func (e *Error) RequestID() string    { return e.RequestId }
+
+// Is reports whether e is of the class target, such as aws.ErrNotFound.
+func (e *Error) Is(target error) bool { return aws.ErrorIs(e, target) }
+
 func buildError(r *http.Response) error {
 	if Debug {
 		log.Printf("got error (status code %v)", r.StatusCode)
@@ -1469,6 +1885,10 @@ func ShouldRetry(err error) bool {
 	if err == nil {
 		return false
 	}
+	// A request whose context is done fails the same way every time.
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
 	if Debug {
 		log.Printf("got error: %s (%#v)", err.Error(), err)
 	}
@@ -1544,6 +1964,10 @@ func shouldRetrySpecific(err error) bool {
 		case "InternalError", "NoSuchUpload", "RequestTimeout":
 			return true
 		}
+		// SlowDown and the other throttling and transient codes.
+		if aws.IsRetryableCode(e.Code) {
+			return true
+		}
 
 		// 500-series responses should always be retried
 		if e.StatusCode >= 500 && e.StatusCode <= 599 {
@@ -1585,6 +2009,9 @@ func shouldRetryAlmostAll(err error) bool {
 			case "InternalError", "NoSuchUpload", "RequestTimeout":
 				return true
 			}
+			if aws.IsRetryableCode(e.Code) {
+				return true
+			}
 
 			switch e.StatusCode {
 			case 429: // http.StatusTooManyRequests:
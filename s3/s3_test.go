@@ -2,8 +2,11 @@ package s3_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -132,6 +135,47 @@ func (s *S) TestURL(c *C) {
 	c.Assert(req.URL.Path, Equals, "/bucket/name")
 }
 
+func (s *S) TestSignedURLV4(c *C) {
+	testServer.Response(200, nil, "content")
+
+	auth := aws.Auth{AccessKey: "abc", SecretKey: "123"}
+	b := s3.NewV4(auth, aws.Region{Name: "faux-region-1", S3Endpoint: testServer.URL}).Bucket("bucket")
+	r, err := http.Get(b.SignedURL("name", time.Now().Add(time.Hour)))
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "content")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/bucket/name")
+	q := req.URL.Query()
+	c.Assert(q.Get("X-Amz-Algorithm"), Equals, "AWS4-HMAC-SHA256")
+	c.Assert(q.Get("X-Amz-Credential"), Matches, `abc/\d{8}/faux-region-1/s3/aws4_request`)
+	c.Assert(q.Get("X-Amz-Expires"), Equals, "3600")
+	c.Assert(q.Get("X-Amz-SignedHeaders"), Equals, "host")
+	c.Assert(q.Get("X-Amz-Signature"), Matches, "[0-9a-f]{64}")
+	c.Assert(q.Get("Signature"), Equals, "")
+
+	// A URL that expires in the past is dated so that it has expired.
+	u, err := url.Parse(b.SignedURL("name", time.Now().Add(-time.Hour)))
+	c.Assert(err, IsNil)
+	date, err := time.Parse(aws.ISO8601BasicFormat, u.Query().Get("X-Amz-Date"))
+	c.Assert(err, IsNil)
+	c.Assert(date.Before(time.Now().Add(-time.Hour)), Equals, true)
+	c.Assert(u.Query().Get("X-Amz-Expires"), Equals, "1")
+
+	u, err = url.Parse(b.UploadSignedURL("name", "PUT", "image/png", time.Now().Add(24*time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(u.Query().Get("X-Amz-SignedHeaders"), Equals, "content-type;host")
+	c.Assert(u.Query().Get("X-Amz-Expires"), Equals, "86400")
+
+	// Signatures are valid for 7 days at most.
+	u, err = url.Parse(b.SignedURL("name", time.Now().Add(8*24*time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(u.Query().Get("X-Amz-Expires"), Equals, "604800")
+}
+
 func (s *S) TestGetReader(c *C) {
 	testServer.Response(200, nil, "content")
 
@@ -149,6 +193,47 @@ func (s *S) TestGetReader(c *C) {
 	c.Assert(req.Header["Date"], Not(Equals), "")
 }
 
+func (s *S) TestGetRange(c *C) {
+	testServer.Response(206, nil, "tent")
+
+	b := s.s3.Bucket("bucket")
+	rc, err := b.GetRange("name", 3, 4)
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "tent")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.Header.Get("Range"), Equals, "bytes=3-6")
+}
+
+func (s *S) TestGetRangeEmpty(c *C) {
+	b := s.s3.Bucket("bucket")
+	rc, err := b.GetRange("name", 3, 0)
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(err, IsNil)
+	c.Assert(data, HasLen, 0)
+
+	// No request was made for the empty range.
+	testServer.Response(206, nil, "t")
+	rc, err = b.GetRange("name", 3, 1)
+	c.Assert(err, IsNil)
+	rc.Close()
+	req := testServer.WaitRequest()
+	c.Assert(req.Header.Get("Range"), Equals, "bytes=3-3")
+}
+
+func (s *S) TestGetRangeNegativeLength(c *C) {
+	b := s.s3.Bucket("bucket")
+	rc, err := b.GetRange("name", 3, -1)
+	c.Assert(rc, IsNil)
+	c.Assert(err, ErrorMatches, "s3: invalid length -1 of range of name")
+}
+
 func (s *S) TestGetNotFound(c *C) {
 	for i := 0; i < 10; i++ {
 		testServer.Response(404, nil, GetObjectErrorDump)
@@ -172,6 +257,65 @@ func (s *S) TestGetNotFound(c *C) {
 	c.Assert(s3err.Message, Equals, "The specified bucket does not exist")
 	c.Assert(s3err.Error(), Equals, "The specified bucket does not exist")
 	c.Assert(data, IsNil)
+
+	var apiErr aws.APIError
+	c.Assert(errors.As(err, &apiErr), Equals, true)
+	c.Assert(apiErr.RequestID(), Equals, "3F1B667FAD71C3D8")
+	c.Assert(aws.IsNotFound(err), Equals, true)
+	c.Assert(errors.Is(err, aws.ErrNotFound), Equals, true)
+	c.Assert(aws.IsRetryable(err), Equals, false)
+}
+
+func (s *S) TestGetWithContextCanceled(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b := s.s3.Bucket("bucket")
+	data, err := b.GetWithContext(ctx, "name")
+	c.Assert(data, IsNil)
+	c.Assert(errors.Is(err, context.Canceled), Equals, true)
+}
+
+func (s *S) TestGetWithContextInterruptsRetries(c *C) {
+	s.s3.AttemptStrategy = aws.FixedAttemptStrategy{
+		Total: 5 * time.Second,
+		Delay: time.Second,
+	}
+	testServer.Response(500, nil, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	b := s.s3.Bucket("bucket")
+	t0 := time.Now()
+	_, err := b.GetWithContext(ctx, "name")
+	c.Assert(err, NotNil)
+	c.Assert(time.Since(t0) < time.Second, Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/bucket/name")
+}
+
+func (s *S) TestGetRunsThroughDefaultPipeline(c *C) {
+	var ops []string
+	aws.DefaultPipeline.Use(aws.SendPhase, "test", func(next aws.Handler) aws.Handler {
+		return func(r *aws.Request) error {
+			ops = append(ops, r.Service+"."+r.Operation)
+			r.HTTPRequest.Header.Set("X-Injected", "yes")
+			return next(r)
+		}
+	})
+	defer aws.DefaultPipeline.Remove(aws.SendPhase, "test")
+	testServer.Response(200, nil, "content")
+
+	b := s.s3.Bucket("bucket")
+	data, err := b.Get("name")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "content")
+	c.Assert(ops, DeepEquals, []string{"s3.GetObject"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Header["X-Injected"], DeepEquals, []string{"yes"})
+	c.Assert(req.Header["Authorization"], NotNil)
 }
 
 // PutObject docs: http://goo.gl/FEBPD
@@ -1,14 +1,20 @@
 package s3_test
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"sort"
 	"strings"
+	"testing/fstest"
 	"time"
 
 	"github.com/hughe/goamz/aws"
@@ -211,17 +217,14 @@ func (s *ClientTests) TestBasicFunctionality(c *C) {
 	c.Check(string(data), Equals, "hey!")
 	rc.Close()
 
-	if !s.isV4 {
-		// V4 currently not supporting the SignedURL method call
-		data, err = get(b.SignedURL("name2", time.Now().Add(time.Hour)))
-		c.Assert(err, IsNil)
-		c.Assert(string(data), Equals, "hey!")
+	data, err = get(b.SignedURL("name2", time.Now().Add(time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "hey!")
 
-		if !s.authIsBroken {
-			data, err = get(b.SignedURL("name2", time.Now().Add(-time.Hour)))
-			c.Assert(err, IsNil)
-			c.Assert(string(data), Matches, "(?s).*AccessDenied.*")
-		}
+	if !s.authIsBroken {
+		data, err = get(b.SignedURL("name2", time.Now().Add(-time.Hour)))
+		c.Assert(err, IsNil)
+		c.Assert(string(data), Matches, "(?s).*AccessDenied.*")
 	}
 
 	err = b.DelBucket()
@@ -435,6 +438,148 @@ func (s *ClientTests) TestBucketList(c *C) {
 	}
 }
 
+func (s *ClientTests) TestBucketListPages(c *C) {
+	b := testBucket(s.s3)
+	err := b.PutBucket(s3.Private)
+	c.Assert(err, IsNil)
+
+	for _, path := range objectNames {
+		err := b.Put(path, nil, "text/plain", s3.Private, s3.Options{})
+		c.Assert(err, IsNil)
+		defer b.Del(path)
+	}
+
+	var pages int
+	var names, prefixes []string
+	p := b.ListPages("", "/", "", 1)
+	for p.Next(context.Background()) {
+		pages++
+		for _, k := range p.Page().Contents {
+			names = append(names, k.Key)
+		}
+		prefixes = append(prefixes, p.Page().CommonPrefixes...)
+	}
+	c.Assert(p.Err(), IsNil)
+	c.Assert(pages, Equals, 4)
+	c.Assert(names, DeepEquals, objectNames[:2])
+	c.Assert(prefixes, DeepEquals, []string{"photos/", "test/"})
+
+	all, err := b.ListAll(context.Background(), "photos/", "")
+	c.Assert(err, IsNil)
+	c.Assert(all, HasLen, 4)
+	c.Assert(all[3].Key, Equals, objectNames[5])
+}
+
+func (s *ClientTests) TestGetRangeAndDownload(c *C) {
+	b := testBucket(s.s3)
+	err := b.PutBucket(s3.Private)
+	c.Assert(err, IsNil)
+
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	err = b.Put("large", data, "application/octet-stream", s3.Private, s3.Options{})
+	c.Assert(err, IsNil)
+	defer b.Del("large")
+
+	rc, err := b.GetRange("large", 100, 50)
+	c.Assert(err, IsNil)
+	got, err := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, data[100:150])
+
+	f, err := ioutil.TempFile(c.MkDir(), "large")
+	c.Assert(err, IsNil)
+	defer f.Close()
+	d := s3.NewDownloader(b)
+	d.PartSize = 64
+	d.Concurrency = 4
+	n, err := d.Download(f, "large")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	got, err = ioutil.ReadFile(f.Name())
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, data)
+}
+
+func (s *ClientTests) TestOpen(c *C) {
+	b := testBucket(s.s3)
+	err := b.PutBucket(s3.Private)
+	c.Assert(err, IsNil)
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		w, err := zw.Create(name)
+		c.Assert(err, IsNil)
+		fmt.Fprintf(w, "contents of %s", name)
+	}
+	c.Assert(zw.Close(), IsNil)
+	data := archive.Bytes()
+	err = b.Put("archive.zip", data, "application/zip", s3.Private, s3.Options{})
+	c.Assert(err, IsNil)
+	defer b.Del("archive.zip")
+
+	o, err := b.Open("archive.zip")
+	c.Assert(err, IsNil)
+	defer o.Close()
+	c.Assert(o.Size(), Equals, int64(len(data)))
+	o.ReadAhead = 64
+
+	zr, err := zip.NewReader(o, o.Size())
+	c.Assert(err, IsNil)
+	c.Assert(zr.File, HasLen, 2)
+	rc, err := zr.File[1].Open()
+	c.Assert(err, IsNil)
+	got, err := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(err, IsNil)
+	c.Assert(string(got), Equals, "contents of b.txt")
+
+	pos, err := o.Seek(-10, io.SeekEnd)
+	c.Assert(err, IsNil)
+	c.Assert(pos, Equals, int64(len(data)-10))
+	got, err = ioutil.ReadAll(o)
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, data[len(data)-10:])
+
+	// The object is replaced.
+	err = b.Put("archive.zip", []byte("replaced"), "text/plain", s3.Private, s3.Options{})
+	c.Assert(err, IsNil)
+	_, err = o.ReadAt(make([]byte, 10), 0)
+	c.Assert(errors.Is(err, s3.ErrObjectChanged), Equals, true, Commentf("%v", err))
+}
+
+func (s *ClientTests) TestFS(c *C) {
+	b := testBucket(s.s3)
+	err := b.PutBucket(s3.Private)
+	c.Assert(err, IsNil)
+
+	paths := []string{"fs/a.txt", "fs/dir.txt", "fs/dir/b.txt", "fs/dir/sub/c.txt", "fs/empty/", "other.txt"}
+	for _, path := range paths {
+		err := b.Put(path, []byte("contents of "+path), "text/plain", s3.Private, s3.Options{})
+		c.Assert(err, IsNil)
+		defer b.Del(path)
+	}
+
+	fsys := b.FS("fs")
+	err = fstest.TestFS(fsys, "a.txt", "dir.txt", "dir/b.txt", "dir/sub/c.txt", "empty")
+	c.Assert(err, IsNil)
+
+	data, err := fs.ReadFile(fsys, "dir/sub/c.txt")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "contents of fs/dir/sub/c.txt")
+	info, err := fs.Stat(fsys, "dir")
+	c.Assert(err, IsNil)
+	c.Assert(info.IsDir(), Equals, true)
+	_, err = fsys.Open("other.txt")
+	c.Assert(errors.Is(err, fs.ErrNotExist), Equals, true, Commentf("%v", err))
+	_, err = fsys.ReadDir("missing")
+	c.Assert(errors.Is(err, fs.ErrNotExist), Equals, true, Commentf("%v", err))
+}
+
 func etag(data []byte) string {
 	sum := md5.New()
 	sum.Write(data)
@@ -1,6 +1,8 @@
 package s3_test
 
 import (
+	"time"
+
 	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/s3"
 	"github.com/hughe/goamz/s3/s3test"
@@ -35,6 +37,13 @@ func (s *LocalServer) SetUp(c *C) {
 type LocalServerSuite struct {
 	srv         LocalServer
 	clientTests ClientTests
+	v4          bool
+}
+
+var verifyingConfig = &s3test.Config{
+	Verifier: &aws.Verifier{
+		Credentials: aws.NewStaticCredentials(aws.Credentials{AccessKey: "access", SecretKey: "secret"}),
+	},
 }
 
 var (
@@ -47,17 +56,94 @@ var (
 			},
 		},
 	})
+
+	// and again with the server checking signatures of both versions.
+	_ = Suite(&LocalServerSuite{
+		srv: LocalServer{
+			auth:   aws.Auth{AccessKey: "access", SecretKey: "secret"},
+			config: verifyingConfig,
+		},
+	})
+	_ = Suite(&LocalServerSuite{
+		srv: LocalServer{
+			auth:   aws.Auth{AccessKey: "access", SecretKey: "secret"},
+			config: verifyingConfig,
+		},
+		v4: true,
+	})
 )
 
 func (s *LocalServerSuite) SetUpSuite(c *C) {
 	s.srv.SetUp(c)
-	s.clientTests.s3 = s3.New(s.srv.auth, s.srv.region)
+	if s.v4 {
+		s.clientTests.s3 = s3.NewV4(s.srv.auth, s.srv.region)
+		s.clientTests.isV4 = true
+	} else {
+		s.clientTests.s3 = s3.New(s.srv.auth, s.srv.region)
+	}
 
-	// TODO Sadly the fake server ignores auth completely right now. :-(
-	s.clientTests.authIsBroken = true
+	// The server ignores auth unless it verifies signatures.
+	s.clientTests.authIsBroken = s.srv.config == nil || s.srv.config.Verifier == nil
 	s.clientTests.Cleanup()
 }
 
+func (s *LocalServerSuite) TestVerifyRejectsBadSignatures(c *C) {
+	if s.clientTests.authIsBroken {
+		c.Skip("server does not verify signatures")
+	}
+	auth := s.srv.auth
+	auth.SecretKey = "wrong"
+	b := s3.New(auth, s.srv.region).Bucket("bucket")
+	if s.v4 {
+		b = s3.NewV4(auth, s.srv.region).Bucket("bucket")
+	}
+	err := b.PutBucket(s3.Private)
+	c.Assert(err, NotNil)
+	c.Assert(err.(*s3.Error).StatusCode, Equals, 403)
+	c.Assert(err.(*s3.Error).Code, Equals, "SignatureDoesNotMatch")
+
+	auth.AccessKey = "unknown"
+	b = s3.New(auth, s.srv.region).Bucket("bucket")
+	_, err = b.Get("name")
+	c.Assert(err, NotNil)
+	c.Assert(err.(*s3.Error).Code, Equals, "InvalidAccessKeyId")
+}
+
+func (s *LocalServerSuite) TestClockSkewCorrection(c *C) {
+	if s.clientTests.authIsBroken {
+		c.Skip("server does not verify signatures")
+	}
+	defer aws.DefaultClock.SetOffset(0)
+
+	// The server's clock is an hour behind.
+	srv, err := s3test.NewServer(&s3test.Config{
+		Verifier: &aws.Verifier{
+			Credentials: verifyingConfig.Verifier.Credentials,
+			Now:         func() time.Time { return time.Now().Add(-time.Hour) },
+		},
+	})
+	c.Assert(err, IsNil)
+	defer srv.Quit()
+	region := s.srv.region
+	region.S3Endpoint = srv.URL()
+	client := s3.New(s.srv.auth, region)
+	if s.v4 {
+		client = s3.NewV4(s.srv.auth, region)
+	}
+
+	b := client.Bucket("skewed")
+	c.Assert(b.PutBucket(s3.Private), IsNil)
+	offset := aws.DefaultClock.Offset()
+	c.Assert(offset < -59*time.Minute && offset > -61*time.Minute, Equals, true, Commentf("offset %v", offset))
+
+	// A payload is sent again with the request.
+	aws.DefaultClock.SetOffset(0)
+	c.Assert(b.Put("name", []byte("hello"), "text/plain", s3.Private, s3.Options{}), IsNil)
+	data, err := b.Get("name")
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "hello")
+}
+
 func (s *LocalServerSuite) TearDownTest(c *C) {
 	s.clientTests.Cleanup()
 }
@@ -74,6 +160,22 @@ func (s *LocalServerSuite) TestBucketList(c *C) {
 	s.clientTests.TestBucketList(c)
 }
 
+func (s *LocalServerSuite) TestBucketListPages(c *C) {
+	s.clientTests.TestBucketListPages(c)
+}
+
 func (s *LocalServerSuite) TestDoublePutBucket(c *C) {
 	s.clientTests.TestDoublePutBucket(c)
 }
+
+func (s *LocalServerSuite) TestGetRangeAndDownload(c *C) {
+	s.clientTests.TestGetRangeAndDownload(c)
+}
+
+func (s *LocalServerSuite) TestOpen(c *C) {
+	s.clientTests.TestOpen(c)
+}
+
+func (s *LocalServerSuite) TestFS(c *C) {
+	s.clientTests.TestFS(c)
+}
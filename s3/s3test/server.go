@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"encoding/xml"
 	"fmt"
+	"github.com/hughe/goamz/aws"
 	"github.com/hughe/goamz/s3"
 	"io"
 	"io/ioutil"
@@ -51,6 +52,11 @@ type Config struct {
 	// all other regions.
 	// http://docs.amazonwebservices.com/AmazonS3/latest/API/ErrorResponses.html
 	Send409Conflict bool
+
+	// Verifier, if not nil, checks the signature of every signed request.
+	// Requests whose signatures it rejects fail with the error code it
+	// reports.
+	Verifier *aws.Verifier
 }
 
 func (c *Config) send409Conflict() bool {
@@ -60,6 +66,13 @@ func (c *Config) send409Conflict() bool {
 	return false
 }
 
+func (c *Config) verifier() *aws.Verifier {
+	if c != nil {
+		return c.Verifier
+	}
+	return nil
+}
+
 // Server is a fake S3 server for testing purposes.
 // All of the data for the server is kept in memory.
 type Server struct {
@@ -133,9 +146,6 @@ func fatalf(code int, codeStr string, errf string, a ...interface{}) {
 
 // serveHTTP serves the S3 protocol.
 func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
-	// ignore error from ParseForm as it's usually spurious.
-	req.ParseForm()
-
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
 
@@ -171,6 +181,26 @@ func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
+	if v := srv.config.verifier(); v != nil {
+		switch err := v.Verify(req).(type) {
+		case nil:
+		case *aws.SignatureError:
+			// S3 serves anonymous requests for public resources, and
+			// ACLs are not implemented, so only signed requests are
+			// checked.
+			if err.Code != "MissingAuthenticationToken" {
+				if !err.ServerTime.IsZero() {
+					w.Header().Set("Date", err.ServerTime.UTC().Format(http.TimeFormat))
+				}
+				fatalf(err.HTTPStatusCode(), err.Code, "%s", err.Error())
+			}
+		default:
+			fatalf(400, "InvalidRequest", "%v", err)
+		}
+	}
+	// ignore error from ParseForm as it's usually spurious.
+	req.ParseForm()
+
 	r = srv.resourceForURL(req.URL)
 
 	var resp interface{}
@@ -362,6 +392,12 @@ func (r bucketResource) get(a *action) interface{} {
 			// Contents contains only keys not found in CommonPrefixes
 			resp.Contents = append(resp.Contents, obj.s3Key())
 		}
+		if delimiter != "" {
+			resp.NextMarker = name
+		}
+	}
+	if !resp.IsTruncated {
+		resp.NextMarker = ""
 	}
 	resp.CommonPrefixes = prefixes
 	return resp
@@ -512,24 +548,36 @@ func (objr objectResource) get(a *action) interface{} {
 			h.Set(name, vals[0])
 		}
 	}
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(obj.checksum))
+	if m := a.req.Header.Get("If-Match"); m != "" && m != "*" && m != etag {
+		fatalf(412, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold")
+	}
+	data := obj.data
+	status := http.StatusOK
 	if r := a.req.Header.Get("Range"); r != "" {
-		fatalf(400, "NotImplemented", "range unimplemented")
+		start, end, ok := parseRange(r, int64(len(data)))
+		if !ok {
+			fatalf(416, "InvalidRange", "The requested range is not satisfiable")
+		}
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		data = data[start : end+1]
+		status = http.StatusPartialContent
 	}
 	// TODO Last-Modified-Since
 	// TODO If-Modified-Since
 	// TODO If-Unmodified-Since
-	// TODO If-Match
 	// TODO If-None-Match
 	// TODO Connection: close ??
 	// TODO x-amz-request-id
-	h.Set("Content-Length", fmt.Sprint(len(obj.data)))
-	h.Set("ETag", hex.EncodeToString(obj.checksum))
-	h.Set("Last-Modified", obj.mtime.Format(time.RFC1123))
+	h.Set("Content-Length", fmt.Sprint(len(data)))
+	h.Set("ETag", etag)
+	h.Set("Last-Modified", obj.mtime.UTC().Format(http.TimeFormat))
 	if a.req.Method == "HEAD" {
 		return nil
 	}
+	a.w.WriteHeader(status)
 	// TODO avoid holding the lock when writing data.
-	_, err := a.w.Write(obj.data)
+	_, err := a.w.Write(data)
 	if err != nil {
 		// we can't do much except just log the fact.
 		log.Printf("error writing data: %v", err)
@@ -537,6 +585,43 @@ func (objr objectResource) get(a *action) interface{} {
 	return nil
 }
 
+// parseRange parses a Range header of a single range of bytes, returning
+// the first and last bytes of the range within an object of size bytes.
+func parseRange(r string, size int64) (start, end int64, ok bool) {
+	spec := strings.TrimPrefix(r, "bytes=")
+	dash := strings.Index(spec, "-")
+	if spec == r || dash < 0 || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last := spec[:dash], spec[dash+1:]
+	end = size - 1
+	var err error
+	switch {
+	case first == "":
+		// The last bytes of the object.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		if start = size - n; start < 0 {
+			start = 0
+		}
+	default:
+		if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+			return 0, 0, false
+		}
+		if last != "" {
+			if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+				return 0, 0, false
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+	}
+	return start, end, start < size
+}
+
 var metaHeaders = map[string]bool{
 	"Content-MD5":         true,
 	"x-amz-acl":           true,
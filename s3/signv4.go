@@ -5,6 +5,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -37,12 +38,14 @@ type V4Signer struct {
 Return a new instance of a V4Signer capable of signing AWS requests.
 */
 func NewV4Signer(auth aws.Auth, serviceName string, region aws.Region) *V4Signer {
-	return &V4Signer{
-		auth:        auth,
-		serviceName: serviceName,
-		region:      region,
+	s := &V4Signer{
+		auth:                     auth,
+		serviceName:              serviceName,
+		region:                   region,
 		IncludeXAmzContentSha256: false,
 	}
+	s.serviceName, s.region.Name = aws.SigningScope(serviceName, region)
+	return s
 }
 
 /*
@@ -58,6 +61,14 @@ The signed request will include a new "Authorization" header indicating that the
 Any changes to the request after signing the request will invalidate the signature.
 */
 func (s *V4Signer) Sign(req *http.Request) {
+	// Sign with a copy holding the current credentials so that the signer
+	// shared by all requests of an S3 value is never modified.
+	signer := *s
+	signer.auth = s.auth.Current()
+	signer.sign(req)
+}
+
+func (s *V4Signer) sign(req *http.Request) {
 	req.Header.Set("host", req.Host) // host header must be included as a signed header
 	payloadHash := s.payloadHash(req)
 	if s.IncludeXAmzContentSha256 {
@@ -77,7 +88,7 @@ func (s *V4Signer) Sign(req *http.Request) {
 requestTime method will parse the time from the request "x-amz-date" or "date" headers.
 If the "x-amz-date" header is present, that will take priority over the "date" header.
 If neither header is defined or we are unable to parse either header as a valid date
-then we will create a new "x-amz-date" header with the current time.
+then we will create a new "x-amz-date" header with the current time of aws.DefaultClock.
 */
 func (s *V4Signer) requestTime(req *http.Request) time.Time {
 
@@ -107,7 +118,7 @@ func (s *V4Signer) requestTime(req *http.Request) time.Time {
 	}
 
 	// Create a current time header to be used
-	t = time.Now().UTC()
+	t = aws.Now().UTC()
 	req.Header.Set("x-amz-date", t.Format(ISO8601BasicFormat))
 	return t
 }
@@ -115,13 +126,13 @@ func (s *V4Signer) requestTime(req *http.Request) time.Time {
 /*
 canonicalRequest method creates the canonical request according to Task 1 of the AWS Signature Version 4 Signing Process. (http://goo.gl/eUUZ3S)
 
-    CanonicalRequest =
-      HTTPRequestMethod + '\n' +
-      CanonicalURI + '\n' +
-      CanonicalQueryString + '\n' +
-      CanonicalHeaders + '\n' +
-      SignedHeaders + '\n' +
-      HexEncode(Hash(Payload))
+	CanonicalRequest =
+	  HTTPRequestMethod + '\n' +
+	  CanonicalURI + '\n' +
+	  CanonicalQueryString + '\n' +
+	  CanonicalHeaders + '\n' +
+	  SignedHeaders + '\n' +
+	  HexEncode(Hash(Payload))
 
 payloadHash is optional; use the empty string and it will be calculated from the request
 */
@@ -217,6 +228,9 @@ func (s *V4Signer) payloadHash(req *http.Request) string {
 			panic(err)
 		}
 		req.Body = ioutil.NopCloser(bytes.NewBuffer(b))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
 	}
 	return s.hash(string(b))
 }
@@ -224,11 +238,11 @@ func (s *V4Signer) payloadHash(req *http.Request) string {
 /*
 stringToSign method creates the string to sign accorting to Task 2 of the AWS Signature Version 4 Signing Process. (http://goo.gl/es1PAu)
 
-    StringToSign  =
-      Algorithm + '\n' +
-      RequestDate + '\n' +
-      CredentialScope + '\n' +
-      HexEncode(Hash(CanonicalRequest))
+	StringToSign  =
+	  Algorithm + '\n' +
+	  RequestDate + '\n' +
+	  CredentialScope + '\n' +
+	  HexEncode(Hash(CanonicalRequest))
 */
 func (s *V4Signer) stringToSign(t time.Time, creq string) string {
 	w := new(bytes.Buffer)
@@ -246,7 +260,7 @@ func (s *V4Signer) credentialScope(t time.Time) string {
 /*
 signature method calculates the AWS Signature Version 4 according to Task 3 of the AWS Signature Version 4 Signing Process. (http://goo.gl/j0Yqe1)
 
-    signature = HexEncode(HMAC(derived-signing-key, string-to-sign))
+	signature = HexEncode(HMAC(derived-signing-key, string-to-sign))
 */
 func (s *V4Signer) signature(t time.Time, sts string) string {
 	h := s.hmac(s.derivedKey(t), []byte(sts))
@@ -256,11 +270,11 @@ func (s *V4Signer) signature(t time.Time, sts string) string {
 /*
 derivedKey method derives a signing key to be used for signing a request.
 
-    kSecret = Your AWS Secret Access Key
-    kDate = HMAC("AWS4" + kSecret, Date)
-    kRegion = HMAC(kDate, Region)
-    kService = HMAC(kRegion, Service)
-    kSigning = HMAC(kService, "aws4_request")
+	kSecret = Your AWS Secret Access Key
+	kDate = HMAC("AWS4" + kSecret, Date)
+	kRegion = HMAC(kDate, Region)
+	kService = HMAC(kRegion, Service)
+	kSigning = HMAC(kService, "aws4_request")
 */
 func (s *V4Signer) derivedKey(t time.Time) []byte {
 	h := s.hmac([]byte("AWS4"+s.auth.SecretKey), []byte(t.Format(ISO8601BasicFormatShort)))
@@ -2,8 +2,10 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
+	"github.com/hughe/goamz/aws"
 	"sort"
 )
 
@@ -29,6 +31,11 @@ func (s byKey) Less(i, j int) bool { return s[i].Key < s[j].Key }
 // code does not handle the ?tagging parameter on the URL properly.
 // Not a big deal for what we use tagging for.
 func (b *Bucket) PutObjectTagging(key string, tagSet map[string]string) error {
+	return b.PutObjectTaggingWithContext(context.Background(), key, tagSet)
+}
+
+// PutObjectTaggingWithContext is like PutObjectTagging but makes its requests with ctx.
+func (b *Bucket) PutObjectTaggingWithContext(ctx context.Context, key string, tagSet map[string]string) error {
 	if !b.S3.v4sign {
 		return errors.New("SigV4 only")
 	}
@@ -54,13 +61,14 @@ func (b *Bucket) PutObjectTagging(key string, tagSet map[string]string) error {
 		"tagging": {""},
 	}
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
 			method:  "PUT",
 			bucket:  b.Name,
 			path:    key,
 			params:  params,
 			payload: bytes.NewReader(data),
+			context: ctx,
 		}
 		err = b.S3.query(req, nil)
 		if ShouldRetry(err) && attempt.HasNext() {
@@ -77,6 +85,11 @@ func (b *Bucket) PutObjectTagging(key string, tagSet map[string]string) error {
 // code does not handle the ?tagging parameter on the URL properly.
 // Not a big deal for what we use tagging for.
 func (b *Bucket) GetObjectTagging(key string) (tagSet map[string]string, err error) {
+	return b.GetObjectTaggingWithContext(context.Background(), key)
+}
+
+// GetObjectTaggingWithContext is like GetObjectTagging but makes its requests with ctx.
+func (b *Bucket) GetObjectTaggingWithContext(ctx context.Context, key string) (tagSet map[string]string, err error) {
 	if !b.S3.v4sign {
 		return nil, errors.New("SigV4 only")
 	}
@@ -87,11 +100,12 @@ func (b *Bucket) GetObjectTagging(key string) (tagSet map[string]string, err err
 
 	result := &Tagging{}
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			bucket: b.Name,
-			path:   key,
-			params: params,
+			bucket:  b.Name,
+			path:    key,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, result)
 		if !ShouldRetry(err) {
@@ -112,6 +126,11 @@ func (b *Bucket) GetObjectTagging(key string) (tagSet map[string]string, err err
 }
 
 func (b *Bucket) DeleteObjectTagging(key string) (err error) {
+	return b.DeleteObjectTaggingWithContext(context.Background(), key)
+}
+
+// DeleteObjectTaggingWithContext is like DeleteObjectTagging but makes its requests with ctx.
+func (b *Bucket) DeleteObjectTaggingWithContext(ctx context.Context, key string) (err error) {
 	if !b.S3.v4sign {
 		return errors.New("SigV4 only")
 	}
@@ -120,12 +139,13 @@ func (b *Bucket) DeleteObjectTagging(key string) (err error) {
 		"tagging": {""},
 	}
 
-	for attempt := b.S3.AttemptStrategy.Start(); attempt.Next(err); {
+	for attempt := aws.StartContext(ctx, b.S3.AttemptStrategy); attempt.Next(err); {
 		req := &request{
-			method: "DELETE",
-			bucket: b.Name,
-			path:   key,
-			params: params,
+			method:  "DELETE",
+			bucket:  b.Name,
+			path:    key,
+			params:  params,
+			context: ctx,
 		}
 		err = b.S3.query(req, nil)
 		if ShouldRetry(err) && attempt.HasNext() {
@@ -0,0 +1,385 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/hughe/goamz/aws"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Limits of multipart uploads.
+const (
+	MinPartSize    = 5 << 20 // the least size of every part but the last
+	MaxPartSize    = 5 << 30
+	MaxUploadParts = 10000
+)
+
+// DefaultUploadConcurrency is the number of parts an Uploader sends at
+// once if its Concurrency is zero.
+const DefaultUploadConcurrency = 5
+
+// DefaultPartRetries is the number of times an Uploader sends a part
+// again after it failed if its PartRetries is zero.
+const DefaultPartRetries = 3
+
+// An Uploader uploads objects of any size from an io.Reader with
+// multipart uploads, sending several parts at once.
+//
+// The parts are read from the reader into buffers, of which no more than
+// Concurrency+1 are held at once, so the memory an Uploader uses is
+// bounded by that many parts. The part size is chosen so that the object
+// fits in MaxUploadParts parts: when the length of the reader is known
+// (it has a Len method or is an io.Seeker), parts are made large enough
+// to hold it; when it is not, the part size doubles every 1000 parts,
+// which lets parts of 5MB at first hold nearly 5TB, the largest object
+// S3 holds.
+//
+// A part that fails to upload is sent again, after a wait chosen as by
+// aws.DefaultRetryPolicy, up to PartRetries times. If the upload still
+// cannot be completed, it is aborted, unless its progress is saved to a
+// Checkpoint, from which it can be resumed.
+type Uploader struct {
+	Bucket *Bucket
+
+	// PartSize is the size of the parts, at least. S3 rejects parts but
+	// the last smaller than MinPartSize, which is used if PartSize is 0.
+	PartSize int64
+
+	// Concurrency is the number of parts sent at once, or
+	// DefaultUploadConcurrency if 0.
+	Concurrency int
+
+	// PartRetries is the number of times a part that failed to upload
+	// is sent again, or DefaultPartRetries if 0. If negative, parts are
+	// not sent again, beyond the retries of Bucket.S3.AttemptStrategy.
+	PartRetries int
+
+	// Options are the options with which uploads are initiated.
+	Options MultiOptions
+
+	// Checkpoint, if set, is where the progress of an upload is saved,
+	// for it to be resumed by ResumeUpload.
+	Checkpoint CheckpointStore
+}
+
+// NewUploader returns an Uploader to b with the default settings.
+func NewUploader(b *Bucket) *Uploader {
+	return &Uploader{Bucket: b}
+}
+
+// Upload uploads the content of r to key with a new multipart upload,
+// with content type contType and permissions perm.
+//
+// If u.Checkpoint is set, the progress of the upload is saved to it, and
+// the upload is not aborted if it fails, so that it may be resumed with
+// ResumeUpload.
+func (u *Uploader) Upload(key string, r io.Reader, contType string, perm ACL) error {
+	return u.UploadWithContext(context.Background(), key, r, contType, perm)
+}
+
+// UploadWithContext is like Upload but makes its requests with ctx.
+func (u *Uploader) UploadWithContext(ctx context.Context, key string, r io.Reader, contType string, perm ACL) error {
+	m, err := u.Bucket.InitMultiWithOptionsWithContext(ctx, key, contType, perm, u.Options)
+	if err != nil {
+		return err
+	}
+	up := &upload{
+		m:        m,
+		partSize: u.PartSize,
+		total:    readerLen(r),
+		store:    u.Checkpoint,
+	}
+	if up.store != nil {
+		up.checkpoint = &UploadCheckpoint{
+			Bucket:   u.Bucket.Name,
+			Key:      key,
+			UploadId: m.UploadId,
+			PartSize: up.partSize,
+			Size:     up.total,
+		}
+		if err := up.store.SaveCheckpoint(up.checkpoint); err != nil {
+			return u.abort(ctx, m, err)
+		}
+	}
+	err = u.finish(ctx, up, r)
+	if err != nil && up.store == nil {
+		return u.abort(ctx, m, err)
+	}
+	return err
+}
+
+// finish sends the parts of up read from r and completes it.
+func (u *Uploader) finish(ctx context.Context, up *upload, r io.Reader) error {
+	parts, err := u.putParts(ctx, up, r)
+	if err != nil {
+		return err
+	}
+	if err := up.m.CompleteWithContext(ctx, parts); err != nil {
+		return err
+	}
+	if up.store != nil {
+		return up.store.DeleteCheckpoint()
+	}
+	return nil
+}
+
+// abort aborts m, which failed with err, even if ctx is done.
+func (u *Uploader) abort(ctx context.Context, m *Multi, err error) error {
+	if abortErr := m.AbortWithContext(context.WithoutCancel(ctx)); abortErr != nil {
+		return fmt.Errorf("%w (aborting upload %s also failed: %v)", err, m.UploadId, abortErr)
+	}
+	return err
+}
+
+// An upload is a multipart upload in progress.
+type upload struct {
+	m        *Multi
+	partSize int64 // as Uploader.PartSize
+	total    int64 // the length of the content, or -1 if unknown
+
+	// uploaded holds the parts sent before the upload was resumed.
+	uploaded map[int]Part
+
+	mu         sync.Mutex
+	store      CheckpointStore // nil if the upload is not checkpointed
+	checkpoint *UploadCheckpoint
+}
+
+// reusable reports whether part n, of content data with MD5 sum sum, was
+// sent before the upload was resumed, and returns it if so.
+func (up *upload) reusable(n int, data []byte, sum []byte) (Part, bool) {
+	part, ok := up.uploaded[n]
+	if !ok || part.Size != int64(len(data)) {
+		return Part{}, false
+	}
+	md5hex := hex.EncodeToString(sum)
+	if part.ETag == `"`+md5hex+`"` {
+		return part, true
+	}
+	// The ETag of a part is not its MD5 sum with some kinds of
+	// encryption, so rely on the checkpoint.
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	for _, p := range up.checkpoint.Parts {
+		if p.N == n && p.ETag == part.ETag && p.MD5 == md5hex {
+			return part, true
+		}
+	}
+	return Part{}, false
+}
+
+// sent records that part, of MD5 sum sum, was sent in the checkpoint of
+// up, if any.
+func (up *upload) sent(part Part, sum []byte) error {
+	if up.store == nil {
+		return nil
+	}
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	up.checkpoint.setPart(CheckpointPart{N: part.N, ETag: part.ETag, Size: part.Size, MD5: hex.EncodeToString(sum)})
+	return up.store.SaveCheckpoint(up.checkpoint)
+}
+
+// uploadPart is a part read from the reader, waiting to be sent.
+type uploadPart struct {
+	n   int
+	buf []byte
+	sum []byte
+}
+
+// putParts reads the parts of up from r and sends those not sent
+// before. It returns all the parts, ordered by part number.
+func (u *Uploader) putParts(ctx context.Context, up *upload, r io.Reader) ([]Part, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultUploadConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		parts   partSlice
+		failure error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if failure == nil {
+			failure = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+	done := func(part Part, sum []byte) {
+		if err := up.sent(part, sum); err != nil {
+			fail(err)
+			return
+		}
+		mu.Lock()
+		parts = append(parts, part)
+		mu.Unlock()
+	}
+
+	// Buffers go round from free to the reader, to the workers through
+	// pending, and back to free once their part is sent.
+	free := make(chan []byte, concurrency+1)
+	for i := 0; i < cap(free); i++ {
+		free <- nil
+	}
+	pending := make(chan uploadPart)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range pending {
+				part, err := u.putPart(ctx, up.m, p)
+				free <- p.buf
+				if err != nil {
+					fail(err)
+					continue
+				}
+				done(part, p.sum)
+			}
+		}()
+	}
+
+Read:
+	for n := 1; ; n++ {
+		var buf []byte
+		select {
+		case buf = <-free:
+		case <-ctx.Done():
+			break Read
+		}
+		size := partSize(up.partSize, n, up.total)
+		if int64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+		read, err := io.ReadFull(r, buf[:size])
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			fail(err)
+			break
+		}
+		if read == 0 && n > 1 {
+			// An empty object is uploaded as a single empty part.
+			break
+		}
+		if n > MaxUploadParts {
+			fail(fmt.Errorf("s3: %s is too large for %d parts", up.m.Key, MaxUploadParts))
+			break
+		}
+		sum := md5.Sum(buf[:read])
+		if part, ok := up.reusable(n, buf[:read], sum[:]); ok {
+			done(part, sum[:])
+			free <- buf
+		} else {
+			select {
+			case pending <- uploadPart{n, buf[:read], sum[:]}:
+			case <-ctx.Done():
+				break Read
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	if failure == nil && ctx.Err() != nil {
+		// The parent context is done.
+		failure = ctx.Err()
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	sort.Sort(parts)
+	return parts, nil
+}
+
+// putPart sends p to m, again if it fails, up to u.PartRetries times.
+func (u *Uploader) putPart(ctx context.Context, m *Multi, p uploadPart) (Part, error) {
+	retries := u.PartRetries
+	if retries == 0 {
+		retries = DefaultPartRetries
+	}
+	md5b64 := base64.StdEncoding.EncodeToString(p.sum)
+	for try := 0; ; try++ {
+		part, err := m.putPart(ctx, p.n, bytes.NewReader(p.buf), int64(len(p.buf)), md5b64)
+		if err == nil || try >= retries || ctx.Err() != nil || aws.IsNotFound(err) || aws.IsAccessDenied(err) {
+			return part, err
+		}
+		if err := retryWait(ctx, try); err != nil {
+			return part, err
+		}
+	}
+}
+
+// retryWait waits before retry number try (starting at 0) of a part or a
+// range, for as long as aws.DefaultRetryPolicy would, so that those
+// failing because S3 is throttling requests are not sent again at once. It returns
+// early, with ctx's error, if ctx is done first.
+func retryWait(ctx context.Context, try int) error {
+	timer := time.NewTimer(aws.DefaultRetryPolicy.Delay(try, nil))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// partSize returns the size of the nth part of an upload of total bytes,
+// or of unknown length if total is negative, whose parts are size bytes
+// at least, or MinPartSize if size is 0.
+func partSize(size int64, n int, total int64) int64 {
+	if size <= 0 {
+		size = MinPartSize
+	}
+	if total >= 0 {
+		if least := (total + MaxUploadParts - 1) / MaxUploadParts; least > size {
+			size = least
+		}
+		return size
+	}
+	for i := (n - 1) / (MaxUploadParts / 10); i > 0 && size < MaxPartSize; i-- {
+		size *= 2
+	}
+	if size > MaxPartSize {
+		size = MaxPartSize
+	}
+	return size
+}
+
+// readerLen returns the number of bytes left in r, or -1 if it is not
+// known.
+func readerLen(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - offset
+	}
+	return -1
+}
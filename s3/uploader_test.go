@@ -0,0 +1,168 @@
+package s3_test
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/s3"
+	"github.com/hughe/goamz/testutil"
+	. "gopkg.in/check.v1"
+)
+
+// unknownLen hides the length of its reader.
+type unknownLen struct {
+	io.Reader
+}
+
+func (s *S) TestUploaderConcurrent(c *C) {
+	var (
+		mu             sync.Mutex
+		inFlight, most int
+	)
+	testServer.Response(200, nil, InitMultiResultDump)
+	testServer.ResponseFunc(4, func(path string) testutil.Response {
+		mu.Lock()
+		inFlight++
+		if inFlight > most {
+			most = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return testutil.Response{Status: 200, Headers: map[string]string{"ETag": `"etag"`}}
+	})
+	testServer.Response(200, nil, "")
+
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.PartSize = 5
+	u.Concurrency = 3
+	err := u.Upload("multi", unknownLen{strings.NewReader("part1part2part3pa")}, "text/plain", s3.Private)
+	c.Assert(err, IsNil)
+	c.Assert(most, Equals, 3)
+
+	reqs := testServer.WaitRequests(6)
+	c.Assert(reqs[0].Method, Equals, "POST")
+	c.Assert(reqs[0].Form["uploads"], DeepEquals, []string{""})
+
+	// The parts are sent in any order.
+	parts := reqs[1:5]
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Form.Get("partNumber") < parts[j].Form.Get("partNumber")
+	})
+	for i, body := range []string{"part1", "part2", "part3", "pa"} {
+		c.Assert(parts[i].Method, Equals, "PUT")
+		c.Assert(parts[i].Form["partNumber"], DeepEquals, []string{string('1' + rune(i))})
+		c.Assert(readAll(parts[i].Body), Equals, body)
+	}
+
+	req := reqs[5]
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form.Get("uploadId"), Matches, "JNbR_[A-Za-z0-9.]+QQ--")
+	var payload struct {
+		Part []struct {
+			PartNumber int
+			ETag       string
+		}
+	}
+	err = xml.NewDecoder(req.Body).Decode(&payload)
+	c.Assert(err, IsNil)
+	c.Assert(payload.Part, HasLen, 4)
+	for i, part := range payload.Part {
+		c.Assert(part.PartNumber, Equals, i+1)
+	}
+}
+
+func (s *S) TestUploaderRetriesPart(c *C) {
+	s.DisableRetries()
+
+	testServer.Response(200, nil, InitMultiResultDump)
+	testServer.Response(500, nil, InternalErrorDump)
+	testServer.Responses(2, 200, map[string]string{"ETag": `"etag"`}, "")
+	testServer.Response(200, nil, "")
+
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.PartSize = 5
+	u.Concurrency = 1
+	err := u.Upload("multi", strings.NewReader("part1part2"), "text/plain", s3.Private)
+	c.Assert(err, IsNil)
+
+	reqs := testServer.WaitRequests(5)
+	for i, n := range []string{"1", "1", "2"} {
+		c.Assert(reqs[i+1].Method, Equals, "PUT")
+		c.Assert(reqs[i+1].Form.Get("partNumber"), Equals, n)
+	}
+	c.Assert(readAll(reqs[2].Body), Equals, "part1")
+	c.Assert(reqs[4].Method, Equals, "POST")
+}
+
+func (s *S) TestUploaderWaitsBeforeRetryingPart(c *C) {
+	s.DisableRetries()
+	defer func(base, max time.Duration) {
+		aws.DefaultRetryPolicy.BaseDelay, aws.DefaultRetryPolicy.MaxDelay = base, max
+	}(aws.DefaultRetryPolicy.BaseDelay, aws.DefaultRetryPolicy.MaxDelay)
+	aws.DefaultRetryPolicy.BaseDelay = time.Hour
+	aws.DefaultRetryPolicy.MaxDelay = time.Hour
+
+	testServer.Response(200, nil, InitMultiResultDump)
+	testServer.Response(503, nil, SlowDownDump)
+	testServer.Response(204, nil, "")
+
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.PartSize = 5
+	u.Concurrency = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := u.UploadWithContext(ctx, "multi", strings.NewReader("part1part2"), "text/plain", s3.Private)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+	c.Assert(time.Since(start) < 5*time.Second, Equals, true)
+
+	reqs := testServer.WaitRequests(3)
+	c.Assert(reqs[1].Method, Equals, "PUT")
+	c.Assert(reqs[2].Method, Equals, "DELETE")
+}
+
+func (s *S) TestUploaderAborts(c *C) {
+	s.DisableRetries()
+
+	testServer.Response(200, nil, InitMultiResultDump)
+	testServer.Response(500, nil, InternalErrorDump)
+	testServer.Response(204, nil, "")
+
+	u := s3.NewUploader(s.s3.Bucket("sample"))
+	u.PartSize = 5
+	u.Concurrency = 1
+	u.PartRetries = -1
+	err := u.Upload("multi", strings.NewReader("part1part2"), "text/plain", s3.Private)
+	c.Assert(err, NotNil)
+	c.Assert(err.(*s3.Error).Code, Equals, "InternalError")
+
+	reqs := testServer.WaitRequests(3)
+	c.Assert(reqs[1].Method, Equals, "PUT")
+	c.Assert(reqs[2].Method, Equals, "DELETE")
+	c.Assert(reqs[2].URL.Path, Equals, "/sample/multi")
+	c.Assert(reqs[2].Form.Get("uploadId"), Matches, "JNbR_[A-Za-z0-9.]+QQ--")
+}
+
+func (s *S) TestUploaderPartSize(c *C) {
+	u := s3.NewUploader(nil)
+	c.Assert(s3.UploaderPartSize(u, 1, 1<<20), Equals, int64(s3.MinPartSize))
+	c.Assert(s3.UploaderPartSize(u, 1, 100<<30), Equals, int64(100<<30+s3.MaxUploadParts-1)/s3.MaxUploadParts)
+
+	// Of unknown length, 10000 parts hold nearly 5TB.
+	var total int64
+	for n := 1; n <= s3.MaxUploadParts; n++ {
+		total += s3.UploaderPartSize(u, n, -1)
+	}
+	c.Assert(s3.UploaderPartSize(u, 1000, -1), Equals, int64(s3.MinPartSize))
+	c.Assert(s3.UploaderPartSize(u, 1001, -1), Equals, int64(2*s3.MinPartSize))
+	c.Assert(total, Equals, int64(1023*1000*s3.MinPartSize))
+}
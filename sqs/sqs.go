@@ -10,6 +10,7 @@
 package sqs
 
 import (
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -34,6 +35,11 @@ const debug = false
 type SQS struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
 	private byte // Reserve the right of using private data.
 }
 
@@ -73,7 +79,7 @@ func NewFrom(accessKey, secretKey, region string) (*SQS, error) {
 
 // NewFrom Create A new SQS Client from an exisisting aws.Auth
 func New(auth aws.Auth, region aws.Region) *SQS {
-	return &SQS{auth, region, 0}
+	return &SQS{auth, region.WithEndpoints(), nil, 0}
 }
 
 // Queue Reference to a Queue
@@ -179,6 +185,17 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 func (err *Error) String() string {
 	return err.Message
 }
@@ -191,19 +208,34 @@ type xmlErrors struct {
 
 // CreateQueue create a queue with a specific name
 func (s *SQS) CreateQueue(queueName string) (*Queue, error) {
-	return s.CreateQueueWithTimeout(queueName, 30)
+	return s.CreateQueueWithContext(context.Background(), queueName)
+}
+
+// CreateQueueWithContext is like CreateQueue but makes its requests with ctx.
+func (s *SQS) CreateQueueWithContext(ctx context.Context, queueName string) (*Queue, error) {
+	return s.CreateQueueWithTimeoutWithContext(ctx, queueName, 30)
 }
 
 // CreateQueue create a queue with a specific name and a timeout
 func (s *SQS) CreateQueueWithTimeout(queueName string, timeout int) (*Queue, error) {
+	return s.CreateQueueWithTimeoutWithContext(context.Background(), queueName, timeout)
+}
+
+// CreateQueueWithTimeoutWithContext is like CreateQueueWithTimeout but makes its requests with ctx.
+func (s *SQS) CreateQueueWithTimeoutWithContext(ctx context.Context, queueName string, timeout int) (*Queue, error) {
 	params := map[string]string{
 		"VisibilityTimeout": strconv.Itoa(timeout),
 	}
-	return s.CreateQueueWithAttributes(queueName, params)
+	return s.CreateQueueWithAttributesWithContext(ctx, queueName, params)
 }
 
 func (s *SQS) CreateQueueWithAttributes(queueName string, attrs map[string]string) (q *Queue, err error) {
-	resp, err := s.newQueue(queueName, attrs)
+	return s.CreateQueueWithAttributesWithContext(context.Background(), queueName, attrs)
+}
+
+// CreateQueueWithAttributesWithContext is like CreateQueueWithAttributes but makes its requests with ctx.
+func (s *SQS) CreateQueueWithAttributesWithContext(ctx context.Context, queueName string, attrs map[string]string) (q *Queue, err error) {
+	resp, err := s.newQueue(ctx, queueName, attrs)
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +245,13 @@ func (s *SQS) CreateQueueWithAttributes(queueName string, attrs map[string]strin
 
 // GetQueue get a reference to the given quename
 func (s *SQS) GetQueue(queueName string) (*Queue, error) {
+	return s.GetQueueWithContext(context.Background(), queueName)
+}
+
+// GetQueueWithContext is like GetQueue but makes its requests with ctx.
+func (s *SQS) GetQueueWithContext(ctx context.Context, queueName string) (*Queue, error) {
 	var q *Queue
-	resp, err := s.getQueueUrl(queueName)
+	resp, err := s.getQueueUrl(ctx, queueName)
 	if err != nil {
 		return q, err
 	}
@@ -227,15 +264,15 @@ func (s *SQS) QueueFromArn(queueUrl string) (q *Queue) {
 	return
 }
 
-func (s *SQS) getQueueUrl(queueName string) (resp *GetQueueUrlResponse, err error) {
+func (s *SQS) getQueueUrl(ctx context.Context, queueName string) (resp *GetQueueUrlResponse, err error) {
 	resp = &GetQueueUrlResponse{}
 	params := makeParams("GetQueueUrl")
 	params["QueueName"] = queueName
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return resp, err
 }
 
-func (s *SQS) newQueue(queueName string, attrs map[string]string) (resp *CreateQueueResponse, err error) {
+func (s *SQS) newQueue(ctx context.Context, queueName string, attrs map[string]string) (resp *CreateQueueResponse, err error) {
 	resp = &CreateQueueResponse{}
 	params := makeParams("CreateQueue")
 	params["QueueName"] = queueName
@@ -249,11 +286,16 @@ func (s *SQS) newQueue(queueName string, attrs map[string]string) (resp *CreateQ
 		i++
 	}
 
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return
 }
 
 func (s *SQS) ListQueues(QueueNamePrefix string) (resp *ListQueuesResponse, err error) {
+	return s.ListQueuesWithContext(context.Background(), QueueNamePrefix)
+}
+
+// ListQueuesWithContext is like ListQueues but makes its requests with ctx.
+func (s *SQS) ListQueuesWithContext(ctx context.Context, QueueNamePrefix string) (resp *ListQueuesResponse, err error) {
 	resp = &ListQueuesResponse{}
 	params := makeParams("ListQueues")
 
@@ -261,48 +303,73 @@ func (s *SQS) ListQueues(QueueNamePrefix string) (resp *ListQueuesResponse, err
 		params["QueueNamePrefix"] = QueueNamePrefix
 	}
 
-	err = s.query("", params, resp)
+	err = s.query(ctx, "", params, resp)
 	return
 }
 
 func (q *Queue) Delete() (resp *DeleteQueueResponse, err error) {
+	return q.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext is like Delete but makes its requests with ctx.
+func (q *Queue) DeleteWithContext(ctx context.Context) (resp *DeleteQueueResponse, err error) {
 	resp = &DeleteQueueResponse{}
 	params := makeParams("DeleteQueue")
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) Purge() (resp *PurgeQueueResponse, err error) {
+	return q.PurgeWithContext(context.Background())
+}
+
+// PurgeWithContext is like Purge but makes its requests with ctx.
+func (q *Queue) PurgeWithContext(ctx context.Context) (resp *PurgeQueueResponse, err error) {
 	resp = &PurgeQueueResponse{}
 	params := makeParams("PurgeQueue")
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) SendMessageWithDelay(MessageBody string, DelaySeconds int64) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithDelayWithContext(context.Background(), MessageBody, DelaySeconds)
+}
+
+// SendMessageWithDelayWithContext is like SendMessageWithDelay but makes its requests with ctx.
+func (q *Queue) SendMessageWithDelayWithContext(ctx context.Context, MessageBody string, DelaySeconds int64) (resp *SendMessageResponse, err error) {
 	resp = &SendMessageResponse{}
 	params := makeParams("SendMessage")
 
 	params["MessageBody"] = MessageBody
 	params["DelaySeconds"] = strconv.Itoa(int(DelaySeconds))
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) SendMessage(MessageBody string) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithContext(context.Background(), MessageBody)
+}
+
+// SendMessageWithContext is like SendMessage but makes its requests with ctx.
+func (q *Queue) SendMessageWithContext(ctx context.Context, MessageBody string) (resp *SendMessageResponse, err error) {
 	resp = &SendMessageResponse{}
 	params := makeParams("SendMessage")
 
 	params["MessageBody"] = MessageBody
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) SendMessageWithAttributes(MessageBody string, attrs map[string]string) (resp *SendMessageResponse, err error) {
+	return q.SendMessageWithAttributesWithContext(context.Background(), MessageBody, attrs)
+}
+
+// SendMessageWithAttributesWithContext is like SendMessageWithAttributes but makes its requests with ctx.
+func (q *Queue) SendMessageWithAttributesWithContext(ctx context.Context, MessageBody string, attrs map[string]string) (resp *SendMessageResponse, err error) {
 	resp = &SendMessageResponse{}
 	params := makeParams("SendMessage")
 
@@ -319,28 +386,43 @@ func (q *Queue) SendMessageWithAttributes(MessageBody string, attrs map[string]s
 		i++
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 // ReceiveMessageWithVisibilityTimeout
 func (q *Queue) ReceiveMessageWithVisibilityTimeout(MaxNumberOfMessages, VisibilityTimeoutSec int) (*ReceiveMessageResponse, error) {
+	return q.ReceiveMessageWithVisibilityTimeoutWithContext(context.Background(), MaxNumberOfMessages, VisibilityTimeoutSec)
+}
+
+// ReceiveMessageWithVisibilityTimeoutWithContext is like ReceiveMessageWithVisibilityTimeout but makes its requests with ctx.
+func (q *Queue) ReceiveMessageWithVisibilityTimeoutWithContext(ctx context.Context, MaxNumberOfMessages, VisibilityTimeoutSec int) (*ReceiveMessageResponse, error) {
 	params := map[string]string{
 		"MaxNumberOfMessages": strconv.Itoa(MaxNumberOfMessages),
 		"VisibilityTimeout":   strconv.Itoa(VisibilityTimeoutSec),
 	}
-	return q.ReceiveMessageWithParameters(params)
+	return q.ReceiveMessageWithParametersWithContext(ctx, params)
 }
 
 // ReceiveMessage
 func (q *Queue) ReceiveMessage(MaxNumberOfMessages int) (*ReceiveMessageResponse, error) {
+	return q.ReceiveMessageWithContext(context.Background(), MaxNumberOfMessages)
+}
+
+// ReceiveMessageWithContext is like ReceiveMessage but makes its requests with ctx.
+func (q *Queue) ReceiveMessageWithContext(ctx context.Context, MaxNumberOfMessages int) (*ReceiveMessageResponse, error) {
 	params := map[string]string{
 		"MaxNumberOfMessages": strconv.Itoa(MaxNumberOfMessages),
 	}
-	return q.ReceiveMessageWithParameters(params)
+	return q.ReceiveMessageWithParametersWithContext(ctx, params)
 }
 
 func (q *Queue) ReceiveMessageWithParameters(p map[string]string) (resp *ReceiveMessageResponse, err error) {
+	return q.ReceiveMessageWithParametersWithContext(context.Background(), p)
+}
+
+// ReceiveMessageWithParametersWithContext is like ReceiveMessageWithParameters but makes its requests with ctx.
+func (q *Queue) ReceiveMessageWithParametersWithContext(ctx context.Context, p map[string]string) (resp *ReceiveMessageResponse, err error) {
 	resp = &ReceiveMessageResponse{}
 	params := makeParams("ReceiveMessage")
 	params["AttributeName"] = "All"
@@ -350,39 +432,59 @@ func (q *Queue) ReceiveMessageWithParameters(p map[string]string) (resp *Receive
 		params[k] = v
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) ChangeMessageVisibility(M *Message, VisibilityTimeout int) (resp *ChangeMessageVisibilityResponse, err error) {
+	return q.ChangeMessageVisibilityWithContext(context.Background(), M, VisibilityTimeout)
+}
+
+// ChangeMessageVisibilityWithContext is like ChangeMessageVisibility but makes its requests with ctx.
+func (q *Queue) ChangeMessageVisibilityWithContext(ctx context.Context, M *Message, VisibilityTimeout int) (resp *ChangeMessageVisibilityResponse, err error) {
 	resp = &ChangeMessageVisibilityResponse{}
 	params := makeParams("ChangeMessageVisibility")
 	params["VisibilityTimeout"] = strconv.Itoa(VisibilityTimeout)
 	params["ReceiptHandle"] = M.ReceiptHandle
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) GetQueueAttributes(A string) (resp *GetQueueAttributesResponse, err error) {
+	return q.GetQueueAttributesWithContext(context.Background(), A)
+}
+
+// GetQueueAttributesWithContext is like GetQueueAttributes but makes its requests with ctx.
+func (q *Queue) GetQueueAttributesWithContext(ctx context.Context, A string) (resp *GetQueueAttributesResponse, err error) {
 	resp = &GetQueueAttributesResponse{}
 	params := makeParams("GetQueueAttributes")
 	params["AttributeName"] = A
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 func (q *Queue) DeleteMessage(M *Message) (resp *DeleteMessageResponse, err error) {
-	return q.DeleteMessageUsingReceiptHandle(M.ReceiptHandle)
+	return q.DeleteMessageWithContext(context.Background(), M)
+}
+
+// DeleteMessageWithContext is like DeleteMessage but makes its requests with ctx.
+func (q *Queue) DeleteMessageWithContext(ctx context.Context, M *Message) (resp *DeleteMessageResponse, err error) {
+	return q.DeleteMessageUsingReceiptHandleWithContext(ctx, M.ReceiptHandle)
 }
 
 func (q *Queue) DeleteMessageUsingReceiptHandle(receiptHandle string) (resp *DeleteMessageResponse, err error) {
+	return q.DeleteMessageUsingReceiptHandleWithContext(context.Background(), receiptHandle)
+}
+
+// DeleteMessageUsingReceiptHandleWithContext is like DeleteMessageUsingReceiptHandle but makes its requests with ctx.
+func (q *Queue) DeleteMessageUsingReceiptHandleWithContext(ctx context.Context, receiptHandle string) (resp *DeleteMessageResponse, err error) {
 	resp = &DeleteMessageResponse{}
 	params := makeParams("DeleteMessage")
 	params["ReceiptHandle"] = receiptHandle
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -400,6 +502,11 @@ type SendMessageBatchResponse struct {
 /* SendMessageBatch
  */
 func (q *Queue) SendMessageBatch(msgList []Message) (resp *SendMessageBatchResponse, err error) {
+	return q.SendMessageBatchWithContext(context.Background(), msgList)
+}
+
+// SendMessageBatchWithContext is like SendMessageBatch but makes its requests with ctx.
+func (q *Queue) SendMessageBatchWithContext(ctx context.Context, msgList []Message) (resp *SendMessageBatchResponse, err error) {
 	resp = &SendMessageBatchResponse{}
 	params := makeParams("SendMessageBatch")
 
@@ -409,13 +516,18 @@ func (q *Queue) SendMessageBatch(msgList []Message) (resp *SendMessageBatchRespo
 		params[fmt.Sprintf("SendMessageBatchRequestEntry.%d.MessageBody", count)] = msg.Body
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
 /* SendMessageBatchString
  */
 func (q *Queue) SendMessageBatchString(msgList []string) (resp *SendMessageBatchResponse, err error) {
+	return q.SendMessageBatchStringWithContext(context.Background(), msgList)
+}
+
+// SendMessageBatchStringWithContext is like SendMessageBatchString but makes its requests with ctx.
+func (q *Queue) SendMessageBatchStringWithContext(ctx context.Context, msgList []string) (resp *SendMessageBatchResponse, err error) {
 	resp = &SendMessageBatchResponse{}
 	params := makeParams("SendMessageBatch")
 
@@ -425,7 +537,7 @@ func (q *Queue) SendMessageBatchString(msgList []string) (resp *SendMessageBatch
 		params[fmt.Sprintf("SendMessageBatchRequestEntry.%d.MessageBody", count)] = msg
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 	return
 }
 
@@ -441,6 +553,11 @@ type DeleteMessageBatchResponse struct {
 
 /* DeleteMessageBatch */
 func (q *Queue) DeleteMessageBatch(msgList []Message) (resp *DeleteMessageBatchResponse, err error) {
+	return q.DeleteMessageBatchWithContext(context.Background(), msgList)
+}
+
+// DeleteMessageBatchWithContext is like DeleteMessageBatch but makes its requests with ctx.
+func (q *Queue) DeleteMessageBatchWithContext(ctx context.Context, msgList []Message) (resp *DeleteMessageBatchResponse, err error) {
 	resp = &DeleteMessageBatchResponse{}
 	params := makeParams("DeleteMessageBatch")
 
@@ -453,7 +570,7 @@ func (q *Queue) DeleteMessageBatch(msgList []Message) (resp *DeleteMessageBatchR
 		lutMsg[string(msgList[idx].MessageId)] = msgList[idx]
 	}
 
-	err = q.SQS.query(q.Url, params, resp)
+	err = q.SQS.query(ctx, q.Url, params, resp)
 
 	messageWithErrors := make([]Message, 0, len(msgList))
 
@@ -473,9 +590,8 @@ func (q *Queue) DeleteMessageBatch(msgList []Message) (resp *DeleteMessageBatchR
 	return
 }
 
-func (s *SQS) query(queueUrl string, params map[string]string, resp interface{}) (err error) {
+func (s *SQS) query(ctx context.Context, queueUrl string, params map[string]string, resp interface{}) (err error) {
 	params["Version"] = API_VERSION
-	params["Timestamp"] = time.Now().In(time.UTC).Format(time.RFC3339)
 	var url_ *url.URL
 	var path string
 
@@ -498,52 +614,76 @@ func (s *SQS) query(queueUrl string, params map[string]string, resp interface{})
 		return err
 	}
 
-	if s.Auth.Token() != "" {
-		params["SecurityToken"] = s.Auth.Token()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "sqs",
+		Region:      s.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: s.RateLimiter,
 	}
-
-	var r *http.Response
-	if s.Region.Name == "cn-north-1" {
-		var sarray []string
-		for k, v := range params {
-			sarray = append(sarray, aws.Encode(k)+"="+aws.Encode(v))
-		}
-
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", url_, strings.Join(sarray, "&")), nil)
-		if err != nil {
+	handlers := aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("GET", url_.String(), nil)
 			return err
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("DUMP:\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			err := xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+			io.Copy(ioutil.Discard, r.HTTPResponse.Body)
+			return err
+		},
+	}
+	if s.Region.Name == "cn-north-1" {
+		v4 := aws.NewV4Signer(s.Auth, "sqs", s.Region).SignHandler()
+		handlers.Sign = func(r *aws.Request) error {
+			signed := make(map[string]string, len(params)+2)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = aws.Now().In(time.UTC).Format(time.RFC3339)
+			auth := s.Auth.Current()
+			if token := auth.Token(); token != "" {
+				signed["SecurityToken"] = token
+			}
+			var sarray []string
+			for k, v := range signed {
+				sarray = append(sarray, aws.Encode(k)+"="+aws.Encode(v))
+			}
+			r.HTTPRequest.URL.RawQuery = strings.Join(sarray, "&")
+			return v4(r)
 		}
-		signer := aws.NewV4Signer(s.Auth, "sqs", s.Region)
-		signer.Sign(req)
-		client := http.Client{}
-		r, err = client.Do(req)
 	} else {
-		sign(s.Auth, "GET", path, params, url_.Host)
-		url_.RawQuery = multimap(params).Encode()
-		r, err = http.Get(url_.String())
+		handlers.Sign = func(r *aws.Request) error {
+			auth := s.Auth.Current()
+			signed := make(map[string]string, len(params)+4)
+			for k, v := range params {
+				signed[k] = v
+			}
+			signed["Timestamp"] = aws.Now().In(time.UTC).Format(time.RFC3339)
+			if token := auth.Token(); token != "" {
+				signed["SecurityToken"] = token
+			}
+			sign(auth, "GET", path, signed, url_.Host)
+			r.HTTPRequest.URL.RawQuery = multimap(signed).Encode()
+			return nil
+		}
 	}
 
 	if debug {
 		log.Printf("GET ", url_.String())
 	}
-
-	if err != nil {
-		return err
-	}
-
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("DUMP:\n", string(dump))
-	}
-
-	if r.StatusCode != 200 {
-		return buildError(r)
+	err = aws.DefaultPipeline.Run(r, handlers)
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
-	io.Copy(ioutil.Discard, r.Body)
-
 	return err
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"hash"
+	"time"
 
 	"github.com/hughe/goamz/aws"
 	. "gopkg.in/check.v1"
@@ -354,6 +355,21 @@ func (s *S) TestReceiveMessage(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *S) TestReceiveMessageLongPoll(c *C) {
+	// Long polls are held by SQS for up to WaitTimeSeconds, which may be
+	// longer than the attempt timeout of aws.RetryingClient.
+	testServer.PrepareDelayedResponse(6*time.Second, 200, nil, TestReceiveMessageXmlOK)
+
+	q := &Queue{s.sqs, testServer.URL + "/123456789012/testQueue/"}
+	resp, err := q.ReceiveMessageWithParameters(map[string]string{"WaitTimeSeconds": "6"})
+	req := testServer.WaitRequest()
+
+	c.Assert(err, IsNil)
+	c.Assert(req.Form.Get("WaitTimeSeconds"), Equals, "6")
+	c.Assert(resp.Messages, HasLen, 1)
+	c.Assert(resp.Messages[0].Body, Equals, "This is a test message")
+}
+
 func (s *S) TestChangeMessageVisibility(c *C) {
 	testServer.PrepareResponse(200, nil, TestReceiveMessageXmlOK)
 
@@ -59,6 +59,7 @@ type testResponse struct {
 	Status  int
 	Headers map[string]string
 	Body    string
+	Delay   time.Duration
 }
 
 func NewTestHTTPServer(url string, timeout time.Duration) *TestHTTPServer {
@@ -115,8 +116,9 @@ func (s *TestHTTPServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	case resp = <-s.response:
 	case <-time.After(s.Timeout):
 		fmt.Fprintf(os.Stderr, "ERROR: Timeout waiting for test to provide response\n")
-		resp = &testResponse{500, nil, ""}
+		resp = &testResponse{500, nil, "", 0}
 	}
+	time.Sleep(resp.Delay)
 	if resp.Headers != nil {
 		h := w.Header()
 		for k, v := range resp.Headers {
@@ -141,5 +143,11 @@ func (s *TestHTTPServer) WaitRequest() *http.Request {
 }
 
 func (s *TestHTTPServer) PrepareResponse(status int, headers map[string]string, body string) {
-	s.response <- &testResponse{status, headers, body}
+	s.response <- &testResponse{status, headers, body, 0}
+}
+
+// PrepareDelayedResponse is like PrepareResponse, but the response is
+// sent only after delay, as SQS does with long polls.
+func (s *TestHTTPServer) PrepareDelayedResponse(delay time.Duration, status int, headers map[string]string, body string) {
+	s.response <- &testResponse{status, headers, body, delay}
 }
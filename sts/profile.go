@@ -0,0 +1,109 @@
+package sts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+)
+
+// MFATokenProvider is called to obtain the current MFA code whenever a
+// profile with an mfa_serial setting assumes its role. If it is nil, such
+// profiles cannot be used. StdinTokenProvider may be assigned to it in
+// interactive programs.
+var MFATokenProvider func() (string, error)
+
+// StdinTokenProvider prompts on stderr for an MFA code and reads it from
+// stdin.
+func StdinTokenProvider() (string, error) {
+	fmt.Fprint(os.Stderr, "Assume Role MFA token code: ")
+	code, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(code), nil
+}
+
+// ProfileAuth resolves the named profile of the shared credentials and
+// config files into an aws.Auth and the profile's default region. An empty
+// name selects AWS_PROFILE or else "default".
+//
+// Profiles with a role_arn are resolved by following their source_profile
+// (or credential_source) settings and assuming each role in turn, or by
+// assuming the role with the token in their web_identity_token_file; the
+// returned Auth renews the role credentials before they expire. Roles are
+// assumed with the regional STS endpoint of the profile's region, or in
+// us-east-1 if the profile does not set a region, in which case the zero
+// Region is returned.
+func ProfileAuth(name string) (aws.Auth, aws.Region, error) {
+	config, err := aws.LoadSharedConfig()
+	if err != nil {
+		return aws.Auth{}, aws.Region{}, err
+	}
+	region, err := config.Region(name)
+	if err != nil {
+		return aws.Auth{}, aws.Region{}, err
+	}
+	return SharedConfigAuth(config, name, profileSTSRegion(region))
+}
+
+// profileSTSRegion returns region with its regional STS endpoint, or
+// aws.USEast if region has no name.
+func profileSTSRegion(region aws.Region) aws.Region {
+	if region.Name == "" {
+		return aws.USEast
+	}
+	if endpoint, err := aws.DefaultEndpointResolver.ResolveEndpoint("sts", region.Name); err == nil {
+		region.STSEndpoint = endpoint.URL
+	}
+	return region
+}
+
+// SharedConfigAuth is like ProfileAuth but resolves the profile from
+// config, calling STS in stsRegion to assume roles.
+func SharedConfigAuth(config *aws.SharedConfig, name string, stsRegion aws.Region) (auth aws.Auth, region aws.Region, err error) {
+	region, err = config.Region(name)
+	if err != nil {
+		return
+	}
+	chain, err := config.ProfileChain(name)
+	if err != nil {
+		return
+	}
+	var source aws.CredentialsProvider
+	if base := chain[0]; base.WebIdentityTokenFile != "" {
+		source = NewWebIdentityProvider(New(aws.Auth{}, stsRegion), base.RoleArn, base.RoleSessionName, base.WebIdentityTokenFile)
+	} else {
+		source, err = config.SourceProvider(base)
+		if err != nil {
+			return
+		}
+	}
+	auth, err = aws.AuthFromProvider(source)
+	if err != nil {
+		return
+	}
+	for _, profile := range chain[1:] {
+		auth, err = aws.AuthFromProvider(profileRoleProvider(auth, stsRegion, profile))
+		if err != nil {
+			return auth, region, fmt.Errorf("profile %q: %v", profile.Name, err)
+		}
+	}
+	return
+}
+
+// profileRoleProvider returns a provider that assumes the role of profile
+// using the credentials of auth.
+func profileRoleProvider(auth aws.Auth, stsRegion aws.Region, profile *aws.Profile) *AssumeRoleProvider {
+	p := NewAssumeRoleProvider(New(auth, stsRegion), profile.RoleArn, profile.RoleSessionName)
+	p.ExternalId = profile.ExternalId
+	p.Duration = time.Duration(profile.DurationSeconds) * time.Second
+	if profile.MFASerial != "" {
+		p.SerialNumber = profile.MFASerial
+		p.TokenProvider = MFATokenProvider
+	}
+	return p
+}
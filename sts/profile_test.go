@@ -0,0 +1,159 @@
+package sts_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/sts"
+)
+
+var profileCredentials = `
+[default]
+aws_access_key_id = access
+aws_secret_access_key = secret
+`
+
+var profileConfig = `
+[profile admin]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = default
+external_id = 123ABC
+duration_seconds = 900
+
+[profile crossaccount]
+role_arn = arn:aws:iam::210987654321:role/deploy
+source_profile = admin
+role_session_name = deploy
+region = eu-west-1
+
+[profile mfa]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = default
+mfa_serial = arn:aws:iam::123456789012:mfa/bob
+`
+
+func loadProfileConfig(c *C) *aws.SharedConfig {
+	d := c.MkDir()
+	credentials := filepath.Join(d, "credentials")
+	config := filepath.Join(d, "config")
+	c.Assert(ioutil.WriteFile(credentials, []byte(profileCredentials), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(config, []byte(profileConfig), 0644), IsNil)
+	shared, err := aws.LoadSharedConfigFiles(credentials, config)
+	c.Assert(err, IsNil)
+	return shared
+}
+
+func (s *S) TestSharedConfigAuthChain(c *C) {
+	config := loadProfileConfig(c)
+	testServer.Response(200, nil, assumeRoleResponse("ADMINKEY", time.Now().Add(time.Hour)))
+	testServer.Response(200, nil, assumeRoleResponse("DEPLOYKEY", time.Now().Add(time.Hour)))
+
+	auth, region, err := sts.SharedConfigAuth(config, "crossaccount", aws.Region{STSEndpoint: testServer.URL})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "DEPLOYKEY")
+	c.Assert(region.Name, Equals, "eu-west-1")
+
+	reqs := testServer.WaitRequests(2)
+	// The admin role is assumed with the default profile's keys...
+	c.Assert(reqs[0].Header.Get("Authorization"), Matches, "AWS4-HMAC-SHA256 Credential=access/.*")
+	c.Assert(reqs[0].PostForm.Get("RoleArn"), Equals, "arn:aws:iam::123456789012:role/admin")
+	c.Assert(reqs[0].PostForm.Get("ExternalId"), Equals, "123ABC")
+	c.Assert(reqs[0].PostForm.Get("DurationSeconds"), Equals, "900")
+	// ... and the deploy role with the admin role's credentials.
+	c.Assert(reqs[1].Header.Get("Authorization"), Matches, "AWS4-HMAC-SHA256 Credential=ADMINKEY/.*")
+	c.Assert(reqs[1].Header.Get("X-Amz-Security-Token"), Equals, "TOKEN")
+	c.Assert(reqs[1].PostForm.Get("RoleArn"), Equals, "arn:aws:iam::210987654321:role/deploy")
+	c.Assert(reqs[1].PostForm.Get("RoleSessionName"), Equals, "deploy")
+}
+
+func (s *S) TestProfileAuthRegion(c *C) {
+	home := c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(home, ".aws"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(home, ".aws", "credentials"), []byte(profileCredentials), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(home, ".aws", "config"), []byte(profileConfig), 0644), IsNil)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+	defer func(r aws.EndpointResolver) { aws.DefaultEndpointResolver = r }(aws.DefaultEndpointResolver)
+	aws.DefaultEndpointResolver = &aws.Resolver{Overrides: map[string]aws.EndpointResolver{
+		"sts": aws.StaticEndpoint(testServer.URL),
+	}}
+
+	// STS is called in the region of the profile...
+	testServer.Response(200, nil, assumeRoleResponse("ADMINKEY", time.Now().Add(time.Hour)))
+	testServer.Response(200, nil, assumeRoleResponse("DEPLOYKEY", time.Now().Add(time.Hour)))
+	_, region, err := sts.ProfileAuth("crossaccount")
+	c.Assert(err, IsNil)
+	c.Assert(region.Name, Equals, "eu-west-1")
+	for _, req := range testServer.WaitRequests(2) {
+		c.Assert(req.Header.Get("Authorization"), Matches, "AWS4-HMAC-SHA256 Credential=[^/]+/[0-9]+/eu-west-1/sts/aws4_request,.*")
+	}
+
+	// ... or in us-east-1 if it has none.
+	testServer.Response(200, nil, assumeRoleResponse("ADMINKEY", time.Now().Add(time.Hour)))
+	_, region, err = sts.ProfileAuth("admin")
+	c.Assert(err, IsNil)
+	c.Assert(region, DeepEquals, aws.Region{})
+	c.Assert(testServer.WaitRequest().Header.Get("Authorization"), Matches, "AWS4-HMAC-SHA256 Credential=[^/]+/[0-9]+/us-east-1/sts/aws4_request,.*")
+}
+
+func (s *S) TestSharedConfigAuthStatic(c *C) {
+	config := loadProfileConfig(c)
+	auth, region, err := sts.SharedConfigAuth(config, "default", aws.Region{STSEndpoint: testServer.URL})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "access")
+	c.Assert(auth.SecretKey, Equals, "secret")
+	c.Assert(region, DeepEquals, aws.Region{})
+}
+
+func (s *S) TestSharedConfigAuthMFA(c *C) {
+	config := loadProfileConfig(c)
+	_, _, err := sts.SharedConfigAuth(config, "mfa", aws.Region{STSEndpoint: testServer.URL})
+	c.Assert(err, ErrorMatches, `profile "mfa": sts: AssumeRoleProvider with a SerialNumber requires a TokenProvider`)
+
+	sts.MFATokenProvider = func() (string, error) { return "123456", nil }
+	defer func() { sts.MFATokenProvider = nil }()
+	testServer.Response(200, nil, assumeRoleResponse("ADMINKEY", time.Now().Add(time.Hour)))
+	auth, _, err := sts.SharedConfigAuth(config, "mfa", aws.Region{STSEndpoint: testServer.URL})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "ADMINKEY")
+	values := testServer.WaitRequest().PostForm
+	c.Assert(values.Get("SerialNumber"), Equals, "arn:aws:iam::123456789012:mfa/bob")
+	c.Assert(values.Get("TokenCode"), Equals, "123456")
+}
+
+func (s *S) TestSharedConfigAuthWebIdentity(c *C) {
+	d := c.MkDir()
+	tokenFile := filepath.Join(d, "token")
+	c.Assert(ioutil.WriteFile(tokenFile, []byte("oidctoken"), 0600), IsNil)
+	config := filepath.Join(d, "config")
+	c.Assert(ioutil.WriteFile(config, []byte(`
+[profile web]
+role_arn = arn:aws:iam::123456789012:role/web
+web_identity_token_file = `+tokenFile+`
+role_session_name = app
+
+[profile deploy]
+role_arn = arn:aws:iam::210987654321:role/deploy
+source_profile = web
+`), 0644), IsNil)
+	shared, err := aws.LoadSharedConfigFiles(filepath.Join(d, "credentials"), config)
+	c.Assert(err, IsNil)
+
+	testServer.Response(200, nil, webIdentityResponse("WEBKEY", time.Now().Add(time.Hour)))
+	testServer.Response(200, nil, assumeRoleResponse("DEPLOYKEY", time.Now().Add(time.Hour)))
+	auth, _, err := sts.SharedConfigAuth(shared, "deploy", aws.Region{STSEndpoint: testServer.URL})
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "DEPLOYKEY")
+
+	reqs := testServer.WaitRequests(2)
+	c.Assert(reqs[0].PostForm.Get("Action"), Equals, "AssumeRoleWithWebIdentity")
+	c.Assert(reqs[0].PostForm.Get("WebIdentityToken"), Equals, "oidctoken")
+	c.Assert(reqs[0].PostForm.Get("RoleSessionName"), Equals, "app")
+	c.Assert(reqs[1].PostForm.Get("Action"), Equals, "AssumeRole")
+	c.Assert(reqs[1].Header.Get("Authorization"), Matches, "AWS4-HMAC-SHA256 Credential=WEBKEY/.*")
+}
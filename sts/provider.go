@@ -0,0 +1,173 @@
+package sts
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hughe/goamz/aws"
+)
+
+// DefaultAssumeRoleDuration is the lifetime requested for assumed role
+// credentials when AssumeRoleProvider.Duration is zero.
+const DefaultAssumeRoleDuration = time.Hour
+
+// AssumeRoleProvider is an aws.CredentialsProvider that returns the
+// temporary credentials of an assumed role.
+//
+// Each call to Retrieve assumes the role again, so the provider is normally
+// used through aws.AuthFromProvider (or AssumeRoleAuth), which caches the
+// credentials and re-assumes the role shortly before they expire.
+type AssumeRoleProvider struct {
+	// Client is used to call AssumeRole; its Auth holds the source
+	// credentials.
+	Client *STS
+
+	RoleArn         string
+	RoleSessionName string // defaults to a name derived from the current time
+	ExternalId      string
+	Policy          string
+
+	// Duration is the requested lifetime of the credentials.
+	Duration time.Duration
+
+	// SerialNumber is the identification number of the MFA device
+	// required by the role's trust policy, if any. TokenProvider is then
+	// called on every renewal to obtain the current MFA code.
+	SerialNumber  string
+	TokenProvider func() (string, error)
+}
+
+// NewAssumeRoleProvider returns an AssumeRoleProvider that uses client to
+// assume roleArn.
+func NewAssumeRoleProvider(client *STS, roleArn, sessionName string) *AssumeRoleProvider {
+	return &AssumeRoleProvider{
+		Client:          client,
+		RoleArn:         roleArn,
+		RoleSessionName: sessionName,
+	}
+}
+
+// AssumeRoleAuth returns an aws.Auth holding the credentials of the role
+// assumed by p. The Auth re-assumes the role before the credentials
+// expire, and can be passed to any service client.
+func AssumeRoleAuth(p *AssumeRoleProvider) (aws.Auth, error) {
+	return aws.AuthFromProvider(p)
+}
+
+// Retrieve assumes the role and returns its temporary credentials.
+func (p *AssumeRoleProvider) Retrieve() (creds aws.Credentials, err error) {
+	if p.RoleArn == "" {
+		err = errors.New("sts: AssumeRoleProvider requires a RoleArn")
+		return
+	}
+	options := &AssumeRoleParams{
+		RoleArn:         p.RoleArn,
+		RoleSessionName: p.RoleSessionName,
+		ExternalId:      p.ExternalId,
+		Policy:          p.Policy,
+		SerialNumber:    p.SerialNumber,
+	}
+	if options.RoleSessionName == "" {
+		options.RoleSessionName = "goamz-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	duration := p.Duration
+	if duration == 0 {
+		duration = DefaultAssumeRoleDuration
+	}
+	options.DurationSeconds = int(duration / time.Second)
+	if p.SerialNumber != "" {
+		if p.TokenProvider == nil {
+			err = errors.New("sts: AssumeRoleProvider with a SerialNumber requires a TokenProvider")
+			return
+		}
+		options.TokenCode, err = p.TokenProvider()
+		if err != nil {
+			return
+		}
+	}
+
+	resp, err := p.Client.AssumeRole(options)
+	if err != nil {
+		return
+	}
+	creds.AccessKey = strings.TrimSpace(resp.Credentials.AccessKeyId)
+	creds.SecretKey = strings.TrimSpace(resp.Credentials.SecretAccessKey)
+	creds.Token = strings.TrimSpace(resp.Credentials.SessionToken)
+	creds.Expiration = resp.Credentials.Expiration
+	return
+}
+
+// WebIdentityProvider is an aws.CredentialsProvider that returns the
+// temporary credentials of a role assumed with an OpenID Connect token,
+// such as the service account tokens projected into Kubernetes pods.
+//
+// The token is read from TokenFile on every call to Retrieve, so rotated
+// tokens are picked up when the credentials are renewed.
+type WebIdentityProvider struct {
+	// Client is used to call AssumeRoleWithWebIdentity; the request is
+	// not signed, so its Auth is ignored.
+	Client *STS
+
+	RoleArn         string
+	RoleSessionName string // defaults to a name derived from the current time
+	TokenFile       string
+	Policy          string
+
+	// Duration is the requested lifetime of the credentials.
+	Duration time.Duration
+}
+
+// NewWebIdentityProvider returns a WebIdentityProvider that uses client to
+// assume roleArn with the token in tokenFile.
+func NewWebIdentityProvider(client *STS, roleArn, sessionName, tokenFile string) *WebIdentityProvider {
+	return &WebIdentityProvider{
+		Client:          client,
+		RoleArn:         roleArn,
+		RoleSessionName: sessionName,
+		TokenFile:       tokenFile,
+	}
+}
+
+// Retrieve reads the token and assumes the role, returning its temporary
+// credentials.
+func (p *WebIdentityProvider) Retrieve() (creds aws.Credentials, err error) {
+	if p.RoleArn == "" || p.TokenFile == "" {
+		err = errors.New("sts: WebIdentityProvider requires a RoleArn and a TokenFile")
+		return
+	}
+	token, err := ioutil.ReadFile(p.TokenFile)
+	if err != nil {
+		return
+	}
+	options := &AssumeRoleWithWebIdentityParams{
+		RoleArn:          p.RoleArn,
+		RoleSessionName:  p.RoleSessionName,
+		Policy:           p.Policy,
+		WebIdentityToken: strings.TrimSpace(string(token)),
+	}
+	if options.RoleSessionName == "" {
+		options.RoleSessionName = "goamz-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	if p.Duration != 0 {
+		options.DurationSeconds = int(p.Duration / time.Second)
+	}
+
+	resp, err := p.Client.AssumeRoleWithWebIdentity(options)
+	if err != nil {
+		return
+	}
+	creds.AccessKey = strings.TrimSpace(resp.Credentials.AccessKeyId)
+	creds.SecretKey = strings.TrimSpace(resp.Credentials.SecretAccessKey)
+	creds.Token = strings.TrimSpace(resp.Credentials.SessionToken)
+	creds.Expiration = resp.Credentials.Expiration
+	return
+}
+
+func init() {
+	aws.WebIdentityRoleProvider = func(roleArn, sessionName, tokenFile string) aws.CredentialsProvider {
+		return NewWebIdentityProvider(New(aws.Auth{}, aws.USEast), roleArn, sessionName, tokenFile)
+	}
+}
@@ -0,0 +1,149 @@
+package sts_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/sts"
+)
+
+func assumeRoleResponse(accessKey string, expiration time.Time) string {
+	return fmt.Sprintf(AssumeRoleTemplate, accessKey, expiration.UTC().Format(time.RFC3339))
+}
+
+func (s *S) TestAssumeRoleProvider(c *C) {
+	testServer.Response(200, nil, assumeRoleResponse("AKID1", time.Now().Add(time.Hour)))
+	p := sts.NewAssumeRoleProvider(s.sts, "arn:aws:iam::123456789012:role/demo", "Bob")
+	p.ExternalId = "123ABC"
+	p.Duration = 15 * time.Minute
+
+	auth, err := sts.AssumeRoleAuth(p)
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "AKID1")
+	c.Assert(auth.SecretKey, Equals, "SECRET")
+	c.Assert(auth.Token(), Equals, "TOKEN")
+
+	values := testServer.WaitRequest().PostForm
+	c.Assert(values.Get("Action"), Equals, "AssumeRole")
+	c.Assert(values.Get("RoleArn"), Equals, "arn:aws:iam::123456789012:role/demo")
+	c.Assert(values.Get("RoleSessionName"), Equals, "Bob")
+	c.Assert(values.Get("ExternalId"), Equals, "123ABC")
+	c.Assert(values.Get("DurationSeconds"), Equals, "900")
+	c.Assert(values.Get("SerialNumber"), Equals, "")
+
+	// The credentials are cached while they remain valid.
+	current := auth.Current()
+	c.Assert(current.AccessKey, Equals, "AKID1")
+}
+
+func (s *S) TestAssumeRoleProviderRenews(c *C) {
+	testServer.Response(200, nil, assumeRoleResponse("AKID1", time.Now().Add(10*time.Second)))
+	p := sts.NewAssumeRoleProvider(s.sts, "arn:aws:iam::123456789012:role/demo", "")
+
+	auth, err := sts.AssumeRoleAuth(p)
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "AKID1")
+	values := testServer.WaitRequest().PostForm
+	c.Assert(values.Get("RoleSessionName"), Matches, "goamz-[0-9]+")
+	c.Assert(values.Get("DurationSeconds"), Equals, "3600")
+
+	// The first credentials are about to expire, so the role is assumed
+	// again.
+	testServer.Response(200, nil, assumeRoleResponse("AKID2", time.Now().Add(time.Hour)))
+	current := auth.Current()
+	c.Assert(current.AccessKey, Equals, "AKID2")
+	testServer.WaitRequest()
+}
+
+func (s *S) TestAssumeRoleProviderMFA(c *C) {
+	testServer.Response(200, nil, assumeRoleResponse("AKID1", time.Now().Add(time.Hour)))
+	p := sts.NewAssumeRoleProvider(s.sts, "arn:aws:iam::123456789012:role/demo", "Bob")
+	p.SerialNumber = "arn:aws:iam::123456789012:mfa/bob"
+	p.TokenProvider = func() (string, error) { return "123456", nil }
+
+	_, err := p.Retrieve()
+	c.Assert(err, IsNil)
+	values := testServer.WaitRequest().PostForm
+	c.Assert(values.Get("SerialNumber"), Equals, "arn:aws:iam::123456789012:mfa/bob")
+	c.Assert(values.Get("TokenCode"), Equals, "123456")
+}
+
+func (s *S) TestAssumeRoleProviderMFANoTokenProvider(c *C) {
+	p := sts.NewAssumeRoleProvider(s.sts, "arn:aws:iam::123456789012:role/demo", "Bob")
+	p.SerialNumber = "arn:aws:iam::123456789012:mfa/bob"
+	_, err := p.Retrieve()
+	c.Assert(err, ErrorMatches, "sts: AssumeRoleProvider with a SerialNumber requires a TokenProvider")
+}
+
+func (s *S) TestAssumeRoleProviderError(c *C) {
+	testServer.Response(403, nil, ErrorResponse)
+	p := sts.NewAssumeRoleProvider(s.sts, "arn:aws:iam::123456789012:role/demo", "Bob")
+	_, err := sts.AssumeRoleAuth(p)
+	c.Assert(err, NotNil)
+	testServer.WaitRequest()
+}
+
+func webIdentityResponse(accessKey string, expiration time.Time) string {
+	return fmt.Sprintf(AssumeRoleWithWebIdentityTemplate, accessKey, expiration.UTC().Format(time.RFC3339))
+}
+
+func writeTokenFile(c *C, token string) string {
+	path := filepath.Join(c.MkDir(), "token")
+	c.Assert(ioutil.WriteFile(path, []byte(token+"\n"), 0600), IsNil)
+	return path
+}
+
+func (s *S) TestAssumeRoleWithWebIdentity(c *C) {
+	testServer.Response(200, nil, webIdentityResponse("WEBKEY", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	resp, err := s.sts.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityParams{
+		RoleArn:          "arn:aws:iam::123456789012:role/web",
+		RoleSessionName:  "app",
+		WebIdentityToken: "eyJhbGciOi",
+		DurationSeconds:  900,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.Credentials.AccessKeyId, Equals, "WEBKEY")
+	c.Assert(resp.Credentials.Expiration, Equals, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.Assert(resp.SubjectFromWebIdentityToken, Equals, "system:serviceaccount:default:app")
+	c.Assert(resp.Provider, Equals, "oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE")
+	c.Assert(resp.RequestId, Equals, "ad4156e9-bce1-11e2-82e6-6b6efEXAMPLE")
+
+	req := testServer.WaitRequest()
+	// The token is the credential, so the request is not signed.
+	c.Assert(req.Header.Get("Authorization"), Equals, "")
+	c.Assert(req.PostForm.Get("Action"), Equals, "AssumeRoleWithWebIdentity")
+	c.Assert(req.PostForm.Get("WebIdentityToken"), Equals, "eyJhbGciOi")
+	c.Assert(req.PostForm.Get("DurationSeconds"), Equals, "900")
+}
+
+func (s *S) TestWebIdentityProvider(c *C) {
+	tokenFile := writeTokenFile(c, "token1")
+	p := sts.NewWebIdentityProvider(s.sts, "arn:aws:iam::123456789012:role/web", "", tokenFile)
+
+	testServer.Response(200, nil, webIdentityResponse("WEBKEY1", time.Now().Add(10*time.Second)))
+	auth, err := aws.AuthFromProvider(p)
+	c.Assert(err, IsNil)
+	c.Assert(auth.AccessKey, Equals, "WEBKEY1")
+	values := testServer.WaitRequest().PostForm
+	c.Assert(values.Get("WebIdentityToken"), Equals, "token1")
+	c.Assert(values.Get("RoleSessionName"), Matches, "goamz-[0-9]+")
+
+	// A rotated token is read again on renewal.
+	c.Assert(ioutil.WriteFile(tokenFile, []byte("token2"), 0600), IsNil)
+	testServer.Response(200, nil, webIdentityResponse("WEBKEY2", time.Now().Add(time.Hour)))
+	current := auth.Current()
+	c.Assert(current.AccessKey, Equals, "WEBKEY2")
+	c.Assert(current.Token(), Equals, "WEBTOKEN")
+	c.Assert(testServer.WaitRequest().PostForm.Get("WebIdentityToken"), Equals, "token2")
+}
+
+func (s *S) TestWebIdentityProviderNoTokenFile(c *C) {
+	p := sts.NewWebIdentityProvider(s.sts, "arn:aws:iam::123456789012:role/web", "", filepath.Join(c.MkDir(), "missing"))
+	_, err := p.Retrieve()
+	c.Assert(err, ErrorMatches, "open .*missing: no such file or directory")
+}
@@ -82,3 +82,57 @@ var GetSessionTokenResponse = `
   </ResponseMetadata>
 </GetSessionTokenResponse>
 `
+
+var AssumeRoleTemplate = `
+<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <SessionToken>TOKEN</SessionToken>
+      <SecretAccessKey>SECRET</SecretAccessKey>
+      <Expiration>%[2]s</Expiration>
+      <AccessKeyId>%[1]s</AccessKeyId>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/demo/Bob</Arn>
+      <AssumedRoleId>ARO123EXAMPLE123:Bob</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata>
+    <RequestId>c6104cbe-af31-11e0-8154-cbc7ccf896c7</RequestId>
+  </ResponseMetadata>
+</AssumeRoleResponse>
+`
+
+var AssumeRoleWithWebIdentityTemplate = `
+<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <SubjectFromWebIdentityToken>system:serviceaccount:default:app</SubjectFromWebIdentityToken>
+    <Audience>sts.amazonaws.com</Audience>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/web/app</Arn>
+      <AssumedRoleId>AROACLKWSDQRAOEXAMPLE:app</AssumedRoleId>
+    </AssumedRoleUser>
+    <Credentials>
+      <SessionToken>WEBTOKEN</SessionToken>
+      <SecretAccessKey>WEBSECRET</SecretAccessKey>
+      <Expiration>%[2]s</Expiration>
+      <AccessKeyId>%[1]s</AccessKeyId>
+    </Credentials>
+    <Provider>oidc.eks.us-west-2.amazonaws.com/id/EXAMPLE</Provider>
+  </AssumeRoleWithWebIdentityResult>
+  <ResponseMetadata>
+    <RequestId>ad4156e9-bce1-11e2-82e6-6b6efEXAMPLE</RequestId>
+  </ResponseMetadata>
+</AssumeRoleWithWebIdentityResponse>
+`
+
+var ErrorResponse = `
+<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>Not authorized to perform sts:AssumeRole</Message>
+  </Error>
+  <RequestId>4f2b8d6e-0000-11e0-8cfe-09039844ac7d</RequestId>
+</ErrorResponse>
+`
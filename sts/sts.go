@@ -7,6 +7,7 @@
 package sts
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
@@ -24,6 +25,11 @@ import (
 type STS struct {
 	aws.Auth
 	aws.Region
+
+	// RateLimiter, if not nil, limits the requests of the client (see
+	// aws.RateLimiter).
+	RateLimiter *aws.RateLimiter
+
 	private byte // Reserve the right of using private data.
 }
 
@@ -32,9 +38,14 @@ type STS struct {
 func New(auth aws.Auth, region aws.Region) *STS {
 	// Make sure we can run the package tests
 	if region.Name == "" {
-		return &STS{auth, region, 0}
+		return &STS{auth, region, nil, 0}
 	}
-	return &STS{auth, aws.Regions["us-east-1"], 0}
+	region = region.WithEndpoints()
+	if region.STSEndpoint == aws.USEast.STSEndpoint {
+		// The global endpoint only accepts signatures for us-east-1.
+		return &STS{auth, aws.USEast, nil, 0}
+	}
+	return &STS{auth, region, nil, 0}
 }
 
 const debug = false
@@ -63,52 +74,73 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
 }
 
+// ErrorCode, ErrorMessage, HTTPStatusCode and RequestID implement
+// aws.APIError.
+
+func (err *Error) ErrorCode() string    { return err.Code }
+func (err *Error) ErrorMessage() string { return err.Message }
+func (err *Error) HTTPStatusCode() int  { return err.StatusCode }
+func (err *Error) RequestID() string    { return err.RequestId }
+
+// Is reports whether err is of the class target, such as aws.ErrNotFound.
+func (err *Error) Is(target error) bool { return aws.ErrorIs(err, target) }
+
 type xmlErrors struct {
 	RequestId string  `xml:"RequestId"`
 	Errors    []Error `xml:"Error"`
 }
 
-func (sts *STS) query(params map[string]string, resp interface{}) error {
-	params["Version"] = "2011-06-15"
-
-	data := strings.NewReader(multimap(params).Encode())
-
-	hreq, err := http.NewRequest("POST", sts.Region.STSEndpoint+"/", data)
-	if err != nil {
-		return err
-	}
-
-	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
-
-	token := sts.Auth.Token()
-	if token != "" {
-		hreq.Header.Set("X-Amz-Security-Token", token)
-	}
+func (sts *STS) query(ctx context.Context, params map[string]string, resp interface{}) error {
+	return sts.doQuery(ctx, params, resp, true)
+}
 
-	signer := aws.NewV4Signer(sts.Auth, "sts", sts.Region)
-	signer.Sign(hreq)
+// anonymousQuery is like query but sends the request unsigned, as is
+// required for AssumeRoleWithWebIdentity.
+func (sts *STS) anonymousQuery(ctx context.Context, params map[string]string, resp interface{}) error {
+	return sts.doQuery(ctx, params, resp, false)
+}
 
-	if debug {
-		log.Printf("%v -> {\n", hreq)
+func (sts *STS) doQuery(ctx context.Context, params map[string]string, resp interface{}, signed bool) error {
+	params["Version"] = "2011-06-15"
+	data := multimap(params).Encode()
+	r := &aws.Request{
+		Context:     ctx,
+		Service:     "sts",
+		Region:      sts.Region.Name,
+		Operation:   params["Action"],
+		Data:        resp,
+		Client:      aws.UntimedRetryingClient,
+		RateLimiter: sts.RateLimiter,
 	}
-	r, err := http.DefaultClient.Do(hreq)
-
-	if err != nil {
-		log.Printf("Error calling Amazon")
-		return err
+	handlers := aws.Handlers{
+		Build: func(r *aws.Request) (err error) {
+			r.HTTPRequest, err = http.NewRequest("POST", sts.Region.STSEndpoint+"/", strings.NewReader(data))
+			if err != nil {
+				return err
+			}
+			r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+			return nil
+		},
+		Unmarshal: func(r *aws.Request) error {
+			if debug {
+				log.Printf("%v -> {\n", r.HTTPRequest)
+				dump, _ := httputil.DumpResponse(r.HTTPResponse, true)
+				log.Printf("response:\n")
+				log.Printf("%v\n}\n", string(dump))
+			}
+			if r.HTTPResponse.StatusCode != 200 {
+				return buildError(r.HTTPResponse)
+			}
+			return xml.NewDecoder(r.HTTPResponse.Body).Decode(r.Data)
+		},
 	}
-
-	defer r.Body.Close()
-
-	if debug {
-		dump, _ := httputil.DumpResponse(r, true)
-		log.Printf("response:\n")
-		log.Printf("%v\n}\n", string(dump))
+	if signed {
+		handlers.Sign = aws.NewV4Signer(sts.Auth, "sts", sts.Region).SignHandler()
 	}
-	if r.StatusCode != 200 {
-		return buildError(r)
+	err := aws.DefaultPipeline.Run(r, handlers)
+	if r.HTTPResponse != nil {
+		r.HTTPResponse.Body.Close()
 	}
-	err = xml.NewDecoder(r.Body).Decode(resp)
 	return err
 }
 
@@ -153,6 +185,8 @@ type AssumeRoleParams struct {
 	Policy          string
 	RoleArn         string
 	RoleSessionName string
+	SerialNumber    string
+	TokenCode       string
 }
 
 type AssumedRoleUser struct {
@@ -178,6 +212,11 @@ type AssumeRoleResult struct {
 //
 // See http://goo.gl/zDZbuQ for more details.
 func (sts *STS) AssumeRole(options *AssumeRoleParams) (resp *AssumeRoleResult, err error) {
+	return sts.AssumeRoleWithContext(context.Background(), options)
+}
+
+// AssumeRoleWithContext is like AssumeRole but makes its requests with ctx.
+func (sts *STS) AssumeRoleWithContext(ctx context.Context, options *AssumeRoleParams) (resp *AssumeRoleResult, err error) {
 	params := makeParams("AssumeRole")
 
 	params["RoleArn"] = options.RoleArn
@@ -192,9 +231,71 @@ func (sts *STS) AssumeRole(options *AssumeRoleParams) (resp *AssumeRoleResult, e
 	if options.Policy != "" {
 		params["Policy"] = options.Policy
 	}
+	if options.SerialNumber != "" {
+		params["SerialNumber"] = options.SerialNumber
+	}
+	if options.TokenCode != "" {
+		params["TokenCode"] = options.TokenCode
+	}
 
 	resp = new(AssumeRoleResult)
-	if err := sts.query(params, resp); err != nil {
+	if err := sts.query(ctx, params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// options for the AssumeRoleWithWebIdentity function
+//
+// See https://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRoleWithWebIdentity.html for details
+type AssumeRoleWithWebIdentityParams struct {
+	DurationSeconds  int
+	Policy           string
+	ProviderId       string
+	RoleArn          string
+	RoleSessionName  string
+	WebIdentityToken string
+}
+
+type AssumeRoleWithWebIdentityResult struct {
+	AssumedRoleUser             AssumedRoleUser `xml:"AssumeRoleWithWebIdentityResult>AssumedRoleUser"`
+	Audience                    string          `xml:"AssumeRoleWithWebIdentityResult>Audience"`
+	Credentials                 Credentials     `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	PackedPolicySize            int             `xml:"AssumeRoleWithWebIdentityResult>PackedPolicySize"`
+	Provider                    string          `xml:"AssumeRoleWithWebIdentityResult>Provider"`
+	SubjectFromWebIdentityToken string          `xml:"AssumeRoleWithWebIdentityResult>SubjectFromWebIdentityToken"`
+	RequestId                   string          `xml:"ResponseMetadata>RequestId"`
+}
+
+// AssumeRoleWithWebIdentity assumes the specified role with a token issued
+// by an OpenID Connect or OAuth 2.0 identity provider. The request is not
+// signed, so the client's Auth is not used.
+//
+// See https://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRoleWithWebIdentity.html for more details.
+func (sts *STS) AssumeRoleWithWebIdentity(options *AssumeRoleWithWebIdentityParams) (resp *AssumeRoleWithWebIdentityResult, err error) {
+	return sts.AssumeRoleWithWebIdentityWithContext(context.Background(), options)
+}
+
+// AssumeRoleWithWebIdentityWithContext is like AssumeRoleWithWebIdentity but makes its requests with ctx.
+func (sts *STS) AssumeRoleWithWebIdentityWithContext(ctx context.Context, options *AssumeRoleWithWebIdentityParams) (resp *AssumeRoleWithWebIdentityResult, err error) {
+	params := makeParams("AssumeRoleWithWebIdentity")
+
+	params["RoleArn"] = options.RoleArn
+	params["RoleSessionName"] = options.RoleSessionName
+	params["WebIdentityToken"] = options.WebIdentityToken
+
+	if options.DurationSeconds != 0 {
+		params["DurationSeconds"] = strconv.Itoa(options.DurationSeconds)
+	}
+	if options.Policy != "" {
+		params["Policy"] = options.Policy
+	}
+	if options.ProviderId != "" {
+		params["ProviderId"] = options.ProviderId
+	}
+
+	resp = new(AssumeRoleWithWebIdentityResult)
+	if err := sts.anonymousQuery(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -222,6 +323,12 @@ type GetFederationTokenResult struct {
 //
 // See http://goo.gl/Iujjeg for more details
 func (sts *STS) GetFederationToken(name, policy string, durationSeconds int) (
+	resp *GetFederationTokenResult, err error) {
+	return sts.GetFederationTokenWithContext(context.Background(), name, policy, durationSeconds)
+}
+
+// GetFederationTokenWithContext is like GetFederationToken but makes its requests with ctx.
+func (sts *STS) GetFederationTokenWithContext(ctx context.Context, name, policy string, durationSeconds int) (
 	resp *GetFederationTokenResult, err error) {
 	params := makeParams("GetFederationToken")
 	params["Name"] = name
@@ -234,7 +341,7 @@ func (sts *STS) GetFederationToken(name, policy string, durationSeconds int) (
 	}
 
 	resp = new(GetFederationTokenResult)
-	if err := sts.query(params, resp); err != nil {
+	if err := sts.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -252,6 +359,12 @@ type GetSessionTokenResult struct {
 //
 // See http://goo.gl/v8s5Y for more details
 func (sts *STS) GetSessionToken(durationSeconds int, serialnNumber, tokenCode string) (
+	resp *GetSessionTokenResult, err error) {
+	return sts.GetSessionTokenWithContext(context.Background(), durationSeconds, serialnNumber, tokenCode)
+}
+
+// GetSessionTokenWithContext is like GetSessionToken but makes its requests with ctx.
+func (sts *STS) GetSessionTokenWithContext(ctx context.Context, durationSeconds int, serialnNumber, tokenCode string) (
 	resp *GetSessionTokenResult, err error) {
 	params := makeParams("GetSessionToken")
 
@@ -266,7 +379,7 @@ func (sts *STS) GetSessionToken(durationSeconds int, serialnNumber, tokenCode st
 	}
 
 	resp = new(GetSessionTokenResult)
-	if err := sts.query(params, resp); err != nil {
+	if err := sts.query(ctx, params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
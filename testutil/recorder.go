@@ -0,0 +1,382 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/hughe/goamz/aws"
+)
+
+// A RecorderMode tells whether a Recorder records or replays.
+type RecorderMode int
+
+const (
+	ModeReplay RecorderMode = iota
+	ModeRecord
+)
+
+// RecordEnv is the environment variable that DefaultRecorderMode reads.
+const RecordEnv = "GOAMZ_RECORD"
+
+// DefaultRecorderMode returns ModeRecord if the GOAMZ_RECORD environment
+// variable is set to a true value, such as "1" or "true", and ModeReplay
+// otherwise, so that new cassettes are recorded with
+//
+//	GOAMZ_RECORD=1 go test ./...
+func DefaultRecorderMode() RecorderMode {
+	if record, _ := strconv.ParseBool(os.Getenv(RecordEnv)); record {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// A Cassette holds the HTTP interactions recorded by a Recorder.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// An Interaction is a request and the response it got.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+	replayed bool
+}
+
+// A RecordedRequest is a request as recorded in a Cassette, with its
+// credentials and signature scrubbed.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// A RecordedResponse is a response as recorded in a Cassette.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Body is the body of a recorded request or response. It is saved as a
+// JSON string, base64-encoded if it is not valid UTF-8.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*b = Body(s)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = decoded
+	return err
+}
+
+// A Recorder records HTTP interactions with AWS to a cassette file, or
+// replays them from it, so that service clients can be tested offline
+// with realistic responses. It can be used as the http.RoundTripper of a
+// client, or installed in an aws.Pipeline, which makes every service
+// client go through it:
+//
+//	rec, err := testutil.NewRecorder("testdata/instances.json", testutil.DefaultRecorderMode())
+//	...
+//	rec.Install(aws.DefaultPipeline)
+//	defer rec.Uninstall(aws.DefaultPipeline)
+//	defer rec.Save()
+//
+// When recording, credentials, signatures and request times are scrubbed
+// from the requests saved, as are the secrets and access key IDs of
+// credentials in the responses. When replaying, requests are matched with
+// the recorded ones by method, path and parameters (from the query, a
+// form body and the X-Amz-Target header), ignoring the host and the
+// scrubbed parameters; the recorded requests that match are replayed in
+// turn, the last one again as long as requests match it.
+type Recorder struct {
+	Mode RecorderMode
+
+	// Path is the cassette file.
+	Path string
+
+	// Transport makes the requests that are recorded by RoundTrip;
+	// http.DefaultTransport if nil.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder in mode with the cassette file at path.
+// In ModeReplay, the cassette is loaded from the file.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{Mode: mode, Path: path}
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("testutil: reading cassette %s: %v", path, err)
+		}
+	}
+	return r, nil
+}
+
+// Save writes the interactions recorded by r to its cassette file. It
+// does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.Path, append(data, '\n'), 0644)
+}
+
+// Interactions returns the interactions recorded or loaded by r.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.cassette.Interactions...)
+}
+
+// Client returns an HTTP client that makes its requests through r.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records req and its response, or replays the response to a
+// recorded request matching req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.Mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return res, r.record(req, body, res)
+}
+
+// Install adds r to the Send phase of p under the name "Recorder", so
+// that the requests of every service client are recorded or replayed.
+func (r *Recorder) Install(p *aws.Pipeline) {
+	p.Use(aws.SendPhase, "Recorder", func(next aws.Handler) aws.Handler {
+		return func(ar *aws.Request) error {
+			req := ar.HTTPRequest
+			body, err := readBody(req)
+			if err != nil {
+				return err
+			}
+			if r.Mode == ModeReplay {
+				ar.HTTPResponse, err = r.replay(req, body)
+				return err
+			}
+			if err := next(ar); err != nil {
+				return err
+			}
+			return r.record(req, body, ar.HTTPResponse)
+		}
+	})
+}
+
+// Uninstall removes r from p.
+func (r *Recorder) Uninstall(p *aws.Pipeline) {
+	p.Remove(aws.SendPhase, "Recorder")
+}
+
+// readBody reads the body of req, leaving it to be read again.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, res *http.Response) error {
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if err != nil {
+		return err
+	}
+	u := *req.URL
+	u.RawQuery = scrubValues(u.Query()).Encode()
+	if isForm(req.Header) {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			body = []byte(scrubValues(values).Encode())
+		}
+	}
+	header := req.Header.Clone()
+	for _, name := range scrubbedHeaders {
+		header.Del(name)
+	}
+	resHeader := res.Header.Clone()
+	resHeader.Del("Set-Cookie")
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: u.String(), Header: header, Body: body},
+		Response: RecordedResponse{Status: res.StatusCode, Header: resHeader, Body: scrubSecrets(resBody)},
+	})
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL, req.Header, body)
+	r.mu.Lock()
+	var found *Interaction
+	for _, i := range r.cassette.Interactions {
+		u, err := url.Parse(i.Request.URL)
+		if err != nil || matchKey(i.Request.Method, u, i.Request.Header, i.Request.Body) != key {
+			continue
+		}
+		found = i
+		if !i.replayed {
+			break
+		}
+	}
+	if found != nil {
+		found.replayed = true
+	}
+	r.mu.Unlock()
+	if found == nil {
+		return nil, fmt.Errorf("testutil: no interaction in cassette %s matches %s", r.Path, key)
+	}
+	resp := found.Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrubbedHeaders are the request headers left out of recordings.
+var scrubbedHeaders = []string{
+	"Authorization",
+	"Date",
+	"X-Amz-Date",
+	"X-Amz-Security-Token",
+	"X-Amz-Content-Sha256",
+	"Cookie",
+}
+
+// scrubbedParams are the request parameters, in the query or a form
+// body, that are left out of recordings and ignored when matching.
+var scrubbedParams = map[string]bool{
+	"AWSAccessKeyId":       true,
+	"Signature":            true,
+	"SignatureMethod":      true,
+	"SignatureVersion":     true,
+	"SecurityToken":        true,
+	"Timestamp":            true,
+	"Expires":              true,
+	"X-Amz-Algorithm":      true,
+	"X-Amz-Credential":     true,
+	"X-Amz-Date":           true,
+	"X-Amz-Expires":        true,
+	"X-Amz-Security-Token": true,
+	"X-Amz-Signature":      true,
+	"X-Amz-SignedHeaders":  true,
+}
+
+func scrubValues(values url.Values) url.Values {
+	for name := range values {
+		if scrubbedParams[name] {
+			delete(values, name)
+		}
+	}
+	return values
+}
+
+// secretPattern matches the secrets of credentials in XML and JSON
+// responses, such as those of STS, and the IDs of access keys, such as
+// those STS and IAM return.
+var secretPattern = regexp.MustCompile(`(<(AccessKeyId|SecretAccessKey|SessionToken|Token)>)[^<]*(</)|("(AccessKeyId|SecretAccessKey|SessionToken|Token)"\s*:\s*")[^"]*(")`)
+
+func scrubSecrets(body []byte) []byte {
+	return secretPattern.ReplaceAll(body, []byte("${1}${4}REDACTED${3}${6}"))
+}
+
+func isForm(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "application/x-www-form-urlencoded")
+}
+
+// matchKey returns what requests are matched by: the method, the path
+// and the parameters, sorted and without the scrubbed ones.
+func matchKey(method string, u *url.URL, header http.Header, body []byte) string {
+	values := u.Query()
+	if isForm(header) {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for name, v := range form {
+				values[name] = append(values[name], v...)
+			}
+		}
+	}
+	if target := header.Get("X-Amz-Target"); target != "" {
+		values.Set("X-Amz-Target", target)
+	}
+	scrubValues(values)
+	for _, v := range values {
+		sort.Strings(v)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return method + " " + path + "?" + values.Encode()
+}
@@ -0,0 +1,142 @@
+package testutil_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hughe/goamz/aws"
+	"github.com/hughe/goamz/sts"
+	"github.com/hughe/goamz/testutil"
+)
+
+const sessionTokenResponse = `
+<GetSessionTokenResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetSessionTokenResult>
+    <Credentials>
+      <SessionToken>AQoEXAMPLEH4aoAH0gNCAPy</SessionToken>
+      <SecretAccessKey>wJalrXUtnFEMI/K7MDENG/bPxRfiCYzEXAMPLEKEY</SecretAccessKey>
+      <Expiration>2011-07-11T19:55:29.611Z</Expiration>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+    </Credentials>
+  </GetSessionTokenResult>
+  <ResponseMetadata>
+    <RequestId>58c5dbae-abef-11e0-8cfe-09039844ac7d</RequestId>
+  </ResponseMetadata>
+</GetSessionTokenResponse>
+`
+
+func TestRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, sessionTokenResponse)
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "sts.json")
+
+	rec, err := testutil.NewRecorder(path, testutil.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Install(aws.DefaultPipeline)
+	client := sts.New(aws.Auth{AccessKey: "AKIDRECORDED", SecretKey: "secret"}, aws.Region{STSEndpoint: ts.URL})
+	resp, err := client.GetSessionToken(3600, "", "")
+	rec.Uninstall(aws.DefaultPipeline)
+	ts.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Credentials.AccessKeyId != "ASIAEXAMPLE" {
+		t.Fatalf("Got %#v", resp.Credentials)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"AKIDRECORDED", "Authorization", "ASIAEXAMPLE", "wJalrXUtnFEMI", "AQoEXAMPLE"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("Cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	// Requests are replayed whatever the endpoint and credentials.
+	rec, err = testutil.NewRecorder(path, testutil.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Install(aws.DefaultPipeline)
+	defer rec.Uninstall(aws.DefaultPipeline)
+	client = sts.New(aws.Auth{AccessKey: "other", SecretKey: "other"}, aws.Region{STSEndpoint: "http://sts.invalid"})
+	for i := 0; i < 2; i++ {
+		resp, err = client.GetSessionToken(3600, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Credentials.AccessKeyId != "REDACTED" || resp.Credentials.SecretAccessKey != "REDACTED" {
+			t.Fatalf("Got %#v", resp.Credentials)
+		}
+	}
+
+	_, err = client.GetSessionToken(900, "", "")
+	if err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Fatalf("Got error %v for an unrecorded request", err)
+	}
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		fmt.Fprintf(w, "page %s", r.Form.Get("Page"))
+	}))
+	path := filepath.Join(t.TempDir(), "pages.json")
+
+	rec, _ := testutil.NewRecorder(path, testutil.ModeRecord)
+	for _, page := range []string{"1", "2"} {
+		res, err := rec.Client().Post(ts.URL+"/list?Signature=abc", "application/x-www-form-urlencoded", strings.NewReader("Page="+page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}
+	ts.Close()
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := testutil.NewRecorder(path, testutil.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, page := range []string{"2", "1"} {
+		res, err := rec.Client().Post("http://example.invalid/list?Signature=xyz", "application/x-www-form-urlencoded", strings.NewReader("Page="+page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if string(body) != "page "+page {
+			t.Fatalf("Got %q for page %s", body, page)
+		}
+	}
+}
+
+func TestDefaultRecorderMode(t *testing.T) {
+	for value, mode := range map[string]testutil.RecorderMode{
+		"":      testutil.ModeReplay,
+		"0":     testutil.ModeReplay,
+		"false": testutil.ModeReplay,
+		"1":     testutil.ModeRecord,
+		"true":  testutil.ModeRecord,
+	} {
+		t.Setenv(testutil.RecordEnv, value)
+		if got := testutil.DefaultRecorderMode(); got != mode {
+			t.Errorf("Mode with %s=%q is %v", testutil.RecordEnv, value, got)
+		}
+	}
+}